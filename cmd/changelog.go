@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"git-log-analyzer/internal/ai"
+	"git-log-analyzer/internal/changelog"
+	"git-log-analyzer/internal/git"
+)
+
+var changelogFormat string
+var changelogRepoURL string
+var changelogAISummary bool
+
+// changelogCmd writes release notes for a tag or revision range
+var changelogCmd = &cobra.Command{
+	Use:   "changelog [<tag> | <from>..<to>]",
+	Short: "Generate a changelog for a tag or revision range",
+	Long: `Changelog groups the commits of a release by type (features, fixes,
+performance, refactoring, docs, ...) using Conventional Commits prefixes and,
+for other subjects, their first word. Breaking changes ("type!:" or a
+BREAKING CHANGE footer) are listed first, pull request numbers are taken from
+GitHub and GitLab merge commits and "(#123)" squash suffixes, and the
+contributors are listed at the end.
+
+Without an argument the changelog covers the commits since the latest tag
+("unreleased"); with a tag it covers the commits since the previous tag.
+The changelog is written to stdout, or to the file given with -o.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := commandContext(cmd)
+		defer cancel()
+
+		spec := ""
+		if len(args) > 0 {
+			spec = args[0]
+		}
+		if err := runChangelog(ctx, spec); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	changelogCmd.Flags().StringVar(&changelogFormat, "format", "markdown", "output format: markdown or json")
+	changelogCmd.Flags().StringVar(&changelogRepoURL, "repo-url", "", "repository URL used to link pull requests and commits, e.g. https://github.com/org/repo")
+	changelogCmd.Flags().BoolVar(&changelogAISummary, "ai-summary", false, "summarize each group of changes with the AI provider")
+	rootCmd.AddCommand(changelogCmd)
+}
+
+func runChangelog(ctx context.Context, spec string) error {
+	if reportLanguage != "" {
+		os.Setenv("REPORT_LANGUAGE", reportLanguage)
+	}
+	if changelogFormat != "markdown" && changelogFormat != "json" {
+		return fmt.Errorf("unknown changelog format %q (expected markdown or json)", changelogFormat)
+	}
+
+	backend, err := git.NewBackend(gitBackend, repoPath)
+	if err != nil {
+		return err
+	}
+	cl, err := changelog.Generate(ctx, backend, spec)
+	if ctx.Err() != nil {
+		return contextError(ctx)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to generate changelog: %v", err)
+	}
+
+	if changelogAISummary {
+		if aiDryRun {
+			config := ai.WithDefaults(loadAIConfig())
+			for _, g := range cl.Groups {
+				printAIMessages(config, ai.BuildChangelogPrompt(cl, g))
+			}
+			return nil
+		}
+		summarizeChangelog(ctx, cl)
+		if ctx.Err() != nil {
+			return contextError(ctx)
+		}
+	}
+
+	var buf bytes.Buffer
+	if changelogFormat == "json" {
+		err = changelog.WriteJSON(&buf, cl)
+	} else {
+		err = changelog.WriteMarkdown(&buf, cl, changelogRepoURL)
+	}
+	if err != nil {
+		return err
+	}
+
+	return writeCommandOutput(&buf, "更新日志")
+}
+
+// summarizeChangelog adds AI summaries to the groups. A failure is reported
+// and the changelog is written without the remaining summaries.
+func summarizeChangelog(ctx context.Context, cl *changelog.Changelog) {
+	client, err := newAIClient()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️ AI摘要已跳过: %v\n", err)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "🤖 正在生成 %d 个分组的AI摘要...\n", len(cl.Groups))
+	if err := client.SummarizeChangelog(ctx, cl); err != nil && ctx.Err() == nil {
+		fmt.Fprintf(os.Stderr, "⚠️ AI摘要失败: %v\n", err)
+	}
+	fmt.Fprintf(os.Stderr, "🤖 AI用量: %s\n", formatAIUsage(client.Usage()))
+}
@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"git-log-analyzer/internal/analyzer"
+	"git-log-analyzer/internal/chat"
+	"git-log-analyzer/internal/git"
+)
+
+var chatScript string
+var chatAnalysis string
+var chatSaveAnalysis string
+
+// chatCmd answers questions about the repository with the AI provider
+var chatCmd = &cobra.Command{
+	Use:   "chat",
+	Short: "Ask the AI questions about the repository's history",
+	Long: `Chat analyzes the repository (or loads a saved analysis) and answers
+questions about it with the configured AI provider. The model looks up
+authors, files, technical debt hotspots and commits in a date range through
+tool calls, e.g. "who knows the payments module best?" or "what changed
+around March 12?".
+
+Type one question per line; "exit" or Ctrl-D ends the session. With --script
+the questions are read from a file ("-" for stdin) and the transcript is
+printed, which together with the fake provider gives reproducible sessions.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := commandContext(cmd)
+		defer cancel()
+
+		if err := runChat(ctx); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	chatCmd.Flags().StringVar(&chatScript, "script", "", "read questions from a file (- for stdin) and print the transcript")
+	chatCmd.Flags().StringVar(&chatAnalysis, "analysis", "", "load an analysis saved with --save-analysis instead of analyzing the repository")
+	chatCmd.Flags().StringVar(&chatSaveAnalysis, "save-analysis", "", "save the analysis to a file for later sessions")
+	rootCmd.AddCommand(chatCmd)
+}
+
+func runChat(ctx context.Context) error {
+	if reportLanguage != "" {
+		os.Setenv("REPORT_LANGUAGE", reportLanguage)
+	}
+
+	// Check the AI configuration before spending time on the analysis
+	client, err := newAIClient()
+	if err != nil {
+		return err
+	}
+
+	stats, err := loadChatAnalysis(ctx)
+	if err != nil {
+		return err
+	}
+	if chatSaveAnalysis != "" {
+		if err := analyzer.SaveStatistics(chatSaveAnalysis, stats); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "💾 分析结果已保存: %s\n", chatSaveAnalysis)
+	}
+
+	name := repoPath
+	if abs, err := filepath.Abs(repoPath); err == nil {
+		name = filepath.Base(abs)
+	}
+	session := chat.NewSession(client, stats, name)
+	session.Trace = os.Stdout
+
+	var in io.Reader = os.Stdin
+	scripted := chatScript != ""
+	if scripted && chatScript != "-" {
+		f, err := os.Open(chatScript)
+		if err != nil {
+			return fmt.Errorf("failed to open script: %v", err)
+		}
+		defer f.Close()
+		in = f
+	}
+	if !scripted {
+		fmt.Fprintf(os.Stderr, "💬 已载入 %d 个提交的分析结果，请输入问题（exit 退出）\n", stats.TotalCommits)
+	}
+
+	err = session.Run(ctx, in, os.Stdout, scripted)
+	fmt.Fprintf(os.Stderr, "🤖 AI用量: %s\n", formatAIUsage(client.Usage()))
+	if errors.Is(err, context.Canceled) && !scripted {
+		// Ctrl-C ends an interactive session like "exit"
+		fmt.Println()
+		return nil
+	}
+	if ctx.Err() != nil {
+		return contextError(ctx)
+	}
+	return err
+}
+
+// loadChatAnalysis loads the saved analysis or analyzes the repository,
+// keeping every commit for the commits tool
+func loadChatAnalysis(ctx context.Context) (*analyzer.Statistics, error) {
+	if chatAnalysis != "" {
+		return analyzer.LoadStatistics(chatAnalysis)
+	}
+
+	fmt.Fprintf(os.Stderr, "🔍 正在分析Git仓库: %s\n", repoPath)
+	backend, err := git.NewBackend(gitBackend, repoPath)
+	if err != nil {
+		return nil, err
+	}
+	a := analyzer.NewAnalyzerWithBackend(backend, analyzer.Options{
+		Jobs:        jobs,
+		LowMemory:   lowMemory,
+		KeepCommits: true,
+	})
+	stats, err := a.Analyze(ctx)
+	if ctx.Err() != nil {
+		return nil, contextError(ctx)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to analyze repository: %v", err)
+	}
+	return stats, nil
+}
@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"git-log-analyzer/internal/analyzer"
+	"git-log-analyzer/internal/git"
+	"git-log-analyzer/internal/policy"
+)
+
+// Exit codes of the check command
+const (
+	checkExitFailed = 1
+	checkExitError  = 2
+)
+
+var checkFormat string
+
+// checkCmd gates a CI pipeline on policies evaluated against the analysis
+var checkCmd = &cobra.Command{
+	Use:   "check [<from>..<to>]",
+	Short: "Evaluate CI policies against the analysis",
+	Long: `Check analyzes the repository and evaluates policies against the result:
+
+  health-score   the code health score is at least --min-health-score
+  concentration  no file has more than --max-concentration percent of all
+                 file changes
+  hotspot-tests  a change editing one of the top --hotspot-count hotspots
+                 also changes a test file (--test-patterns)
+  bus-factor     at least --min-bus-factor authors are needed to cover half
+                 of the file changes
+
+The hotspot policy checks the change under review, given as a range such as
+origin/main..HEAD, and is skipped without one. A threshold of 0 disables a
+policy. The policies can also be set in the config file under "check" (e.g.
+check.min-health-score).
+
+Exit codes: 0 when every policy passes, 1 when a policy fails, 2 when the
+check couldn't run.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := commandContext(cmd)
+		defer cancel()
+
+		spec := ""
+		if len(args) > 0 {
+			spec = args[0]
+		}
+		passed, err := runCheck(ctx, spec)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(checkExitError)
+		}
+		if !passed {
+			os.Exit(checkExitFailed)
+		}
+	},
+}
+
+func init() {
+	defaults := policy.DefaultConfig()
+	flags := checkCmd.Flags()
+	flags.Float64("min-health-score", defaults.MinHealthScore, "minimum code health score (0-100)")
+	flags.Float64("max-concentration", defaults.MaxConcentration, "maximum share of all file changes in a single file, in percent")
+	flags.Int("hotspot-count", defaults.HotspotCount, "edits of the top N hotspots in the range require a test change")
+	flags.StringSlice("test-patterns", defaults.TestPatterns, "test file patterns: base name globs, or directories ending in /")
+	flags.Int("min-bus-factor", defaults.MinBusFactor, "minimum number of authors behind half of the file changes")
+	flags.StringVar(&checkFormat, "format", "text", "output format: text or json")
+
+	for _, name := range []string{"min-health-score", "max-concentration", "hotspot-count", "test-patterns", "min-bus-factor"} {
+		viper.BindPFlag("check."+name, flags.Lookup(name))
+	}
+	rootCmd.AddCommand(checkCmd)
+}
+
+// loadPolicyConfig reads the policies from the flags and the config file
+func loadPolicyConfig() policy.Config {
+	return policy.Config{
+		MinHealthScore:   viper.GetFloat64("check.min-health-score"),
+		MaxConcentration: viper.GetFloat64("check.max-concentration"),
+		HotspotCount:     viper.GetInt("check.hotspot-count"),
+		TestPatterns:     viper.GetStringSlice("check.test-patterns"),
+		MinBusFactor:     viper.GetInt("check.min-bus-factor"),
+	}
+}
+
+// runCheck evaluates the policies and reports whether all of them passed
+func runCheck(ctx context.Context, spec string) (bool, error) {
+	if checkFormat != "text" && checkFormat != "json" {
+		return false, fmt.Errorf("unknown check format %q (expected text or json)", checkFormat)
+	}
+	if spec != "" && !strings.Contains(spec, "..") {
+		return false, fmt.Errorf("invalid range %q (expected <from>..<to>)", spec)
+	}
+
+	backend, err := git.NewBackend(gitBackend, repoPath)
+	if err != nil {
+		return false, err
+	}
+	fmt.Fprintf(os.Stderr, "🔍 正在分析Git仓库: %s\n", repoPath)
+	stats, err := analyzer.NewAnalyzerWithBackend(backend, analyzer.Options{
+		Jobs:      jobs,
+		LowMemory: lowMemory,
+	}).Analyze(ctx)
+	if ctx.Err() != nil {
+		return false, contextError(ctx)
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to analyze repository: %v", err)
+	}
+
+	var change *policy.Change
+	if spec != "" {
+		if change, err = readChange(ctx, backend, spec); err != nil {
+			return false, err
+		}
+	}
+
+	report := policy.Evaluate(loadPolicyConfig(), stats, change)
+
+	var buf bytes.Buffer
+	if checkFormat == "json" {
+		if err := encodeJSON(&buf, report); err != nil {
+			return false, err
+		}
+	} else {
+		writeCheckReport(&buf, report)
+	}
+
+	if err := writeCommandOutput(&buf, "检查结果"); err != nil {
+		return false, err
+	}
+	return report.Passed, nil
+}
+
+// readChange collects the files changed by the commits of a range
+func readChange(ctx context.Context, backend git.Backend, spec string) (*policy.Change, error) {
+	from, to, _ := strings.Cut(spec, "..")
+	if to == "" {
+		to = "HEAD"
+	}
+	commits, err := backend.GetRangeCommits(ctx, from, to)
+	if ctx.Err() != nil {
+		return nil, contextError(ctx)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read commits: %v", err)
+	}
+
+	seen := make(map[string]bool)
+	change := &policy.Change{Range: spec, Commits: len(commits)}
+	for _, commit := range commits {
+		_, _, files, err := backend.GetCommitStats(ctx, commit.Hash)
+		if ctx.Err() != nil {
+			return nil, contextError(ctx)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read changes of %s: %v", commit.Hash, err)
+		}
+		for _, file := range files {
+			if !seen[file] {
+				seen[file] = true
+				change.Files = append(change.Files, file)
+			}
+		}
+	}
+	sort.Strings(change.Files)
+	return change, nil
+}
+
+// writeCheckReport writes one line per policy and the overall outcome
+func writeCheckReport(buf *bytes.Buffer, report *policy.Report) {
+	fmt.Fprintf(buf, "🚦 策略检查")
+	if report.Range != "" {
+		fmt.Fprintf(buf, " (%s)", report.Range)
+	}
+	fmt.Fprintln(buf, ":")
+
+	icons := map[policy.Status]string{policy.StatusPass: "✅", policy.StatusFail: "❌", policy.StatusSkip: "⏭️"}
+	for _, r := range report.Results {
+		fmt.Fprintf(buf, "  %s %-14s %s\n", icons[r.Status], r.Policy, r.Message)
+		if r.Status == policy.StatusFail {
+			for _, detail := range r.Details {
+				fmt.Fprintf(buf, "      - %s\n", detail)
+			}
+		}
+	}
+
+	if report.Passed {
+		fmt.Fprintln(buf, "\n✅ 所有策略均已通过")
+	} else {
+		fmt.Fprintf(buf, "\n❌ %d 项策略未通过\n", report.Failed)
+	}
+}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"git-log-analyzer/internal/analyzer"
+	"git-log-analyzer/internal/git"
+	"git-log-analyzer/internal/health"
+)
+
+var healthFormat string
+
+// healthCmd exports the code health findings for CI systems
+var healthCmd = &cobra.Command{
+	Use:   "health",
+	Short: "Export code health findings (text, JSON, SARIF, JUnit XML)",
+	Long: `Health analyzes the repository and writes the code health findings:
+technical debt hotspots, God files, refactoring signals and unstable files.
+
+  text   a summary for the terminal
+  json   the health metrics, as served by "serve" at /api/health
+  sarif  SARIF 2.1.0 results with file locations and levels, for code
+         scanning (e.g. github/codeql-action/upload-sarif)
+  junit  JUnit XML with a test suite per rule and a test case per file, for
+         test result views; errors and warnings are failures
+
+The output goes to stdout or the -o file.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := commandContext(cmd)
+		defer cancel()
+		if err := runHealth(ctx); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	healthCmd.Flags().StringVar(&healthFormat, "format", "text", "output format: text, json, sarif or junit")
+	rootCmd.AddCommand(healthCmd)
+}
+
+func runHealth(ctx context.Context) error {
+	switch healthFormat {
+	case "text", "json", "sarif", "junit":
+	default:
+		return fmt.Errorf("unknown health format %q (expected text, json, sarif or junit)", healthFormat)
+	}
+
+	backend, err := git.NewBackend(gitBackend, repoPath)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "🔍 正在分析Git仓库: %s\n", repoPath)
+	stats, err := analyzer.NewAnalyzerWithBackend(backend, analyzer.Options{
+		Jobs:      jobs,
+		LowMemory: lowMemory,
+	}).Analyze(ctx)
+	if ctx.Err() != nil {
+		return contextError(ctx)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to analyze repository: %v", err)
+	}
+	metrics := stats.CodeHealthMetrics
+
+	var buf bytes.Buffer
+	switch healthFormat {
+	case "json":
+		err = encodeJSON(&buf, metrics)
+	case "sarif":
+		err = health.WriteSARIF(&buf, metrics)
+	case "junit":
+		err = health.WriteJUnit(&buf, metrics)
+	default:
+		writeHealthReport(&buf, metrics)
+	}
+	if err != nil {
+		return err
+	}
+
+	return writeCommandOutput(&buf, "健康报告")
+}
+
+// writeHealthReport writes the score and the findings
+func writeHealthReport(buf *bytes.Buffer, metrics *health.CodeHealthMetrics) {
+	fmt.Fprintf(buf, "🩺 代码健康评分: %.0f/100\n", metrics.HealthScore*100)
+	if metrics.HealthSummary != "" {
+		fmt.Fprintf(buf, "   %s\n", metrics.HealthSummary)
+	}
+
+	findings := health.Findings(metrics)
+	if len(findings) == 0 {
+		fmt.Fprintln(buf, "\n✅ 没有发现问题")
+		return
+	}
+	icons := map[string]string{health.LevelError: "❌", health.LevelWarning: "⚠️", health.LevelNote: "ℹ️"}
+	fmt.Fprintf(buf, "\n发现 %d 个问题:\n", len(findings))
+	for _, f := range findings {
+		fmt.Fprintf(buf, "  %s %-18s %s\n      %s\n", icons[f.Level], f.RuleID, f.File, f.Message)
+	}
+}
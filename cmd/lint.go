@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"git-log-analyzer/internal/git"
+	"git-log-analyzer/internal/quality"
+)
+
+// Exit codes of the lint command
+const (
+	lintExitViolations = 1
+	lintExitError      = 2
+)
+
+var lintFormat string
+var lintMinCompliance float64
+
+// lintCmd checks commit messages against the team's convention
+var lintCmd = &cobra.Command{
+	Use:   "lint [<rev> | <from>..<to>]",
+	Short: "Check commit messages against a convention",
+	Long: `Lint checks every commit of a range (default: the whole history of HEAD)
+against a commit message convention and reports the compliance rate per
+author along with each violation:
+
+  conventional      "type(scope): description" header (Conventional Commits)
+  type              the type is one of the allowed types
+  pattern           the subject matches a custom regular expression
+  subject-length    the subject fits in the maximum width
+  body-line-length  body lines are wrapped at the maximum width
+  trailer           required trailers such as Signed-off-by are present
+
+The convention can also be set in the config file under "lint" (e.g.
+lint.pattern, lint.required-trailers). Merge commits are skipped unless
+--include-merges is set.
+
+Exit codes: 0 when the compliance rate reaches --min-compliance (default: every
+commit must pass), 1 when it doesn't, 2 when the lint couldn't run.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := commandContext(cmd)
+		defer cancel()
+
+		spec := ""
+		if len(args) > 0 {
+			spec = args[0]
+		}
+		passed, err := runLint(ctx, spec)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(lintExitError)
+		}
+		if !passed {
+			os.Exit(lintExitViolations)
+		}
+	},
+}
+
+func init() {
+	defaults := quality.DefaultLintConfig()
+	flags := lintCmd.Flags()
+	flags.Bool("conventional", defaults.Conventional, "require Conventional Commits headers")
+	flags.StringSlice("types", defaults.Types, "Conventional Commits types to accept (empty accepts any)")
+	flags.String("pattern", "", "regular expression every subject must match")
+	flags.Int("max-subject-length", defaults.MaxSubjectLength, "maximum subject width (0 disables the check)")
+	flags.Int("max-body-line-length", defaults.MaxBodyLineLength, "maximum body line width (0 disables the check)")
+	flags.StringSlice("required-trailers", nil, "trailers every commit must have, e.g. Signed-off-by")
+	flags.Bool("include-merges", false, "lint merge commits too")
+	flags.StringVar(&lintFormat, "format", "text", "output format: text or json")
+	flags.Float64Var(&lintMinCompliance, "min-compliance", 100, "minimum compliance rate in percent for a zero exit code")
+
+	for _, name := range []string{"conventional", "types", "pattern", "max-subject-length", "max-body-line-length", "required-trailers", "include-merges"} {
+		viper.BindPFlag("lint."+name, flags.Lookup(name))
+	}
+	rootCmd.AddCommand(lintCmd)
+}
+
+// loadLintConfig reads the convention from the flags and the config file
+func loadLintConfig() quality.LintConfig {
+	return quality.LintConfig{
+		Conventional:      viper.GetBool("lint.conventional"),
+		Types:             viper.GetStringSlice("lint.types"),
+		Pattern:           viper.GetString("lint.pattern"),
+		MaxSubjectLength:  viper.GetInt("lint.max-subject-length"),
+		MaxBodyLineLength: viper.GetInt("lint.max-body-line-length"),
+		RequiredTrailers:  viper.GetStringSlice("lint.required-trailers"),
+		IncludeMerges:     viper.GetBool("lint.include-merges"),
+	}
+}
+
+// runLint lints the range and reports whether it is compliant enough
+func runLint(ctx context.Context, spec string) (bool, error) {
+	if lintFormat != "text" && lintFormat != "json" {
+		return false, fmt.Errorf("unknown lint format %q (expected text or json)", lintFormat)
+	}
+	linter, err := quality.NewLinter(loadLintConfig())
+	if err != nil {
+		return false, err
+	}
+
+	backend, err := git.NewBackend(gitBackend, repoPath)
+	if err != nil {
+		return false, err
+	}
+	from, to, ok := strings.Cut(spec, "..")
+	if !ok {
+		from, to = "", spec
+	}
+	if to == "" {
+		to = "HEAD"
+	}
+	commits, err := backend.GetRangeCommits(ctx, from, to)
+	if ctx.Err() != nil {
+		return false, contextError(ctx)
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read commits: %v", err)
+	}
+
+	report := linter.Lint(commits)
+	passed := report.Rate >= lintMinCompliance
+
+	var buf bytes.Buffer
+	if lintFormat == "json" {
+		if err := encodeJSON(&buf, report); err != nil {
+			return false, err
+		}
+	} else {
+		writeLintReport(&buf, report, passed)
+	}
+
+	if err := writeCommandOutput(&buf, "检查结果"); err != nil {
+		return false, err
+	}
+	return passed, nil
+}
+
+// writeLintReport writes the compliance summary, the per-author rates and
+// the violations
+func writeLintReport(buf *bytes.Buffer, report *quality.LintReport, passed bool) {
+	fmt.Fprintf(buf, "📋 提交规范检查: %d 个提交, %d 个通过 (%.1f%%)\n", report.Commits, report.Passed, report.Rate)
+	if report.Commits == 0 {
+		return
+	}
+
+	fmt.Fprintf(buf, "\n按作者:\n")
+	for _, a := range report.Authors {
+		fmt.Fprintf(buf, "  %-40s %4d/%-4d %6.1f%%\n", a.Author, a.Passed, a.Commits, a.Rate)
+	}
+
+	if len(report.Failures) > 0 {
+		fmt.Fprintf(buf, "\n违规提交:\n")
+		for _, f := range report.Failures {
+			hash := f.Hash
+			if len(hash) > 7 {
+				hash = hash[:7]
+			}
+			fmt.Fprintf(buf, "  %s %s\n      %s\n", hash, f.Subject, f.Author)
+			for _, v := range f.Violations {
+				fmt.Fprintf(buf, "    ✗ [%s] %s\n", v.Rule, v.Message)
+			}
+		}
+	}
+
+	if passed {
+		fmt.Fprintf(buf, "\n✅ 合规率达到要求 (%.1f%%)\n", lintMinCompliance)
+	} else {
+		fmt.Fprintf(buf, "\n❌ 合规率低于要求 (%.1f%%)\n", lintMinCompliance)
+	}
+}
@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// encodeJSON writes v to buf as indented JSON, leaving <, > and & readable
+func encodeJSON(buf *bytes.Buffer, v any) error {
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// writeCommandOutput writes a subcommand's output to stdout, or to the -o
+// file followed by a note on stderr naming what was saved
+func writeCommandOutput(buf *bytes.Buffer, what string) error {
+	if outputFile == "" {
+		_, err := os.Stdout.Write(buf.Bytes())
+		return err
+	}
+	if err := os.WriteFile(outputFile, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %v", outputFile, err)
+	}
+	fmt.Fprintf(os.Stderr, "📝 %s已保存: %s\n", what, outputFile)
+	return nil
+}
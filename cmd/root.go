@@ -1,10 +1,14 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"runtime"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -12,8 +16,12 @@ import (
 
 	"git-log-analyzer/internal/ai"
 	"git-log-analyzer/internal/analyzer"
+	"git-log-analyzer/internal/chart"
 	"git-log-analyzer/internal/developer"
+	"git-log-analyzer/internal/git"
+	"git-log-analyzer/internal/i18n"
 	"git-log-analyzer/internal/progress"
+	"git-log-analyzer/internal/quality"
 	"git-log-analyzer/internal/report"
 )
 
@@ -25,6 +33,18 @@ var generateWeb bool
 var outputDir string
 var openBrowser bool
 var reportLanguage string
+var timeout time.Duration
+var jobs int
+var lowMemory bool
+var gitBackend string
+var aiDryRun bool
+var scoreMessages bool
+var scoreMessagesAI bool
+var scoreMessagesLimit int
+var aiNoCache bool
+var aiDevelopers bool
+var reportFormats []string
+var singleFile bool
 
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
@@ -38,17 +58,27 @@ This tool can:
 - Generate statistical reports
 - Use AI models for advanced analysis
 
-Environment variables for AI analysis:
-- AI_API_ENDPOINT: API endpoint (default: https://api.openai.com/v1/chat/completions)
-- AI_API_KEY: API key (required for AI analysis)
-- AI_MODEL: Model to use (default: gpt-3.5-turbo)
+Environment variables for AI analysis (or the "ai" section of the config file):
+- AI_PROVIDER: openai, ollama or fake (default: openai)
+- AI_API_ENDPOINT: API endpoint (default: https://api.openai.com/v1/chat/completions,
+  http://localhost:11434 for ollama)
+- AI_API_KEY: API key (required for the openai provider)
+- AI_MODEL: Model to use (default: gpt-3.5-turbo, llama3 for ollama)
 - AI_MAX_TOKENS: Maximum tokens (default: 2000)
 - AI_TEMPERATURE: Temperature setting (default: 0.7)
+- AI_TIMEOUT: Per-request timeout (default: 60s)
+- AI_PROMPT_BUDGET: Prompt size limit in tokens (default: derived from the model's context window)
+- AI_MAX_RETRIES: Retries of rate-limited or failed requests (default: 3, negative disables)
+- AI_INPUT_PRICE / AI_OUTPUT_PRICE: Model price in USD per million tokens, for cost estimates
+  (default: built-in prices for OpenAI models)
 
 Environment variables for report customization:
 - REPORT_LANGUAGE: Report language (zh/en, default: zh)`,
 	Run: func(cmd *cobra.Command, args []string) {
-		if err := analyzeGitLog(repoPath); err != nil {
+		ctx, cancel := commandContext(cmd)
+		defer cancel()
+
+		if err := analyzeGitLog(ctx, repoPath); err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
@@ -56,8 +86,33 @@ Environment variables for report customization:
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
+// The command context is canceled on SIGINT/SIGTERM so a long analysis can be
+// stopped cleanly with Ctrl-C.
 func Execute() error {
-	return rootCmd.Execute()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	return rootCmd.ExecuteContext(ctx)
+}
+
+// commandContext derives the context for a command run, applying --timeout
+func commandContext(cmd *cobra.Command) (context.Context, context.CancelFunc) {
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if timeout > 0 {
+		return context.WithTimeout(ctx, timeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+// contextError converts a context error into a user-facing error
+func contextError(ctx context.Context) error {
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("analysis timed out after %v", timeout)
+	}
+	return fmt.Errorf("analysis interrupted")
 }
 
 func init() {
@@ -69,17 +124,40 @@ func init() {
 	rootCmd.PersistentFlags().BoolVar(&useAI, "ai", false, "enable AI-powered analysis")
 	rootCmd.PersistentFlags().StringVarP(&outputFile, "output", "o", "", "output file for the text report")
 	rootCmd.PersistentFlags().BoolVar(&generateWeb, "web", true, "generate web-based HTML report")
+	rootCmd.PersistentFlags().BoolVar(&singleFile, "single-file", false, "write the web report as one self-contained HTML file that works offline (built-in chart and Markdown renderers; Markdown tables show as plain text)")
 	rootCmd.PersistentFlags().StringVar(&outputDir, "output-dir", getEnv("REPORT_OUTPUT_DIR", "./analysis-reports"), "output directory for reports")
 	rootCmd.PersistentFlags().BoolVar(&openBrowser, "open", getEnvBool("AUTO_OPEN_BROWSER", false), "automatically open web report in browser")
 	rootCmd.PersistentFlags().StringVarP(&reportLanguage, "lang", "l", getEnv("REPORT_LANGUAGE", "zh"), "report language (zh/en)")
+	rootCmd.PersistentFlags().IntVarP(&jobs, "jobs", "j", runtime.NumCPU(), "number of parallel workers computing per-commit statistics")
+	rootCmd.PersistentFlags().BoolVar(&lowMemory, "low-memory", false, "stream commits instead of loading the whole history (skips branch structure)")
+	rootCmd.PersistentFlags().StringVar(&gitBackend, "git-backend", getEnv("GIT_BACKEND", git.BackendExec), "git backend: exec (git binary) or go (pure Go, no git required)")
+	rootCmd.PersistentFlags().BoolVar(&aiDryRun, "ai-dry-run", false, "print the AI prompt and its estimated token count without calling the model or writing reports")
+	rootCmd.PersistentFlags().BoolVar(&aiNoCache, "ai-no-cache", false, "ignore cached AI responses and message scores and request them again")
+	rootCmd.PersistentFlags().BoolVar(&aiDevelopers, "ai-developers", false, "add an AI narrative and growth suggestions to each developer profile (see ai.privacy in the config file)")
+	rootCmd.PersistentFlags().BoolVar(&scoreMessages, "score-messages", false, "score commit message quality (clarity, scope, imperative mood, references)")
+	rootCmd.PersistentFlags().BoolVar(&scoreMessagesAI, "score-messages-ai", false, "refine commit message scores with the AI provider (implies --score-messages)")
+	rootCmd.PersistentFlags().IntVar(&scoreMessagesLimit, "score-messages-limit", 200, "maximum number of recent commit messages sent to the AI provider for scoring")
+	rootCmd.PersistentFlags().DurationVar(&timeout, "timeout", 0, "abort the analysis after this duration, e.g. 5m (0 means no timeout)")
+	rootCmd.Flags().StringSliceVar(&reportFormats, "format", []string{"html"}, "report formats written to --output-dir: html, csv, print (print-ready HTML), pdf (comma separated)")
 
 	// Bind flags to viper
 	viper.BindPFlag("repo", rootCmd.PersistentFlags().Lookup("repo"))
-	viper.BindPFlag("ai", rootCmd.PersistentFlags().Lookup("ai"))
+	// --ai is not bound: the "ai" key holds the AI section of the config file
 	viper.BindPFlag("output", rootCmd.PersistentFlags().Lookup("output"))
 	viper.BindPFlag("web", rootCmd.PersistentFlags().Lookup("web"))
+	viper.BindPFlag("single-file", rootCmd.PersistentFlags().Lookup("single-file"))
 	viper.BindPFlag("output-dir", rootCmd.PersistentFlags().Lookup("output-dir"))
 	viper.BindPFlag("open", rootCmd.PersistentFlags().Lookup("open"))
+	viper.BindPFlag("jobs", rootCmd.PersistentFlags().Lookup("jobs"))
+	viper.BindPFlag("low-memory", rootCmd.PersistentFlags().Lookup("low-memory"))
+	viper.BindPFlag("git-backend", rootCmd.PersistentFlags().Lookup("git-backend"))
+	viper.BindPFlag("timeout", rootCmd.PersistentFlags().Lookup("timeout"))
+	viper.BindPFlag("ai-dry-run", rootCmd.PersistentFlags().Lookup("ai-dry-run"))
+	viper.BindPFlag("ai-no-cache", rootCmd.PersistentFlags().Lookup("ai-no-cache"))
+	viper.BindPFlag("ai-developers", rootCmd.PersistentFlags().Lookup("ai-developers"))
+	viper.BindPFlag("score-messages", rootCmd.PersistentFlags().Lookup("score-messages"))
+	viper.BindPFlag("score-messages-ai", rootCmd.PersistentFlags().Lookup("score-messages-ai"))
+	viper.BindPFlag("score-messages-limit", rootCmd.PersistentFlags().Lookup("score-messages-limit"))
 }
 
 // initConfig reads in config file and ENV variables if set.
@@ -96,17 +174,140 @@ func initConfig() {
 	}
 
 	viper.AutomaticEnv()
+	bindAIEnv()
 
 	if err := viper.ReadInConfig(); err == nil {
 		fmt.Fprintln(os.Stderr, "Using config file:", viper.ConfigFileUsed())
 	}
 }
 
-func analyzeGitLog(repoPath string) error {
+// aiConfigKeys maps the "ai" section of the config file to environment variables
+var aiConfigKeys = map[string]string{
+	"ai.provider":      "AI_PROVIDER",
+	"ai.endpoint":      "AI_API_ENDPOINT",
+	"ai.api-key":       "AI_API_KEY",
+	"ai.model":         "AI_MODEL",
+	"ai.max-tokens":    "AI_MAX_TOKENS",
+	"ai.temperature":   "AI_TEMPERATURE",
+	"ai.timeout":       "AI_TIMEOUT",
+	"ai.prompt-budget": "AI_PROMPT_BUDGET",
+	"ai.input-price":   "AI_INPUT_PRICE",
+	"ai.output-price":  "AI_OUTPUT_PRICE",
+	"ai.max-retries":   "AI_MAX_RETRIES",
+}
+
+// bindAIEnv lets environment variables override the config file's ai section
+func bindAIEnv() {
+	for key, env := range aiConfigKeys {
+		viper.BindEnv(key, env)
+	}
+}
+
+// loadAIConfig reads the AI configuration from the config file and environment
+func loadAIConfig() ai.AIConfig {
+	config := ai.AIConfig{
+		Provider:     viper.GetString("ai.provider"),
+		APIEndpoint:  viper.GetString("ai.endpoint"),
+		APIKey:       viper.GetString("ai.api-key"),
+		Model:        viper.GetString("ai.model"),
+		MaxTokens:    viper.GetInt64("ai.max-tokens"),
+		Timeout:      ai.ParseTimeout(viper.GetString("ai.timeout")),
+		PromptBudget: viper.GetInt("ai.prompt-budget"),
+		InputPrice:   viper.GetFloat64("ai.input-price"),
+		OutputPrice:  viper.GetFloat64("ai.output-price"),
+		MaxRetries:   viper.GetInt("ai.max-retries"),
+		Privacy: ai.PrivacyConfig{
+			ExcludeEmails: viper.GetBool("ai.privacy.exclude-emails"),
+			Anonymize:     viper.GetBool("ai.privacy.anonymize"),
+		},
+	}
+	// Unset is the default temperature, while 0 is a valid setting
+	if viper.IsSet("ai.temperature") {
+		temperature := viper.GetFloat64("ai.temperature")
+		config.Temperature = &temperature
+	}
+	return config
+}
+
+// newAIClient creates the AI client from the config file and environment.
+// Replies are cached on disk; --ai-no-cache requests them again.
+func newAIClient() (*ai.AIClient, error) {
+	config := loadAIConfig()
+	if (config.Provider == "" || config.Provider == ai.ProviderOpenAI) && config.APIKey == "" {
+		return nil, fmt.Errorf("AI_API_KEY environment variable (or ai.api-key in the config file) is required")
+	}
+	client, err := ai.NewAIClientWithConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	cache := ai.NewResponseCache(ai.DefaultResponseCacheDir())
+	cache.Refresh = aiNoCache
+	client.SetResponseCache(cache)
+	return client, nil
+}
+
+// formatAIUsage describes the calls, tokens and estimated cost of an AI client
+func formatAIUsage(usage ai.UsageSummary) string {
+	text := fmt.Sprintf("%d 次调用", usage.Calls)
+	if usage.CachedCalls > 0 {
+		text += fmt.Sprintf(" (另有 %d 次命中缓存)", usage.CachedCalls)
+	}
+	text += fmt.Sprintf(", %d tokens (输入 %d / 输出 %d)", usage.TotalTokens(), usage.PromptTokens, usage.CompletionTokens)
+	if usage.CostKnown {
+		text += fmt.Sprintf(", 预估费用 $%.4f", usage.Cost)
+	} else if usage.Calls > 0 {
+		text += ", 费用未知 (可通过 ai.input-price / ai.output-price 配置价格)"
+	}
+	return text
+}
+
+// refineMessageScores rescores commit messages with the AI provider. Scores
+// are cached on disk by commit hash and model, so reruns only send new commits.
+func refineMessageScores(ctx context.Context, client *ai.AIClient, mq *quality.MessageQuality) (int, error) {
+	cache, err := quality.OpenScoreCache(quality.DefaultScoreCachePath())
+	if err != nil {
+		return 0, err
+	}
+	cache.Refresh = aiNoCache
+
+	scored, err := client.ScoreCommitMessages(ctx, mq, cache, scoreMessagesLimit)
+	if saveErr := cache.Save(); err == nil {
+		err = saveErr
+	}
+	return scored, err
+}
+
+// printAIPrompt prints the exact messages of an AI request and its estimated size
+func printAIPrompt(config ai.AIConfig, prompt *ai.Prompt) {
+	printAIMessages(config, prompt.Messages)
+	fmt.Printf("\n=== Estimated tokens: %d (budget %d", prompt.Tokens, prompt.Budget)
+	if prompt.Omitted > 0 {
+		fmt.Printf(", %d facts omitted", prompt.Omitted)
+	}
+	fmt.Println(") ===")
+}
+
+// printAIMessages prints the messages of an AI request
+func printAIMessages(config ai.AIConfig, messages []ai.ChatMessage) {
+	fmt.Printf("\n=== AI prompt (provider %s, model %s) ===\n", config.Provider, config.Model)
+	for _, m := range messages {
+		fmt.Printf("\n--- %s ---\n%s\n", m.Role, m.Content)
+	}
+}
+
+func analyzeGitLog(ctx context.Context, repoPath string) error {
 	// Set language from command line flag
 	if reportLanguage != "" {
 		os.Setenv("REPORT_LANGUAGE", reportLanguage)
 	}
+	for _, format := range reportFormats {
+		switch format {
+		case "html", "csv", "print", "pdf":
+		default:
+			return fmt.Errorf("unknown report format %q (expected html, csv, print or pdf)", format)
+		}
+	}
 	
 	// Initialize progress tracker (using custom implementation)
 	totalSteps := 4 // Git分析、开发者分析、报告生成、输出
@@ -130,9 +331,18 @@ func analyzeGitLog(repoPath string) error {
 	
 	// Create analyzer
 	time.Sleep(400 * time.Millisecond) // 模拟耗时操作
+	backend, err := git.NewBackend(gitBackend, repoPath)
+	if err != nil {
+		tracker.FailStep(fmt.Sprintf("初始化失败: %v", err))
+		return err
+	}
 	tracker.UpdateStepProgress("创建分析器实例")
 	
-	a := analyzer.NewAnalyzer(repoPath)
+	a := analyzer.NewAnalyzerWithBackend(backend, analyzer.Options{
+		Jobs:          jobs,
+		LowMemory:     lowMemory,
+		ScoreMessages: scoreMessages || scoreMessagesAI,
+	})
 	tracker.CompleteStep("环境初始化完成")
 	
 	time.Sleep(300 * time.Millisecond) // 让用户看到完成状态
@@ -141,7 +351,11 @@ func analyzeGitLog(repoPath string) error {
 	tracker.StartStep("Git日志分析")
 	tracker.UpdateStepProgress("获取提交历史...")
 	
-	stats, err := a.Analyze()
+	stats, err := a.Analyze(ctx)
+	if ctx.Err() != nil {
+		tracker.FailStep("分析已中断")
+		return contextError(ctx)
+	}
 	if err != nil {
 		tracker.FailStep(fmt.Sprintf("分析失败: %v", err))
 		return fmt.Errorf("failed to analyze repository: %v", err)
@@ -151,9 +365,48 @@ func analyzeGitLog(repoPath string) error {
 	if stats.CodeHealthMetrics != nil {
 		tracker.UpdateStepProgress(fmt.Sprintf("代码健康评分: %.0f/100", stats.CodeHealthMetrics.HealthScore*100))
 	}
+
+	// The AI client is shared by message scoring and the analysis step so
+	// their usage is accounted together
+	var aiClient *ai.AIClient
+	var aiClientErr error
+	getAIClient := func() (*ai.AIClient, error) {
+		if aiClient == nil && aiClientErr == nil {
+			aiClient, aiClientErr = newAIClient()
+		}
+		return aiClient, aiClientErr
+	}
+
+	// Optionally refine the heuristic message scores with the model; failures
+	// keep the heuristic scores
+	var messageScoreWarning string
+	if stats.MessageQuality != nil && scoreMessagesAI && !aiDryRun {
+		tracker.UpdateStepProgress("AI评估提交信息质量...")
+		scored := 0
+		client, err := getAIClient()
+		if err == nil {
+			scored, err = refineMessageScores(ctx, client, stats.MessageQuality)
+		}
+		if ctx.Err() != nil {
+			tracker.FailStep("分析已中断")
+			return contextError(ctx)
+		}
+		if err != nil {
+			messageScoreWarning = fmt.Sprintf("AI提交信息评分失败，已使用启发式评分: %v", err)
+		} else {
+			tracker.UpdateStepProgress(fmt.Sprintf("AI已评估 %d 条提交信息", scored))
+		}
+	}
+	if stats.MessageQuality != nil {
+		tracker.UpdateStepProgress(fmt.Sprintf("提交信息质量: %.0f/100", stats.MessageQuality.Average))
+	}
 	
 	basicReport := stats.GenerateReport()
-	tracker.CompleteStep("Git日志分析完成")
+	if messageScoreWarning != "" {
+		tracker.CompleteStepWithWarning("Git日志分析完成", messageScoreWarning)
+	} else {
+		tracker.CompleteStep("Git日志分析完成")
+	}
 	
 	time.Sleep(300 * time.Millisecond) // 让用户看到完成状态
 	
@@ -175,13 +428,44 @@ func analyzeGitLog(repoPath string) error {
 		if idx >= contributorCount {
 			break
 		}
+		if ctx.Err() != nil {
+			tracker.FailStep("分析已中断")
+			return contextError(ctx)
+		}
 		tracker.UpdateStepProgress(fmt.Sprintf("分析开发者: %s (%d/%d)", authorName, idx+1, contributorCount))
 		profile := profileAnalyzer.AnalyzeDeveloper(authorStat)
 		developerProfiles = append(developerProfiles, profile)
 		idx++
 	}
 	
-	tracker.CompleteStep(fmt.Sprintf("开发者风格画像分析完成 (%d位开发者)", len(developerProfiles)))
+	// Optionally let the model describe each developer; the first failure
+	// stops the pass and keeps the rule-based profiles
+	var narrativeWarning string
+	if aiDevelopers && !aiDryRun && len(developerProfiles) > 0 {
+		client, err := getAIClient()
+		authors := ai.NewAuthorRedactor(stats)
+		for i, profile := range developerProfiles {
+			if err != nil {
+				break
+			}
+			tracker.UpdateStepProgress(fmt.Sprintf("AI解读开发者: %s (%d/%d)", profile.Name, i+1, len(developerProfiles)))
+			author := stats.AuthorStats[fmt.Sprintf("%s <%s>", profile.Name, profile.Email)]
+			profile.AINarrative, err = client.NarrateDeveloper(ctx, profile, author, i, authors)
+		}
+		if ctx.Err() != nil {
+			tracker.FailStep("分析已中断")
+			return contextError(ctx)
+		}
+		if err != nil {
+			narrativeWarning = fmt.Sprintf("AI开发者解读失败: %v", err)
+		}
+	}
+	
+	if narrativeWarning != "" {
+		tracker.CompleteStepWithWarning(fmt.Sprintf("开发者风格画像分析完成 (%d位开发者)", len(developerProfiles)), narrativeWarning)
+	} else {
+		tracker.CompleteStep(fmt.Sprintf("开发者风格画像分析完成 (%d位开发者)", len(developerProfiles)))
+	}
 	
 	// Generate developer profiles report
 	var developerReport strings.Builder
@@ -193,54 +477,90 @@ func analyzeGitLog(repoPath string) error {
 		}
 	}
 
+	// A dry run shows what would be sent to the model and stops there
+	if aiDryRun {
+		config := ai.WithDefaults(loadAIConfig())
+		printAIPrompt(config, ai.BuildAnalysisPrompt(config, stats, developerProfiles))
+		if aiDevelopers {
+			authors := ai.NewAuthorRedactor(stats)
+			for i, profile := range developerProfiles {
+				author := stats.AuthorStats[fmt.Sprintf("%s <%s>", profile.Name, profile.Email)]
+				printAIMessages(config, ai.BuildDeveloperPrompt(config, profile, author, i, authors).Messages)
+			}
+		}
+		return nil
+	}
+
 	var finalReport string
-	var aiAnalysis string
+	var aiAnalysis *ai.AnalysisResult
 	var aiError error
-	var aiConfigError bool
+	var aiErrorType string
 	
 	// Step 4: AI Analysis (if enabled)
 	if useAI {
 		tracker.StartStep("AI智能分析")
 		tracker.UpdateStepProgress("初始化AI客户端...")
 		
-		aiClient, err := ai.NewAIClient()
+		client, err := getAIClient()
 		if err != nil {
 			aiError = err
-			aiConfigError = true
+			aiErrorType = ai.ErrorTypeConfig
 			tracker.CompleteStepWithWarning("AI分析跳过", fmt.Sprintf("AI客户端初始化失败: %v", err))
 			finalReport = basicReport + developerReport.String()
 		} else {
 			tracker.UpdateStepProgress("发送分析请求到AI服务...")
-			aiResult, err := aiClient.AnalyzeWithAI(stats, basicReport)
+			aiResult, err := client.AnalyzeWithAI(ctx, stats, developerProfiles)
+			if ctx.Err() != nil {
+				tracker.FailStep("AI分析已中断")
+				return contextError(ctx)
+			}
 			if err != nil {
 				aiError = err
-				aiConfigError = false
+				aiErrorType = ai.ErrorType(err)
 				tracker.CompleteStepWithWarning("AI分析跳过", fmt.Sprintf("AI分析失败: %v", err))
 				finalReport = basicReport + developerReport.String()
 			} else {
 				tracker.UpdateStepProgress("AI分析响应处理完成")
 				aiAnalysis = aiResult
-				finalReport = basicReport + developerReport.String() + "\n\n=== AI-Powered Analysis ===\n" + aiAnalysis
-				tracker.CompleteStep("AI智能分析完成")
+				finalReport = basicReport + developerReport.String() + "\n\n=== AI-Powered Analysis ===\n" + aiAnalysis.Text
+				if aiAnalysis.Structured != nil {
+					tracker.CompleteStep("AI智能分析完成")
+				} else {
+					tracker.CompleteStepWithWarning("AI智能分析完成", "模型未返回有效的结构化结果，已使用自由文本模式")
+				}
 			}
 		}
 	} else {
 		finalReport = basicReport + developerReport.String()
 	}
 	
+	// Nothing is written to disk once the run has been canceled
+	if ctx.Err() != nil {
+		return contextError(ctx)
+	}
+
+	// Account for the tokens and cost of every AI call in this run
+	var aiUsage *ai.UsageSummary
+	if aiClient != nil {
+		usage := aiClient.Usage()
+		aiUsage = &usage
+		finalReport += "\n\n=== AI 用量 ===\n" + formatAIUsage(usage) + "\n"
+	}
+
 	// Step 5: Report Generation
 	tracker.StartStep("报告生成与输出")
 	
 	reportGenerated := false
+	projectName := filepath.Base(repoPath)
+	if projectName == "." || projectName == "" {
+		projectName = "Current Repository"
+	}
 	
 	// Generate web report
-	if generateWeb {
+	if generateWeb && hasReportFormat("html") {
 		tracker.UpdateStepProgress("生成Web报告...")
 		webGen := report.NewWebReportGenerator(outputDir)
-		projectName := filepath.Base(repoPath)
-		if projectName == "." || projectName == "" {
-			projectName = "Current Repository"
-		}
+		webGen.SetSingleFile(singleFile)
 		
 		// Prepare AI status
 		var aiStatus report.AIStatus
@@ -252,20 +572,11 @@ func analyzeGitLog(repoPath string) error {
 				ErrorMessage: "",
 			}
 		} else if aiError != nil {
-			if aiConfigError {
-				aiStatus = report.AIStatus{
-					Enabled:      true,
-					Available:    false,
-					ErrorType:    "config_error",
-					ErrorMessage: aiError.Error(),
-				}
-			} else {
-				aiStatus = report.AIStatus{
-					Enabled:      true,
-					Available:    false,
-					ErrorType:    "analysis_error",
-					ErrorMessage: aiError.Error(),
-				}
+			aiStatus = report.AIStatus{
+				Enabled:      true,
+				Available:    false,
+				ErrorType:    aiErrorType,
+				ErrorMessage: aiError.Error(),
 			}
 		} else {
 			aiStatus = report.AIStatus{
@@ -276,11 +587,17 @@ func analyzeGitLog(repoPath string) error {
 			}
 		}
 		
+		aiStatus.Usage = aiUsage
+		
 		subTracker := tracker.CreateSubTracker("Web报告生成", 3)
 		subTracker.UpdateSub("准备报告数据")
 		subTracker.UpdateSub("渲染HTML模板")
 		
-		err := webGen.GenerateReport(stats, aiAnalysis, aiStatus, projectName, developerProfiles)
+		err := webGen.GenerateReport(ctx, stats, aiAnalysis, aiStatus, projectName, developerProfiles)
+		if ctx.Err() != nil {
+			tracker.FailStep("报告生成已中断")
+			return contextError(ctx)
+		}
 		if err != nil {
 			tracker.UpdateStepProgress(fmt.Sprintf("Web报告生成失败: %v", err))
 		} else {
@@ -296,8 +613,57 @@ func analyzeGitLog(repoPath string) error {
 		}
 	}
 	
+	// Generate CSV datasets
+	if hasReportFormat("csv") && ctx.Err() == nil {
+		tracker.UpdateStepProgress("生成CSV数据...")
+		paths, err := report.NewCSVReportGenerator(outputDir).GenerateReport(ctx, stats, developerProfiles)
+		if ctx.Err() != nil {
+			tracker.FailStep("报告生成已中断")
+			return contextError(ctx)
+		}
+		if err != nil {
+			tracker.UpdateStepProgress(fmt.Sprintf("CSV数据生成失败: %v", err))
+		} else {
+			tracker.UpdateStepProgress(fmt.Sprintf("CSV数据已生成: %d 个文件 (%s)", len(paths), outputDir))
+			reportGenerated = true
+		}
+	}
+	
+	// Generate the print-ready HTML report
+	if hasReportFormat("print") && ctx.Err() == nil {
+		tracker.UpdateStepProgress("生成打印版报告...")
+		webGen := report.NewWebReportGenerator(outputDir)
+		err := webGen.GeneratePrintReport(ctx, stats, aiAnalysis, projectName)
+		if ctx.Err() != nil {
+			tracker.FailStep("报告生成已中断")
+			return contextError(ctx)
+		}
+		if err != nil {
+			tracker.UpdateStepProgress(fmt.Sprintf("打印版报告生成失败: %v", err))
+		} else {
+			tracker.UpdateStepProgress(fmt.Sprintf("打印版报告已生成: %s", webGen.GetPrintReportPath()))
+			reportGenerated = true
+		}
+	}
+	
+	// Generate the PDF report
+	if hasReportFormat("pdf") && ctx.Err() == nil {
+		tracker.UpdateStepProgress("生成PDF报告...")
+		path, err := report.NewPDFReportGenerator(outputDir).GenerateReport(ctx, stats, projectName)
+		if ctx.Err() != nil {
+			tracker.FailStep("报告生成已中断")
+			return contextError(ctx)
+		}
+		if err != nil {
+			tracker.UpdateStepProgress(fmt.Sprintf("PDF报告生成失败: %v", err))
+		} else {
+			tracker.UpdateStepProgress(fmt.Sprintf("PDF报告已生成: %s", path))
+			reportGenerated = true
+		}
+	}
+	
 	// Output text results
-	if outputFile != "" {
+	if outputFile != "" && ctx.Err() == nil {
 		tracker.UpdateStepProgress("保存文本报告...")
 		err := os.WriteFile(outputFile, []byte(finalReport), 0644)
 		if err != nil {
@@ -305,6 +671,11 @@ func analyzeGitLog(repoPath string) error {
 		} else {
 			tracker.UpdateStepProgress(fmt.Sprintf("文本报告已保存: %s", outputFile))
 			reportGenerated = true
+			if paths, err := writeTextCharts(stats); err != nil {
+				tracker.UpdateStepProgress(fmt.Sprintf("SVG图表保存失败: %v", err))
+			} else {
+				tracker.UpdateStepProgress(fmt.Sprintf("SVG图表已保存: %d 个", len(paths)))
+			}
 		}
 	}
 	
@@ -320,10 +691,23 @@ func analyzeGitLog(repoPath string) error {
 	// Complete the entire process
 	tracker.Complete()
 	tracker.ShowSummary(stats)
+	if aiUsage != nil {
+		fmt.Printf("   🤖 AI用量: %s\n", formatAIUsage(*aiUsage))
+	}
 	
 	return nil
 }
 
+// hasReportFormat reports whether --format includes format
+func hasReportFormat(format string) bool {
+	for _, f := range reportFormats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
 // openWebReport opens the web report in the default browser
 func openWebReport(reportPath string) {
 	absPath, err := filepath.Abs(reportPath)
@@ -333,8 +717,17 @@ func openWebReport(reportPath string) {
 	}
 	
 	fmt.Printf("Opening web report in browser: file://%s\n", absPath)
-	// Note: This is a simplified version. In a real implementation,
-	// you might want to use a library like "github.com/pkg/browser"
+	if err := openURL("file://" + absPath); err != nil {
+		fmt.Printf("Warning: %v\n", err)
+	}
+}
+
+// writeTextCharts writes the SVG charts of stats next to the text report,
+// named after it: report.txt gets report-timeline.svg and so on
+func writeTextCharts(stats *analyzer.Statistics) ([]string, error) {
+	base := filepath.Base(outputFile)
+	prefix := strings.TrimSuffix(base, filepath.Ext(base)) + "-"
+	return chart.WriteFiles(filepath.Dir(outputFile), prefix, chart.Render(stats, i18n.T()))
 }
 
 // getEnv gets environment variable with default value
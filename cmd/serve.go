@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"git-log-analyzer/internal/analyzer"
+	"git-log-analyzer/internal/git"
+	"git-log-analyzer/internal/server"
+)
+
+var serveAddr string
+var serveSince string
+var serveUntil string
+var serveAuthors []string
+var servePaths []string
+
+// serveCmd hosts the report over HTTP and re-analyzes on filter changes
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve the report over HTTP with live re-analysis",
+	Long: `Serve analyzes the repository and hosts the HTML report on localhost. The
+report gets a filter panel (date range, authors, paths); applying a filter
+re-runs the analysis on the server and reloads the page.
+
+The analysis is also available as JSON:
+
+  GET  /api/stats      the whole analysis
+  GET  /api/authors    authors by commits
+  GET  /api/files      files by changes (?limit=N)
+  GET  /api/timeline   commits per day
+  GET  /api/health     code health metrics
+  GET  /api/activity   punch card and calendar heatmap (?author=name or email)
+  GET  /api/filter     the current filter
+  POST /api/filter     re-analyze, e.g. {"since": "2024-01-01", "authors": ["alice"]}
+
+Dates are YYYY-MM-DD and the until date is inclusive. --timeout applies to
+each analysis. Press Ctrl-C to stop the server.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		if err := runServe(ctx); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	serveCmd.Flags().StringVar(&serveAddr, "addr", "127.0.0.1:8080", "address to listen on")
+	serveCmd.Flags().StringVar(&serveSince, "since", "", "initial filter: commits on or after this date (YYYY-MM-DD)")
+	serveCmd.Flags().StringVar(&serveUntil, "until", "", "initial filter: commits on or before this date (YYYY-MM-DD)")
+	serveCmd.Flags().StringSliceVar(&serveAuthors, "author", nil, "initial filter: authors whose name or email contains the value")
+	serveCmd.Flags().StringSliceVar(&servePaths, "path", nil, "initial filter: only files under the path")
+	rootCmd.AddCommand(serveCmd)
+}
+
+func runServe(ctx context.Context) error {
+	if reportLanguage != "" {
+		os.Setenv("REPORT_LANGUAGE", reportLanguage)
+	}
+	filter, err := server.FilterRequest{Since: serveSince, Until: serveUntil, Authors: serveAuthors, Paths: servePaths}.Filter()
+	if err != nil {
+		return err
+	}
+
+	backend, err := git.NewBackend(gitBackend, repoPath)
+	if err != nil {
+		return err
+	}
+	analyze := func(ctx context.Context, filter analyzer.Filter) (*analyzer.Statistics, error) {
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		a := analyzer.NewAnalyzerWithBackend(backend, analyzer.Options{
+			Jobs:      jobs,
+			LowMemory: lowMemory,
+			Filter:    filter,
+		})
+		stats, err := a.Analyze(ctx)
+		if ctx.Err() != nil {
+			return nil, contextError(ctx)
+		}
+		return stats, err
+	}
+
+	dir, err := os.MkdirTemp("", "git-log-analyzer-serve-")
+	if err != nil {
+		return fmt.Errorf("failed to create report directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	projectName := filepath.Base(repoPath)
+	if abs, err := filepath.Abs(repoPath); err == nil {
+		projectName = filepath.Base(abs)
+	}
+	srv := server.New(analyze, dir, projectName)
+	srv.Log = os.Stderr
+	srv.Addr = serveAddr
+
+	fmt.Fprintf(os.Stderr, "🔍 正在分析Git仓库: %s\n", repoPath)
+	if err := srv.Refresh(ctx, filter); err != nil {
+		return fmt.Errorf("failed to analyze repository: %v", err)
+	}
+
+	listener, err := net.Listen("tcp", serveAddr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %v", serveAddr, err)
+	}
+	url := "http://" + listener.Addr().String() + "/"
+	httpServer := &http.Server{Handler: srv.Handler(), ReadHeaderTimeout: 10 * time.Second}
+
+	errc := make(chan error, 1)
+	go func() { errc <- httpServer.Serve(listener) }()
+	fmt.Fprintf(os.Stderr, "🌐 报告服务已启动: %s (按 Ctrl-C 停止)\n", url)
+	if openBrowser {
+		if err := openURL(url); err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️ %v\n", err)
+		}
+	}
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := httpServer.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	fmt.Fprintln(os.Stderr, "👋 报告服务已停止")
+	return nil
+}
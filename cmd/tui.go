@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"git-log-analyzer/internal/analyzer"
+	"git-log-analyzer/internal/developer"
+	"git-log-analyzer/internal/git"
+	"git-log-analyzer/internal/i18n"
+	"git-log-analyzer/internal/tui"
+)
+
+var tuiAnalysis string
+
+// tuiCmd shows the analysis in an interactive terminal dashboard
+var tuiCmd = &cobra.Command{
+	Use:   "tui",
+	Short: "Browse the analysis in an interactive terminal dashboard",
+	Long: `Tui analyzes the repository (or loads a saved analysis) and shows it in
+the terminal, for working over SSH where the HTML report cannot be opened.
+
+Tabs: overview (summary, commit timeline and activity sparklines), contributors,
+files, code health findings, branches and developer profiles.
+
+  ←/→, Tab, 1-6   switch tabs
+  ↑/↓, j/k        select a row (PgUp/PgDn, Home/End)
+  s / r           sort by the next column / reverse the order
+  Enter           open the files of an author, the authors of a file, or a
+                  developer profile
+  Esc             back from a drill-down
+  q, Ctrl-C       quit
+
+--timeout applies to the analysis only, not to the dashboard.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		if err := runTUI(ctx); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	tuiCmd.Flags().StringVar(&tuiAnalysis, "analysis", "", "load an analysis saved with --save-analysis instead of analyzing the repository")
+	rootCmd.AddCommand(tuiCmd)
+}
+
+func runTUI(ctx context.Context) error {
+	if reportLanguage != "" {
+		os.Setenv("REPORT_LANGUAGE", reportLanguage)
+	}
+
+	stats, profiles, err := analyzeForTUI(ctx)
+	if err != nil {
+		return err
+	}
+
+	projectName := filepath.Base(repoPath)
+	if abs, err := filepath.Abs(repoPath); err == nil {
+		projectName = filepath.Base(abs)
+	}
+	// The dashboard stays open until the user quits or the process is
+	// interrupted, however long that takes
+	return tui.Run(ctx, tui.NewDashboard(stats, profiles, i18n.T(), projectName), os.Stdin, os.Stdout)
+}
+
+// analyzeForTUI loads or computes the analysis and the developer profiles
+// within --timeout
+func analyzeForTUI(ctx context.Context) (*analyzer.Statistics, []*developer.DeveloperProfile, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	var stats *analyzer.Statistics
+	var err error
+	if tuiAnalysis != "" {
+		stats, err = analyzer.LoadStatistics(tuiAnalysis)
+		if err != nil {
+			return nil, nil, err
+		}
+	} else {
+		backend, err := git.NewBackend(gitBackend, repoPath)
+		if err != nil {
+			return nil, nil, err
+		}
+		fmt.Fprintf(os.Stderr, "🔍 正在分析Git仓库: %s\n", repoPath)
+		stats, err = analyzer.NewAnalyzerWithBackend(backend, analyzer.Options{
+			Jobs:      jobs,
+			LowMemory: lowMemory,
+		}).Analyze(ctx)
+		if ctx.Err() != nil {
+			return nil, nil, contextError(ctx)
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to analyze repository: %v", err)
+		}
+	}
+
+	profiles, err := developer.NewProfileAnalyzer(stats).AnalyzeAllDevelopers(ctx)
+	if ctx.Err() != nil {
+		return nil, nil, contextError(ctx)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return stats, profiles, nil
+}
@@ -0,0 +1,223 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"git-log-analyzer/internal/analyzer"
+	"git-log-analyzer/internal/developer"
+	"git-log-analyzer/internal/git"
+	"git-log-analyzer/internal/report"
+	"git-log-analyzer/internal/watch"
+)
+
+var watchInterval time.Duration
+var watchExec string
+
+// watchCmd keeps the reports up to date while commits are made
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Regenerate the reports whenever new commits arrive",
+	Long: `Watch analyzes the repository, writes the reports and then polls HEAD and
+the refs in the git directory. When HEAD gains commits only those commits
+are analyzed and merged into the statistics; when history is rewritten
+(rebase, reset, checkout of another branch) the analysis starts over.
+
+After each refresh the web report (--web, --output-dir) and the text report
+(-o) are written again and the --exec command, if any, is run through the
+shell with these environment variables:
+
+  GLA_HEAD           the analyzed commit
+  GLA_NEW_COMMITS    commits added by this refresh
+  GLA_TOTAL_COMMITS  commits in the analysis
+  GLA_REBUILT        "true" when the history was analyzed again
+  GLA_REPORT_DIR     the web report directory
+
+Like --low-memory, watch mode skips the branch structure. --timeout applies
+to each refresh. Press Ctrl-C to stop watching.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		if err := runWatch(ctx); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	watchCmd.Flags().DurationVar(&watchInterval, "interval", watch.DefaultInterval, "how often to check the refs for changes")
+	watchCmd.Flags().StringVar(&watchExec, "exec", "", "shell command to run after each refresh")
+	rootCmd.AddCommand(watchCmd)
+}
+
+func runWatch(ctx context.Context) error {
+	if reportLanguage != "" {
+		os.Setenv("REPORT_LANGUAGE", reportLanguage)
+	}
+
+	watcher, err := watch.New(repoPath, watchInterval)
+	if err != nil {
+		return err
+	}
+	backend, err := git.NewBackend(gitBackend, repoPath)
+	if err != nil {
+		return err
+	}
+	inc := analyzer.NewAnalyzerWithBackend(backend, analyzer.Options{
+		Jobs:          jobs,
+		ScoreMessages: scoreMessages || scoreMessagesAI,
+	}).Incremental()
+
+	projectName := filepath.Base(repoPath)
+	if abs, err := filepath.Abs(repoPath); err == nil {
+		projectName = filepath.Base(abs)
+	}
+
+	fmt.Fprintf(os.Stderr, "🔍 正在分析Git仓库: %s\n", repoPath)
+	if err := refreshWatch(ctx, inc, projectName); err != nil {
+		return err
+	}
+	if openBrowser && generateWeb {
+		openWebReport(filepath.Join(outputDir, "index.html"))
+	}
+	fmt.Fprintf(os.Stderr, "👀 正在监视新提交 (每 %v 检查一次, 按 Ctrl-C 停止)\n", watchInterval)
+
+	for {
+		if err := watcher.Wait(ctx); err != nil {
+			break
+		}
+		// Errors are reported and the next change retries
+		if err := refreshWatch(ctx, inc, projectName); err != nil && ctx.Err() == nil {
+			fmt.Fprintf(os.Stderr, "❌ 更新失败: %v\n", err)
+		}
+	}
+	fmt.Fprintln(os.Stderr, "👋 已停止监视")
+	return nil
+}
+
+// refreshWatch merges new commits into the analysis, then rewrites the
+// reports and runs the --exec command if anything changed
+func refreshWatch(ctx context.Context, inc *analyzer.Incremental, projectName string) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	added, rebuilt, err := inc.Update(ctx)
+	if ctx.Err() != nil {
+		return contextError(ctx)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to analyze repository: %v", err)
+	}
+	if added == 0 && !rebuilt {
+		// A ref of another branch moved
+		return nil
+	}
+
+	stats := inc.Statistics()
+	switch {
+	case rebuilt:
+		fmt.Fprintf(os.Stderr, "🔄 历史已改写, 重新分析了 %d 个提交\n", stats.TotalCommits)
+	case added == stats.TotalCommits:
+		fmt.Fprintf(os.Stderr, "✅ 已分析 %d 个提交 (%s), 用时 %v\n",
+			added, shortHash(inc.Head()), time.Since(start).Round(time.Millisecond))
+	default:
+		fmt.Fprintf(os.Stderr, "✅ 新增 %d 个提交, 共 %d 个 (%s), 用时 %v\n",
+			added, stats.TotalCommits, shortHash(inc.Head()), time.Since(start).Round(time.Millisecond))
+	}
+
+	if err := writeWatchReports(ctx, stats, projectName); err != nil {
+		return err
+	}
+	if watchExec != "" {
+		runWatchHook(ctx, inc, added, rebuilt)
+	}
+	return nil
+}
+
+// writeWatchReports writes the web and text reports of stats
+func writeWatchReports(ctx context.Context, stats *analyzer.Statistics, projectName string) error {
+	profiles, err := developer.NewProfileAnalyzer(stats).AnalyzeAllDevelopers(ctx)
+	if err != nil {
+		return err
+	}
+	if len(profiles) > 10 {
+		profiles = profiles[:10]
+	}
+
+	if generateWeb {
+		webGen := report.NewWebReportGenerator(outputDir)
+		webGen.SetSingleFile(singleFile)
+		if err := webGen.GenerateReport(ctx, stats, nil, report.AIStatus{ErrorType: "disabled"}, projectName, profiles); err != nil {
+			return fmt.Errorf("failed to generate web report: %v", err)
+		}
+		fmt.Fprintf(os.Stderr, "📝 Web报告已更新: %s\n", webGen.GetReportPath())
+	}
+
+	if outputFile != "" {
+		var text strings.Builder
+		text.WriteString(stats.GenerateReport())
+		if len(profiles) > 0 {
+			text.WriteString("\n\n=== 🎭 开发者风格画像分析 ===\n")
+			for _, profile := range profiles {
+				text.WriteString(profile.GenerateReport())
+				text.WriteString("\n")
+			}
+		}
+		if err := os.WriteFile(outputFile, []byte(text.String()), 0644); err != nil {
+			return fmt.Errorf("failed to write text report: %v", err)
+		}
+		fmt.Fprintf(os.Stderr, "📝 文本报告已更新: %s\n", outputFile)
+		if _, err := writeTextCharts(stats); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runWatchHook runs the --exec command; a failing command is reported but
+// doesn't stop watching
+func runWatchHook(ctx context.Context, inc *analyzer.Incremental, added int, rebuilt bool) {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", watchExec)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", watchExec)
+	}
+	reportDir, _ := filepath.Abs(outputDir)
+	cmd.Env = append(os.Environ(),
+		"GLA_HEAD="+inc.Head(),
+		"GLA_NEW_COMMITS="+strconv.Itoa(added),
+		"GLA_TOTAL_COMMITS="+strconv.Itoa(inc.Statistics().TotalCommits),
+		"GLA_REBUILT="+strconv.FormatBool(rebuilt),
+		"GLA_REPORT_DIR="+reportDir,
+	)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil && ctx.Err() == nil {
+		fmt.Fprintf(os.Stderr, "⚠️ --exec 命令失败: %v\n", err)
+	}
+}
+
+// shortHash abbreviates a commit hash for progress lines
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
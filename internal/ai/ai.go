@@ -3,169 +3,289 @@ package ai
 import (
 	"context"
 	"fmt"
-	"net/http"
 	"os"
+	"strings"
+	"sync"
 	"time"
 
 	"git-log-analyzer/internal/analyzer"
+	"git-log-analyzer/internal/developer"
 	"git-log-analyzer/internal/i18n"
-
-	"github.com/openai/openai-go"
-	"github.com/openai/openai-go/option"
-	"github.com/openai/openai-go/packages/param"
 )
 
 // AIConfig contains configuration for AI analysis
 type AIConfig struct {
+	Provider    string // openai (default), ollama or fake
 	APIEndpoint string
 	APIKey      string
 	Model       string
 	MaxTokens   int64
-	Temperature float64
+	Temperature *float64      // nil uses DefaultTemperature; 0 is a valid setting
+	Timeout     time.Duration // per-request HTTP timeout
+	// PromptBudget caps the prompt size in tokens; 0 derives it from the
+	// model's context window
+	PromptBudget int
+	// InputPrice and OutputPrice override the price of the model in USD per
+	// million tokens; 0 uses the built-in price table
+	InputPrice  float64
+	OutputPrice float64
+	// MaxRetries is how often retryable failures are retried; 0 uses
+	// DefaultMaxRetries and a negative value disables retries
+	MaxRetries int
+	// Privacy controls the developer data sent by NarrateDeveloper
+	Privacy PrivacyConfig
 }
 
+// Defaults applied by NewAIClientWithConfig
+const (
+	DefaultOpenAIEndpoint = "https://api.openai.com/v1/chat/completions"
+	DefaultOpenAIModel    = "gpt-3.5-turbo"
+	DefaultOllamaEndpoint = "http://localhost:11434"
+	DefaultOllamaModel    = "llama3"
+	DefaultFakeModel      = "fake-model"
+	DefaultMaxTokens      = 2000
+	DefaultTemperature    = 0.7
+	DefaultTimeout        = 60 * time.Second
+)
+
 // AIClient handles communication with AI models
 type AIClient struct {
-	config AIConfig
-	client *http.Client
-}
-
-// ChatMessage represents a chat message
-type ChatMessage struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
-}
+	config   AIConfig
+	provider Provider
+	cache    *ResponseCache // nil disables caching
+	retry    RetryPolicy
+	sleep    func(ctx context.Context, d time.Duration) error
 
-// ChatRequest represents the request payload for chat API
-type ChatRequest struct {
-	Model       string        `json:"model"`
-	Messages    []ChatMessage `json:"messages"`
-	MaxTokens   int64         `json:"max_tokens,omitempty"`
-	Temperature float64       `json:"temperature,omitempty"`
+	mu    sync.Mutex
+	usage UsageSummary
 }
 
-// ChatResponse represents the response from chat API
-type ChatResponse struct {
-	Choices []struct {
-		Message ChatMessage `json:"message"`
-	} `json:"choices"`
-	Error struct {
-		Message string `json:"message"`
-		Type    string `json:"type"`
-	} `json:"error,omitempty"`
+// ConfigFromEnv reads the AI configuration from environment variables.
+// Unset values are left empty so NewAIClientWithConfig can apply defaults.
+func ConfigFromEnv() AIConfig {
+	return AIConfig{
+		Provider:     getEnv("AI_PROVIDER", ""),
+		APIEndpoint:  getEnv("AI_API_ENDPOINT", ""),
+		APIKey:       getEnv("AI_API_KEY", ""),
+		Model:        getEnv("AI_MODEL", ""),
+		MaxTokens:    int64(getEnvInt("AI_MAX_TOKENS", 0)),
+		Temperature:  getEnvFloatPtr("AI_TEMPERATURE"),
+		Timeout:      getEnvDuration("AI_TIMEOUT"),
+		PromptBudget: getEnvInt("AI_PROMPT_BUDGET", 0),
+		InputPrice:   getEnvFloat("AI_INPUT_PRICE", 0),
+		OutputPrice:  getEnvFloat("AI_OUTPUT_PRICE", 0),
+		MaxRetries:   getEnvInt("AI_MAX_RETRIES", 0),
+	}
 }
 
 // NewAIClient creates a new AI client from environment variables
 func NewAIClient() (*AIClient, error) {
-	config := AIConfig{
-		APIEndpoint: getEnv("AI_API_ENDPOINT", "https://api.openai.com/v1/chat/completions"),
-		APIKey:      getEnv("AI_API_KEY", ""),
-		Model:       getEnv("AI_MODEL", "gpt-3.5-turbo"),
-		MaxTokens:   int64(getEnvInt("AI_MAX_TOKENS", 2000)),
-		Temperature: getEnvFloat("AI_TEMPERATURE", 0.7),
-	}
-
-	if config.APIKey == "" {
+	config := ConfigFromEnv()
+	if providerOrDefault(config.Provider) == ProviderOpenAI && config.APIKey == "" {
 		return nil, fmt.Errorf("AI_API_KEY environment variable is required")
 	}
-
-	return &AIClient{
-		config: config,
-		client: &http.Client{
-			Timeout: 30 * time.Second,
-		},
-	}, nil
+	return NewAIClientWithConfig(config)
 }
 
 // NewAIClientWithConfig creates a new AI client with custom configuration
 func NewAIClientWithConfig(config AIConfig) (*AIClient, error) {
-	if config.APIKey == "" {
+	config = WithDefaults(config)
+	if config.Provider == ProviderOpenAI && config.APIKey == "" {
 		return nil, fmt.Errorf("API key is required")
 	}
 
-	// Set defaults if not provided
-	if config.APIEndpoint == "" {
-		config.APIEndpoint = "https://api.openai.com/v1/chat/completions"
+	provider, err := NewProvider(config)
+	if err != nil {
+		return nil, err
 	}
-	if config.Model == "" {
-		config.Model = "gpt-3.5-turbo"
+
+	return newClient(config, provider), nil
+}
+
+// NewAIClientWithProvider creates a new AI client that sends its requests to
+// provider. config supplies the model and sampling parameters.
+func NewAIClientWithProvider(config AIConfig, provider Provider) *AIClient {
+	if config.Provider == "" {
+		config.Provider = provider.Name()
 	}
-	if config.MaxTokens == 0 {
-		config.MaxTokens = 2000
+	return newClient(WithDefaults(config), provider)
+}
+
+// newClient creates a client for a config with defaults applied
+func newClient(config AIConfig, provider Provider) *AIClient {
+	maxRetries := config.MaxRetries
+	if maxRetries < 0 {
+		maxRetries = 0
 	}
-	if config.Temperature == 0 {
-		config.Temperature = 0.7
+	return &AIClient{
+		config:   config,
+		provider: provider,
+		retry:    RetryPolicy{MaxRetries: maxRetries, BaseDelay: DefaultRetryDelay, MaxDelay: DefaultMaxDelay},
+		sleep:    sleepContext,
 	}
+}
 
-	return &AIClient{
-		config: config,
-		client: &http.Client{
-			Timeout: 30 * time.Second,
-		},
-	}, nil
+// SetResponseCache makes the client reuse stored replies for identical
+// requests and store new ones; nil disables caching
+func (c *AIClient) SetResponseCache(cache *ResponseCache) {
+	c.cache = cache
+}
+
+// Config returns the effective configuration, with defaults applied
+func (c *AIClient) Config() AIConfig {
+	return c.config
+}
+
+// providerOrDefault returns the provider kind, defaulting to OpenAI
+func providerOrDefault(provider string) string {
+	if provider == "" {
+		return ProviderOpenAI
+	}
+	return provider
+}
+
+// WithDefaults returns config with unset values filled with the provider's defaults
+func WithDefaults(config AIConfig) AIConfig {
+	config.Provider = providerOrDefault(config.Provider)
+
+	switch config.Provider {
+	case ProviderOllama:
+		if config.APIEndpoint == "" {
+			config.APIEndpoint = DefaultOllamaEndpoint
+		}
+		if config.Model == "" {
+			config.Model = DefaultOllamaModel
+		}
+	case ProviderFake:
+		if config.Model == "" {
+			config.Model = DefaultFakeModel
+		}
+	default:
+		if config.APIEndpoint == "" {
+			config.APIEndpoint = DefaultOpenAIEndpoint
+		}
+		if config.Model == "" {
+			config.Model = DefaultOpenAIModel
+		}
+	}
+
+	if config.MaxTokens == 0 {
+		config.MaxTokens = DefaultMaxTokens
+	}
+	if config.Temperature == nil {
+		temperature := float64(DefaultTemperature)
+		config.Temperature = &temperature
+	}
+	if config.Timeout == 0 {
+		config.Timeout = DefaultTimeout
+	}
+	if config.MaxRetries == 0 {
+		config.MaxRetries = DefaultMaxRetries
+	}
+	return config
 }
 
 // ValidateConfig validates AI configuration
 func ValidateConfig(config AIConfig) error {
-	if config.APIKey == "" {
-		return fmt.Errorf("API key is required")
+	switch providerOrDefault(config.Provider) {
+	case ProviderOpenAI:
+		if config.APIKey == "" {
+			return fmt.Errorf("API key is required")
+		}
+	case ProviderOllama, ProviderFake:
+	default:
+		return fmt.Errorf("unknown AI provider %q", config.Provider)
 	}
 	if config.MaxTokens < 100 || config.MaxTokens > 8000 {
 		return fmt.Errorf("max tokens should be between 100 and 8000")
 	}
-	if config.Temperature < 0.0 || config.Temperature > 2.0 {
+	if t := config.Temperature; t != nil && (*t < 0.0 || *t > 2.0) {
 		return fmt.Errorf("temperature should be between 0.0 and 2.0")
 	}
 	return nil
 }
 
-// AnalyzeWithAI performs AI-powered analysis of git statistics
-func (c *AIClient) AnalyzeWithAI(stats *analyzer.Statistics, basicReport string) (string, error) {
-	prompt := c.buildAnalysisPrompt(stats, basicReport)
-	// log.Println("AI analysis request with prompt:", prompt)
-	return c.sendChatRequest(prompt)
-}
+// maxRepairAttempts is how often the model is asked to fix a reply that
+// violates the JSON schema before falling back to free text
+const maxRepairAttempts = 1
 
-// buildAnalysisPrompt creates a prompt for AI analysis
-func (c *AIClient) buildAnalysisPrompt(stats *analyzer.Statistics, basicReport string) string {
+// AnalyzeWithAI performs AI-powered analysis of git statistics. The model is
+// asked for a StructuredAnalysis of the facts selected by BuildAnalysisPrompt;
+// replies that violate the schema are sent back for repair, and when the
+// model still can't comply the analysis is requested again as free text.
+func (c *AIClient) AnalyzeWithAI(ctx context.Context, stats *analyzer.Statistics, profiles []*developer.DeveloperProfile) (*AnalysisResult, error) {
 	msg := i18n.T()
-	
-	prompt := fmt.Sprintf(msg.AIPromptTemplate,
-		basicReport,
-		stats.TotalCommits,
-		len(stats.AuthorStats),
-		stats.TimeStats.FirstCommit.Format("2006-01-02"),
-		stats.TimeStats.LastCommit.Format("2006-01-02"),
-		stats.TimeStats.ActiveDays)
+	prompt := BuildAnalysisPrompt(c.config, stats, profiles)
+
+	messages := prompt.Messages
+	for attempt := 0; ; attempt++ {
+		reply, err := c.chat(ctx, messages)
+		if err != nil {
+			return nil, err
+		}
+
+		analysis, violations := parseStructuredAnalysis(reply)
+		if analysis != nil {
+			return &AnalysisResult{Structured: analysis, Text: analysis.Markdown()}, nil
+		}
+		if attempt == maxRepairAttempts {
+			break
+		}
+
+		messages = append(messages,
+			ChatMessage{Role: "assistant", Content: reply},
+			ChatMessage{Role: "user", Content: fmt.Sprintf(msg.AIRepairPrompt, "- "+strings.Join(violations, "\n- "))},
+		)
+	}
 
-	return prompt
+	text, err := c.chat(ctx, prompt.freeTextMessages())
+	if err != nil {
+		return nil, err
+	}
+	return &AnalysisResult{Text: text}, nil
 }
 
-// sendChatRequest sends a request to the AI chat API
-func (c *AIClient) sendChatRequest(prompt string) (string, error) {
-	msg := i18n.T()
-	
-	client := openai.NewClient(
-		option.WithAPIKey(os.Getenv("AI_API_KEY")),
-		option.WithBaseURL(os.Getenv("AI_API_ENDPOINT")),
-	)
-	chatCompletion, err := client.Chat.Completions.New(
-		context.TODO(), 
-		openai.ChatCompletionNewParams{
-			Messages: []openai.ChatCompletionMessageParamUnion{
-				openai.SystemMessage(msg.AISystemMessage),
-				openai.UserMessage(prompt),
-			},
-			Model: "qwen-plus",
-			MaxTokens: param.Opt[int64]{Value: c.config.MaxTokens},
-			Temperature: param.Opt[float64]{Value: c.config.Temperature},
-		},
-	)
+// chat sends a conversation to the configured provider and model, answering
+// from the response cache when the same request was made before
+func (c *AIClient) chat(ctx context.Context, messages []ChatMessage) (string, error) {
+	resp, err := c.complete(ctx, messages, nil)
+	if err != nil {
+		return "", err
+	}
+	return resp.Content, nil
+}
 
+// complete sends the conversation with the configured model parameters,
+// answering from the response cache when possible
+func (c *AIClient) complete(ctx context.Context, messages []ChatMessage, tools []Tool) (*ChatResponse, error) {
+	req := ChatRequest{
+		Model:       c.config.Model,
+		Messages:    messages,
+		MaxTokens:   c.config.MaxTokens,
+		Temperature: c.config.Temperature,
+		Tools:       tools,
+	}
+
+	var key string
+	if c.cache != nil {
+		key = cacheKey(c.config, req)
+		if resp, ok := c.cache.Get(key); ok {
+			c.record(resp.Usage, true)
+			return resp, nil
+		}
+	}
+
+	resp, err := c.send(ctx, req)
 	if err != nil {
-		return "", fmt.Errorf("failed to get AI response: %v", err)
+		return nil, err
 	}
-	return chatCompletion.Choices[0].Message.Content, nil
+	c.record(resp.Usage, false)
+
+	if c.cache != nil {
+		// A reply that can't be stored is still a valid reply
+		c.cache.Put(key, resp)
+	}
+	return resp, nil
 }
 
 // getEnv gets environment variable with default value
@@ -187,6 +307,39 @@ func getEnvInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+// getEnvFloatPtr gets environment variable as float, nil when unset or
+// invalid
+func getEnvFloatPtr(key string) *float64 {
+	if value := os.Getenv(key); value != "" {
+		var floatValue float64
+		if _, err := fmt.Sscanf(value, "%f", &floatValue); err == nil {
+			return &floatValue
+		}
+	}
+	return nil
+}
+
+// getEnvDuration gets environment variable as a timeout, see ParseTimeout
+func getEnvDuration(key string) time.Duration {
+	return ParseTimeout(os.Getenv(key))
+}
+
+// ParseTimeout parses a timeout such as "90s"; a plain number is taken as
+// seconds. Empty or invalid values are 0, which means the default timeout.
+func ParseTimeout(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return d
+	}
+	var seconds int
+	if _, err := fmt.Sscanf(value, "%d", &seconds); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	return 0
+}
+
 // getEnvFloat gets environment variable as float with default value
 func getEnvFloat(key string, defaultValue float64) float64 {
 	if value := os.Getenv(key); value != "" {
@@ -0,0 +1,169 @@
+package ai
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"git-log-analyzer/internal/analyzer"
+)
+
+// float returns a pointer to v, for AIConfig.Temperature
+func float(v float64) *float64 {
+	return &v
+}
+
+func TestNewAIClientWithConfig_Defaults(t *testing.T) {
+	tests := []struct {
+		config   AIConfig
+		endpoint string
+		model    string
+	}{
+		{AIConfig{APIKey: "sk-test"}, DefaultOpenAIEndpoint, DefaultOpenAIModel},
+		{AIConfig{Provider: ProviderOllama}, DefaultOllamaEndpoint, DefaultOllamaModel},
+		{AIConfig{Provider: ProviderFake}, "", DefaultFakeModel},
+		{AIConfig{Provider: ProviderOllama, APIEndpoint: "http://gpu:11434", Model: "qwen2.5"}, "http://gpu:11434", "qwen2.5"},
+	}
+
+	for _, tt := range tests {
+		client, err := NewAIClientWithConfig(tt.config)
+		if err != nil {
+			t.Fatalf("NewAIClientWithConfig(%+v) failed: %v", tt.config, err)
+		}
+		config := client.Config()
+		if config.APIEndpoint != tt.endpoint || config.Model != tt.model {
+			t.Errorf("Expected %s / %s, got %s / %s", tt.endpoint, tt.model, config.APIEndpoint, config.Model)
+		}
+		if config.MaxTokens != DefaultMaxTokens || *config.Temperature != DefaultTemperature || config.Timeout != DefaultTimeout {
+			t.Errorf("Expected default sampling parameters, got %+v", config)
+		}
+		if client.provider.Name() != config.Provider {
+			t.Errorf("Expected provider %s, got %s", config.Provider, client.provider.Name())
+		}
+	}
+}
+
+func TestNewAIClientWithConfig_RequiresKeyForOpenAI(t *testing.T) {
+	if _, err := NewAIClientWithConfig(AIConfig{}); err == nil {
+		t.Error("Expected an error without an API key")
+	}
+	if _, err := NewAIClientWithConfig(AIConfig{Provider: "unknown", APIKey: "sk-test"}); err == nil {
+		t.Error("Expected an error for an unknown provider")
+	}
+}
+
+func TestNewAIClient_FromEnv(t *testing.T) {
+	t.Setenv("AI_PROVIDER", ProviderFake)
+	t.Setenv("AI_API_KEY", "")
+	t.Setenv("AI_MODEL", "env-model")
+	t.Setenv("AI_MAX_TOKENS", "1234")
+	t.Setenv("AI_TEMPERATURE", "0.1")
+
+	client, err := NewAIClient()
+	if err != nil {
+		t.Fatalf("NewAIClient failed: %v", err)
+	}
+	config := client.Config()
+	if config.Provider != ProviderFake || config.Model != "env-model" || config.MaxTokens != 1234 || *config.Temperature != 0.1 {
+		t.Errorf("Environment was not honored: %+v", config)
+	}
+
+	// A temperature of 0 is kept, and the timeout is read
+	t.Setenv("AI_TEMPERATURE", "0")
+	t.Setenv("AI_TIMEOUT", "90s")
+	client, err = NewAIClient()
+	if err != nil {
+		t.Fatalf("NewAIClient failed: %v", err)
+	}
+	if config := client.Config(); *config.Temperature != 0 || config.Timeout != 90*time.Second {
+		t.Errorf("Expected temperature 0 and a 90s timeout, got %v and %v", *config.Temperature, config.Timeout)
+	}
+	t.Setenv("AI_TIMEOUT", "60")
+	if config := ConfigFromEnv(); config.Timeout != 60*time.Second {
+		t.Errorf("Expected a plain number to be seconds, got %v", config.Timeout)
+	}
+
+	t.Setenv("AI_PROVIDER", "")
+	if _, err := NewAIClient(); err == nil || !strings.Contains(err.Error(), "AI_API_KEY") {
+		t.Errorf("Expected a missing AI_API_KEY error, got %v", err)
+	}
+}
+
+func TestParseTimeout(t *testing.T) {
+	tests := map[string]time.Duration{
+		"":      0,
+		"60":    60 * time.Second,
+		"90s":   90 * time.Second,
+		"2m":    2 * time.Minute,
+		"later": 0,
+	}
+	for value, want := range tests {
+		if got := ParseTimeout(value); got != want {
+			t.Errorf("ParseTimeout(%q) = %v, want %v", value, got, want)
+		}
+	}
+}
+
+func TestValidateConfig(t *testing.T) {
+	valid := AIConfig{Provider: ProviderOllama, MaxTokens: 2000, Temperature: float(0.7)}
+	if err := ValidateConfig(valid); err != nil {
+		t.Errorf("Expected a valid config, got %v", err)
+	}
+
+	invalid := []AIConfig{
+		{MaxTokens: 2000, Temperature: float(0.7)}, // openai without a key
+		{Provider: "unknown", MaxTokens: 2000, Temperature: float(0.7)},
+		{Provider: ProviderFake, MaxTokens: 10, Temperature: float(0.7)},
+		{Provider: ProviderFake, MaxTokens: 2000, Temperature: float(3)},
+	}
+	for _, config := range invalid {
+		if err := ValidateConfig(config); err == nil {
+			t.Errorf("Expected %+v to be invalid", config)
+		}
+	}
+}
+
+func TestAnalyzeWithAI_UsesConfiguredModel(t *testing.T) {
+	t.Setenv("REPORT_LANGUAGE", "en")
+
+	fake := NewFakeProvider(`{"summary": "AI says hi", "confidence": 0.5}`)
+	client := NewAIClientWithProvider(AIConfig{Model: "custom-model", MaxTokens: 800, Temperature: float(0.3)}, fake)
+
+	result, err := client.AnalyzeWithAI(context.Background(), testStats(), nil)
+	if err != nil {
+		t.Fatalf("AnalyzeWithAI failed: %v", err)
+	}
+	if result.Structured == nil || result.Structured.Summary != "AI says hi" {
+		t.Errorf("Expected the provider's reply, got %+v", result)
+	}
+
+	requests := fake.Requests()
+	if len(requests) != 1 {
+		t.Fatalf("Expected 1 request, got %d", len(requests))
+	}
+	req := requests[0]
+	if req.Model != "custom-model" || req.MaxTokens != 800 || *req.Temperature != 0.3 {
+		t.Errorf("Request did not use the configured parameters: %+v", req)
+	}
+	if len(req.Messages) != 2 || req.Messages[0].Role != "system" || req.Messages[1].Role != "user" {
+		t.Fatalf("Expected a system and a user message, got %+v", req.Messages)
+	}
+	prompt := req.Messages[1].Content
+	if !strings.Contains(prompt, "42 commits by 1 contributors") || !strings.Contains(prompt, "2023-03-01") {
+		t.Errorf("Prompt is missing the statistics: %s", prompt)
+	}
+}
+
+// testStats returns minimal statistics for prompt building
+func testStats() *analyzer.Statistics {
+	return &analyzer.Statistics{
+		TotalCommits: 42,
+		AuthorStats:  map[string]*analyzer.AuthorStat{"Alice <alice@example.com>": {Name: "Alice"}},
+		TimeStats: &analyzer.TimeStat{
+			FirstCommit: time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC),
+			LastCommit:  time.Date(2023, 3, 8, 0, 0, 0, 0, time.UTC),
+			ActiveDays:  6,
+		},
+	}
+}
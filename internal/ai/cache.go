@@ -0,0 +1,90 @@
+package ai
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// ResponseCache stores model replies on disk, one file per request, keyed by
+// a hash of the provider, endpoint, model, sampling parameters and messages
+type ResponseCache struct {
+	dir string
+	// Refresh skips lookups so every request reaches the provider; the new
+	// replies still replace the stored ones
+	Refresh bool
+}
+
+// DefaultResponseCacheDir returns the cache directory in the user's cache directory
+func DefaultResponseCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "git-log-analyzer", "ai-responses")
+}
+
+// NewResponseCache creates a cache storing its entries in dir
+func NewResponseCache(dir string) *ResponseCache {
+	return &ResponseCache{dir: dir}
+}
+
+// cacheKey identifies a request to a provider
+func cacheKey(config AIConfig, req ChatRequest) string {
+	data, _ := json.Marshal(struct {
+		Provider string      `json:"provider"`
+		Endpoint string      `json:"endpoint"`
+		Request  ChatRequest `json:"request"`
+	}{config.Provider, config.APIEndpoint, req})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func (c *ResponseCache) path(key string) string {
+	return filepath.Join(c.dir, key+".json")
+}
+
+// Get returns the stored reply for key
+func (c *ResponseCache) Get(key string) (*ChatResponse, bool) {
+	if c.Refresh {
+		return nil, false
+	}
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+	var resp ChatResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, false
+	}
+	return &resp, true
+}
+
+// Put stores the reply for key
+func (c *ResponseCache) Put(key string, resp *ChatResponse) error {
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return err
+	}
+
+	// Write then rename, so concurrent runs never read a partial entry
+	tmp, err := os.CreateTemp(c.dir, key+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), c.path(key))
+}
@@ -0,0 +1,87 @@
+package ai
+
+import (
+	"context"
+	"math"
+	"testing"
+)
+
+func TestAIClient_ResponseCache(t *testing.T) {
+	dir := t.TempDir()
+	config := AIConfig{Provider: ProviderFake, Model: "m", InputPrice: 1, OutputPrice: 2}
+	newClient := func(refresh bool) (*AIClient, *FakeProvider) {
+		provider := NewFakeProvider()
+		client := NewAIClientWithProvider(config, provider)
+		cache := NewResponseCache(dir)
+		cache.Refresh = refresh
+		client.SetResponseCache(cache)
+		return client, provider
+	}
+	ctx := context.Background()
+
+	first, provider := newClient(false)
+	reply, err := first.chat(ctx, testMessages)
+	if err != nil {
+		t.Fatalf("chat failed: %v", err)
+	}
+	usage := first.Usage()
+	if usage.Calls != 1 || usage.CachedCalls != 0 || usage.PromptTokens == 0 || !usage.CostKnown {
+		t.Errorf("Expected one paid call, got %+v", usage)
+	}
+	want := (float64(usage.PromptTokens)*1 + float64(usage.CompletionTokens)*2) / 1e6
+	if math.Abs(usage.Cost-want) > 1e-12 {
+		t.Errorf("Expected cost %v, got %v", want, usage.Cost)
+	}
+
+	// The same request is answered from disk by a new client
+	second, provider := newClient(false)
+	cached, err := second.chat(ctx, testMessages)
+	if err != nil || cached != reply {
+		t.Fatalf("Expected the cached reply %q, got %q, %v", reply, cached, err)
+	}
+	if len(provider.Requests()) != 0 {
+		t.Error("A cached request should not reach the provider")
+	}
+	if usage := second.Usage(); usage.Calls != 0 || usage.CachedCalls != 1 || usage.TotalTokens() != 0 || usage.Cost != 0 {
+		t.Errorf("Cached replies should be free, got %+v", usage)
+	}
+
+	// Other parameters are a different request
+	second.config.Temperature = float(0.1)
+	second.chat(ctx, testMessages)
+	if len(provider.Requests()) != 1 {
+		t.Error("A request with other parameters should reach the provider")
+	}
+
+	// Refresh bypasses lookups
+	refreshed, provider := newClient(true)
+	refreshed.chat(ctx, testMessages)
+	if len(provider.Requests()) != 1 || refreshed.Usage().Calls != 1 {
+		t.Error("Refresh should send the request again")
+	}
+}
+
+func TestPriceFor(t *testing.T) {
+	tests := []struct {
+		config AIConfig
+		price  Price
+		known  bool
+	}{
+		{AIConfig{Provider: ProviderOpenAI, Model: "gpt-4o-mini-2024-07-18"}, Price{0.15, 0.60}, true},
+		{AIConfig{Provider: ProviderOpenAI, Model: "gpt-4o"}, Price{2.50, 10.00}, true},
+		{AIConfig{Provider: ProviderOpenAI, Model: "deepseek-chat"}, Price{}, false},
+		{AIConfig{Provider: ProviderOpenAI, Model: "deepseek-chat", InputPrice: 0.27, OutputPrice: 1.1}, Price{0.27, 1.1}, true},
+		{AIConfig{Provider: ProviderOllama, Model: "llama3"}, Price{}, true},
+	}
+	for _, tt := range tests {
+		price, known := PriceFor(tt.config)
+		if price != tt.price || known != tt.known {
+			t.Errorf("PriceFor(%s) = %+v, %v; want %+v, %v", tt.config.Model, price, known, tt.price, tt.known)
+		}
+	}
+
+	cost := Price{Input: 2, Output: 10}.Cost(Usage{PromptTokens: 500000, CompletionTokens: 100000})
+	if cost != 2 {
+		t.Errorf("Expected a cost of $2, got %v", cost)
+	}
+}
@@ -0,0 +1,51 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"git-log-analyzer/internal/changelog"
+	"git-log-analyzer/internal/i18n"
+)
+
+// maxChangelogEntries is the number of entries of a group sent to the model
+const maxChangelogEntries = 100
+
+// BuildChangelogPrompt describes one group of the changelog
+func BuildChangelogPrompt(cl *changelog.Changelog, g changelog.Group) []ChatMessage {
+	msg := i18n.T()
+	var sb strings.Builder
+	for i, e := range g.Entries {
+		if i == maxChangelogEntries {
+			fmt.Fprintf(&sb, "... and %d more\n", len(g.Entries)-i)
+			break
+		}
+		sb.WriteString("- ")
+		if e.Scope != "" {
+			fmt.Fprintf(&sb, "%s: ", e.Scope)
+		}
+		sb.WriteString(e.Description)
+		if e.Breaking {
+			sb.WriteString(" (breaking)")
+		}
+		sb.WriteString("\n")
+	}
+	return []ChatMessage{
+		{Role: "system", Content: msg.AISystemMessage},
+		{Role: "user", Content: fmt.Sprintf(msg.AIChangelogPrompt, cl.Version, g.Title, sb.String())},
+	}
+}
+
+// SummarizeChangelog sets the summary of every group of the changelog. It
+// stops at the first failure; groups summarized before it keep their summary.
+func (c *AIClient) SummarizeChangelog(ctx context.Context, cl *changelog.Changelog) error {
+	for i := range cl.Groups {
+		reply, err := c.chat(ctx, BuildChangelogPrompt(cl, cl.Groups[i]))
+		if err != nil {
+			return err
+		}
+		cl.Groups[i].Summary = strings.TrimSpace(reply)
+	}
+	return nil
+}
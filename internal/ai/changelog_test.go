@@ -0,0 +1,38 @@
+package ai
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"git-log-analyzer/internal/changelog"
+	"git-log-analyzer/internal/git"
+)
+
+func TestSummarizeChangelog(t *testing.T) {
+	cl := changelog.Build([]git.GitCommit{
+		{Hash: "c", Author: "Bob", Subject: "fix(api): handle empty input"},
+		{Hash: "b", Author: "Alice", Subject: "feat!: drop the v1 API"},
+		{Hash: "a", Author: "Alice", Subject: "feat(search): add filters"},
+	})
+	cl.Version = "v2.0.0"
+
+	provider := NewFakeProvider("  New search filters; the v1 API is gone.\n", "Empty input no longer fails.")
+	client := NewAIClientWithProvider(WithDefaults(AIConfig{Provider: ProviderFake, Model: "m"}), provider)
+	if err := client.SummarizeChangelog(context.Background(), cl); err != nil {
+		t.Fatalf("SummarizeChangelog failed: %v", err)
+	}
+	if cl.Groups[0].Summary != "New search filters; the v1 API is gone." || cl.Groups[1].Summary != "Empty input no longer fails." {
+		t.Errorf("Unexpected summaries %q, %q", cl.Groups[0].Summary, cl.Groups[1].Summary)
+	}
+
+	prompt := provider.Requests()[0].Messages[1].Content
+	for _, want := range []string{"v2.0.0", cl.Groups[0].Title, "- drop the v1 API (breaking)\n", "- search: add filters\n"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("Expected the prompt to contain %q:\n%s", want, prompt)
+		}
+	}
+	if strings.Contains(prompt, "handle empty input") {
+		t.Error("Expected each prompt to describe only its own group")
+	}
+}
@@ -0,0 +1,261 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"git-log-analyzer/internal/analyzer"
+	"git-log-analyzer/internal/developer"
+	"git-log-analyzer/internal/i18n"
+)
+
+// PrivacyConfig controls which personal data of developers is sent to the
+// model. Both options are off by default.
+type PrivacyConfig struct {
+	// ExcludeEmails leaves out email addresses, including those found in
+	// commit subjects
+	ExcludeEmails bool
+	// Anonymize replaces developer names with aliases such as "Developer 1";
+	// the aliases are mapped back to the real names in the reply. The names
+	// and emails of all other authors are removed from commit subjects and
+	// file paths, and merge commits are left out since their subjects name
+	// branches and forks.
+	Anonymize bool
+}
+
+// maxDeveloperFiles is the number of most changed files sent per developer
+const maxDeveloperFiles = 5
+
+var emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+
+// AuthorRedactor finds the names and emails of the authors of an analysis in
+// free text. It is built once per analysis and shared by the developer
+// prompts.
+type AuthorRedactor struct {
+	pattern *regexp.Regexp // nil without authors
+}
+
+// NewAuthorRedactor returns a redactor for the authors of stats
+func NewAuthorRedactor(stats *analyzer.Statistics) *AuthorRedactor {
+	var terms []string
+	if stats != nil {
+		for _, a := range stats.AuthorStats {
+			terms = append(terms, a.Name, a.Email)
+		}
+	}
+	return newAuthorRedactor(terms)
+}
+
+func newAuthorRedactor(terms []string) *AuthorRedactor {
+	seen := make(map[string]bool)
+	var alternatives []string
+	for _, term := range terms {
+		term = strings.TrimSpace(term)
+		if term == "" || seen[strings.ToLower(term)] {
+			continue
+		}
+		seen[strings.ToLower(term)] = true
+		alternatives = append(alternatives, term)
+	}
+	if len(alternatives) == 0 {
+		return &AuthorRedactor{}
+	}
+	// Longest first, so "Bob Smith" wins over "Bob"
+	sort.Slice(alternatives, func(i, j int) bool {
+		if len(alternatives[i]) != len(alternatives[j]) {
+			return len(alternatives[i]) > len(alternatives[j])
+		}
+		return alternatives[i] < alternatives[j]
+	})
+	for i, term := range alternatives {
+		alternatives[i] = wordPattern(term)
+	}
+	return &AuthorRedactor{pattern: regexp.MustCompile(`(?i)` + strings.Join(alternatives, "|"))}
+}
+
+// wordPattern matches term as a whole word where it begins or ends with a
+// letter or digit of a space-separated script; names in scripts such as
+// Chinese are matched anywhere
+func wordPattern(term string) string {
+	isWord := func(b byte) bool {
+		return b == '_' || (b >= '0' && b <= '9') || (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z')
+	}
+	pattern := regexp.QuoteMeta(term)
+	if isWord(term[0]) {
+		pattern = `\b` + pattern
+	}
+	if isWord(term[len(term)-1]) {
+		pattern += `\b`
+	}
+	return pattern
+}
+
+// redact replaces the name of the developer with alias, emails with
+// "[email]" and the names of other authors with "[developer]"
+func (r *AuthorRedactor) redact(s, name, alias string) string {
+	if r != nil && r.pattern != nil {
+		s = r.pattern.ReplaceAllStringFunc(s, func(match string) string {
+			switch {
+			case strings.Contains(match, "@"):
+				return "[email]"
+			case strings.EqualFold(match, name):
+				return alias
+			default:
+				return "[developer]"
+			}
+		})
+	}
+	return emailPattern.ReplaceAllString(s, "[email]")
+}
+
+// DeveloperPrompt is the request for one developer's narrative
+type DeveloperPrompt struct {
+	Messages []ChatMessage
+	name     string // real name
+	alias    string // name used in the prompt; equals name unless anonymized
+}
+
+// BuildDeveloperPrompt describes one developer's metrics and recent commits.
+// index numbers the alias used when the privacy config anonymizes names;
+// authors lists the names removed from free text then. A nil authors only
+// knows the developer.
+func BuildDeveloperPrompt(config AIConfig, profile *developer.DeveloperProfile, author *analyzer.AuthorStat, index int, authors *AuthorRedactor) *DeveloperPrompt {
+	msg := i18n.T()
+	privacy := config.Privacy
+
+	p := &DeveloperPrompt{name: profile.Name, alias: profile.Name}
+	if privacy.Anonymize {
+		p.alias = fmt.Sprintf("Developer %d", index+1)
+		if authors == nil {
+			authors = newAuthorRedactor([]string{profile.Name, profile.Email})
+		}
+	}
+
+	// scrub removes personal data from free text such as commit subjects
+	// and file paths
+	scrub := func(s string) string {
+		switch {
+		case privacy.Anonymize:
+			return authors.redact(s, profile.Name, p.alias)
+		case privacy.ExcludeEmails:
+			return emailPattern.ReplaceAllString(s, "[email]")
+		}
+		return s
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Developer: %s\n", p.alias)
+	if !privacy.ExcludeEmails && !privacy.Anonymize {
+		fmt.Fprintf(&sb, "Email: %s\n", profile.Email)
+	}
+
+	ws := profile.WorkStyleMetrics
+	cp := profile.CodingPatterns
+	qi := profile.QualityIndicators
+	tm := profile.TimeManagement
+	if author != nil {
+		fmt.Fprintf(&sb, "Activity: %d commits, +%d/-%d lines, %d files, from %s to %s\n",
+			author.CommitCount, author.Additions, author.Deletions, len(author.Files),
+			author.FirstCommit.Format("2006-01-02"), author.LastCommit.Format("2006-01-02"))
+	}
+	fmt.Fprintf(&sb, "Work style: %.2f commits/day, %.1f lines per commit, consistency %.0f/100, burst ratio %.0f%%, %s\n",
+		ws.CommitFrequency, ws.AverageCommitSize, ws.ConsistencyScore, ws.BurstWorkRatio*100, profile.PersonalityTraits.WorkStyleType)
+	fmt.Fprintf(&sb, "Commit mix: %s commits, refactoring %.0f%%, bug fixes %.0f%%, features %.0f%%, docs %.0f%%, tests %.0f%%\n",
+		cp.PreferredCommitSize, cp.RefactoringTendency*100, cp.BugFixRatio*100, cp.FeatureFocusRatio*100, cp.DocumentationRatio*100, cp.TestingEngagement*100)
+	fmt.Fprintf(&sb, "Quality: commit messages %.0f/100, code stability %.0f/100, technical debt %.0f%%\n",
+		qi.CommitMessageQuality, qi.CodeStabilityScore, qi.TechnicalDebtRatio)
+	if len(tm.PreferredWorkHours) > 0 {
+		hours := make([]string, len(tm.PreferredWorkHours))
+		for i, h := range tm.PreferredWorkHours {
+			hours[i] = fmt.Sprintf("%02d:00", h)
+		}
+		fmt.Fprintf(&sb, "Working hours: %s (weekends: %v, late nights: %v)\n", strings.Join(hours, ", "), tm.WeekendWorker, tm.NightOwl)
+	}
+
+	if author != nil {
+		if files := topFiles(author.Files, maxDeveloperFiles); len(files) > 0 {
+			sb.WriteString("Most changed files:\n")
+			for _, f := range files {
+				fmt.Fprintf(&sb, "- %s: %d changes\n", scrub(f), author.Files[f])
+			}
+		}
+		var commits []analyzer.CommitSample
+		for _, c := range author.RecentCommits {
+			if !c.Merge || !privacy.Anonymize {
+				commits = append(commits, c)
+			}
+		}
+		if len(commits) > 0 {
+			sb.WriteString("Recent commits:\n")
+			for _, c := range commits {
+				fmt.Fprintf(&sb, "- %s %s (+%d/-%d, %d files)\n",
+					c.Date.Format("2006-01-02"), scrub(c.Subject), c.Additions, c.Deletions, c.Files)
+			}
+		}
+	}
+
+	p.Messages = []ChatMessage{
+		{Role: "system", Content: msg.AISystemMessage},
+		{Role: "user", Content: fmt.Sprintf(msg.AIDeveloperPrompt, sb.String())},
+	}
+	return p
+}
+
+// topFiles returns the n most changed files, ties broken by path
+func topFiles(files map[string]int, n int) []string {
+	paths := make([]string, 0, len(files))
+	for f := range files {
+		paths = append(paths, f)
+	}
+	sort.Slice(paths, func(i, j int) bool {
+		if files[paths[i]] != files[paths[j]] {
+			return files[paths[i]] > files[paths[j]]
+		}
+		return paths[i] < paths[j]
+	})
+	if len(paths) > n {
+		paths = paths[:n]
+	}
+	return paths
+}
+
+// NarrateDeveloper asks the model for a short narrative of a developer's work
+// and growth suggestions. A reply that isn't the requested JSON document is
+// used as the narrative as is.
+func (c *AIClient) NarrateDeveloper(ctx context.Context, profile *developer.DeveloperProfile, author *analyzer.AuthorStat, index int, authors *AuthorRedactor) (*developer.AINarrative, error) {
+	prompt := BuildDeveloperPrompt(c.config, profile, author, index, authors)
+	reply, err := c.chat(ctx, prompt.Messages)
+	if err != nil {
+		return nil, err
+	}
+	return prompt.parseNarrative(reply), nil
+}
+
+// parseNarrative reads the reply and maps the alias back to the real name
+func (p *DeveloperPrompt) parseNarrative(reply string) *developer.AINarrative {
+	var narrative developer.AINarrative
+	if raw := extractJSON(reply); raw == "" || json.Unmarshal([]byte(raw), &narrative) != nil || strings.TrimSpace(narrative.Narrative) == "" {
+		narrative = developer.AINarrative{Narrative: reply}
+	}
+
+	restore := func(s string) string {
+		s = strings.TrimSpace(s)
+		if p.alias != p.name {
+			s = strings.ReplaceAll(s, p.alias, p.name)
+		}
+		return s
+	}
+	narrative.Narrative = restore(narrative.Narrative)
+	suggestions := narrative.Suggestions[:0]
+	for _, s := range narrative.Suggestions {
+		if s = restore(s); s != "" {
+			suggestions = append(suggestions, s)
+		}
+	}
+	narrative.Suggestions = suggestions
+	return &narrative
+}
@@ -0,0 +1,147 @@
+package ai
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"git-log-analyzer/internal/analyzer"
+	"git-log-analyzer/internal/developer"
+)
+
+func testDeveloper() (*developer.DeveloperProfile, *analyzer.AuthorStat) {
+	profile := &developer.DeveloperProfile{Name: "Alice Smith", Email: "alice@example.com"}
+	profile.WorkStyleMetrics.CommitFrequency = 1.5
+	profile.QualityIndicators.CommitMessageQuality = 72
+	profile.TimeManagement.PreferredWorkHours = []int{9, 14}
+
+	day := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	author := &analyzer.AuthorStat{
+		Name:        "Alice Smith",
+		Email:       "alice@example.com",
+		CommitCount: 2,
+		Additions:   120,
+		Deletions:   30,
+		Files:       map[string]int{"main.go": 2, "README.md": 1},
+		FirstCommit: day,
+		LastCommit:  day.AddDate(0, 0, 1),
+		RecentCommits: []analyzer.CommitSample{
+			{Hash: "b", Date: day.AddDate(0, 0, 1), Subject: "Fix parser, reported by alice smith <alice@example.com>", Additions: 20, Deletions: 10, Files: 1},
+			{Hash: "a", Date: day, Subject: "Add parser", Additions: 100, Deletions: 20, Files: 2},
+		},
+	}
+	return profile, author
+}
+
+func TestBuildDeveloperPrompt(t *testing.T) {
+	profile, author := testDeveloper()
+	prompt := BuildDeveloperPrompt(WithDefaults(AIConfig{Provider: ProviderFake}), profile, author, 0, nil)
+
+	content := prompt.Messages[1].Content
+	for _, want := range []string{
+		"Developer: Alice Smith",
+		"Email: alice@example.com",
+		"2 commits, +120/-30 lines, 2 files",
+		"commit messages 72/100",
+		"09:00, 14:00",
+		"- main.go: 2 changes",
+		"- 2024-05-02 Fix parser",
+		"- 2024-05-01 Add parser (+100/-20, 2 files)",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("Expected the prompt to contain %q:\n%s", want, content)
+		}
+	}
+}
+
+func TestBuildDeveloperPrompt_Privacy(t *testing.T) {
+	profile, author := testDeveloper()
+
+	config := WithDefaults(AIConfig{Provider: ProviderFake, Privacy: PrivacyConfig{ExcludeEmails: true}})
+	content := BuildDeveloperPrompt(config, profile, author, 0, nil).Messages[1].Content
+	if strings.Contains(content, "@example.com") || !strings.Contains(content, "Alice Smith") {
+		t.Errorf("Expected emails to be excluded and the name kept:\n%s", content)
+	}
+
+	config.Privacy = PrivacyConfig{Anonymize: true}
+	prompt := BuildDeveloperPrompt(config, profile, author, 2, nil)
+	content = prompt.Messages[1].Content
+	if strings.Contains(strings.ToLower(content), "alice") || strings.Contains(content, "@example.com") {
+		t.Errorf("Expected the developer to be anonymized:\n%s", content)
+	}
+	if !strings.Contains(content, "Developer: Developer 3") || !strings.Contains(content, "reported by Developer 3 <[email]>") {
+		t.Errorf("Expected the alias in the facts and subjects:\n%s", content)
+	}
+
+	narrative := prompt.parseNarrative(`{"narrative": "Developer 3 ships parsers.", "suggestions": ["Developer 3 should add tests", " "]}`)
+	if narrative.Narrative != "Alice Smith ships parsers." || len(narrative.Suggestions) != 1 || narrative.Suggestions[0] != "Alice Smith should add tests" {
+		t.Errorf("Expected the alias to be mapped back, got %+v", narrative)
+	}
+}
+
+func TestBuildDeveloperPrompt_AnonymizeOthers(t *testing.T) {
+	profile, author := testDeveloper()
+	author.Files["docs/bob/notes.md"] = 3
+	author.RecentCommits = append(author.RecentCommits,
+		analyzer.CommitSample{Hash: "c", Date: author.FirstCommit, Subject: "Pair with Bob Jones and 张伟 on the lexer (cc carol@example.org)"},
+		analyzer.CommitSample{Hash: "d", Date: author.FirstCommit, Subject: "Merge pull request #12 from bobj/lexer", Merge: true},
+		analyzer.CommitSample{Hash: "e", Date: author.FirstCommit, Subject: "Bump Bobcat parser"},
+	)
+	stats := &analyzer.Statistics{AuthorStats: map[string]*analyzer.AuthorStat{
+		"Alice Smith <alice@example.com>": author,
+		"Bob Jones <bobj@example.com>":    {Name: "Bob Jones", Email: "bobj@example.com"},
+		"bob <bob@example.net>":           {Name: "bob", Email: "bob@example.net"},
+		"张伟 <zw@example.cn>":              {Name: "张伟", Email: "zw@example.cn"},
+	}}
+
+	config := WithDefaults(AIConfig{Provider: ProviderFake, Privacy: PrivacyConfig{Anonymize: true}})
+	content := BuildDeveloperPrompt(config, profile, author, 0, NewAuthorRedactor(stats)).Messages[1].Content
+	for _, leaked := range []string{"Bob Jones", "张伟", "carol@", "bobj", "docs/bob/"} {
+		if strings.Contains(content, leaked) {
+			t.Errorf("Expected %q to be removed:\n%s", leaked, content)
+		}
+	}
+	for _, want := range []string{
+		"Pair with [developer] and [developer] on the lexer (cc [email])",
+		"- docs/[developer]/notes.md: 3 changes",
+		"Bump Bobcat parser",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("Expected the prompt to contain %q:\n%s", want, content)
+		}
+	}
+	if strings.Contains(content, "Merge pull request") {
+		t.Error("Expected merge commits to be left out")
+	}
+
+	// Without anonymizing, merges and other names are kept
+	config.Privacy = PrivacyConfig{}
+	content = BuildDeveloperPrompt(config, profile, author, 0, NewAuthorRedactor(stats)).Messages[1].Content
+	if !strings.Contains(content, "Merge pull request #12 from bobj/lexer") || !strings.Contains(content, "Bob Jones") {
+		t.Errorf("Expected the subjects as is:\n%s", content)
+	}
+}
+
+func TestNarrateDeveloper(t *testing.T) {
+	profile, author := testDeveloper()
+	provider := NewFakeProvider(
+		"```json\n"+`{"narrative": "Steady contributor.", "suggestions": ["Write longer messages"]}`+"\n```",
+		"Just some prose.",
+	)
+	client := NewAIClientWithProvider(WithDefaults(AIConfig{Provider: ProviderFake, Model: "m"}), provider)
+
+	narrative, err := client.NarrateDeveloper(context.Background(), profile, author, 0, nil)
+	if err != nil {
+		t.Fatalf("NarrateDeveloper failed: %v", err)
+	}
+	if narrative.Narrative != "Steady contributor." || len(narrative.Suggestions) != 1 {
+		t.Errorf("Unexpected narrative: %+v", narrative)
+	}
+
+	// A reply that isn't JSON becomes the narrative
+	narrative, err = client.NarrateDeveloper(context.Background(), profile, author, 0, nil)
+	if err != nil || narrative.Narrative != "Just some prose." || len(narrative.Suggestions) != 0 {
+		t.Errorf("Expected the plain-text fallback, got %+v, %v", narrative, err)
+	}
+}
@@ -0,0 +1,127 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Error kinds of a failed provider call
+const (
+	ErrorKindConfig        = "config"         // authentication, unknown model, bad request
+	ErrorKindRateLimit     = "rate_limit"     // HTTP 429
+	ErrorKindServer        = "server"         // HTTP 5xx
+	ErrorKindTimeout       = "timeout"        // request or gateway timeout
+	ErrorKindNetwork       = "network"        // no response received
+	ErrorKindEmpty         = "empty_response" // no choices or no content
+	ErrorKindContentFilter = "content_filter" // the provider refused to answer
+)
+
+// Report error types (see report.AIStatus.ErrorType)
+const (
+	ErrorTypeConfig   = "config_error"
+	ErrorTypeAnalysis = "analysis_error"
+)
+
+// Error is a failed provider call
+type Error struct {
+	Kind       string
+	StatusCode int           // HTTP status, 0 when no response was received
+	RetryAfter time.Duration // requested by the server, 0 when not given
+	Message    string
+	Err        error // underlying error, if any
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Retryable reports whether the same request may succeed when sent again
+func (e *Error) Retryable() bool {
+	switch e.Kind {
+	case ErrorKindRateLimit, ErrorKindServer, ErrorKindTimeout, ErrorKindNetwork, ErrorKindEmpty:
+		return true
+	}
+	return false
+}
+
+// ErrorType classifies err into the report's AIStatus.ErrorType values:
+// problems the user has to fix in the configuration are "config_error",
+// everything else is "analysis_error"
+func ErrorType(err error) string {
+	var aiErr *Error
+	if errors.As(err, &aiErr) && aiErr.Kind == ErrorKindConfig {
+		return ErrorTypeConfig
+	}
+	return ErrorTypeAnalysis
+}
+
+// statusError builds the error for a non-successful HTTP response
+func statusError(status int, header http.Header, message string) *Error {
+	if message == "" {
+		message = http.StatusText(status)
+	}
+	e := &Error{
+		StatusCode: status,
+		Message:    fmt.Sprintf("failed to get AI response: %d %s", status, message),
+	}
+	if header != nil {
+		e.RetryAfter = parseRetryAfter(header.Get("Retry-After"), time.Now())
+	}
+
+	switch {
+	case status == http.StatusTooManyRequests && strings.Contains(message, "insufficient_quota"):
+		// Out of credit: retrying won't help, the account needs attention
+		e.Kind = ErrorKindConfig
+	case status == http.StatusTooManyRequests:
+		e.Kind = ErrorKindRateLimit
+	case status == http.StatusRequestTimeout || status == http.StatusGatewayTimeout:
+		e.Kind = ErrorKindTimeout
+	case status >= 500:
+		e.Kind = ErrorKindServer
+	default:
+		e.Kind = ErrorKindConfig
+	}
+	return e
+}
+
+// transportError builds the error for a request that got no response.
+// Cancellation of ctx is returned as is.
+func transportError(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	kind := ErrorKindNetwork
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		kind = ErrorKindTimeout
+	}
+	return &Error{Kind: kind, Message: fmt.Sprintf("failed to get AI response: %v", err), Err: err}
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds or as an HTTP date
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil && date.After(now) {
+		return date.Sub(now)
+	}
+	return 0
+}
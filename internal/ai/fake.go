@@ -0,0 +1,99 @@
+package ai
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// FakeProvider is a deterministic offline provider. It replays the queued
+// responses in order and then answers with a digest of the prompt, so the
+// same request always yields the same reply. When tools are offered, a user
+// message naming tools is answered with calls to them, which makes tool
+// conversations reproducible too. Every request is recorded and usage is
+// estimated from the text.
+type FakeProvider struct {
+	mu        sync.Mutex
+	responses []ChatResponse
+	requests  []ChatRequest
+}
+
+// NewFakeProvider creates a fake provider that replays responses in order
+func NewFakeProvider(responses ...string) *FakeProvider {
+	scripted := make([]ChatResponse, len(responses))
+	for i, content := range responses {
+		scripted[i] = ChatResponse{Content: content}
+	}
+	return &FakeProvider{responses: scripted}
+}
+
+// NewScriptedFakeProvider creates a fake provider that replays complete
+// responses, including tool calls, in order
+func NewScriptedFakeProvider(responses ...ChatResponse) *FakeProvider {
+	return &FakeProvider{responses: responses}
+}
+
+// Name returns the provider kind
+func (p *FakeProvider) Name() string {
+	return ProviderFake
+}
+
+// Chat records the request and returns the next canned response
+func (p *FakeProvider) Chat(ctx context.Context, req ChatRequest) (*ChatResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.requests = append(p.requests, req)
+
+	var resp ChatResponse
+	if len(p.responses) > 0 {
+		resp = p.responses[0]
+		p.responses = p.responses[1:]
+	} else {
+		resp = fakeReply(req)
+	}
+	resp.Model = req.Model
+
+	// Usage is estimated the way prompts are budgeted
+	completion := resp.Content
+	for _, call := range resp.ToolCalls {
+		completion += call.Name + call.Arguments
+	}
+	resp.Usage = Usage{PromptTokens: int64(estimateMessages(req.Messages)), CompletionTokens: int64(EstimateTokens(completion))}
+	return &resp, nil
+}
+
+// fakeReply answers a request when no canned responses are left
+func fakeReply(req ChatRequest) ChatResponse {
+	if n := len(req.Messages); len(req.Tools) > 0 && n > 0 && req.Messages[n-1].Role == "user" {
+		question := strings.ToLower(req.Messages[n-1].Content)
+		var calls []ToolCall
+		for _, tool := range req.Tools {
+			if strings.Contains(question, strings.ToLower(tool.Name)) {
+				calls = append(calls, ToolCall{ID: fmt.Sprintf("call_%d", len(calls)+1), Name: tool.Name, Arguments: "{}"})
+			}
+		}
+		if len(calls) > 0 {
+			return ChatResponse{ToolCalls: calls}
+		}
+	}
+
+	h := sha256.New()
+	for _, m := range req.Messages {
+		fmt.Fprintf(h, "%s\x00%s\x00", m.Role, m.Content)
+	}
+	return ChatResponse{Content: fmt.Sprintf("Fake analysis by %s (prompt %x)", req.Model, h.Sum(nil)[:6])}
+}
+
+// Requests returns the requests received so far
+func (p *FakeProvider) Requests() []ChatRequest {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]ChatRequest(nil), p.requests...)
+}
@@ -0,0 +1,131 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"git-log-analyzer/internal/i18n"
+	"git-log-analyzer/internal/quality"
+)
+
+const (
+	// messageBatchSize is how many commit messages are scored per request
+	messageBatchSize = 20
+	// messageBodyLines is how many body lines of a message are sent
+	messageBodyLines = 3
+	// shortHashLength is the hash prefix used to identify messages in prompts
+	shortHashLength = 12
+)
+
+// messageScores is the JSON document the model returns for a batch
+type messageScores struct {
+	Scores []struct {
+		Hash   string   `json:"hash"`
+		Score  float64  `json:"score"`
+		Issues []string `json:"issues"`
+	} `json:"scores"`
+}
+
+// ScoreCommitMessages refines the heuristic scores of the limit most recent
+// messages with the model, sending them in batches, newest first.
+// Scores already in the cache are reused and new ones are added to it;
+// messages the model skips keep their heuristic score. It returns the number
+// of messages that now carry an AI score.
+func (c *AIClient) ScoreCommitMessages(ctx context.Context, mq *quality.MessageQuality, cache *quality.ScoreCache, limit int) (int, error) {
+	defer mq.Recalculate()
+
+	scored := 0
+	var pending []*quality.MessageScore
+	for _, m := range mq.Recent(limit) {
+		if cached, ok := cache.Get(m.Hash, c.config.Model); ok {
+			applyAIScore(m, cached)
+			scored++
+			continue
+		}
+		pending = append(pending, m)
+	}
+
+	for start := 0; start < len(pending); start += messageBatchSize {
+		end := start + messageBatchSize
+		if end > len(pending) {
+			end = len(pending)
+		}
+		batch := pending[start:end]
+
+		reply, err := c.chat(ctx, scoringMessages(batch))
+		if err != nil {
+			return scored, err
+		}
+		for m, score := range parseMessageScores(reply, batch) {
+			cache.Put(m.Hash, c.config.Model, score)
+			applyAIScore(m, score)
+			scored++
+		}
+	}
+	return scored, nil
+}
+
+func applyAIScore(m *quality.MessageScore, score quality.CachedScore) {
+	m.Score = score.Score
+	m.Issues = score.Issues
+	m.Source = quality.SourceAI
+}
+
+// scoringMessages builds the conversation for one batch
+func scoringMessages(batch []*quality.MessageScore) []ChatMessage {
+	msg := i18n.T()
+
+	var sb strings.Builder
+	for _, m := range batch {
+		fmt.Fprintf(&sb, "[%s] %s\n", shortHash(m.Hash), m.Subject)
+		lines := strings.Split(strings.TrimSpace(m.Body), "\n")
+		for i, line := range lines {
+			if i == messageBodyLines || strings.TrimSpace(line) == "" && i == 0 {
+				break
+			}
+			fmt.Fprintf(&sb, "    %s\n", line)
+		}
+	}
+
+	return []ChatMessage{
+		{Role: "system", Content: msg.AISystemMessage},
+		{Role: "user", Content: fmt.Sprintf(msg.AIMessageScoringPrompt, sb.String())},
+	}
+}
+
+// parseMessageScores maps the valid scores of a reply to the batch messages.
+// Unknown hashes and out-of-range scores are ignored.
+func parseMessageScores(reply string, batch []*quality.MessageScore) map[*quality.MessageScore]quality.CachedScore {
+	raw := extractJSON(reply)
+	if raw == "" {
+		return nil
+	}
+	var doc messageScores
+	if err := json.Unmarshal([]byte(raw), &doc); err != nil {
+		return nil
+	}
+
+	result := make(map[*quality.MessageScore]quality.CachedScore)
+	for _, s := range doc.Scores {
+		hash := strings.TrimSpace(s.Hash)
+		if len(hash) < 7 || s.Score < 0 || s.Score > 100 {
+			continue
+		}
+		for _, m := range batch {
+			if strings.HasPrefix(m.Hash, hash) {
+				result[m] = quality.CachedScore{Score: s.Score, Issues: s.Issues}
+				break
+			}
+		}
+	}
+	return result
+}
+
+func shortHash(hash string) string {
+	if len(hash) > shortHashLength {
+		return hash[:shortHashLength]
+	}
+	return hash
+}
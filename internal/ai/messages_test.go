@@ -0,0 +1,124 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"git-log-analyzer/internal/git"
+	"git-log-analyzer/internal/quality"
+)
+
+// scoredMessages returns heuristic scores for n one-word commits
+func scoredMessages(n int) *quality.MessageQuality {
+	scorer := quality.NewMessageScorer()
+	for i := 0; i < n; i++ {
+		scorer.AddCommit(&git.GitCommit{
+			Hash:    fmt.Sprintf("%012d", i) + strings.Repeat("a", 28),
+			Author:  "Alice",
+			Email:   "alice@example.com",
+			Subject: "wip",
+		})
+	}
+	return scorer.Result()
+}
+
+func TestScoreCommitMessages(t *testing.T) {
+	mq := scoredMessages(25)
+	// The first batch scores two messages (one by its short hash) plus an
+	// unknown hash and an out-of-range score; the second batch is unparseable
+	provider := NewFakeProvider(
+		"```json\n"+`{"scores": [`+
+			`{"hash": "`+mq.Messages[0].Hash+`", "score": 80, "issues": ["vague"]},`+
+			`{"hash": "000000000001", "score": 60},`+
+			`{"hash": "ffffffffffff", "score": 90},`+
+			`{"hash": "000000000002", "score": 250}]}`+"\n```",
+		"not json",
+	)
+	client := NewAIClientWithProvider(WithDefaults(AIConfig{Provider: ProviderFake, Model: "m"}), provider)
+	cache, _ := quality.OpenScoreCache("")
+
+	scored, err := client.ScoreCommitMessages(context.Background(), mq, cache, 0)
+	if err != nil {
+		t.Fatalf("ScoreCommitMessages failed: %v", err)
+	}
+	if scored != 2 {
+		t.Errorf("Expected 2 AI scores, got %d", scored)
+	}
+
+	requests := provider.Requests()
+	if len(requests) != 2 {
+		t.Fatalf("Expected 25 messages in 2 batches, got %d requests", len(requests))
+	}
+	if prompt := requests[0].Messages[1].Content; !strings.Contains(prompt, "[000000000000] wip") || strings.Contains(prompt, mq.Messages[20].Hash[:12]) {
+		t.Errorf("Unexpected first batch:\n%s", prompt)
+	}
+
+	if m := mq.Messages[0]; m.Score != 80 || m.Source != quality.SourceAI || m.Issues[0] != "vague" {
+		t.Errorf("Expected the AI score to replace the heuristic, got %+v", m)
+	}
+	if m := mq.Messages[2]; m.Score != 35 || m.Source != quality.SourceHeuristic {
+		t.Errorf("Out-of-range scores should keep the heuristic, got %+v", m)
+	}
+	if mq.Worst[len(mq.Worst)-1].Score != 35 || mq.AuthorScores["Alice <alice@example.com>"] <= 35 {
+		t.Errorf("Expected the summary to be recalculated, got %+v", mq)
+	}
+
+	// A second run answers from the cache for the scored messages only
+	rerun := scoredMessages(25)
+	provider = NewFakeProvider()
+	client = NewAIClientWithProvider(WithDefaults(AIConfig{Provider: ProviderFake, Model: "m"}), provider)
+	if scored, _ := client.ScoreCommitMessages(context.Background(), rerun, cache, 0); scored != 2 {
+		t.Errorf("Expected 2 cached scores, got %d", scored)
+	}
+	if prompt := provider.Requests()[0].Messages[1].Content; strings.Contains(prompt, "[000000000000]") {
+		t.Error("Cached messages should not be sent again")
+	}
+}
+
+func TestScoreCommitMessages_Limit(t *testing.T) {
+	mq := scoredMessages(30)
+	provider := NewFakeProvider()
+	client := NewAIClientWithProvider(WithDefaults(AIConfig{Provider: ProviderFake}), provider)
+	cache, _ := quality.OpenScoreCache("")
+
+	if _, err := client.ScoreCommitMessages(context.Background(), mq, cache, 5); err != nil {
+		t.Fatalf("ScoreCommitMessages failed: %v", err)
+	}
+	requests := provider.Requests()
+	if len(requests) != 1 || strings.Count(requests[0].Messages[1].Content, "] wip") != 5 {
+		t.Errorf("Expected a single batch of the 5 most recent messages, got %d requests", len(requests))
+	}
+
+	// The analyzer sorts its commits oldest first; the newest still go first
+	oldestFirst := quality.NewMessageScorer()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 4; i++ {
+		oldestFirst.AddCommit(&git.GitCommit{
+			Hash:    fmt.Sprintf("%012d", i) + strings.Repeat("b", 28),
+			Author:  "Alice",
+			Email:   "alice@example.com",
+			Date:    start.AddDate(0, 0, i),
+			Subject: "wip",
+		})
+	}
+	provider = NewFakeProvider()
+	client = NewAIClientWithProvider(WithDefaults(AIConfig{Provider: ProviderFake}), provider)
+	if _, err := client.ScoreCommitMessages(context.Background(), oldestFirst.Result(), cache, 2); err != nil {
+		t.Fatalf("ScoreCommitMessages failed: %v", err)
+	}
+	prompt := provider.Requests()[0].Messages[1].Content
+	sent := regexp.MustCompile(`\[(\d{12})\]`).FindAllStringSubmatch(prompt, -1)
+	if len(sent) != 2 || sent[0][1] != "000000000003" || sent[1][1] != "000000000002" {
+		t.Errorf("Expected the two newest messages, newest first, got %v", sent)
+	}
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := client.ScoreCommitMessages(canceled, scoredMessages(3), cache, 0); err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
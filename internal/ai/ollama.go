@@ -0,0 +1,163 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// OllamaProvider talks to an Ollama-style local server (POST /api/chat)
+type OllamaProvider struct {
+	endpoint string
+	client   *http.Client
+}
+
+// ollamaRequest is the payload of POST /api/chat
+type ollamaRequest struct {
+	Model    string          `json:"model"`
+	Messages []ollamaMessage `json:"messages"`
+	Tools    []ollamaTool    `json:"tools,omitempty"`
+	Stream   bool            `json:"stream"`
+	Options  struct {
+		Temperature *float64 `json:"temperature,omitempty"`
+		NumPredict  int64    `json:"num_predict,omitempty"`
+	} `json:"options"`
+}
+
+// ollamaMessage is a chat message in Ollama's format, which identifies tool
+// calls by function name rather than by ID
+type ollamaMessage struct {
+	Role      string           `json:"role"`
+	Content   string           `json:"content"`
+	ToolCalls []ollamaToolCall `json:"tool_calls,omitempty"`
+	ToolName  string           `json:"tool_name,omitempty"`
+}
+
+type ollamaToolCall struct {
+	Function struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+	} `json:"function"`
+}
+
+type ollamaTool struct {
+	Type     string `json:"type"`
+	Function Tool   `json:"function"`
+}
+
+// ollamaResponse is the non-streaming reply of POST /api/chat
+type ollamaResponse struct {
+	Model           string        `json:"model"`
+	Message         ollamaMessage `json:"message"`
+	Error           string        `json:"error"`
+	PromptEvalCount int64         `json:"prompt_eval_count"`
+	EvalCount       int64         `json:"eval_count"`
+}
+
+// ollamaMessages converts messages to Ollama's format
+func ollamaMessages(messages []ChatMessage) []ollamaMessage {
+	names := make(map[string]string) // tool call ID -> function name
+	result := make([]ollamaMessage, 0, len(messages))
+	for _, m := range messages {
+		om := ollamaMessage{Role: m.Role, Content: m.Content, ToolName: names[m.ToolCallID]}
+		for _, call := range m.ToolCalls {
+			names[call.ID] = call.Name
+			var oc ollamaToolCall
+			oc.Function.Name = call.Name
+			oc.Function.Arguments = json.RawMessage(call.Arguments)
+			if !json.Valid(oc.Function.Arguments) {
+				oc.Function.Arguments = json.RawMessage("{}")
+			}
+			om.ToolCalls = append(om.ToolCalls, oc)
+		}
+		result = append(result, om)
+	}
+	return result
+}
+
+// NewOllamaProvider creates a provider for the server at config.APIEndpoint
+func NewOllamaProvider(config AIConfig) *OllamaProvider {
+	endpoint := strings.TrimSuffix(config.APIEndpoint, "/")
+	if !strings.HasSuffix(endpoint, "/api/chat") {
+		endpoint += "/api/chat"
+	}
+	return &OllamaProvider{
+		endpoint: endpoint,
+		client:   &http.Client{Timeout: config.Timeout},
+	}
+}
+
+// Name returns the provider kind
+func (p *OllamaProvider) Name() string {
+	return ProviderOllama
+}
+
+// Chat sends a non-streaming chat request
+func (p *OllamaProvider) Chat(ctx context.Context, req ChatRequest) (*ChatResponse, error) {
+	payload := ollamaRequest{
+		Model:    req.Model,
+		Messages: ollamaMessages(req.Messages),
+	}
+	for _, tool := range req.Tools {
+		payload.Tools = append(payload.Tools, ollamaTool{Type: "function", Function: tool})
+	}
+	payload.Options.Temperature = req.Temperature
+	payload.Options.NumPredict = req.MaxTokens
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode AI request: %v", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create AI request: %v", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(httpReq)
+	if err != nil {
+		return nil, transportError(ctx, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, transportError(ctx, err)
+	}
+
+	var result ollamaResponse
+	if err := json.Unmarshal(data, &result); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return nil, statusError(resp.StatusCode, resp.Header, "")
+		}
+		return nil, fmt.Errorf("failed to decode AI response: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusError(resp.StatusCode, resp.Header, result.Error)
+	}
+	if result.Error != "" {
+		return nil, &Error{Kind: ErrorKindServer, Message: "failed to get AI response: " + result.Error}
+	}
+
+	// Ollama doesn't number tool calls; IDs only need to be unique per reply
+	var calls []ToolCall
+	for i, call := range result.Message.ToolCalls {
+		calls = append(calls, ToolCall{
+			ID:        fmt.Sprintf("call_%d", i+1),
+			Name:      call.Function.Name,
+			Arguments: string(call.Function.Arguments),
+		})
+	}
+
+	return &ChatResponse{
+		Content:   result.Message.Content,
+		ToolCalls: calls,
+		Model:     result.Model,
+		Usage:     Usage{PromptTokens: result.PromptEvalCount, CompletionTokens: result.EvalCount},
+	}, nil
+}
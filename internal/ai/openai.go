@@ -0,0 +1,143 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/openai/openai-go"
+	"github.com/openai/openai-go/option"
+	"github.com/openai/openai-go/packages/param"
+	"github.com/openai/openai-go/shared"
+)
+
+// OpenAIProvider talks to any OpenAI-compatible chat completions API
+type OpenAIProvider struct {
+	client openai.Client
+}
+
+// NewOpenAIProvider creates a provider for config.APIEndpoint using config.APIKey
+func NewOpenAIProvider(config AIConfig) *OpenAIProvider {
+	return &OpenAIProvider{
+		client: openai.NewClient(
+			option.WithAPIKey(config.APIKey),
+			option.WithBaseURL(openAIBaseURL(config.APIEndpoint)),
+			option.WithHTTPClient(&http.Client{Timeout: config.Timeout}),
+			// AIClient retries with its own policy for every provider
+			option.WithMaxRetries(0),
+		),
+	}
+}
+
+// openAIBaseURL turns a configured endpoint into the SDK's base URL. The
+// endpoint is documented as the full chat completions URL, while the SDK
+// appends "chat/completions" itself.
+func openAIBaseURL(endpoint string) string {
+	base := strings.TrimSuffix(endpoint, "/")
+	base = strings.TrimSuffix(base, "/chat/completions")
+	return base + "/"
+}
+
+// Name returns the provider kind
+func (p *OpenAIProvider) Name() string {
+	return ProviderOpenAI
+}
+
+// Chat sends a chat completion request
+func (p *OpenAIProvider) Chat(ctx context.Context, req ChatRequest) (*ChatResponse, error) {
+	messages := make([]openai.ChatCompletionMessageParamUnion, 0, len(req.Messages))
+	for _, m := range req.Messages {
+		switch m.Role {
+		case "system":
+			messages = append(messages, openai.SystemMessage(m.Content))
+		case "assistant":
+			if len(m.ToolCalls) == 0 {
+				messages = append(messages, openai.AssistantMessage(m.Content))
+				break
+			}
+			assistant := openai.ChatCompletionAssistantMessageParam{}
+			if m.Content != "" {
+				assistant.Content.OfString = param.NewOpt(m.Content)
+			}
+			for _, call := range m.ToolCalls {
+				assistant.ToolCalls = append(assistant.ToolCalls, openai.ChatCompletionMessageToolCallParam{
+					ID:       call.ID,
+					Function: openai.ChatCompletionMessageToolCallFunctionParam{Name: call.Name, Arguments: call.Arguments},
+				})
+			}
+			messages = append(messages, openai.ChatCompletionMessageParamUnion{OfAssistant: &assistant})
+		case "tool":
+			messages = append(messages, openai.ToolMessage(m.Content, m.ToolCallID))
+		default:
+			messages = append(messages, openai.UserMessage(m.Content))
+		}
+	}
+
+	var tools []openai.ChatCompletionToolParam
+	for _, tool := range req.Tools {
+		tools = append(tools, openai.ChatCompletionToolParam{
+			Function: shared.FunctionDefinitionParam{
+				Name:        tool.Name,
+				Description: param.NewOpt(tool.Description),
+				Parameters:  shared.FunctionParameters(tool.Parameters),
+			},
+		})
+	}
+
+	params := openai.ChatCompletionNewParams{
+		Model:    req.Model,
+		Messages: messages,
+		Tools:    tools,
+	}
+	// Only configured values are sent, so a temperature of 0 is not omitted
+	if req.MaxTokens > 0 {
+		params.MaxTokens = param.NewOpt(req.MaxTokens)
+	}
+	if req.Temperature != nil {
+		params.Temperature = param.NewOpt(*req.Temperature)
+	}
+	completion, err := p.client.Chat.Completions.New(ctx, params)
+	if err != nil {
+		var apiErr *openai.Error
+		if ctx.Err() == nil && errors.As(err, &apiErr) {
+			var header http.Header
+			if apiErr.Response != nil {
+				header = apiErr.Response.Header
+			}
+			message := apiErr.Message
+			if apiErr.Code != "" {
+				message += " (" + apiErr.Code + ")"
+			}
+			return nil, statusError(apiErr.StatusCode, header, message)
+		}
+		return nil, transportError(ctx, err)
+	}
+	if len(completion.Choices) == 0 {
+		return nil, &Error{Kind: ErrorKindEmpty, Message: "AI response contained no choices"}
+	}
+
+	choice := completion.Choices[0]
+	if choice.FinishReason == "content_filter" || choice.Message.Refusal != "" {
+		message := "AI response was blocked by the provider's content filter"
+		if choice.Message.Refusal != "" {
+			message += ": " + choice.Message.Refusal
+		}
+		return nil, &Error{Kind: ErrorKindContentFilter, Message: message}
+	}
+
+	var calls []ToolCall
+	for _, call := range choice.Message.ToolCalls {
+		calls = append(calls, ToolCall{ID: call.ID, Name: call.Function.Name, Arguments: call.Function.Arguments})
+	}
+
+	return &ChatResponse{
+		Content:   choice.Message.Content,
+		ToolCalls: calls,
+		Model:     completion.Model,
+		Usage: Usage{
+			PromptTokens:     completion.Usage.PromptTokens,
+			CompletionTokens: completion.Usage.CompletionTokens,
+		},
+	}, nil
+}
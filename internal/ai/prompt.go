@@ -0,0 +1,329 @@
+package ai
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"git-log-analyzer/internal/analyzer"
+	"git-log-analyzer/internal/developer"
+	"git-log-analyzer/internal/i18n"
+)
+
+// Prompt is the exact conversation sent for an analysis
+type Prompt struct {
+	Messages []ChatMessage
+	Tokens   int // estimated size of Messages
+	Budget   int // token budget the facts were selected for
+	Omitted  int // facts dropped to stay within the budget
+	facts    string
+}
+
+// Per-section limits, so a single long list can't crowd out the others
+const (
+	maxPromptItems  = 10
+	maxPromptMonths = 12
+)
+
+// promptReserve is kept free in the context window for the system message,
+// message framing and estimation error
+const promptReserve = 256
+
+// contextWindows lists context window sizes by model name prefix. More
+// specific prefixes come first.
+var contextWindows = []struct {
+	prefix string
+	tokens int
+}{
+	{"gpt-4o", 128000},
+	{"gpt-4-turbo", 128000},
+	{"gpt-4-32k", 32768},
+	{"gpt-4", 8192},
+	{"gpt-3.5-turbo", 16385},
+	{"qwen", 32768},
+	{"deepseek", 65536},
+	{"mistral", 32768},
+	{"llama3", 8192},
+}
+
+// defaultContextWindow is assumed for unknown models
+const defaultContextWindow = 8192
+
+// contextWindow returns the context window size of a model
+func contextWindow(model string) int {
+	model = strings.ToLower(model)
+	for _, w := range contextWindows {
+		if strings.HasPrefix(model, w.prefix) {
+			return w.tokens
+		}
+	}
+	return defaultContextWindow
+}
+
+// PromptBudget returns the token budget for the prompt: the configured
+// PromptBudget, or whatever the model's context window leaves after the
+// reply (MaxTokens) and a small reserve
+func PromptBudget(config AIConfig) int {
+	config = WithDefaults(config)
+	if config.PromptBudget > 0 {
+		return config.PromptBudget
+	}
+	budget := contextWindow(config.Model) - int(config.MaxTokens) - promptReserve
+	if budget < 512 {
+		budget = 512
+	}
+	return budget
+}
+
+// EstimateTokens approximates the token count of s: about four ASCII
+// characters per token, and one token per non-ASCII rune (CJK text)
+func EstimateTokens(s string) int {
+	ascii, other := 0, 0
+	for _, r := range s {
+		if r < 128 {
+			ascii++
+		} else {
+			other++
+		}
+	}
+	return (ascii+3)/4 + other
+}
+
+// estimateMessages approximates the token count of a conversation, including
+// a few tokens of framing per message
+func estimateMessages(messages []ChatMessage) int {
+	total := 0
+	for _, m := range messages {
+		total += EstimateTokens(m.Content) + 4
+	}
+	return total
+}
+
+// BuildAnalysisPrompt selects the most relevant facts from the analysis so the
+// structured analysis request fits the token budget of config's model.
+// Nothing is sent; this is what AnalyzeWithAI sends first.
+func BuildAnalysisPrompt(config AIConfig, stats *analyzer.Statistics, profiles []*developer.DeveloperProfile) *Prompt {
+	msg := i18n.T()
+	budget := PromptBudget(config)
+
+	// Everything but the facts is fixed cost
+	skeleton := []ChatMessage{
+		{Role: "system", Content: msg.AISystemMessage},
+		{Role: "user", Content: fmt.Sprintf(msg.AIPromptTemplate, "") + "\n\n" + msg.AIStructuredInstructions},
+	}
+	facts, omitted := selectFacts(promptSections(stats, profiles), budget-estimateMessages(skeleton))
+
+	messages := []ChatMessage{
+		{Role: "system", Content: msg.AISystemMessage},
+		{Role: "user", Content: fmt.Sprintf(msg.AIPromptTemplate, facts) + "\n\n" + msg.AIStructuredInstructions},
+	}
+	return &Prompt{
+		Messages: messages,
+		Tokens:   estimateMessages(messages),
+		Budget:   budget,
+		Omitted:  omitted,
+		facts:    facts,
+	}
+}
+
+// freeTextMessages returns the fallback conversation, asking for free text
+// about the same facts
+func (p *Prompt) freeTextMessages() []ChatMessage {
+	msg := i18n.T()
+	return []ChatMessage{
+		{Role: "system", Content: msg.AISystemMessage},
+		{Role: "user", Content: fmt.Sprintf(msg.AIPromptTemplate, p.facts)},
+	}
+}
+
+// promptSection is a titled list of facts
+type promptSection struct {
+	title string
+	items []string
+}
+
+// selectFacts renders sections in priority order, adding items while they
+// fit the budget. It returns the facts and how many items were left out.
+func selectFacts(sections []promptSection, budget int) (string, int) {
+	var sb strings.Builder
+	used, omitted := 0, 0
+
+	for _, section := range sections {
+		header := "\n" + section.title + ":\n"
+		written := false
+		for _, item := range section.items {
+			line := "- " + item + "\n"
+			cost := EstimateTokens(line)
+			if !written {
+				cost += EstimateTokens(header)
+			}
+			if used+cost > budget {
+				omitted++
+				continue
+			}
+			if !written {
+				sb.WriteString(header)
+				written = true
+			}
+			sb.WriteString(line)
+			used += cost
+		}
+	}
+
+	return strings.TrimPrefix(sb.String(), "\n"), omitted
+}
+
+// promptSections collects the facts worth telling the model, most relevant first
+func promptSections(stats *analyzer.Statistics, profiles []*developer.DeveloperProfile) []promptSection {
+	msg := i18n.T()
+	sections := []promptSection{{title: msg.AIFactsOverview, items: overviewFacts(stats)}}
+
+	if metrics := stats.CodeHealthMetrics; metrics != nil {
+		var hotspots, signals, issues []string
+		for _, h := range capped(metrics.TechnicalDebtHotspots) {
+			hotspots = append(hotspots, fmt.Sprintf("%s: %d changes by %d authors, risk %.2f (%s)", h.FilePath, h.TotalChanges, h.UniqueAuthors, h.RiskScore, h.Reason))
+		}
+		for _, s := range capped(metrics.RefactoringSignals) {
+			signals = append(signals, fmt.Sprintf("%s: %d changes on %d days within %s (%s)", s.FilePath, s.ShortTermChanges, s.IntensiveModDays, s.TimeWindow, s.RefactoringSignal))
+		}
+		for _, c := range capped(metrics.CodeConcentrationIssues) {
+			issues = append(issues, fmt.Sprintf("%s: %.0f%% of all changes, %d authors (%s, %s)", c.FilePath, c.ChangeRatio*100, c.AuthorCount, c.ConcentrationLevel, c.ImpactLevel))
+		}
+		sections = append(sections,
+			promptSection{title: msg.AIFactsHotspots, items: hotspots},
+			promptSection{title: msg.AIFactsRefactoring, items: signals},
+			promptSection{title: msg.AIFactsConcentration, items: issues},
+		)
+	}
+
+	sections = append(sections,
+		promptSection{title: msg.AIFactsTimeline, items: timelineFacts(stats)},
+		promptSection{title: msg.AIFactsDevelopers, items: developerFacts(stats, profiles)},
+		promptSection{title: msg.AIFactsFiles, items: fileFacts(stats)},
+	)
+	return sections
+}
+
+// capped returns at most the first maxPromptItems items
+func capped[T any](items []T) []T {
+	if len(items) > maxPromptItems {
+		return items[:maxPromptItems]
+	}
+	return items
+}
+
+func overviewFacts(stats *analyzer.Statistics) []string {
+	facts := []string{
+		fmt.Sprintf("%d commits by %d contributors", stats.TotalCommits, len(stats.AuthorStats)),
+	}
+	if ts := stats.TimeStats; ts != nil {
+		facts = append(facts, fmt.Sprintf("active from %s to %s on %d days (%d weeks, %d months)",
+			ts.FirstCommit.Format("2006-01-02"), ts.LastCommit.Format("2006-01-02"), ts.ActiveDays, ts.ActiveWeeks, ts.ActiveMonths))
+	}
+	if stats.BranchData != nil {
+		facts = append(facts, fmt.Sprintf("%d branches, %d merges", len(stats.BranchData.Branches), len(stats.BranchData.MergePatterns)))
+	}
+	if stats.CodeHealthMetrics != nil {
+		facts = append(facts, fmt.Sprintf("code health score %.0f/100", stats.CodeHealthMetrics.HealthScore*100))
+	}
+	return facts
+}
+
+// timelineFacts summarizes commits per month (most recent months) and the
+// recent trend
+func timelineFacts(stats *analyzer.Statistics) []string {
+	monthly := make(map[string]int)
+	for date, count := range stats.CommitFrequency {
+		if len(date) >= 7 {
+			monthly[date[:7]] += count
+		}
+	}
+	if len(monthly) == 0 {
+		return nil
+	}
+
+	months := make([]string, 0, len(monthly))
+	for month := range monthly {
+		months = append(months, month)
+	}
+	sort.Strings(months)
+
+	var facts []string
+	if len(months) >= 6 {
+		recent, overall := 0, 0
+		for i, month := range months {
+			overall += monthly[month]
+			if i >= len(months)-3 {
+				recent += monthly[month]
+			}
+		}
+		facts = append(facts, fmt.Sprintf("last 3 active months average %.1f commits/month vs %.1f overall",
+			float64(recent)/3, float64(overall)/float64(len(months))))
+	}
+
+	if len(months) > maxPromptMonths {
+		months = months[len(months)-maxPromptMonths:]
+	}
+	for i := len(months) - 1; i >= 0; i-- {
+		facts = append(facts, fmt.Sprintf("%s: %d commits", months[i], monthly[months[i]]))
+	}
+	return facts
+}
+
+// developerFacts summarizes the most active developers, using their profiles
+// when available
+func developerFacts(stats *analyzer.Statistics, profiles []*developer.DeveloperProfile) []string {
+	byName := make(map[string]*developer.DeveloperProfile, len(profiles))
+	for _, p := range profiles {
+		byName[p.Name+"\x00"+p.Email] = p
+	}
+
+	authors := make([]*analyzer.AuthorStat, 0, len(stats.AuthorStats))
+	for _, a := range stats.AuthorStats {
+		authors = append(authors, a)
+	}
+	sort.Slice(authors, func(i, j int) bool {
+		if authors[i].CommitCount != authors[j].CommitCount {
+			return authors[i].CommitCount > authors[j].CommitCount
+		}
+		return authors[i].Name < authors[j].Name
+	})
+
+	var facts []string
+	for _, a := range capped(authors) {
+		fact := fmt.Sprintf("%s: %d commits (%.0f%%), +%d/-%d lines, %d files",
+			a.Name, a.CommitCount, percent(a.CommitCount, stats.TotalCommits), a.Additions, a.Deletions, len(a.Files))
+		if p := byName[a.Name+"\x00"+a.Email]; p != nil {
+			fact += fmt.Sprintf(", %s commits, %s work style", p.CodingPatterns.PreferredCommitSize, p.PersonalityTraits.WorkStyleType)
+		}
+		facts = append(facts, fact)
+	}
+	return facts
+}
+
+// fileFacts lists the most modified files
+func fileFacts(stats *analyzer.Statistics) []string {
+	files := make([]string, 0, len(stats.FileStats))
+	for file := range stats.FileStats {
+		files = append(files, file)
+	}
+	sort.Slice(files, func(i, j int) bool {
+		if stats.FileStats[files[i]] != stats.FileStats[files[j]] {
+			return stats.FileStats[files[i]] > stats.FileStats[files[j]]
+		}
+		return files[i] < files[j]
+	})
+
+	var facts []string
+	for _, file := range capped(files) {
+		facts = append(facts, fmt.Sprintf("%s: %d changes", file, stats.FileStats[file]))
+	}
+	return facts
+}
+
+func percent(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part) / float64(total) * 100
+}
@@ -0,0 +1,153 @@
+package ai
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"git-log-analyzer/internal/analyzer"
+	"git-log-analyzer/internal/developer"
+	"git-log-analyzer/internal/health"
+)
+
+func TestEstimateTokens(t *testing.T) {
+	tests := map[string]int{
+		"":           0,
+		"abcd":       1,
+		"abcde":      2,
+		"代码健康":       4,
+		"code 健康 ok": 2 + 2, // 7 ASCII characters, 2 CJK runes
+	}
+	for s, want := range tests {
+		if got := EstimateTokens(s); got != want {
+			t.Errorf("EstimateTokens(%q) = %d, want %d", s, got, want)
+		}
+	}
+}
+
+func TestPromptBudget(t *testing.T) {
+	tests := []struct {
+		config AIConfig
+		want   int
+	}{
+		{AIConfig{Model: "gpt-3.5-turbo"}, 16385 - 2000 - promptReserve},
+		{AIConfig{Model: "gpt-4o-mini", MaxTokens: 4000}, 128000 - 4000 - promptReserve},
+		{AIConfig{Model: "gpt-4-0613"}, 8192 - 2000 - promptReserve},
+		{AIConfig{Provider: ProviderOllama}, 8192 - 2000 - promptReserve}, // llama3
+		{AIConfig{Model: "unknown-model", MaxTokens: 8000}, 512},
+		{AIConfig{Model: "gpt-4o", PromptBudget: 1500}, 1500},
+	}
+	for _, tt := range tests {
+		if got := PromptBudget(tt.config); got != tt.want {
+			t.Errorf("PromptBudget(%+v) = %d, want %d", tt.config, got, tt.want)
+		}
+	}
+}
+
+func TestSelectFacts(t *testing.T) {
+	sections := []promptSection{
+		{title: "First", items: []string{"aaaa", "bbbb"}},
+		{title: "Empty"},
+		{title: "Second", items: []string{"cccc"}},
+	}
+
+	facts, omitted := selectFacts(sections, 1000)
+	if want := "First:\n- aaaa\n- bbbb\n\nSecond:\n- cccc\n"; facts != want || omitted != 0 {
+		t.Errorf("Expected all facts, got %q (%d omitted)", facts, omitted)
+	}
+
+	// "\nFirst:\n" costs 2 tokens and each "- xxxx\n" line costs 2 tokens
+	facts, omitted = selectFacts(sections, 6)
+	if want := "First:\n- aaaa\n- bbbb\n"; facts != want || omitted != 1 {
+		t.Errorf("Expected the first section only, got %q (%d omitted)", facts, omitted)
+	}
+}
+
+// largeStats returns statistics with more facts than any budget below needs
+func largeStats() *analyzer.Statistics {
+	stats := &analyzer.Statistics{
+		TotalCommits:    500,
+		AuthorStats:     make(map[string]*analyzer.AuthorStat),
+		FileStats:       make(map[string]int),
+		CommitFrequency: make(map[string]int),
+		TimeStats: &analyzer.TimeStat{
+			FirstCommit: time.Date(2022, 1, 3, 0, 0, 0, 0, time.UTC),
+			LastCommit:  time.Date(2023, 6, 30, 0, 0, 0, 0, time.UTC),
+			ActiveDays:  300, ActiveWeeks: 70, ActiveMonths: 18,
+		},
+		CodeHealthMetrics: &health.CodeHealthMetrics{HealthScore: 0.42},
+	}
+	for i := 0; i < 20; i++ {
+		name := fmt.Sprintf("dev%02d", i)
+		stats.AuthorStats[name] = &analyzer.AuthorStat{Name: name, Email: name + "@example.com", CommitCount: 100 - i, Additions: 1000, Deletions: 100}
+		stats.FileStats[fmt.Sprintf("pkg/file%02d.go", i)] = 50 - i
+		stats.CodeHealthMetrics.TechnicalDebtHotspots = append(stats.CodeHealthMetrics.TechnicalDebtHotspots,
+			health.TechnicalDebtHotspot{FilePath: fmt.Sprintf("pkg/hot%02d.go", i), TotalChanges: 30 - i, UniqueAuthors: 4, RiskScore: 0.9, Reason: "频繁修改"})
+	}
+	for m := 1; m <= 18; m++ {
+		date := time.Date(2022, time.Month(m), 10, 0, 0, 0, 0, time.UTC)
+		stats.CommitFrequency[date.Format("2006-01-02")] = m
+	}
+	return stats
+}
+
+func TestBuildAnalysisPrompt(t *testing.T) {
+	t.Setenv("REPORT_LANGUAGE", "en")
+
+	stats := largeStats()
+	profiles := []*developer.DeveloperProfile{{Name: "dev00", Email: "dev00@example.com",
+		CodingPatterns:    developer.CodingPatterns{PreferredCommitSize: "moderate"},
+		PersonalityTraits: developer.PersonalityTraits{WorkStyleType: "steady"}}}
+
+	prompt := BuildAnalysisPrompt(AIConfig{Model: "gpt-4o"}, stats, profiles)
+	user := prompt.Messages[1].Content
+	for _, want := range []string{
+		"500 commits by 20 contributors",
+		"code health score 42/100",
+		"Technical debt hotspots:\n- pkg/hot00.go: 30 changes by 4 authors, risk 0.90 (频繁修改)",
+		"- pkg/hot09.go",
+		"last 3 active months average 17.0 commits/month vs 9.5 overall",
+		"- 2023-06: 18 commits",
+		"- dev00: 100 commits (20%), +1000/-100 lines, 0 files, moderate commits, steady work style",
+		"- pkg/file00.go: 50 changes",
+		`"confidence"`,
+	} {
+		if !strings.Contains(user, want) {
+			t.Errorf("Prompt is missing %q:\n%s", want, user)
+		}
+	}
+	// Lists are capped so one section can't crowd out the others
+	for _, unwanted := range []string{"pkg/hot10.go", "dev10", "pkg/file10.go", "2022-06: 6 commits"} {
+		if strings.Contains(user, unwanted) {
+			t.Errorf("Prompt should not contain %q", unwanted)
+		}
+	}
+	if prompt.Omitted != 0 || prompt.Tokens > prompt.Budget {
+		t.Errorf("Expected everything to fit: %d tokens, budget %d, %d omitted", prompt.Tokens, prompt.Budget, prompt.Omitted)
+	}
+	if prompt.Tokens != estimateMessages(prompt.Messages) {
+		t.Errorf("Token estimate does not match the messages")
+	}
+}
+
+func TestBuildAnalysisPrompt_Budget(t *testing.T) {
+	t.Setenv("REPORT_LANGUAGE", "en")
+
+	stats := largeStats()
+	full := BuildAnalysisPrompt(AIConfig{Model: "gpt-4o"}, stats, nil)
+	small := BuildAnalysisPrompt(AIConfig{Model: "gpt-4o", PromptBudget: full.Tokens - 150}, stats, nil)
+
+	if small.Budget != full.Tokens-150 || small.Tokens > small.Budget {
+		t.Errorf("Prompt exceeds its budget: %d tokens, budget %d", small.Tokens, small.Budget)
+	}
+	if small.Omitted == 0 {
+		t.Error("Expected facts to be omitted")
+	}
+
+	user := small.Messages[1].Content
+	// Higher priority facts survive, lower priority ones are dropped first
+	if !strings.Contains(user, "pkg/hot00.go") || strings.Contains(user, "pkg/file09.go") {
+		t.Errorf("Expected hotspots to be kept over file statistics:\n%s", user)
+	}
+}
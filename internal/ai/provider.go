@@ -0,0 +1,78 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+)
+
+// Supported provider kinds
+const (
+	ProviderOpenAI = "openai" // OpenAI-compatible chat completions API
+	ProviderOllama = "ollama" // Ollama-style local /api/chat endpoint
+	ProviderFake   = "fake"   // deterministic offline provider for tests and demos
+)
+
+// Provider sends chat requests to a language model
+type Provider interface {
+	// Name returns the provider kind, e.g. "openai"
+	Name() string
+	// Chat sends the conversation and returns the model's reply
+	Chat(ctx context.Context, req ChatRequest) (*ChatResponse, error)
+}
+
+// ChatMessage represents a chat message. Besides "system", "user" and
+// "assistant" messages, a conversation with tools contains assistant messages
+// requesting ToolCalls and "tool" messages answering them.
+type ChatMessage struct {
+	Role       string     `json:"role"`
+	Content    string     `json:"content"`
+	ToolCalls  []ToolCall `json:"tool_calls,omitempty"`
+	ToolCallID string     `json:"tool_call_id,omitempty"` // call answered by a "tool" message
+}
+
+// Tool is a function the model may call to look up data
+type Tool struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Parameters  map[string]any `json:"parameters"` // JSON Schema of the arguments object
+}
+
+// ToolCall is a function call requested by the model
+type ToolCall struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"` // JSON object
+}
+
+// ChatRequest is a provider-independent chat request
+type ChatRequest struct {
+	Model       string        `json:"model"`
+	Messages    []ChatMessage `json:"messages"`
+	MaxTokens   int64         `json:"max_tokens,omitempty"`
+	Temperature *float64      `json:"temperature,omitempty"` // nil leaves it to the provider
+	Tools       []Tool        `json:"tools,omitempty"`
+}
+
+// ChatResponse is a provider-independent chat response. A reply may consist
+// of tool calls only, in which case Content is empty.
+type ChatResponse struct {
+	Content   string     `json:"content"`
+	ToolCalls []ToolCall `json:"tool_calls,omitempty"`
+	Model     string     `json:"model"` // model reported by the provider
+	Usage     Usage      `json:"usage"`
+}
+
+// NewProvider creates the provider selected by config.Provider. The config is
+// expected to have its defaults applied (see NewAIClientWithConfig).
+func NewProvider(config AIConfig) (Provider, error) {
+	switch config.Provider {
+	case ProviderOpenAI:
+		return NewOpenAIProvider(config), nil
+	case ProviderOllama:
+		return NewOllamaProvider(config), nil
+	case ProviderFake:
+		return NewFakeProvider(), nil
+	default:
+		return nil, fmt.Errorf("unknown AI provider %q (expected %s, %s or %s)", config.Provider, ProviderOpenAI, ProviderOllama, ProviderFake)
+	}
+}
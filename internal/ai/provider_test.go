@@ -0,0 +1,205 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var testMessages = []ChatMessage{
+	{Role: "system", Content: "You are a reviewer"},
+	{Role: "user", Content: "Analyze this"},
+}
+
+func TestOpenAIProvider_Chat(t *testing.T) {
+	var got map[string]interface{}
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		auth = r.Header.Get("Authorization")
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"1","object":"chat.completion","model":"my-model-0613","choices":[{"index":0,"finish_reason":"stop","message":{"role":"assistant","content":"Looks healthy"}}],"usage":{"prompt_tokens":42,"completion_tokens":7,"total_tokens":49}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewAIClientWithConfig(AIConfig{
+		APIEndpoint: server.URL + "/v1/chat/completions",
+		APIKey:      "sk-test",
+		Model:       "my-model",
+		MaxTokens:   500,
+		Temperature: float(0.2),
+	})
+	if err != nil {
+		t.Fatalf("NewAIClientWithConfig failed: %v", err)
+	}
+
+	resp, err := client.provider.Chat(context.Background(), ChatRequest{
+		Model: "my-model", Messages: testMessages, MaxTokens: 500, Temperature: float(0.2),
+	})
+	if err != nil {
+		t.Fatalf("Chat failed: %v", err)
+	}
+	if resp.Content != "Looks healthy" || resp.Model != "my-model-0613" {
+		t.Errorf("Unexpected response %+v", resp)
+	}
+	if resp.Usage != (Usage{PromptTokens: 42, CompletionTokens: 7}) {
+		t.Errorf("Expected the reported usage, got %+v", resp.Usage)
+	}
+	if auth != "Bearer sk-test" {
+		t.Errorf("Expected the configured API key, got %q", auth)
+	}
+	if got["model"] != "my-model" || got["max_tokens"] != 500.0 || got["temperature"] != 0.2 {
+		t.Errorf("Request did not use the configured parameters: %v", got)
+	}
+	if messages, _ := got["messages"].([]interface{}); len(messages) != 2 {
+		t.Errorf("Expected 2 messages, got %v", got["messages"])
+	}
+
+	// A temperature of 0 is sent; an unset one is left to the API
+	for _, temperature := range []*float64{float(0), nil} {
+		got = nil
+		_, err = client.provider.Chat(context.Background(), ChatRequest{Model: "my-model", Messages: testMessages, Temperature: temperature})
+		if err != nil {
+			t.Fatalf("Chat failed: %v", err)
+		}
+		value, sent := got["temperature"]
+		if sent != (temperature != nil) || (sent && value != 0.0) {
+			t.Errorf("Expected temperature %v to be sent as is, got %v", temperature, got)
+		}
+		if _, ok := got["max_tokens"]; ok {
+			t.Errorf("Expected no max_tokens without a value, got %v", got)
+		}
+	}
+}
+
+func TestOpenAIProvider_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":{"message":"model not found","type":"invalid_request_error"}}`))
+	}))
+	defer server.Close()
+
+	provider := NewOpenAIProvider(WithDefaults(AIConfig{APIEndpoint: server.URL, APIKey: "sk-test"}))
+	_, err := provider.Chat(context.Background(), ChatRequest{Model: "missing", Messages: testMessages})
+	if err == nil || !strings.Contains(err.Error(), "failed to get AI response") {
+		t.Errorf("Expected an AI response error, got %v", err)
+	}
+}
+
+func TestOpenAIBaseURL(t *testing.T) {
+	tests := map[string]string{
+		"https://api.openai.com/v1/chat/completions":  "https://api.openai.com/v1/",
+		"https://api.openai.com/v1/chat/completions/": "https://api.openai.com/v1/",
+		"https://api.openai.com/v1":                   "https://api.openai.com/v1/",
+		"http://localhost:8080/v1/":                   "http://localhost:8080/v1/",
+	}
+	for endpoint, want := range tests {
+		if got := openAIBaseURL(endpoint); got != want {
+			t.Errorf("openAIBaseURL(%q) = %q, want %q", endpoint, got, want)
+		}
+	}
+}
+
+func TestOllamaProvider_Chat(t *testing.T) {
+	var got ollamaRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/chat" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Write([]byte(`{"model":"llama3:8b","message":{"role":"assistant","content":"Local answer"},"done":true,"prompt_eval_count":30,"eval_count":5}`))
+	}))
+	defer server.Close()
+
+	provider := NewOllamaProvider(WithDefaults(AIConfig{Provider: ProviderOllama, APIEndpoint: server.URL}))
+	resp, err := provider.Chat(context.Background(), ChatRequest{
+		Model: "llama3:8b", Messages: testMessages, MaxTokens: 300, Temperature: float(0.4),
+	})
+	if err != nil {
+		t.Fatalf("Chat failed: %v", err)
+	}
+	if resp.Content != "Local answer" || resp.Model != "llama3:8b" {
+		t.Errorf("Unexpected response %+v", resp)
+	}
+	if resp.Usage != (Usage{PromptTokens: 30, CompletionTokens: 5}) {
+		t.Errorf("Expected the reported usage, got %+v", resp.Usage)
+	}
+	if got.Model != "llama3:8b" || got.Stream || got.Options.NumPredict != 300 || *got.Options.Temperature != 0.4 {
+		t.Errorf("Request did not use the configured parameters: %+v", got)
+	}
+	if !reflect.DeepEqual(got.Messages, ollamaMessages(testMessages)) {
+		t.Errorf("Expected messages %v, got %v", testMessages, got.Messages)
+	}
+}
+
+func TestOllamaProvider_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error":"model \"nope\" not found"}`))
+	}))
+	defer server.Close()
+
+	provider := NewOllamaProvider(AIConfig{APIEndpoint: server.URL + "/api/chat"})
+	_, err := provider.Chat(context.Background(), ChatRequest{Model: "nope", Messages: testMessages})
+	if err == nil || !strings.Contains(err.Error(), `model "nope" not found`) {
+		t.Errorf("Expected the server error message, got %v", err)
+	}
+}
+
+func TestFakeProvider(t *testing.T) {
+	provider := NewFakeProvider("first")
+	ctx := context.Background()
+	req := ChatRequest{Model: "m", Messages: testMessages}
+
+	resp, err := provider.Chat(ctx, req)
+	if err != nil || resp.Content != "first" {
+		t.Fatalf("Expected the queued response, got %+v, %v", resp, err)
+	}
+
+	a, _ := provider.Chat(ctx, req)
+	b, _ := provider.Chat(ctx, req)
+	if a.Content != b.Content || !strings.HasPrefix(a.Content, "Fake analysis by m") {
+		t.Errorf("Expected deterministic replies, got %q and %q", a.Content, b.Content)
+	}
+	other, _ := provider.Chat(ctx, ChatRequest{Model: "m", Messages: testMessages[:1]})
+	if other.Content == a.Content {
+		t.Errorf("Different prompts should produce different replies")
+	}
+
+	if n := len(provider.Requests()); n != 4 {
+		t.Errorf("Expected 4 recorded requests, got %d", n)
+	}
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := provider.Chat(canceled, req); err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestNewProvider(t *testing.T) {
+	for kind, want := range map[string]string{
+		ProviderOpenAI: ProviderOpenAI,
+		ProviderOllama: ProviderOllama,
+		ProviderFake:   ProviderFake,
+	} {
+		provider, err := NewProvider(WithDefaults(AIConfig{Provider: kind}))
+		if err != nil {
+			t.Fatalf("NewProvider(%q) failed: %v", kind, err)
+		}
+		if provider.Name() != want {
+			t.Errorf("Expected provider %s, got %s", want, provider.Name())
+		}
+	}
+
+	if _, err := NewProvider(AIConfig{Provider: "carrier-pigeon"}); err == nil {
+		t.Error("Expected an error for an unknown provider")
+	}
+}
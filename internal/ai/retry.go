@@ -0,0 +1,78 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+// Retry defaults applied by NewAIClientWithConfig
+const (
+	DefaultMaxRetries = 3
+	DefaultRetryDelay = time.Second
+	DefaultMaxDelay   = 30 * time.Second
+)
+
+// RetryPolicy controls how retryable provider errors are retried
+type RetryPolicy struct {
+	MaxRetries int
+	BaseDelay  time.Duration // delay before the first retry, doubled each time
+	MaxDelay   time.Duration // cap of the backoff and of accepted Retry-After values
+}
+
+// delay returns how long to wait before retry number attempt (0-based), or
+// false when the server asks for a longer pause than the policy allows.
+// Retry-After is honored exactly; otherwise the exponential backoff is
+// jittered between half and the full delay so parallel runs spread out.
+func (p RetryPolicy) delay(attempt int, err *Error, jitter func() float64) (time.Duration, bool) {
+	if err.RetryAfter > 0 {
+		return err.RetryAfter, err.RetryAfter <= p.MaxDelay
+	}
+
+	d := p.BaseDelay << uint(attempt)
+	if d <= 0 || d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	return d/2 + time.Duration(jitter()*float64(d/2)), true
+}
+
+// sleepContext waits for d or until ctx is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// send calls the provider, retrying rate limits, server errors, network
+// failures and empty replies with backoff. A reply of tool calls only is
+// not empty.
+func (c *AIClient) send(ctx context.Context, req ChatRequest) (*ChatResponse, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := c.provider.Chat(ctx, req)
+		if err == nil && strings.TrimSpace(resp.Content) == "" && len(resp.ToolCalls) == 0 {
+			err = &Error{Kind: ErrorKindEmpty, Message: "AI response was empty"}
+		}
+		if err == nil {
+			return resp, nil
+		}
+
+		var aiErr *Error
+		if !errors.As(err, &aiErr) || !aiErr.Retryable() || attempt >= c.retry.MaxRetries {
+			return nil, err
+		}
+		wait, ok := c.retry.delay(attempt, aiErr, rand.Float64)
+		if !ok {
+			return nil, err
+		}
+		if err := c.sleep(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
+}
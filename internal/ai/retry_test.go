@@ -0,0 +1,206 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	tests := map[string]time.Duration{
+		"":                              0,
+		"7":                             7 * time.Second,
+		"-3":                            0,
+		"Wed, 01 May 2024 12:00:30 GMT": 30 * time.Second,
+		"Wed, 01 May 2024 11:00:00 GMT": 0,
+		"soon":                          0,
+	}
+	for value, want := range tests {
+		if got := parseRetryAfter(value, now); got != want {
+			t.Errorf("parseRetryAfter(%q) = %v, want %v", value, got, want)
+		}
+	}
+}
+
+func TestStatusError(t *testing.T) {
+	tests := []struct {
+		status    int
+		message   string
+		kind      string
+		retryable bool
+		errorType string
+	}{
+		{401, "invalid api key", ErrorKindConfig, false, ErrorTypeConfig},
+		{404, "model not found", ErrorKindConfig, false, ErrorTypeConfig},
+		{429, "rate limited", ErrorKindRateLimit, true, ErrorTypeAnalysis},
+		{429, "quota exceeded (insufficient_quota)", ErrorKindConfig, false, ErrorTypeConfig},
+		{503, "", ErrorKindServer, true, ErrorTypeAnalysis},
+		{504, "", ErrorKindTimeout, true, ErrorTypeAnalysis},
+	}
+	for _, tt := range tests {
+		err := statusError(tt.status, nil, tt.message)
+		if err.Kind != tt.kind || err.Retryable() != tt.retryable || ErrorType(err) != tt.errorType {
+			t.Errorf("statusError(%d, %q) = %s (retryable %v, type %s), want %s (%v, %s)",
+				tt.status, tt.message, err.Kind, err.Retryable(), ErrorType(err), tt.kind, tt.retryable, tt.errorType)
+		}
+	}
+
+	if ErrorType(errors.New("boom")) != ErrorTypeAnalysis {
+		t.Error("Unclassified errors should be analysis errors")
+	}
+}
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	policy := RetryPolicy{MaxRetries: 5, BaseDelay: time.Second, MaxDelay: 10 * time.Second}
+	half := func() float64 { return 0.5 }
+
+	for attempt, want := range []time.Duration{750 * time.Millisecond, 1500 * time.Millisecond, 3 * time.Second, 6 * time.Second, 7500 * time.Millisecond} {
+		if got, ok := policy.delay(attempt, &Error{Kind: ErrorKindServer}, half); !ok || got != want {
+			t.Errorf("delay(%d) = %v, %v; want %v", attempt, got, ok, want)
+		}
+	}
+	if got, ok := policy.delay(0, &Error{RetryAfter: 4 * time.Second}, half); !ok || got != 4*time.Second {
+		t.Errorf("Expected Retry-After to be honored, got %v, %v", got, ok)
+	}
+	if _, ok := policy.delay(0, &Error{RetryAfter: time.Minute}, half); ok {
+		t.Error("Retry-After beyond the maximum delay should not be retried")
+	}
+}
+
+// retryServer replies with the given handlers in order, repeating the last one
+func retryServer(t *testing.T, replies ...func(w http.ResponseWriter)) (*httptest.Server, *int) {
+	t.Helper()
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reply := replies[len(replies)-1]
+		if calls < len(replies) {
+			reply = replies[calls]
+		}
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		reply(w)
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func reply(status int, body string, header ...string) func(w http.ResponseWriter) {
+	return func(w http.ResponseWriter) {
+		for i := 0; i+1 < len(header); i += 2 {
+			w.Header().Set(header[i], header[i+1])
+		}
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}
+}
+
+const completionOK = `{"id":"1","object":"chat.completion","model":"m","choices":[{"index":0,"finish_reason":"stop","message":{"role":"assistant","content":"fine"}}]}`
+
+// retryClient returns an OpenAI client for server that records its sleeps
+func retryClient(t *testing.T, server *httptest.Server) (*AIClient, *[]time.Duration) {
+	t.Helper()
+	client, err := NewAIClientWithConfig(AIConfig{APIEndpoint: server.URL, APIKey: "sk-test", Model: "m"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sleeps []time.Duration
+	client.sleep = func(ctx context.Context, d time.Duration) error {
+		sleeps = append(sleeps, d)
+		return ctx.Err()
+	}
+	return client, &sleeps
+}
+
+func TestAIClient_RetriesTransientErrors(t *testing.T) {
+	server, calls := retryServer(t,
+		reply(429, `{"error":{"message":"slow down","type":"rate_limit"}}`, "Retry-After", "2"),
+		reply(500, `{"error":{"message":"oops"}}`),
+		reply(200, `{"id":"1","object":"chat.completion","model":"m","choices":[]}`),
+		reply(200, completionOK),
+	)
+	client, sleeps := retryClient(t, server)
+
+	got, err := client.chat(context.Background(), testMessages)
+	if err != nil || got != "fine" {
+		t.Fatalf("Expected the reply after retries, got %q, %v", got, err)
+	}
+	if *calls != 4 || len(*sleeps) != 3 {
+		t.Errorf("Expected 4 calls and 3 waits, got %d and %v", *calls, *sleeps)
+	}
+	if (*sleeps)[0] != 2*time.Second {
+		t.Errorf("Expected the first wait to follow Retry-After, got %v", (*sleeps)[0])
+	}
+	if usage := client.Usage(); usage.Calls != 1 {
+		t.Errorf("Only the successful call should be accounted, got %+v", usage)
+	}
+}
+
+func TestAIClient_GivesUp(t *testing.T) {
+	server, calls := retryServer(t, reply(503, `{"error":{"message":"unavailable"}}`))
+	client, _ := retryClient(t, server)
+
+	_, err := client.chat(context.Background(), testMessages)
+	var aiErr *Error
+	if !errors.As(err, &aiErr) || aiErr.Kind != ErrorKindServer || aiErr.StatusCode != 503 {
+		t.Fatalf("Expected a server error, got %v", err)
+	}
+	if *calls != 1+DefaultMaxRetries {
+		t.Errorf("Expected %d calls, got %d", 1+DefaultMaxRetries, *calls)
+	}
+}
+
+func TestAIClient_PermanentErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		reply     func(w http.ResponseWriter)
+		kind      string
+		errorType string
+	}{
+		{"unauthorized", reply(401, `{"error":{"message":"Incorrect API key","code":"invalid_api_key"}}`), ErrorKindConfig, ErrorTypeConfig},
+		{"content filter", reply(200, `{"id":"1","object":"chat.completion","model":"m","choices":[{"index":0,"finish_reason":"content_filter","message":{"role":"assistant","content":""}}]}`), ErrorKindContentFilter, ErrorTypeAnalysis},
+		{"refusal", reply(200, `{"id":"1","object":"chat.completion","model":"m","choices":[{"index":0,"finish_reason":"stop","message":{"role":"assistant","content":"","refusal":"I can't help"}}]}`), ErrorKindContentFilter, ErrorTypeAnalysis},
+	}
+	for _, tt := range tests {
+		server, calls := retryServer(t, tt.reply)
+		client, _ := retryClient(t, server)
+
+		_, err := client.chat(context.Background(), testMessages)
+		var aiErr *Error
+		if !errors.As(err, &aiErr) || aiErr.Kind != tt.kind || ErrorType(err) != tt.errorType {
+			t.Errorf("%s: expected a %s error, got %v", tt.name, tt.kind, err)
+		}
+		if *calls != 1 {
+			t.Errorf("%s: permanent errors should not be retried, got %d calls", tt.name, *calls)
+		}
+	}
+}
+
+func TestAIClient_RetryCanceled(t *testing.T) {
+	server, _ := retryServer(t, reply(429, `{"error":{"message":"slow down"}}`))
+	client, _ := retryClient(t, server)
+	ctx, cancel := context.WithCancel(context.Background())
+	client.sleep = func(context.Context, time.Duration) error {
+		cancel()
+		return context.Canceled
+	}
+
+	if _, err := client.chat(ctx, testMessages); err != context.Canceled {
+		t.Errorf("Expected context.Canceled while waiting, got %v", err)
+	}
+}
+
+func TestOllamaProvider_Classification(t *testing.T) {
+	server, _ := retryServer(t, reply(429, `{"error":"busy"}`, "Retry-After", "1"))
+	provider := NewOllamaProvider(WithDefaults(AIConfig{Provider: ProviderOllama, APIEndpoint: server.URL}))
+
+	_, err := provider.Chat(context.Background(), ChatRequest{Model: "m", Messages: testMessages})
+	var aiErr *Error
+	if !errors.As(err, &aiErr) || aiErr.Kind != ErrorKindRateLimit || aiErr.RetryAfter != time.Second || !strings.Contains(err.Error(), "busy") {
+		t.Errorf("Expected a rate limit error with Retry-After, got %#v", err)
+	}
+}
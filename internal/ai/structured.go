@@ -0,0 +1,170 @@
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"git-log-analyzer/internal/i18n"
+)
+
+// Risk severities, from least to most severe
+var severities = []string{"low", "medium", "high", "critical"}
+
+// StructuredAnalysis is the JSON document the model is asked to return
+type StructuredAnalysis struct {
+	Summary         string           `json:"summary"`
+	Risks           []Risk           `json:"risks"`
+	Recommendations []Recommendation `json:"recommendations"`
+	Confidence      float64          `json:"confidence"` // 0..1
+}
+
+// Risk is a problem the model found in the history
+type Risk struct {
+	Title       string `json:"title"`
+	Severity    string `json:"severity"` // low, medium, high or critical
+	Description string `json:"description"`
+}
+
+// Recommendation is an actionable suggestion, optionally tied to files and authors
+type Recommendation struct {
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	Files       []string `json:"files,omitempty"`
+	Authors     []string `json:"authors,omitempty"`
+}
+
+// AnalysisResult is the outcome of the AI step. Structured is nil when the
+// model could not produce a valid document and the free-text mode was used;
+// Text is always set (Markdown) so text reports can embed it either way.
+type AnalysisResult struct {
+	Structured *StructuredAnalysis
+	Text       string
+}
+
+// parseStructuredAnalysis extracts the JSON document from a model reply,
+// repairs what can be repaired and reports the remaining schema violations
+func parseStructuredAnalysis(reply string) (*StructuredAnalysis, []string) {
+	raw := extractJSON(reply)
+	if raw == "" {
+		return nil, []string{"the reply does not contain a JSON object"}
+	}
+
+	var analysis StructuredAnalysis
+	if err := json.Unmarshal([]byte(raw), &analysis); err != nil {
+		return nil, []string{fmt.Sprintf("invalid JSON: %v", err)}
+	}
+
+	analysis.repair()
+	if violations := analysis.validate(); len(violations) > 0 {
+		return nil, violations
+	}
+	return &analysis, nil
+}
+
+// extractJSON returns the outermost JSON object of a reply, ignoring Markdown
+// code fences and any prose around it
+func extractJSON(reply string) string {
+	start := strings.Index(reply, "{")
+	end := strings.LastIndex(reply, "}")
+	if start < 0 || end < start {
+		return ""
+	}
+	return reply[start : end+1]
+}
+
+// repair fixes harmless deviations: whitespace, severity spelling and
+// confidence given as a percentage
+func (a *StructuredAnalysis) repair() {
+	a.Summary = strings.TrimSpace(a.Summary)
+	for i := range a.Risks {
+		r := &a.Risks[i]
+		r.Title = strings.TrimSpace(r.Title)
+		r.Description = strings.TrimSpace(r.Description)
+		r.Severity = strings.ToLower(strings.TrimSpace(r.Severity))
+		if r.Severity == "moderate" {
+			r.Severity = "medium"
+		}
+	}
+	for i := range a.Recommendations {
+		r := &a.Recommendations[i]
+		r.Title = strings.TrimSpace(r.Title)
+		r.Description = strings.TrimSpace(r.Description)
+	}
+	if a.Confidence > 1 && a.Confidence <= 100 {
+		a.Confidence /= 100
+	}
+}
+
+// validate returns the schema violations of the document
+func (a *StructuredAnalysis) validate() []string {
+	var violations []string
+	if a.Summary == "" {
+		violations = append(violations, "summary must not be empty")
+	}
+	for i, r := range a.Risks {
+		if r.Title == "" {
+			violations = append(violations, fmt.Sprintf("risks[%d].title must not be empty", i))
+		}
+		if !validSeverity(r.Severity) {
+			violations = append(violations, fmt.Sprintf("risks[%d].severity must be one of %s, got %q", i, strings.Join(severities, ", "), r.Severity))
+		}
+	}
+	for i, r := range a.Recommendations {
+		if r.Title == "" {
+			violations = append(violations, fmt.Sprintf("recommendations[%d].title must not be empty", i))
+		}
+	}
+	if a.Confidence < 0 || a.Confidence > 1 {
+		violations = append(violations, fmt.Sprintf("confidence must be between 0 and 1, got %v", a.Confidence))
+	}
+	return violations
+}
+
+func validSeverity(severity string) bool {
+	for _, s := range severities {
+		if s == severity {
+			return true
+		}
+	}
+	return false
+}
+
+// Markdown renders the analysis for the text report
+func (a *StructuredAnalysis) Markdown() string {
+	msg := i18n.T()
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "## %s\n\n%s\n", msg.AISummaryTitle, a.Summary)
+
+	if len(a.Risks) > 0 {
+		fmt.Fprintf(&sb, "\n## %s\n\n", msg.AIRisksTitle)
+		for _, r := range a.Risks {
+			fmt.Fprintf(&sb, "- **[%s] %s**", strings.ToUpper(r.Severity), r.Title)
+			if r.Description != "" {
+				fmt.Fprintf(&sb, ": %s", r.Description)
+			}
+			sb.WriteString("\n")
+		}
+	}
+
+	if len(a.Recommendations) > 0 {
+		fmt.Fprintf(&sb, "\n## %s\n\n", msg.AIRecommendationsTitle)
+		for i, r := range a.Recommendations {
+			fmt.Fprintf(&sb, "%d. **%s**", i+1, r.Title)
+			if r.Description != "" {
+				fmt.Fprintf(&sb, ": %s", r.Description)
+			}
+			sb.WriteString("\n")
+			if len(r.Files) > 0 {
+				fmt.Fprintf(&sb, "   - %s: %s\n", msg.AIFilesLabel, strings.Join(r.Files, ", "))
+			}
+			if len(r.Authors) > 0 {
+				fmt.Fprintf(&sb, "   - %s: %s\n", msg.AIAuthorsLabel, strings.Join(r.Authors, ", "))
+			}
+		}
+	}
+
+	fmt.Fprintf(&sb, "\n%s: %.0f%%\n", msg.AIConfidenceLabel, a.Confidence*100)
+	return sb.String()
+}
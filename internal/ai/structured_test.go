@@ -0,0 +1,145 @@
+package ai
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+const validAnalysis = `{
+  "summary": "A small, healthy project",
+  "risks": [{"title": "Single maintainer", "severity": "high", "description": "Alice wrote most commits"}],
+  "recommendations": [{"title": "Share ownership", "description": "Pair on core files", "files": ["main.go"], "authors": ["Alice", "Bob"]}],
+  "confidence": 0.8
+}`
+
+func TestParseStructuredAnalysis(t *testing.T) {
+	fenced := "Here you go:\n```json\n" + validAnalysis + "\n```\nHope this helps!"
+	analysis, violations := parseStructuredAnalysis(fenced)
+	if analysis == nil {
+		t.Fatalf("Expected a valid analysis, got violations %v", violations)
+	}
+	if analysis.Summary != "A small, healthy project" || analysis.Confidence != 0.8 {
+		t.Errorf("Unexpected analysis %+v", analysis)
+	}
+	if len(analysis.Risks) != 1 || analysis.Risks[0].Severity != "high" {
+		t.Errorf("Unexpected risks %+v", analysis.Risks)
+	}
+	rec := analysis.Recommendations[0]
+	if len(rec.Files) != 1 || rec.Files[0] != "main.go" || len(rec.Authors) != 2 {
+		t.Errorf("Unexpected recommendation %+v", rec)
+	}
+}
+
+func TestParseStructuredAnalysis_Repairs(t *testing.T) {
+	reply := `{"summary": "  ok  ", "risks": [{"title": "Churn", "severity": " Moderate "}], "confidence": 85}`
+	analysis, violations := parseStructuredAnalysis(reply)
+	if analysis == nil {
+		t.Fatalf("Expected the reply to be repaired, got violations %v", violations)
+	}
+	if analysis.Summary != "ok" || analysis.Risks[0].Severity != "medium" || analysis.Confidence != 0.85 {
+		t.Errorf("Unexpected repaired analysis %+v", analysis)
+	}
+}
+
+func TestParseStructuredAnalysis_Violations(t *testing.T) {
+	tests := map[string]string{
+		"The project looks fine.": "does not contain a JSON object",
+		`{"summary": "x",}`:       "invalid JSON",
+		`{"summary": ""}`:         "summary must not be empty",
+		`{"summary": "x", "risks": [{"title": "t", "severity": "urgent"}]}`: `risks[0].severity must be one of low, medium, high, critical, got "urgent"`,
+		`{"summary": "x", "recommendations": [{"description": "d"}]}`:       "recommendations[0].title must not be empty",
+		`{"summary": "x", "confidence": -1}`:                                "confidence must be between 0 and 1",
+	}
+	for reply, want := range tests {
+		analysis, violations := parseStructuredAnalysis(reply)
+		if analysis != nil {
+			t.Errorf("Expected %q to be rejected", reply)
+			continue
+		}
+		if !strings.Contains(strings.Join(violations, "\n"), want) {
+			t.Errorf("Expected violation %q for %q, got %v", want, reply, violations)
+		}
+	}
+}
+
+func TestStructuredAnalysis_Markdown(t *testing.T) {
+	t.Setenv("REPORT_LANGUAGE", "en")
+
+	analysis, _ := parseStructuredAnalysis(validAnalysis)
+	text := analysis.Markdown()
+	for _, want := range []string{
+		"## Summary\n\nA small, healthy project",
+		"- **[HIGH] Single maintainer**: Alice wrote most commits",
+		"1. **Share ownership**: Pair on core files",
+		"   - Files: main.go",
+		"   - Authors: Alice, Bob",
+		"Confidence: 80%",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Markdown is missing %q:\n%s", want, text)
+		}
+	}
+}
+
+func TestAnalyzeWithAI_Structured(t *testing.T) {
+	fake := NewFakeProvider(validAnalysis)
+	client := NewAIClientWithProvider(AIConfig{}, fake)
+
+	result, err := client.AnalyzeWithAI(context.Background(), testStats(), nil)
+	if err != nil {
+		t.Fatalf("AnalyzeWithAI failed: %v", err)
+	}
+	if result.Structured == nil || result.Structured.Summary != "A small, healthy project" {
+		t.Fatalf("Expected a structured result, got %+v", result)
+	}
+	if result.Text != result.Structured.Markdown() {
+		t.Errorf("Expected the text to be the rendered analysis")
+	}
+	if n := len(fake.Requests()); n != 1 {
+		t.Errorf("Expected 1 request, got %d", n)
+	}
+}
+
+func TestAnalyzeWithAI_RepairsSchemaViolations(t *testing.T) {
+	fake := NewFakeProvider(`{"summary": "x", "risks": [{"title": "t", "severity": "urgent"}]}`, validAnalysis)
+	client := NewAIClientWithProvider(AIConfig{}, fake)
+
+	result, err := client.AnalyzeWithAI(context.Background(), testStats(), nil)
+	if err != nil {
+		t.Fatalf("AnalyzeWithAI failed: %v", err)
+	}
+	if result.Structured == nil {
+		t.Fatalf("Expected the repaired reply to be used, got %+v", result)
+	}
+
+	requests := fake.Requests()
+	if len(requests) != 2 {
+		t.Fatalf("Expected 2 requests, got %d", len(requests))
+	}
+	repair := requests[1].Messages
+	if len(repair) != 4 || repair[2].Role != "assistant" || !strings.Contains(repair[3].Content, `got "urgent"`) {
+		t.Errorf("Expected the repair request to quote the violation, got %+v", repair)
+	}
+}
+
+func TestAnalyzeWithAI_FallsBackToFreeText(t *testing.T) {
+	fake := NewFakeProvider("not json", "still not json", "## Free text analysis")
+	client := NewAIClientWithProvider(AIConfig{}, fake)
+
+	result, err := client.AnalyzeWithAI(context.Background(), testStats(), nil)
+	if err != nil {
+		t.Fatalf("AnalyzeWithAI failed: %v", err)
+	}
+	if result.Structured != nil || result.Text != "## Free text analysis" {
+		t.Errorf("Expected the free-text fallback, got %+v", result)
+	}
+
+	requests := fake.Requests()
+	if len(requests) != 3 {
+		t.Fatalf("Expected 3 requests, got %d", len(requests))
+	}
+	if last := requests[2].Messages; len(last) != 2 || strings.Contains(last[1].Content, `"confidence"`) {
+		t.Errorf("Expected the fallback to be a plain prompt, got %+v", last)
+	}
+}
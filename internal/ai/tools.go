@@ -0,0 +1,51 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+)
+
+// MaxToolRounds limits how often the model may request tool calls before
+// it has to answer
+const MaxToolRounds = 8
+
+// ToolHandler runs a tool call and returns the result passed to the model.
+// An error is reported to the model so it can correct its arguments.
+type ToolHandler func(ctx context.Context, call ToolCall) (string, error)
+
+// Converse sends the conversation together with the tools the model may call.
+// Requested calls are run with handle and their results sent back until the
+// model answers. It returns the messages added to the conversation: the tool
+// calls, their results and, last, the answer.
+func (c *AIClient) Converse(ctx context.Context, messages []ChatMessage, tools []Tool, handle ToolHandler) ([]ChatMessage, error) {
+	conversation := append([]ChatMessage(nil), messages...)
+	for round := 0; round <= MaxToolRounds; round++ {
+		// The last round offers no tools so the model has to answer
+		offered := tools
+		if round == MaxToolRounds {
+			offered = nil
+		}
+		resp, err := c.complete(ctx, conversation, offered)
+		if err != nil {
+			return nil, err
+		}
+
+		reply := ChatMessage{Role: "assistant", Content: resp.Content, ToolCalls: resp.ToolCalls}
+		conversation = append(conversation, reply)
+		if len(resp.ToolCalls) == 0 {
+			return conversation[len(messages):], nil
+		}
+
+		for _, call := range resp.ToolCalls {
+			result, err := handle(ctx, call)
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			if err != nil {
+				result = fmt.Sprintf("error: %v", err)
+			}
+			conversation = append(conversation, ChatMessage{Role: "tool", Content: result, ToolCallID: call.ID})
+		}
+	}
+	return nil, &Error{Kind: ErrorKindEmpty, Message: fmt.Sprintf("AI requested tools after %d rounds without answering", MaxToolRounds)}
+}
@@ -0,0 +1,160 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+var testTools = []Tool{
+	{Name: "authors", Description: "List authors", Parameters: map[string]any{"type": "object", "properties": map[string]any{}}},
+	{Name: "hotspots", Description: "List hotspots", Parameters: map[string]any{"type": "object", "properties": map[string]any{}}},
+}
+
+// toolConversation is a conversation in which the model called a tool
+var toolConversation = []ChatMessage{
+	{Role: "user", Content: "Who works here?"},
+	{Role: "assistant", ToolCalls: []ToolCall{{ID: "call_1", Name: "authors", Arguments: `{"limit":3}`}}},
+	{Role: "tool", Content: `[{"name":"Alice"}]`, ToolCallID: "call_1"},
+}
+
+func TestConverse(t *testing.T) {
+	provider := NewScriptedFakeProvider(
+		ChatResponse{ToolCalls: []ToolCall{
+			{ID: "a", Name: "authors", Arguments: `{"limit":3}`},
+			{ID: "b", Name: "missing", Arguments: `{}`},
+		}},
+		ChatResponse{Content: "Alice does."},
+	)
+	client := NewAIClientWithProvider(WithDefaults(AIConfig{Provider: ProviderFake, Model: "m"}), provider)
+
+	var handled []string
+	handle := func(ctx context.Context, call ToolCall) (string, error) {
+		handled = append(handled, call.Name+call.Arguments)
+		if call.Name != "authors" {
+			return "", errors.New("unknown tool")
+		}
+		return `[{"name":"Alice"}]`, nil
+	}
+
+	added, err := client.Converse(context.Background(), []ChatMessage{{Role: "user", Content: "Who works here?"}}, testTools, handle)
+	if err != nil {
+		t.Fatalf("Converse failed: %v", err)
+	}
+	if strings.Join(handled, ",") != `authors{"limit":3},missing{}` {
+		t.Errorf("Unexpected tool calls %v", handled)
+	}
+	if len(added) != 4 || added[3].Content != "Alice does." || added[2].Content != "error: unknown tool" || added[2].ToolCallID != "b" {
+		t.Errorf("Unexpected conversation %+v", added)
+	}
+
+	requests := provider.Requests()
+	if len(requests) != 2 || len(requests[0].Tools) != 2 || len(requests[1].Messages) != 4 {
+		t.Fatalf("Expected the tool results to be sent back, got %+v", requests)
+	}
+	if usage := client.Usage(); usage.Calls != 2 {
+		t.Errorf("Expected 2 calls, got %+v", usage)
+	}
+}
+
+func TestConverse_TooManyRounds(t *testing.T) {
+	var script []ChatResponse
+	for i := 0; i <= MaxToolRounds; i++ {
+		script = append(script, ChatResponse{ToolCalls: []ToolCall{{ID: "a", Name: "authors", Arguments: "{}"}}})
+	}
+	provider := NewScriptedFakeProvider(script...)
+	client := NewAIClientWithProvider(WithDefaults(AIConfig{Provider: ProviderFake, Model: "m"}), provider)
+
+	handle := func(context.Context, ToolCall) (string, error) { return "[]", nil }
+	if _, err := client.Converse(context.Background(), testMessages, testTools, handle); err == nil {
+		t.Fatal("Expected an error when the model never answers")
+	}
+	requests := provider.Requests()
+	if len(requests) != MaxToolRounds+1 || requests[MaxToolRounds].Tools != nil {
+		t.Errorf("Expected the last request to offer no tools, got %d requests", len(requests))
+	}
+}
+
+func TestFakeProvider_Tools(t *testing.T) {
+	provider := NewFakeProvider()
+	ctx := context.Background()
+
+	resp, _ := provider.Chat(ctx, ChatRequest{Model: "m", Tools: testTools, Messages: []ChatMessage{{Role: "user", Content: "Show the Hotspots and authors"}}})
+	if len(resp.ToolCalls) != 2 || resp.ToolCalls[0].Name != "authors" || resp.ToolCalls[1].Name != "hotspots" {
+		t.Errorf("Expected calls to the named tools, got %+v", resp.ToolCalls)
+	}
+
+	resp, _ = provider.Chat(ctx, ChatRequest{Model: "m", Tools: testTools, Messages: toolConversation})
+	if len(resp.ToolCalls) != 0 || !strings.HasPrefix(resp.Content, "Fake analysis by m") {
+		t.Errorf("Expected an answer after the tool results, got %+v", resp)
+	}
+}
+
+func TestOpenAIProvider_Tools(t *testing.T) {
+	var got struct {
+		Tools []struct {
+			Type     string `json:"type"`
+			Function Tool   `json:"function"`
+		} `json:"tools"`
+		Messages []map[string]any `json:"messages"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"1","object":"chat.completion","model":"m","choices":[{"index":0,"finish_reason":"tool_calls","message":{"role":"assistant","content":null,"tool_calls":[{"id":"call_9","type":"function","function":{"name":"hotspots","arguments":"{\"limit\":5}"}}]}}]}`))
+	}))
+	defer server.Close()
+
+	provider := NewOpenAIProvider(WithDefaults(AIConfig{APIEndpoint: server.URL, APIKey: "sk-test"}))
+	resp, err := provider.Chat(context.Background(), ChatRequest{Model: "m", Messages: toolConversation, Tools: testTools})
+	if err != nil {
+		t.Fatalf("Chat failed: %v", err)
+	}
+	if len(resp.ToolCalls) != 1 || resp.ToolCalls[0] != (ToolCall{ID: "call_9", Name: "hotspots", Arguments: `{"limit":5}`}) {
+		t.Errorf("Unexpected tool calls %+v", resp.ToolCalls)
+	}
+
+	if len(got.Tools) != 2 || got.Tools[0].Type != "function" || got.Tools[0].Function.Name != "authors" || got.Tools[0].Function.Parameters["type"] != "object" {
+		t.Errorf("Unexpected tools %+v", got.Tools)
+	}
+	if len(got.Messages) != 3 {
+		t.Fatalf("Expected 3 messages, got %v", got.Messages)
+	}
+	calls, _ := got.Messages[1]["tool_calls"].([]any)
+	if len(calls) != 1 || got.Messages[2]["role"] != "tool" || got.Messages[2]["tool_call_id"] != "call_1" {
+		t.Errorf("Expected the tool call and its result, got %v", got.Messages)
+	}
+}
+
+func TestOllamaProvider_Tools(t *testing.T) {
+	var got ollamaRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Write([]byte(`{"model":"llama3","message":{"role":"assistant","content":"","tool_calls":[{"function":{"name":"authors","arguments":{"limit":2}}}]},"done":true}`))
+	}))
+	defer server.Close()
+
+	provider := NewOllamaProvider(WithDefaults(AIConfig{Provider: ProviderOllama, APIEndpoint: server.URL}))
+	resp, err := provider.Chat(context.Background(), ChatRequest{Model: "llama3", Messages: toolConversation, Tools: testTools})
+	if err != nil {
+		t.Fatalf("Chat failed: %v", err)
+	}
+	if len(resp.ToolCalls) != 1 || resp.ToolCalls[0] != (ToolCall{ID: "call_1", Name: "authors", Arguments: `{"limit":2}`}) {
+		t.Errorf("Unexpected tool calls %+v", resp.ToolCalls)
+	}
+
+	if len(got.Tools) != 2 || got.Tools[1].Type != "function" || got.Tools[1].Function.Name != "hotspots" {
+		t.Errorf("Unexpected tools %+v", got.Tools)
+	}
+	call := got.Messages[1].ToolCalls
+	if len(call) != 1 || call[0].Function.Name != "authors" || string(call[0].Function.Arguments) != `{"limit":3}` {
+		t.Errorf("Expected the tool call with object arguments, got %+v", got.Messages[1])
+	}
+	if got.Messages[2].Role != "tool" || got.Messages[2].ToolName != "authors" {
+		t.Errorf("Expected the tool result to name its tool, got %+v", got.Messages[2])
+	}
+}
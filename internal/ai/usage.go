@@ -0,0 +1,98 @@
+package ai
+
+import "strings"
+
+// Usage is the token usage the provider reported for one call
+type Usage struct {
+	PromptTokens     int64 `json:"prompt_tokens"`
+	CompletionTokens int64 `json:"completion_tokens"`
+}
+
+// UsageSummary accumulates the usage and estimated cost of a client's calls.
+// Replies served from the response cache cost nothing and are only counted.
+type UsageSummary struct {
+	Calls            int // requests sent to the provider
+	CachedCalls      int // requests answered from the response cache
+	PromptTokens     int64
+	CompletionTokens int64
+	Cost             float64 // estimated, in USD
+	CostKnown        bool    // false when the model's price is unknown
+}
+
+// TotalTokens returns the prompt and completion tokens paid for
+func (s UsageSummary) TotalTokens() int64 {
+	return s.PromptTokens + s.CompletionTokens
+}
+
+// Price is the cost of a model in USD per million tokens
+type Price struct {
+	Input  float64
+	Output float64
+}
+
+// modelPrices lists list prices by model name prefix; more specific prefixes
+// come first
+var modelPrices = []struct {
+	prefix string
+	price  Price
+}{
+	{"gpt-4o-mini", Price{0.15, 0.60}},
+	{"gpt-4o", Price{2.50, 10.00}},
+	{"gpt-4.1-mini", Price{0.40, 1.60}},
+	{"gpt-4.1", Price{2.00, 8.00}},
+	{"gpt-4-turbo", Price{10.00, 30.00}},
+	{"gpt-4", Price{30.00, 60.00}},
+	{"gpt-3.5-turbo", Price{0.50, 1.50}},
+}
+
+// PriceFor returns the price used to estimate the cost of calls. Configured
+// prices win; local and fake providers are free; otherwise the model is
+// looked up in the built-in price table.
+func PriceFor(config AIConfig) (Price, bool) {
+	if config.InputPrice > 0 || config.OutputPrice > 0 {
+		return Price{config.InputPrice, config.OutputPrice}, true
+	}
+	switch config.Provider {
+	case ProviderOllama, ProviderFake:
+		return Price{}, true
+	}
+
+	model := strings.ToLower(config.Model)
+	for _, p := range modelPrices {
+		if strings.HasPrefix(model, p.prefix) {
+			return p.price, true
+		}
+	}
+	return Price{}, false
+}
+
+// Cost returns the estimated cost of usage in USD
+func (p Price) Cost(usage Usage) float64 {
+	return (float64(usage.PromptTokens)*p.Input + float64(usage.CompletionTokens)*p.Output) / 1e6
+}
+
+// record adds one call to the client's usage summary
+func (c *AIClient) record(usage Usage, cached bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if cached {
+		c.usage.CachedCalls++
+		return
+	}
+
+	c.usage.Calls++
+	c.usage.PromptTokens += usage.PromptTokens
+	c.usage.CompletionTokens += usage.CompletionTokens
+	price, _ := PriceFor(c.config)
+	c.usage.Cost += price.Cost(usage)
+}
+
+// Usage returns the usage and estimated cost of the calls made so far
+func (c *AIClient) Usage() UsageSummary {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	summary := c.usage
+	_, summary.CostKnown = PriceFor(c.config)
+	return summary
+}
@@ -0,0 +1,79 @@
+package analyzer
+
+import (
+	"sort"
+	"time"
+)
+
+// dateLayout is the format of the CommitFrequency keys
+const dateLayout = "2006-01-02"
+
+// Activity is when the commits of the repository or an author were made:
+// the weekday by hour punch card and a GitHub-style calendar of commits per
+// day
+type Activity struct {
+	PunchCard [7][24]int     `json:"punchCard"` // weekday (Sunday first) -> hour -> commits
+	Calendar  []CalendarYear `json:"calendar"`  // newest year first
+}
+
+// CalendarYear is one year of the calendar heatmap: a column per week and a
+// row per weekday, Sunday first. The first week starts on the Sunday on or
+// before January 1; days of the neighbouring years are -1.
+type CalendarYear struct {
+	Year  int      `json:"year"`
+	Start string   `json:"start"` // date of the first cell, YYYY-MM-DD
+	Weeks [][7]int `json:"weeks"`
+	Total int      `json:"total"`
+	Max   int      `json:"max"` // most commits on one day
+}
+
+// Activity returns the activity of the whole repository
+func (s *Statistics) Activity() Activity {
+	return Activity{PunchCard: s.TimeStats.PunchCard, Calendar: Calendar(s.CommitFrequency)}
+}
+
+// Activity returns the activity of the author
+func (a *AuthorStat) Activity() Activity {
+	return Activity{PunchCard: a.PunchCard, Calendar: Calendar(a.CommitFrequency)}
+}
+
+// Calendar lays the commits per day of frequency out as calendar years,
+// newest first, for every year with commits
+func Calendar(frequency map[string]int) []CalendarYear {
+	years := make(map[int]bool)
+	for date, count := range frequency {
+		if day, err := time.Parse(dateLayout, date); err == nil && count > 0 {
+			years[day.Year()] = true
+		}
+	}
+	var order []int
+	for year := range years {
+		order = append(order, year)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(order)))
+
+	calendar := make([]CalendarYear, 0, len(order))
+	for _, year := range order {
+		first := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+		start := first.AddDate(0, 0, -int(first.Weekday()))
+		cy := CalendarYear{Year: year, Start: start.Format(dateLayout)}
+		for day := start; day.Year() <= year; day = day.AddDate(0, 0, 7) {
+			var week [7]int
+			for i := range week {
+				d := day.AddDate(0, 0, i)
+				if d.Year() != year {
+					week[i] = -1
+					continue
+				}
+				week[i] = frequency[d.Format(dateLayout)]
+				cy.Total += week[i]
+				if week[i] > cy.Max {
+					cy.Max = week[i]
+				}
+			}
+			cy.Weeks = append(cy.Weeks, week)
+		}
+		calendar = append(calendar, cy)
+	}
+	return calendar
+}
@@ -0,0 +1,53 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCalendar(t *testing.T) {
+	years := Calendar(map[string]int{
+		"2024-01-01": 3,
+		"2024-12-31": 1,
+		"2023-01-01": 2,
+		"2023-06-15": 5,
+		"2021-05-05": 0,
+		"not a date": 7,
+	})
+
+	var order []int
+	for _, y := range years {
+		order = append(order, y.Year)
+	}
+	if !reflect.DeepEqual(order, []int{2024, 2023}) {
+		t.Fatalf("Expected the years with commits, newest first, got %v", order)
+	}
+
+	// 2024 begins on a Monday: the first week starts on Sunday 2023-12-31
+	y2024 := years[0]
+	if y2024.Start != "2023-12-31" || len(y2024.Weeks) != 53 {
+		t.Errorf("Unexpected 2024 layout: start %s, %d weeks", y2024.Start, len(y2024.Weeks))
+	}
+	if first := y2024.Weeks[0]; first[0] != -1 || first[1] != 3 {
+		t.Errorf("Expected the last day of 2023 padded and January 1 counted, got %v", first)
+	}
+	if last := y2024.Weeks[52]; last[2] != 1 || last[3] != -1 {
+		t.Errorf("Expected December 31 on the Tuesday of the last week, got %v", last)
+	}
+	if y2024.Total != 4 || y2024.Max != 3 {
+		t.Errorf("Expected 4 commits, at most 3 a day, got %d and %d", y2024.Total, y2024.Max)
+	}
+
+	// 2023 begins on a Sunday and ends on a Sunday
+	y2023 := years[1]
+	if y2023.Start != "2023-01-01" || len(y2023.Weeks) != 53 || y2023.Weeks[0][0] != 2 {
+		t.Errorf("Unexpected 2023 layout: start %s, %d weeks", y2023.Start, len(y2023.Weeks))
+	}
+	if y2023.Total != 7 || y2023.Max != 5 {
+		t.Errorf("Expected 7 commits, at most 5 a day, got %d and %d", y2023.Total, y2023.Max)
+	}
+
+	if Calendar(nil) == nil || len(Calendar(nil)) != 0 {
+		t.Error("Expected an empty calendar without commits")
+	}
+}
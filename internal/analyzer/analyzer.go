@@ -1,24 +1,29 @@
 package analyzer
 
 import (
+	"context"
 	"fmt"
 	"sort"
+	"strings"
 	"time"
 
 	"git-log-analyzer/internal/git"
 	"git-log-analyzer/internal/health"
 	"git-log-analyzer/internal/i18n"
+	"git-log-analyzer/internal/quality"
 )
 
 // Statistics contains analysis results
 type Statistics struct {
-	TotalCommits     int
-	AuthorStats      map[string]*AuthorStat
-	TimeStats        *TimeStat
-	FileStats        map[string]int
-	CommitFrequency  map[string]int // date -> count
+	TotalCommits      int
+	AuthorStats       map[string]*AuthorStat
+	TimeStats         *TimeStat
+	FileStats         map[string]int
+	CommitFrequency   map[string]int            // date -> count
 	CodeHealthMetrics *health.CodeHealthMetrics // 代码健康分析
-	BranchData       *BranchData // 分支数据
+	BranchData        *BranchData               // 分支数据
+	MessageQuality    *quality.MessageQuality   // 提交信息质量，仅在 Options.ScoreMessages 时计算
+	Commits           []CommitRecord            // 提交记录（从新到旧），仅在 Options.KeepCommits 时保留
 }
 
 // BranchData contains branch structure and commit relationships
@@ -65,14 +70,59 @@ type MergeInfo struct {
 
 // AuthorStat contains statistics for a single author
 type AuthorStat struct {
-	Name         string
-	Email        string
-	CommitCount  int
-	Additions    int
-	Deletions    int
-	FirstCommit  time.Time
-	LastCommit   time.Time
-	Files        map[string]int
+	Name            string
+	Email           string
+	CommitCount     int
+	Additions       int
+	Deletions       int
+	FirstCommit     time.Time
+	LastCommit      time.Time
+	Files           map[string]int
+	RecentCommits   []CommitSample // newest first, at most maxCommitSamples
+	CommitFrequency map[string]int // date -> count
+	PunchCard       [7][24]int     // weekday -> hour -> count
+}
+
+// maxCommitSamples is the number of recent commits kept per author
+const maxCommitSamples = 10
+
+// CommitSample summarizes one commit of an author
+type CommitSample struct {
+	Hash      string
+	Date      time.Time
+	Subject   string
+	Additions int
+	Deletions int
+	Files     int
+	Merge     bool
+}
+
+// CommitRecord is one commit kept by Options.KeepCommits
+type CommitRecord struct {
+	Hash      string
+	Author    string // "Name <email>", the key of Statistics.AuthorStats
+	Date      time.Time
+	Subject   string
+	Additions int
+	Deletions int
+	Files     []string
+	Merge     bool
+}
+
+// addSample keeps the sample if it is among the author's most recent commits
+func (as *AuthorStat) addSample(sample CommitSample) {
+	i := sort.Search(len(as.RecentCommits), func(i int) bool {
+		return as.RecentCommits[i].Date.Before(sample.Date)
+	})
+	if i >= maxCommitSamples {
+		return
+	}
+	as.RecentCommits = append(as.RecentCommits, CommitSample{})
+	copy(as.RecentCommits[i+1:], as.RecentCommits[i:])
+	as.RecentCommits[i] = sample
+	if len(as.RecentCommits) > maxCommitSamples {
+		as.RecentCommits = as.RecentCommits[:maxCommitSamples]
+	}
 }
 
 // TimeStat contains time-based statistics
@@ -84,32 +134,63 @@ type TimeStat struct {
 	ActiveMonths  int
 	HourlyPattern map[int]int // hour -> count
 	DailyPattern  map[time.Weekday]int
+	PunchCard     [7][24]int // weekday -> hour -> count
 }
 
 // Analyzer analyzes git commits
 type Analyzer struct {
-	repo *git.Repository
+	repo git.Backend
+	opts Options
+}
+
+// Options controls how the analyzer processes commits
+type Options struct {
+	// Jobs is the number of workers computing per-commit statistics.
+	// Values below 2 process commits sequentially.
+	Jobs int
+
+	// LowMemory streams commits from git and feeds them into the aggregators
+	// one at a time instead of loading the whole history. Branch structure
+	// and the commit graph need every commit at once and are skipped.
+	LowMemory bool
+
+	// ScoreMessages scores every commit message with the quality heuristic
+	// and fills Statistics.MessageQuality.
+	ScoreMessages bool
+
+	// KeepCommits keeps a summary of every commit with its changed files in
+	// Statistics.Commits, for queries by date, author or path.
+	KeepCommits bool
+
+	// Filter restricts the analysis to a date range, authors or paths
+	Filter Filter
 }
 
 // NewAnalyzer creates a new analyzer instance
 func NewAnalyzer(repoPath string) *Analyzer {
-	return &Analyzer{
-		repo: git.NewRepository(repoPath),
-	}
+	return NewAnalyzerWithOptions(repoPath, Options{Jobs: 1})
 }
 
-// Analyze performs comprehensive analysis of the git repository
-func (a *Analyzer) Analyze() (*Statistics, error) {
-	commits, err := a.repo.GetCommits(0) // Get all commits
-	if err != nil {
-		return nil, err
+// NewAnalyzerWithOptions creates a new analyzer instance with custom options
+func NewAnalyzerWithOptions(repoPath string, opts Options) *Analyzer {
+	return NewAnalyzerWithBackend(git.NewRepository(repoPath), opts)
+}
+
+// NewAnalyzerWithBackend creates a new analyzer reading history from backend
+func NewAnalyzerWithBackend(backend git.Backend, opts Options) *Analyzer {
+	if opts.Jobs < 1 {
+		opts.Jobs = 1
 	}
 
-	if len(commits) == 0 {
-		return nil, fmt.Errorf("no commits found in repository")
+	return &Analyzer{
+		repo: backend,
+		opts: opts,
 	}
+}
 
-	stats := &Statistics{
+// newStatistics creates an empty Statistics ready for aggregation
+func newStatistics() *Statistics {
+	return &Statistics{
 		AuthorStats:     make(map[string]*AuthorStat),
 		FileStats:       make(map[string]int),
 		CommitFrequency: make(map[string]int),
@@ -118,19 +199,59 @@ func (a *Analyzer) Analyze() (*Statistics, error) {
 			DailyPattern:  make(map[time.Weekday]int),
 		},
 	}
+}
 
-	stats.TotalCommits = len(commits)
+// Analyze performs comprehensive analysis of the git repository
+func (a *Analyzer) Analyze(ctx context.Context) (*Statistics, error) {
+	if a.opts.LowMemory {
+		return a.analyzeStream(ctx)
+	}
 
-	// Process each commit
-	for _, commit := range commits {
-		a.processCommit(&commit, stats)
+	commits, err := a.repo.GetCommits(ctx, 0) // Get all commits
+	if err != nil {
+		return nil, err
 	}
 
+	if len(commits) == 0 {
+		return nil, fmt.Errorf("no commits found in repository")
+	}
+
+	stats := newStatistics()
+
+	if !a.opts.Filter.IsZero() {
+		commits = a.opts.Filter.filterCommits(commits)
+	}
+
+	// Process each commit, writing diff statistics back into the slice.
+	// Commits outside the filter's paths are dropped afterwards.
+	matched := make([]bool, len(commits))
+	err = a.processCommits(ctx, sliceSource(commits), stats, func(index int, commit *git.GitCommit) {
+		commits[index] = *commit
+		matched[index] = true
+	})
+	if err != nil {
+		return nil, err
+	}
+	kept := commits[:0]
+	for i := range commits {
+		if matched[i] {
+			kept = append(kept, commits[i])
+		}
+	}
+	commits = kept
+	if len(commits) == 0 {
+		return nil, fmt.Errorf("no commits match the filter: %s", a.opts.Filter)
+	}
+	stats.TotalCommits = len(commits)
+
 	// Calculate time statistics
 	a.calculateTimeStats(commits, stats.TimeStats)
 
 	// Analyze branch structure
-	branchData, err := a.analyzeBranchStructure(commits)
+	branchData, err := a.analyzeBranchStructure(ctx, commits)
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
 	if err != nil {
 		// Branch analysis is optional, continue without it
 		fmt.Printf("Warning: Failed to analyze branch structure: %v\n", err)
@@ -140,27 +261,115 @@ func (a *Analyzer) Analyze() (*Statistics, error) {
 
 	// Perform code health analysis
 	healthAnalyzer := health.NewCodeHealthAnalyzer(commits)
-	stats.CodeHealthMetrics = healthAnalyzer.AnalyzeCodeHealth()
+	stats.CodeHealthMetrics, err = healthAnalyzer.AnalyzeCodeHealth(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if a.opts.ScoreMessages {
+		scorer := quality.NewMessageScorer()
+		for i := range commits {
+			scorer.AddCommit(&commits[i])
+		}
+		stats.MessageQuality = scorer.Result()
+	}
 
 	return stats, nil
 }
 
-// processCommit processes a single commit and updates statistics
-func (a *Analyzer) processCommit(commit *git.GitCommit, stats *Statistics) {
+// analyzeStream analyzes the repository without holding the commit history in
+// memory. Commits are read from a git log pipe and each one is merged into the
+// statistics, time and health aggregators before the next is read.
+func (a *Analyzer) analyzeStream(ctx context.Context) (*Statistics, error) {
+	stats := newStatistics()
+	timeAcc := newTimeAccumulator()
+	healthAnalyzer := health.NewCodeHealthAnalyzer(nil)
+	var scorer *quality.MessageScorer
+	if a.opts.ScoreMessages {
+		scorer = quality.NewMessageScorer()
+	}
+
+	source := a.streamSource(ctx)
+	if !a.opts.Filter.IsZero() {
+		source = filterSource(source, a.opts.Filter)
+	}
+	err := a.processCommits(ctx, source, stats, func(_ int, commit *git.GitCommit) {
+		stats.TotalCommits++
+		timeAcc.add(commit.Date)
+		healthAnalyzer.AddCommit(commit)
+		if scorer != nil {
+			scorer.AddCommit(commit)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if stats.TotalCommits == 0 {
+		if !a.opts.Filter.IsZero() {
+			return nil, fmt.Errorf("no commits match the filter: %s", a.opts.Filter)
+		}
+		return nil, fmt.Errorf("no commits found in repository")
+	}
+
+	timeAcc.apply(stats.TimeStats)
+
+	stats.CodeHealthMetrics, err = healthAnalyzer.AnalyzeCodeHealth(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if scorer != nil {
+		stats.MessageQuality = scorer.Result()
+	}
+
+	return stats, nil
+}
+
+// processCommit processes a single commit and updates statistics. It
+// reports whether the commit matched the path filter.
+func (a *Analyzer) processCommit(ctx context.Context, commit *git.GitCommit, stats *Statistics) bool {
+	additions, deletions, files, err := a.repo.GetCommitStats(ctx, commit.Hash)
+	return a.applyCommit(commit, stats, commitStats{
+		additions: additions,
+		deletions: deletions,
+		files:     files,
+		err:       err,
+	})
+}
+
+// applyCommit merges a commit and its diff statistics into stats, unless
+// the commit changes none of the filter's paths; it reports whether the
+// commit was merged. It must only be called from a single goroutine.
+func (a *Analyzer) applyCommit(commit *git.GitCommit, stats *Statistics, cs commitStats) bool {
+	if len(a.opts.Filter.Paths) > 0 {
+		if cs.err != nil {
+			return false
+		}
+		if cs.files = a.opts.Filter.matchingFiles(cs.files); len(cs.files) == 0 {
+			return false
+		}
+	}
+
 	authorKey := fmt.Sprintf("%s <%s>", commit.Author, commit.Email)
 	
 	// Update author statistics
 	if _, exists := stats.AuthorStats[authorKey]; !exists {
 		stats.AuthorStats[authorKey] = &AuthorStat{
-			Name:        commit.Author,
-			Email:       commit.Email,
-			FirstCommit: commit.Date,
-			LastCommit:  commit.Date,
-			Files:       make(map[string]int),
+			Name:            commit.Author,
+			Email:           commit.Email,
+			FirstCommit:     commit.Date,
+			LastCommit:      commit.Date,
+			Files:           make(map[string]int),
+			CommitFrequency: make(map[string]int),
 		}
 	}
 
 	authorStat := stats.AuthorStats[authorKey]
+	if authorStat.CommitFrequency == nil {
+		// Loaded from an analysis saved before the field existed
+		authorStat.CommitFrequency = make(map[string]int)
+	}
 	authorStat.CommitCount++
 
 	if commit.Date.Before(authorStat.FirstCommit) {
@@ -170,28 +379,54 @@ func (a *Analyzer) processCommit(commit *git.GitCommit, stats *Statistics) {
 		authorStat.LastCommit = commit.Date
 	}
 
-	// Get detailed commit statistics
-	additions, deletions, files, err := a.repo.GetCommitStats(commit.Hash)
-	if err == nil {
-		authorStat.Additions += additions
-		authorStat.Deletions += deletions
-		commit.Additions = additions
-		commit.Deletions = deletions
-		commit.Files = files
+	// Merge detailed commit statistics
+	if cs.err == nil {
+		authorStat.Additions += cs.additions
+		authorStat.Deletions += cs.deletions
+		commit.Additions = cs.additions
+		commit.Deletions = cs.deletions
+		commit.Files = cs.files
 
 		// Update file statistics
-		for _, file := range files {
+		for _, file := range cs.files {
 			stats.FileStats[file]++
 			authorStat.Files[file]++
 		}
 	}
 
+	authorStat.addSample(CommitSample{
+		Hash:      commit.Hash,
+		Date:      commit.Date,
+		Subject:   commit.Subject,
+		Additions: commit.Additions,
+		Deletions: commit.Deletions,
+		Files:     len(commit.Files),
+		Merge:     len(commit.Parents) > 1,
+	})
+
+	if a.opts.KeepCommits {
+		stats.Commits = append(stats.Commits, CommitRecord{
+			Hash:      commit.Hash,
+			Author:    authorKey,
+			Date:      commit.Date,
+			Subject:   commit.Subject,
+			Additions: commit.Additions,
+			Deletions: commit.Deletions,
+			Files:     commit.Files,
+			Merge:     len(commit.Parents) > 1,
+		})
+	}
+
 	// Update time-based statistics
 	dateKey := commit.Date.Format("2006-01-02")
 	stats.CommitFrequency[dateKey]++
 	
 	stats.TimeStats.HourlyPattern[commit.Date.Hour()]++
 	stats.TimeStats.DailyPattern[commit.Date.Weekday()]++
+	stats.TimeStats.PunchCard[commit.Date.Weekday()][commit.Date.Hour()]++
+	authorStat.CommitFrequency[dateKey]++
+	authorStat.PunchCard[commit.Date.Weekday()][commit.Date.Hour()]++
+	return true
 }
 
 // calculateTimeStats calculates time-related statistics
@@ -205,27 +440,52 @@ func (a *Analyzer) calculateTimeStats(commits []git.GitCommit, timeStats *TimeSt
 		return commits[i].Date.Before(commits[j].Date)
 	})
 
-	timeStats.FirstCommit = commits[0].Date
-	timeStats.LastCommit = commits[len(commits)-1].Date
+	acc := newTimeAccumulator()
+	for _, commit := range commits {
+		acc.add(commit.Date)
+	}
+	acc.apply(timeStats)
+}
 
-	// Calculate active periods
-	uniqueDays := make(map[string]bool)
-	uniqueWeeks := make(map[string]bool)
-	uniqueMonths := make(map[string]bool)
+// timeAccumulator incrementally collects the active period of a history
+type timeAccumulator struct {
+	first        time.Time
+	last         time.Time
+	uniqueDays   map[string]bool
+	uniqueWeeks  map[string]bool
+	uniqueMonths map[string]bool
+}
 
-	for _, commit := range commits {
-		day := commit.Date.Format("2006-01-02")
-		week := fmt.Sprintf("%d-W%02d", commit.Date.Year(), getWeekNumber(commit.Date))
-		month := commit.Date.Format("2006-01")
+// newTimeAccumulator creates an empty time accumulator
+func newTimeAccumulator() *timeAccumulator {
+	return &timeAccumulator{
+		uniqueDays:   make(map[string]bool),
+		uniqueWeeks:  make(map[string]bool),
+		uniqueMonths: make(map[string]bool),
+	}
+}
 
-		uniqueDays[day] = true
-		uniqueWeeks[week] = true
-		uniqueMonths[month] = true
+// add records a commit date
+func (acc *timeAccumulator) add(date time.Time) {
+	if acc.first.IsZero() || date.Before(acc.first) {
+		acc.first = date
+	}
+	if acc.last.IsZero() || date.After(acc.last) {
+		acc.last = date
 	}
 
-	timeStats.ActiveDays = len(uniqueDays)
-	timeStats.ActiveWeeks = len(uniqueWeeks)
-	timeStats.ActiveMonths = len(uniqueMonths)
+	acc.uniqueDays[date.Format("2006-01-02")] = true
+	acc.uniqueWeeks[fmt.Sprintf("%d-W%02d", date.Year(), getWeekNumber(date))] = true
+	acc.uniqueMonths[date.Format("2006-01")] = true
+}
+
+// apply writes the accumulated active period into timeStats
+func (acc *timeAccumulator) apply(timeStats *TimeStat) {
+	timeStats.FirstCommit = acc.first
+	timeStats.LastCommit = acc.last
+	timeStats.ActiveDays = len(acc.uniqueDays)
+	timeStats.ActiveWeeks = len(acc.uniqueWeeks)
+	timeStats.ActiveMonths = len(acc.uniqueMonths)
 }
 
 // getWeekNumber returns the week number of the year
@@ -362,11 +622,36 @@ func (stats *Statistics) GenerateReport() string {
 		}
 	}
 
+	// Add commit message quality
+	if mq := stats.MessageQuality; mq != nil && len(mq.Messages) > 0 {
+		report += "\n\n=== 提交信息质量 ===\n"
+		report += fmt.Sprintf("平均得分: %.1f (%d 条提交信息)\n\n", mq.Average, len(mq.Messages))
+
+		report += "质量最差的提交信息:\n"
+		for i, m := range mq.Worst {
+			if i >= 5 { // Top 5
+				break
+			}
+			report += fmt.Sprintf("%d. %s %q (得分: %.0f, 作者: %s)\n", i+1, shortHash(m.Hash), m.Subject, m.Score, m.Author)
+			if len(m.Issues) > 0 {
+				report += fmt.Sprintf("   问题: %s\n", strings.Join(m.Issues, ", "))
+			}
+		}
+	}
+
 	return report
 }
 
+// shortHash abbreviates a commit hash to 8 characters
+func shortHash(hash string) string {
+	if len(hash) > 8 {
+		return hash[:8]
+	}
+	return hash
+}
+
 // analyzeBranchStructure analyzes git branch structure and commit relationships
-func (a *Analyzer) analyzeBranchStructure(commits []git.GitCommit) (*BranchData, error) {
+func (a *Analyzer) analyzeBranchStructure(ctx context.Context, commits []git.GitCommit) (*BranchData, error) {
 	branchData := &BranchData{
 		Branches:      make([]BranchInfo, 0),
 		CommitGraph:   make([]CommitNode, 0),
@@ -374,7 +659,7 @@ func (a *Analyzer) analyzeBranchStructure(commits []git.GitCommit) (*BranchData,
 	}
 
 	// Get branch information from git
-	branches, err := a.repo.GetBranches()
+	branches, err := a.repo.GetBranches(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get branches: %v", err)
 	}
@@ -396,7 +681,10 @@ func (a *Analyzer) analyzeBranchStructure(commits []git.GitCommit) (*BranchData,
 		}
 
 		// Get commits for this branch
-		branchCommits, err := a.repo.GetBranchCommits(branch)
+		branchCommits, err := a.repo.GetBranchCommits(ctx, branch)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
 		if err != nil {
 			continue // Skip this branch if we can't get commits
 		}
@@ -447,7 +735,10 @@ func (a *Analyzer) analyzeBranchStructure(commits []git.GitCommit) (*BranchData,
 	}
 
 	// Build commit graph
-	branchData.CommitGraph = a.buildCommitGraph(commits, branchStats)
+	branchData.CommitGraph = a.buildCommitGraph(ctx, commits, branchStats)
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
 
 	// Analyze merge patterns
 	branchData.MergePatterns = a.analyzeMergePatterns(commits)
@@ -456,7 +747,7 @@ func (a *Analyzer) analyzeBranchStructure(commits []git.GitCommit) (*BranchData,
 }
 
 // buildCommitGraph builds a graph structure for commits
-func (a *Analyzer) buildCommitGraph(commits []git.GitCommit, branchStats map[string]*BranchInfo) []CommitNode {
+func (a *Analyzer) buildCommitGraph(ctx context.Context, commits []git.GitCommit, branchStats map[string]*BranchInfo) []CommitNode {
 	nodes := make([]CommitNode, 0, len(commits))
 	commitMap := make(map[string]*CommitNode)
 
@@ -468,7 +759,7 @@ func (a *Analyzer) buildCommitGraph(commits []git.GitCommit, branchStats map[str
 			Message:   commit.Message,
 			Author:    commit.Author,
 			Date:      commit.Date,
-			Branch:    a.getBranchForCommit(commit.Hash, branchStats),
+			Branch:    a.getBranchForCommit(ctx, commit.Hash, branchStats),
 			Parents:   commit.Parents,
 			Children:  make([]string, 0),
 			X:         0, // Will be calculated later
@@ -497,10 +788,10 @@ func (a *Analyzer) buildCommitGraph(commits []git.GitCommit, branchStats map[str
 }
 
 // getBranchForCommit determines which branch a commit belongs to
-func (a *Analyzer) getBranchForCommit(commitHash string, branchStats map[string]*BranchInfo) string {
+func (a *Analyzer) getBranchForCommit(ctx context.Context, commitHash string, branchStats map[string]*BranchInfo) string {
 	// For simplicity, we'll try to get the branch from git command
 	// In a real implementation, this would be more sophisticated
-	branch, err := a.repo.GetCommitBranch(commitHash)
+	branch, err := a.repo.GetCommitBranch(ctx, commitHash)
 	if err != nil {
 		return "unknown"
 	}
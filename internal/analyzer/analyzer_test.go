@@ -1,10 +1,18 @@
 package analyzer
 
 import (
+	"context"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
+	"git-log-analyzer/internal/fixture"
 	"git-log-analyzer/internal/git"
+	"git-log-analyzer/internal/health"
 )
 
 func TestNewAnalyzer(t *testing.T) {
@@ -12,63 +20,421 @@ func TestNewAnalyzer(t *testing.T) {
 	if analyzer == nil {
 		t.Error("NewAnalyzer should not return nil")
 	}
-	if analyzer.repo.Path != "/tmp" {
-		t.Errorf("Expected repo path '/tmp', got '%s'", analyzer.repo.Path)
+	repo, ok := analyzer.repo.(*git.Repository)
+	if !ok {
+		t.Fatalf("Expected exec-based repository backend, got %T", analyzer.repo)
 	}
+	if repo.Path != "/tmp" {
+		t.Errorf("Expected repo path '/tmp', got '%s'", repo.Path)
+	}
+}
+
+// day returns a UTC timestamp in March 2023
+func day(d, hour int) time.Time {
+	return time.Date(2023, 3, d, hour, 0, 0, 0, time.UTC)
+}
+
+// buildHistory creates a small history with a feature branch, a merge, a
+// rename, a deletion and a tag:
+//
+//	Alice  03-01 10:00  main.go +10, README.md +2
+//	Bob    03-02 14:00  util.go +5
+//	Carol  03-03 09:00  feature.go +8            (feature)
+//	Carol  03-03 16:00  feature.go +4            (feature)
+//	Alice  03-06 10:00  main.go +5
+//	Alice  03-07 11:00  merge feature            (feature.go +12 vs first parent)
+//	Bob    03-08 10:00  util.go => pkg/util.go
+//	Alice  03-08 11:00  README.md -2
+func buildHistory(t *testing.T) *fixture.Repo {
+	t.Helper()
+	return fixture.Build(t, fixture.Script{
+		fixture.Commit{ID: "initial", Author: fixture.Alice, Date: day(1, 10), Message: "Initial commit",
+			Files: map[string]string{"main.go": fixture.Lines(10), "README.md": fixture.Lines(2)}},
+		fixture.Commit{Author: fixture.Bob, Date: day(2, 14), Message: "Add util",
+			Files: map[string]string{"util.go": fixture.Lines(5)}},
+		fixture.Branch{Name: "feature"},
+		fixture.Checkout{Ref: "feature"},
+		fixture.Commit{Author: fixture.Carol, Date: day(3, 9), Message: "Add feature",
+			Files: map[string]string{"feature.go": fixture.Lines(8)}},
+		fixture.Commit{Author: fixture.Carol, Date: day(3, 16), Message: "Extend feature",
+			Files: map[string]string{"feature.go": fixture.Lines(12)}},
+		fixture.Checkout{Ref: fixture.DefaultBranch},
+		fixture.Commit{Author: fixture.Alice, Date: day(6, 10), Message: "Grow main",
+			Files: map[string]string{"main.go": fixture.Lines(15)}},
+		fixture.Merge{ID: "merge", Branch: "feature", Author: fixture.Alice, Date: day(7, 11)},
+		fixture.Commit{Author: fixture.Bob, Date: day(8, 10), Message: "Move util",
+			Rename: map[string]string{"util.go": "pkg/util.go"}},
+		fixture.Commit{Author: fixture.Alice, Date: day(8, 11), Message: "Remove readme",
+			Delete: []string{"README.md"}},
+		fixture.Tag{Name: "v1.0.0", Message: "Release 1.0.0", Author: fixture.Alice, Date: day(8, 12)},
+	})
 }
 
 func TestProcessCommit(t *testing.T) {
-	stats := &Statistics{
-		AuthorStats:     make(map[string]*AuthorStat),
-		FileStats:       make(map[string]int),
-		CommitFrequency: make(map[string]int),
-		TimeStats: &TimeStat{
-			HourlyPattern: make(map[int]int),
-			DailyPattern:  make(map[time.Weekday]int),
-		},
-	}
+	repo := buildHistory(t)
+	stats := newStatistics()
 
 	commit := &git.GitCommit{
-		Hash:    "abc123",
-		Author:  "John Doe",
-		Email:   "john@example.com",
-		Date:    time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC),
-		Subject: "Test commit",
-		Files:   []string{"file1.go", "file2.go"},
+		Hash:    repo.Hash("initial"),
+		Author:  "Alice",
+		Email:   "alice@example.com",
+		Date:    day(1, 10),
+		Subject: "Initial commit",
 	}
 
-	analyzer := NewAnalyzer(".")
-	analyzer.processCommit(commit, stats)
+	analyzer := NewAnalyzer(repo.Dir)
+	analyzer.processCommit(context.Background(), commit, stats)
 
 	// Check author stats
-	authorKey := "John Doe <john@example.com>"
-	if _, exists := stats.AuthorStats[authorKey]; !exists {
-		t.Error("Author should be added to stats")
+	authorKey := "Alice <alice@example.com>"
+	authorStat, exists := stats.AuthorStats[authorKey]
+	if !exists {
+		t.Fatal("Author should be added to stats")
 	}
-
-	authorStat := stats.AuthorStats[authorKey]
 	if authorStat.CommitCount != 1 {
 		t.Errorf("Expected commit count 1, got %d", authorStat.CommitCount)
 	}
-	if authorStat.Name != "John Doe" {
-		t.Errorf("Expected author name 'John Doe', got '%s'", authorStat.Name)
+	if authorStat.Name != "Alice" {
+		t.Errorf("Expected author name 'Alice', got '%s'", authorStat.Name)
+	}
+	if authorStat.Additions != 12 || authorStat.Deletions != 0 {
+		t.Errorf("Expected +12/-0, got +%d/-%d", authorStat.Additions, authorStat.Deletions)
 	}
 
-	// Check time stats
+	// Diff statistics are written back to the commit
+	if !reflect.DeepEqual(commit.Files, []string{"README.md", "main.go"}) {
+		t.Errorf("Expected files [README.md main.go], got %v", commit.Files)
+	}
+	if stats.FileStats["main.go"] != 1 || authorStat.Files["README.md"] != 1 {
+		t.Errorf("Unexpected file stats: %v", stats.FileStats)
+	}
+
+	// Check time stats (2023-03-01 is a Wednesday)
 	if stats.TimeStats.HourlyPattern[10] != 1 {
 		t.Errorf("Expected 1 commit at hour 10, got %d", stats.TimeStats.HourlyPattern[10])
 	}
-	if stats.TimeStats.DailyPattern[time.Sunday] != 1 {
-		t.Errorf("Expected 1 commit on Sunday, got %d", stats.TimeStats.DailyPattern[time.Sunday])
+	if stats.TimeStats.DailyPattern[time.Wednesday] != 1 {
+		t.Errorf("Expected 1 commit on Wednesday, got %d", stats.TimeStats.DailyPattern[time.Wednesday])
 	}
 
 	// Check commit frequency
-	dateKey := "2023-01-01"
+	dateKey := "2023-03-01"
 	if stats.CommitFrequency[dateKey] != 1 {
 		t.Errorf("Expected 1 commit on %s, got %d", dateKey, stats.CommitFrequency[dateKey])
 	}
 }
 
+func TestAnalyze(t *testing.T) {
+	repo := buildHistory(t)
+
+	stats, err := NewAnalyzer(repo.Dir).Analyze(context.Background())
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+
+	if stats.TotalCommits != 8 {
+		t.Errorf("Expected 8 commits, got %d", stats.TotalCommits)
+	}
+
+	expectedAuthors := map[string][3]int{ // commits, additions, deletions
+		"Alice <alice@example.com>": {4, 29, 2},
+		"Bob <bob@example.com>":     {2, 5, 0},
+		"Carol <carol@example.com>": {2, 12, 0},
+	}
+	if len(stats.AuthorStats) != len(expectedAuthors) {
+		t.Errorf("Expected %d authors, got %d", len(expectedAuthors), len(stats.AuthorStats))
+	}
+	for key, want := range expectedAuthors {
+		got := stats.AuthorStats[key]
+		if got == nil {
+			t.Errorf("Missing author %s", key)
+			continue
+		}
+		if got.CommitCount != want[0] || got.Additions != want[1] || got.Deletions != want[2] {
+			t.Errorf("%s: expected %d commits +%d/-%d, got %d commits +%d/-%d",
+				key, want[0], want[1], want[2], got.CommitCount, got.Additions, got.Deletions)
+		}
+	}
+	carol := stats.AuthorStats["Carol <carol@example.com>"]
+	if !carol.FirstCommit.Equal(day(3, 9)) || !carol.LastCommit.Equal(day(3, 16)) {
+		t.Errorf("Unexpected Carol activity range: %v - %v", carol.FirstCommit, carol.LastCommit)
+	}
+	if carol.CommitFrequency["2023-03-03"] != 2 || carol.PunchCard[time.Friday][9] != 1 || carol.PunchCard[time.Friday][16] != 1 {
+		t.Errorf("Unexpected Carol activity: %v %v", carol.CommitFrequency, carol.PunchCard[time.Friday])
+	}
+	var subjects []string
+	for _, c := range stats.AuthorStats["Alice <alice@example.com>"].RecentCommits {
+		subjects = append(subjects, c.Subject)
+	}
+	expectedSubjects := []string{"Remove readme", "Merge branch 'feature'", "Grow main", "Initial commit"}
+	if !reflect.DeepEqual(subjects, expectedSubjects) {
+		t.Errorf("Expected recent commits %v, newest first, got %v", expectedSubjects, subjects)
+	}
+
+	expectedFiles := map[string]int{
+		"main.go":                2,
+		"README.md":              2,
+		"util.go":                1,
+		"feature.go":             3,
+		"util.go => pkg/util.go": 1,
+	}
+	if !reflect.DeepEqual(stats.FileStats, expectedFiles) {
+		t.Errorf("Expected file stats %v, got %v", expectedFiles, stats.FileStats)
+	}
+
+	expectedFrequency := map[string]int{
+		"2023-03-01": 1, "2023-03-02": 1, "2023-03-03": 2,
+		"2023-03-06": 1, "2023-03-07": 1, "2023-03-08": 2,
+	}
+	if !reflect.DeepEqual(stats.CommitFrequency, expectedFrequency) {
+		t.Errorf("Expected commit frequency %v, got %v", expectedFrequency, stats.CommitFrequency)
+	}
+
+	ts := stats.TimeStats
+	if !ts.FirstCommit.Equal(day(1, 10)) || !ts.LastCommit.Equal(day(8, 11)) {
+		t.Errorf("Unexpected active period: %v - %v", ts.FirstCommit, ts.LastCommit)
+	}
+	if ts.ActiveDays != 6 || ts.ActiveWeeks != 2 || ts.ActiveMonths != 1 {
+		t.Errorf("Expected 6 days, 2 weeks, 1 month, got %d, %d, %d", ts.ActiveDays, ts.ActiveWeeks, ts.ActiveMonths)
+	}
+	expectedHours := map[int]int{9: 1, 10: 3, 11: 2, 14: 1, 16: 1}
+	if !reflect.DeepEqual(ts.HourlyPattern, expectedHours) {
+		t.Errorf("Expected hourly pattern %v, got %v", expectedHours, ts.HourlyPattern)
+	}
+	expectedDays := map[time.Weekday]int{time.Monday: 1, time.Tuesday: 1, time.Wednesday: 3, time.Thursday: 1, time.Friday: 2}
+	if !reflect.DeepEqual(ts.DailyPattern, expectedDays) {
+		t.Errorf("Expected daily pattern %v, got %v", expectedDays, ts.DailyPattern)
+	}
+	if ts.PunchCard[time.Wednesday][10] != 2 || ts.PunchCard[time.Friday][16] != 1 {
+		t.Errorf("Unexpected punch card: %v", ts.PunchCard)
+	}
+}
+
+func TestAnalyze_BranchStructure(t *testing.T) {
+	repo := buildHistory(t)
+
+	stats, err := NewAnalyzer(repo.Dir).Analyze(context.Background())
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if stats.BranchData == nil {
+		t.Fatal("Expected branch data")
+	}
+
+	counts := make(map[string]int)
+	for _, branch := range stats.BranchData.Branches {
+		counts[branch.Name] = branch.CommitCount
+	}
+	expected := map[string]int{"main": 8, "feature": 4}
+	if !reflect.DeepEqual(counts, expected) {
+		t.Errorf("Expected branch commit counts %v, got %v", expected, counts)
+	}
+
+	if len(stats.BranchData.MergePatterns) != 1 {
+		t.Fatalf("Expected 1 merge, got %d", len(stats.BranchData.MergePatterns))
+	}
+	merge := stats.BranchData.MergePatterns[0]
+	if merge.MergeCommit != repo.Hash("merge") || merge.Author != "Alice" {
+		t.Errorf("Unexpected merge info: %+v", merge)
+	}
+
+	if len(stats.BranchData.CommitGraph) != 8 {
+		t.Errorf("Expected 8 commit graph nodes, got %d", len(stats.BranchData.CommitGraph))
+	}
+	merges := 0
+	for _, node := range stats.BranchData.CommitGraph {
+		if node.IsMerge {
+			merges++
+		}
+	}
+	if merges != 1 {
+		t.Errorf("Expected 1 merge node, got %d", merges)
+	}
+}
+
+func TestAnalyze_CodeHealth(t *testing.T) {
+	repo := buildHistory(t)
+
+	stats, err := NewAnalyzer(repo.Dir).Analyze(context.Background())
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+
+	metrics := stats.CodeHealthMetrics
+	if metrics == nil {
+		t.Fatal("Expected code health metrics")
+	}
+
+	// feature.go has 3 changes by 2 authors: risk 0.09 + 0.16 = 0.25, below the 0.3 threshold
+	if len(metrics.TechnicalDebtHotspots) != 0 {
+		t.Errorf("Expected no hotspots, got %+v", metrics.TechnicalDebtHotspots)
+	}
+	// Every file holds more than 10% of the 9 file changes
+	if len(metrics.CodeConcentrationIssues) != 5 {
+		t.Errorf("Expected 5 concentration issues, got %d", len(metrics.CodeConcentrationIssues))
+	}
+	if top := metrics.CodeConcentrationIssues[0]; top.FilePath != "feature.go" || top.ConcentrationLevel != health.ConcentrationSevere {
+		t.Errorf("Expected feature.go to be the most concentrated file, got %+v", top)
+	}
+	if len(metrics.RefactoringSignals) != 0 {
+		t.Errorf("Expected no recent refactoring signals, got %d", len(metrics.RefactoringSignals))
+	}
+	if len(metrics.StabilityIndicators) != 3 {
+		t.Errorf("Expected 3 stability indicators, got %d", len(metrics.StabilityIndicators))
+	}
+	if metrics.HealthScore != 0.5 {
+		t.Errorf("Expected health score 0.5, got %.2f", metrics.HealthScore)
+	}
+}
+
+func TestAnalyze_ScoreMessages(t *testing.T) {
+	repo := buildHistory(t)
+
+	for _, lowMemory := range []bool{false, true} {
+		a := NewAnalyzerWithOptions(repo.Dir, Options{LowMemory: lowMemory, ScoreMessages: true})
+		stats, err := a.Analyze(context.Background())
+		if err != nil {
+			t.Fatalf("Analyze (low memory %v) failed: %v", lowMemory, err)
+		}
+
+		mq := stats.MessageQuality
+		if mq == nil {
+			t.Fatalf("Expected message quality (low memory %v)", lowMemory)
+		}
+		// The merge commit is skipped; "Add util", "Grow main" and "Move util"
+		// are too short (45), the other four score 65
+		if len(mq.Messages) != 7 {
+			t.Errorf("Expected 7 scored messages, got %d", len(mq.Messages))
+		}
+		if math.Abs(mq.Average-395.0/7) > 0.001 {
+			t.Errorf("Expected average %.2f, got %.2f", 395.0/7, mq.Average)
+		}
+		if got := mq.AuthorScores["Bob <bob@example.com>"]; got != 45 {
+			t.Errorf("Expected Bob to score 45, got %.2f", got)
+		}
+		if got := mq.AuthorScores["Carol <carol@example.com>"]; got != 65 {
+			t.Errorf("Expected Carol to score 65, got %.2f", got)
+		}
+		if worst := mq.Worst[0]; worst.Score != 45 || len(worst.Issues) == 0 {
+			t.Errorf("Expected a 45-point message with issues first, got %+v", worst)
+		}
+		if report := stats.GenerateReport(); !contains(report, "=== 提交信息质量 ===") || !contains(report, "标题过短") {
+			t.Errorf("Expected the worst messages in the text report, got:\n%s", report)
+		}
+	}
+
+	stats, err := NewAnalyzer(repo.Dir).Analyze(context.Background())
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if stats.MessageQuality != nil {
+		t.Error("Messages should only be scored when requested")
+	}
+}
+
+func TestAnalyze_KeepCommits(t *testing.T) {
+	repo := buildHistory(t)
+
+	for _, lowMemory := range []bool{false, true} {
+		stats, err := NewAnalyzerWithOptions(repo.Dir, Options{LowMemory: lowMemory, KeepCommits: true}).Analyze(context.Background())
+		if err != nil {
+			t.Fatalf("Analyze (low memory %v) failed: %v", lowMemory, err)
+		}
+		if len(stats.Commits) != 8 {
+			t.Fatalf("Expected 8 commit records (low memory %v), got %d", lowMemory, len(stats.Commits))
+		}
+
+		latest := stats.Commits[0]
+		if latest.Subject != "Remove readme" || latest.Author != "Alice <alice@example.com>" || !latest.Date.Equal(day(8, 11)) {
+			t.Errorf("Expected the newest commit first, got %+v", latest)
+		}
+		if !reflect.DeepEqual(latest.Files, []string{"README.md"}) || latest.Deletions != 2 {
+			t.Errorf("Expected the changed files and lines, got %+v", latest)
+		}
+		merges := 0
+		for _, c := range stats.Commits {
+			if c.Merge {
+				merges++
+			}
+		}
+		if merges != 1 {
+			t.Errorf("Expected one merge commit, got %d", merges)
+		}
+	}
+
+	stats, err := NewAnalyzer(repo.Dir).Analyze(context.Background())
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if stats.Commits != nil {
+		t.Error("Commits should only be kept when requested")
+	}
+}
+
+func TestAnalyze_Filter(t *testing.T) {
+	repo := buildHistory(t)
+
+	for _, opts := range []Options{{Jobs: 1}, {Jobs: 4}, {LowMemory: true}} {
+		opts.Filter = Filter{Since: day(3, 0), Until: day(8, 0), Authors: []string{"CAROL", "alice@"}}
+		stats, err := NewAnalyzerWithOptions(repo.Dir, opts).Analyze(context.Background())
+		if err != nil {
+			t.Fatalf("Analyze (%+v) failed: %v", opts, err)
+		}
+		// Carol's two feature commits, "Grow main" and the merge
+		if stats.TotalCommits != 4 || len(stats.AuthorStats) != 2 || stats.AuthorStats["Bob <bob@example.com>"] != nil {
+			t.Errorf("Expected 4 commits by Alice and Carol (%+v), got %d by %d authors", opts, stats.TotalCommits, len(stats.AuthorStats))
+		}
+		if !stats.TimeStats.FirstCommit.Equal(day(3, 9)) || !stats.TimeStats.LastCommit.Equal(day(7, 11)) {
+			t.Errorf("Unexpected period %v to %v", stats.TimeStats.FirstCommit, stats.TimeStats.LastCommit)
+		}
+
+		opts.Filter = Filter{Paths: []string{"feature.go/"}}
+		stats, err = NewAnalyzerWithOptions(repo.Dir, opts).Analyze(context.Background())
+		if err != nil {
+			t.Fatalf("Analyze (%+v) failed: %v", opts, err)
+		}
+		if !reflect.DeepEqual(stats.FileStats, map[string]int{"feature.go": 3}) || stats.TotalCommits != 3 {
+			t.Errorf("Expected only the feature.go changes (%+v), got %d commits: %v", opts, stats.TotalCommits, stats.FileStats)
+		}
+		if alice := stats.AuthorStats["Alice <alice@example.com>"]; alice == nil || alice.CommitCount != 1 {
+			t.Errorf("Expected the merge to count for Alice, got %+v", alice)
+		}
+	}
+
+	_, err := NewAnalyzerWithOptions(repo.Dir, Options{Filter: Filter{Authors: []string{"dave"}}}).Analyze(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "no commits match the filter: authors dave") {
+		t.Errorf("Expected an error for a filter without commits, got %v", err)
+	}
+}
+
+func TestSaveStatistics(t *testing.T) {
+	repo := buildHistory(t)
+	stats, err := NewAnalyzerWithOptions(repo.Dir, Options{KeepCommits: true}).Analyze(context.Background())
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "cache", "analysis.json")
+	if err := SaveStatistics(path, stats); err != nil {
+		t.Fatalf("SaveStatistics failed: %v", err)
+	}
+	loaded, err := LoadStatistics(path)
+	if err != nil {
+		t.Fatalf("LoadStatistics failed: %v", err)
+	}
+	if loaded.TotalCommits != 8 || len(loaded.Commits) != 8 || loaded.TimeStats.DailyPattern[time.Wednesday] != 3 {
+		t.Errorf("Expected the saved statistics back, got %+v", loaded)
+	}
+	if !reflect.DeepEqual(loaded.AuthorStats["Bob <bob@example.com>"].Files, stats.AuthorStats["Bob <bob@example.com>"].Files) {
+		t.Error("Expected the author file counts to be restored")
+	}
+
+	os.WriteFile(path, []byte(`{"version": 99}`), 0644)
+	if _, err := LoadStatistics(path); err == nil {
+		t.Error("Expected an error for an unsupported version")
+	}
+}
+
 func TestGetWeekNumber(t *testing.T) {
 	// Test week number calculation
 	testDate := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
@@ -79,6 +445,8 @@ func TestGetWeekNumber(t *testing.T) {
 }
 
 func TestGenerateReport(t *testing.T) {
+	t.Setenv("REPORT_LANGUAGE", "en")
+
 	stats := &Statistics{
 		TotalCommits:    10,
 		AuthorStats:     make(map[string]*AuthorStat),
@@ -113,11 +481,11 @@ func TestGenerateReport(t *testing.T) {
 
 	// Check that report contains expected sections
 	expectedSections := []string{
-		"=== Git Repository Analysis Report ===",
+		"Git Repository Analysis Report",
 		"Total Commits: 10",
-		"=== Top Contributors ===",
-		"=== Most Active Hours ===",
-		"=== Most Modified Files ===",
+		"Top Contributors",
+		"Most Active Hours",
+		"Most Modified Files",
 	}
 
 	for _, section := range expectedSections {
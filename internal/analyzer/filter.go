@@ -0,0 +1,114 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"git-log-analyzer/internal/git"
+)
+
+// Filter restricts the analysis to part of the history. The zero value
+// matches every commit.
+type Filter struct {
+	Since time.Time // commits on or after, inclusive
+	Until time.Time // commits before, exclusive
+
+	// Authors keeps commits whose "Name <email>" contains any of the values,
+	// ignoring case
+	Authors []string
+
+	// Paths keeps commits that change a file under any of the path prefixes
+	// and only counts those files. Line counts stay per commit since diff
+	// statistics aren't split by file.
+	Paths []string
+}
+
+// IsZero reports whether the filter matches every commit
+func (f Filter) IsZero() bool {
+	return f.Since.IsZero() && f.Until.IsZero() && len(f.Authors) == 0 && len(f.Paths) == 0
+}
+
+// String describes the filter in a line, e.g. for logs
+func (f Filter) String() string {
+	if f.IsZero() {
+		return "all commits"
+	}
+	var parts []string
+	if !f.Since.IsZero() {
+		parts = append(parts, "since "+f.Since.Format("2006-01-02"))
+	}
+	if !f.Until.IsZero() {
+		parts = append(parts, "until "+f.Until.Format("2006-01-02"))
+	}
+	if len(f.Authors) > 0 {
+		parts = append(parts, "authors "+strings.Join(f.Authors, ", "))
+	}
+	if len(f.Paths) > 0 {
+		parts = append(parts, "paths "+strings.Join(f.Paths, ", "))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// matchesCommit checks the date and author of a commit
+func (f Filter) matchesCommit(commit *git.GitCommit) bool {
+	if !f.Since.IsZero() && commit.Date.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && !commit.Date.Before(f.Until) {
+		return false
+	}
+	if len(f.Authors) == 0 {
+		return true
+	}
+	author := strings.ToLower(fmt.Sprintf("%s <%s>", commit.Author, commit.Email))
+	for _, a := range f.Authors {
+		if a = strings.ToLower(strings.TrimSpace(a)); a != "" && strings.Contains(author, a) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchingFiles returns the files under the filter's paths, or all files
+// without path filter
+func (f Filter) matchingFiles(files []string) []string {
+	if len(f.Paths) == 0 {
+		return files
+	}
+	var matched []string
+	for _, file := range files {
+		for _, p := range f.Paths {
+			p = strings.Trim(strings.TrimSpace(p), "/")
+			if p == "" || file == p || strings.HasPrefix(file, p+"/") {
+				matched = append(matched, file)
+				break
+			}
+		}
+	}
+	return matched
+}
+
+// filterCommits returns the commits matching the filter's dates and authors
+func (f Filter) filterCommits(commits []git.GitCommit) []git.GitCommit {
+	var matched []git.GitCommit
+	for i := range commits {
+		if f.matchesCommit(&commits[i]) {
+			matched = append(matched, commits[i])
+		}
+	}
+	return matched
+}
+
+// filterSource drops the commits of source that don't match the filter's
+// dates and authors before their diff statistics are computed
+func filterSource(source commitSource, f Filter) commitSource {
+	return func(emit func(git.GitCommit) error) error {
+		return source(func(commit git.GitCommit) error {
+			if !f.matchesCommit(&commit) {
+				return nil
+			}
+			return emit(commit)
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+
+	"git-log-analyzer/internal/git"
+	"git-log-analyzer/internal/health"
+	"git-log-analyzer/internal/quality"
+)
+
+// Incremental is an analysis of HEAD that can be brought up to date with the
+// commits added since the last update instead of reading the whole history
+// again. Like the low-memory mode it keeps only aggregates, so the branch
+// structure is skipped.
+type Incremental struct {
+	a *Analyzer
+
+	stats   *Statistics
+	timeAcc *timeAccumulator
+	health  *health.CodeHealthAnalyzer
+	scorer  *quality.MessageScorer
+	head    string
+}
+
+// Incremental creates an empty incremental analysis with the analyzer's
+// options; the first Update reads the whole history
+func (a *Analyzer) Incremental() *Incremental {
+	inc := &Incremental{a: a}
+	inc.reset()
+	return inc
+}
+
+// reset discards the analysis so the next Update starts over
+func (inc *Incremental) reset() {
+	inc.stats = newStatistics()
+	inc.timeAcc = newTimeAccumulator()
+	inc.health = health.NewCodeHealthAnalyzer(nil)
+	inc.scorer = nil
+	if inc.a.opts.ScoreMessages {
+		inc.scorer = quality.NewMessageScorer()
+	}
+	inc.head = ""
+}
+
+// Head returns the commit the analysis is up to date with
+func (inc *Incremental) Head() string {
+	return inc.head
+}
+
+// Statistics returns the current analysis. It is updated in place by the
+// next Update.
+func (inc *Incremental) Statistics() *Statistics {
+	return inc.stats
+}
+
+// Update analyzes the commits added to HEAD since the last update and
+// returns how many were merged into the statistics. When HEAD no longer
+// contains the analyzed commit, e.g. after a rebase, reset or checkout of
+// another branch, the whole history is analyzed again and rebuilt is true.
+// On error the analysis is discarded and the next Update starts over.
+func (inc *Incremental) Update(ctx context.Context) (added int, rebuilt bool, err error) {
+	latest, err := inc.a.repo.GetCommits(ctx, 1)
+	if err != nil {
+		return 0, false, err
+	}
+	if len(latest) == 0 {
+		return 0, false, fmt.Errorf("no commits found in repository")
+	}
+	head := latest[0].Hash
+	if head == inc.head {
+		return 0, false, nil
+	}
+
+	from := inc.head
+	if from != "" {
+		// Commits of the old head missing from HEAD mean history was rewritten
+		dropped, err := inc.a.repo.GetRangeCommits(ctx, head, from)
+		if err != nil || len(dropped) > 0 {
+			inc.reset()
+			from = ""
+			rebuilt = true
+		}
+	}
+
+	commits, err := inc.a.repo.GetRangeCommits(ctx, from, head)
+	if err != nil {
+		inc.reset()
+		return 0, rebuilt, err
+	}
+
+	added, err = inc.apply(ctx, commits)
+	if err != nil {
+		inc.reset()
+		return 0, rebuilt, err
+	}
+	inc.head = head
+
+	if inc.stats.TotalCommits == 0 {
+		if !inc.a.opts.Filter.IsZero() {
+			return added, rebuilt, fmt.Errorf("no commits match the filter: %s", inc.a.opts.Filter)
+		}
+		return added, rebuilt, fmt.Errorf("no commits found in repository")
+	}
+	return added, rebuilt, nil
+}
+
+// apply merges new commits, newest first, into the aggregates and
+// recomputes the derived statistics
+func (inc *Incremental) apply(ctx context.Context, commits []git.GitCommit) (int, error) {
+	stats := inc.stats
+
+	// New commits go before the kept ones, which are newest first too
+	previous := stats.Commits
+	stats.Commits = nil
+
+	source := sliceSource(commits)
+	if !inc.a.opts.Filter.IsZero() {
+		source = filterSource(source, inc.a.opts.Filter)
+	}
+	var fresh []git.GitCommit
+	err := inc.a.processCommits(ctx, source, stats, func(_ int, commit *git.GitCommit) {
+		fresh = append(fresh, *commit)
+		inc.timeAcc.add(commit.Date)
+		if inc.scorer != nil {
+			inc.scorer.AddCommit(commit)
+		}
+	})
+	stats.Commits = append(stats.Commits, previous...)
+	if err != nil {
+		return 0, err
+	}
+	// The health analysis measures the intervals from the ends of the known
+	// history, so the new commits continue it oldest first
+	for i := len(fresh) - 1; i >= 0; i-- {
+		inc.health.AddCommit(&fresh[i])
+	}
+	added := len(fresh)
+	stats.TotalCommits += added
+	if stats.TotalCommits == 0 {
+		return 0, nil
+	}
+
+	inc.timeAcc.apply(stats.TimeStats)
+	stats.CodeHealthMetrics, err = inc.health.AnalyzeCodeHealth(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if inc.scorer != nil {
+		stats.MessageQuality = inc.scorer.Result()
+	}
+	return added, nil
+}
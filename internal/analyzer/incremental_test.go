@@ -0,0 +1,132 @@
+package analyzer
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"git-log-analyzer/internal/fixture"
+)
+
+// assertMatchesFullAnalysis compares an incremental analysis with a fresh
+// low-memory analysis of the same repository
+func assertMatchesFullAnalysis(t *testing.T, dir string, inc *Incremental) {
+	t.Helper()
+	full, err := NewAnalyzerWithOptions(dir, Options{LowMemory: true, KeepCommits: true}).Analyze(context.Background())
+	if err != nil {
+		t.Fatalf("Full analysis failed: %v", err)
+	}
+
+	stats := inc.Statistics()
+	if stats.TotalCommits != full.TotalCommits {
+		t.Errorf("Total commits differ: %d vs %d", stats.TotalCommits, full.TotalCommits)
+	}
+	if !reflect.DeepEqual(stats.AuthorStats, full.AuthorStats) {
+		t.Error("Author statistics differ")
+	}
+	if !reflect.DeepEqual(stats.FileStats, full.FileStats) {
+		t.Errorf("File statistics differ: %v vs %v", stats.FileStats, full.FileStats)
+	}
+	if !reflect.DeepEqual(stats.TimeStats, full.TimeStats) {
+		t.Errorf("Time statistics differ: %+v vs %+v", stats.TimeStats, full.TimeStats)
+	}
+	if !reflect.DeepEqual(stats.CodeHealthMetrics, full.CodeHealthMetrics) {
+		t.Error("Code health differs")
+	}
+	if !reflect.DeepEqual(stats.Commits, full.Commits) {
+		t.Error("Kept commits differ")
+	}
+}
+
+func TestIncremental_Update(t *testing.T) {
+	repo := buildHistory(t)
+	inc := NewAnalyzerWithOptions(repo.Dir, Options{KeepCommits: true}).Incremental()
+
+	added, rebuilt, err := inc.Update(context.Background())
+	if err != nil {
+		t.Fatalf("First update failed: %v", err)
+	}
+	if added != 8 || rebuilt {
+		t.Errorf("Expected 8 commits from the first update, got %d (rebuilt %v)", added, rebuilt)
+	}
+	assertMatchesFullAnalysis(t, repo.Dir, inc)
+
+	// Nothing new
+	if added, _, err := inc.Update(context.Background()); err != nil || added != 0 {
+		t.Errorf("Expected no new commits, got %d: %v", added, err)
+	}
+
+	repo.Apply(t, fixture.Script{
+		fixture.Commit{Author: fixture.Bob, Date: day(9, 15), Message: "Grow util",
+			Files: map[string]string{"pkg/util.go": fixture.Lines(9)}},
+		fixture.Commit{ID: "latest", Author: fixture.Carol, Date: day(10, 9), Message: "Add docs",
+			Files: map[string]string{"docs/guide.md": fixture.Lines(4)}},
+	})
+	added, rebuilt, err = inc.Update(context.Background())
+	if err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if added != 2 || rebuilt {
+		t.Errorf("Expected 2 new commits, got %d (rebuilt %v)", added, rebuilt)
+	}
+	if inc.Head() != repo.Hash("latest") {
+		t.Errorf("Expected head %s, got %s", repo.Hash("latest"), inc.Head())
+	}
+	assertMatchesFullAnalysis(t, repo.Dir, inc)
+}
+
+func TestIncremental_IrregularIntervals(t *testing.T) {
+	repo := buildHistory(t)
+	repo.Apply(t, irregularCommits(9, 10, 14))
+	inc := NewAnalyzerWithOptions(repo.Dir, Options{KeepCommits: true}).Incremental()
+
+	if _, _, err := inc.Update(context.Background()); err != nil {
+		t.Fatalf("First update failed: %v", err)
+	}
+	repo.Apply(t, irregularCommits(15, 22, 29, 30))
+	added, rebuilt, err := inc.Update(context.Background())
+	if err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if added != 4 || rebuilt {
+		t.Errorf("Expected 4 new commits, got %d (rebuilt %v)", added, rebuilt)
+	}
+
+	full, err := NewAnalyzerWithOptions(repo.Dir, Options{}).Analyze(context.Background())
+	if err != nil {
+		t.Fatalf("Full analysis failed: %v", err)
+	}
+	got := inc.Statistics().CodeHealthMetrics.StabilityIndicators
+	if want := full.CodeHealthMetrics.StabilityIndicators; !reflect.DeepEqual(got, want) {
+		t.Errorf("Stability indicators differ: %+v vs %+v", got, want)
+	}
+	assertMatchesFullAnalysis(t, repo.Dir, inc)
+}
+
+func TestIncremental_Rewrite(t *testing.T) {
+	repo := buildHistory(t)
+	inc := NewAnalyzerWithOptions(repo.Dir, Options{KeepCommits: true}).Incremental()
+	if _, _, err := inc.Update(context.Background()); err != nil {
+		t.Fatalf("First update failed: %v", err)
+	}
+
+	// Moving HEAD back drops commits, so the analysis starts over
+	repo.Apply(t, fixture.Script{fixture.Checkout{Ref: repo.Hash("merge")}})
+	added, rebuilt, err := inc.Update(context.Background())
+	if err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if !rebuilt || added != 6 {
+		t.Errorf("Expected a rebuild with 6 commits, got %d (rebuilt %v)", added, rebuilt)
+	}
+	assertMatchesFullAnalysis(t, repo.Dir, inc)
+}
+
+func TestIncremental_Filter(t *testing.T) {
+	repo := buildHistory(t)
+	inc := NewAnalyzerWithOptions(repo.Dir, Options{Filter: Filter{Authors: []string{"dave"}}}).Incremental()
+
+	if _, _, err := inc.Update(context.Background()); err == nil {
+		t.Error("Expected an error when no commit matches the filter")
+	}
+}
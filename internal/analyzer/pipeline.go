@@ -0,0 +1,170 @@
+package analyzer
+
+import (
+	"context"
+	"sync"
+
+	"git-log-analyzer/internal/git"
+)
+
+// commitSource produces commits one at a time by calling emit. It stops and
+// returns the error if emit fails.
+type commitSource func(emit func(git.GitCommit) error) error
+
+// commitSink receives each commit after its diff statistics have been merged.
+// index is the commit's position in the source; sinks are called in source
+// order and always from the reducer goroutine.
+type commitSink func(index int, commit *git.GitCommit)
+
+// sliceSource produces commits from an in-memory slice
+func sliceSource(commits []git.GitCommit) commitSource {
+	return func(emit func(git.GitCommit) error) error {
+		for _, commit := range commits {
+			if err := emit(commit); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// streamSource produces commits straight from a git log pipe
+func (a *Analyzer) streamSource(ctx context.Context) commitSource {
+	return func(emit func(git.GitCommit) error) error {
+		return a.repo.StreamCommits(ctx, 0, emit)
+	}
+}
+
+// commitStats holds the diff statistics computed for a single commit
+type commitStats struct {
+	additions int
+	deletions int
+	files     []string
+	err       error
+}
+
+// reorderWindow is how many commits per worker the parallel path may hand
+// out ahead of the oldest one not yet merged
+const reorderWindow = 16
+
+// commitJob is a unit of work handed from the producer to the workers
+type commitJob struct {
+	index  int
+	commit git.GitCommit
+}
+
+// commitResult is a worker's output, merged into Statistics by the reducer
+type commitResult struct {
+	index  int
+	commit git.GitCommit
+	stats  commitStats
+}
+
+// processCommits computes diff statistics for every commit from source, merges
+// them into stats and hands each commit to sink
+func (a *Analyzer) processCommits(ctx context.Context, source commitSource, stats *Statistics, sink commitSink) error {
+	if a.opts.Jobs > 1 {
+		return a.processCommitsParallel(ctx, source, stats, sink)
+	}
+
+	index := 0
+	return source(func(commit git.GitCommit) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if a.processCommit(ctx, &commit, stats) {
+			sink(index, &commit)
+		}
+		index++
+		return nil
+	})
+}
+
+// processCommitsParallel computes per-commit statistics with a bounded pool of
+// workers. A producer streams commits to the workers and a single reducer (the
+// calling goroutine) merges results into stats, so the aggregation itself never
+// runs concurrently. Results are merged and handed to sink in source order,
+// so order-sensitive consumers such as the kept commits and the modification
+// gaps of the health analysis see the same sequence as on the sequential
+// path. At most reorderWindow results per worker wait for a slower one.
+func (a *Analyzer) processCommitsParallel(ctx context.Context, source commitSource, stats *Statistics, sink commitSink) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	jobs := make(chan commitJob)
+	results := make(chan commitResult, a.opts.Jobs)
+	sourceErr := make(chan error, 1)
+	// A slot is taken per commit handed out and released once it is merged
+	window := make(chan struct{}, a.opts.Jobs*reorderWindow)
+
+	// Producer
+	go func() {
+		defer close(jobs)
+		index := 0
+		sourceErr <- source(func(commit git.GitCommit) error {
+			select {
+			case window <- struct{}{}:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			select {
+			case jobs <- commitJob{index: index, commit: commit}:
+				index++
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+	}()
+
+	// Workers
+	var wg sync.WaitGroup
+	for w := 0; w < a.opts.Jobs; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				additions, deletions, files, err := a.repo.GetCommitStats(ctx, job.commit.Hash)
+				result := commitResult{
+					index:  job.index,
+					commit: job.commit,
+					stats:  commitStats{additions: additions, deletions: deletions, files: files, err: err},
+				}
+				select {
+				case results <- result:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	// Reducer
+	pending := make(map[int]commitResult)
+	next := 0
+	for result := range results {
+		pending[result.index] = result
+		for {
+			result, ok := pending[next]
+			if !ok {
+				break
+			}
+			delete(pending, next)
+			if a.applyCommit(&result.commit, stats, result.stats) {
+				sink(result.index, &result.commit)
+			}
+			next++
+			<-window
+		}
+	}
+
+	if err := <-sourceErr; err != nil {
+		return err
+	}
+	return ctx.Err()
+}
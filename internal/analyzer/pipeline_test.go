@@ -0,0 +1,275 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"git-log-analyzer/internal/fixture"
+	"git-log-analyzer/internal/git"
+)
+
+func TestAnalyze_ParallelMatchesSequential(t *testing.T) {
+	dir := buildHistory(t).Dir
+
+	sequential, err := NewAnalyzerWithOptions(dir, Options{Jobs: 1}).Analyze(context.Background())
+	if err != nil {
+		t.Fatalf("Sequential analysis failed: %v", err)
+	}
+	parallel, err := NewAnalyzerWithOptions(dir, Options{Jobs: 4}).Analyze(context.Background())
+	if err != nil {
+		t.Fatalf("Parallel analysis failed: %v", err)
+	}
+
+	if sequential.TotalCommits != 8 {
+		t.Errorf("Expected 8 commits, got %d", sequential.TotalCommits)
+	}
+	if parallel.TotalCommits != sequential.TotalCommits {
+		t.Errorf("Total commits differ: %d vs %d", parallel.TotalCommits, sequential.TotalCommits)
+	}
+	if !reflect.DeepEqual(parallel.AuthorStats, sequential.AuthorStats) {
+		t.Error("Author statistics differ between parallel and sequential runs")
+	}
+	if !reflect.DeepEqual(parallel.FileStats, sequential.FileStats) {
+		t.Errorf("File statistics differ: %v vs %v", parallel.FileStats, sequential.FileStats)
+	}
+	if !reflect.DeepEqual(parallel.CommitFrequency, sequential.CommitFrequency) {
+		t.Error("Commit frequency differs between parallel and sequential runs")
+	}
+	if !reflect.DeepEqual(parallel.TimeStats, sequential.TimeStats) {
+		t.Error("Time statistics differ between parallel and sequential runs")
+	}
+}
+
+func TestAnalyze_ParallelKeepsCommitOrder(t *testing.T) {
+	repo := buildHistory(t)
+	repo.Apply(t, irregularCommits(9, 10, 14, 15, 22, 29, 30))
+
+	for _, lowMemory := range []bool{false, true} {
+		sequential, err := NewAnalyzerWithOptions(repo.Dir, Options{Jobs: 1, LowMemory: lowMemory, KeepCommits: true}).Analyze(context.Background())
+		if err != nil {
+			t.Fatalf("Sequential analysis (low memory %v) failed: %v", lowMemory, err)
+		}
+		parallel, err := NewAnalyzerWithOptions(repo.Dir, Options{Jobs: 8, LowMemory: lowMemory, KeepCommits: true}).Analyze(context.Background())
+		if err != nil {
+			t.Fatalf("Parallel analysis (low memory %v) failed: %v", lowMemory, err)
+		}
+
+		if len(parallel.Commits) != 15 {
+			t.Fatalf("Expected 15 commit records (low memory %v), got %d", lowMemory, len(parallel.Commits))
+		}
+		if !reflect.DeepEqual(parallel.Commits, sequential.Commits) {
+			t.Errorf("Kept commits differ between parallel and sequential runs (low memory %v)", lowMemory)
+		}
+		for i := 1; i < len(parallel.Commits); i++ {
+			if parallel.Commits[i].Date.After(parallel.Commits[i-1].Date) {
+				t.Errorf("Expected commits newest first, %s comes after %s",
+					parallel.Commits[i-1].Subject, parallel.Commits[i].Subject)
+			}
+		}
+	}
+}
+
+func TestAnalyze_ParallelCanceled(t *testing.T) {
+	dir := buildHistory(t).Dir
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := NewAnalyzerWithOptions(dir, Options{Jobs: 4}).Analyze(ctx); err == nil {
+		t.Error("Analyze should fail with a canceled context")
+	}
+}
+
+// irregularCommits returns one commit per given day of the month, each
+// changing core.go and every other one notes.md, so the intervals between
+// their changes vary
+func irregularCommits(days ...int) fixture.Script {
+	var script fixture.Script
+	for i, d := range days {
+		files := map[string]string{"core.go": fixture.Lines(i + 2)}
+		if i%2 == 0 {
+			files["notes.md"] = fixture.Lines(i + 1)
+		}
+		script = append(script, fixture.Commit{Author: fixture.Alice, Date: day(d, 9+i%5),
+			Message: fmt.Sprintf("Change core on day %d", d), Files: files})
+	}
+	return script
+}
+
+func TestAnalyze_LowMemoryMatchesInMemory(t *testing.T) {
+	repo := buildHistory(t)
+	repo.Apply(t, irregularCommits(9, 10, 14, 15, 22, 29, 30))
+	dir := repo.Dir
+
+	inMemory, err := NewAnalyzerWithOptions(dir, Options{Jobs: 1}).Analyze(context.Background())
+	if err != nil {
+		t.Fatalf("In-memory analysis failed: %v", err)
+	}
+	for _, jobs := range []int{1, 4, 8} {
+		streamed, err := NewAnalyzerWithOptions(dir, Options{Jobs: jobs, LowMemory: true}).Analyze(context.Background())
+		if err != nil {
+			t.Fatalf("Low-memory analysis with %d jobs failed: %v", jobs, err)
+		}
+
+		if streamed.TotalCommits != inMemory.TotalCommits {
+			t.Errorf("Total commits differ: %d vs %d", streamed.TotalCommits, inMemory.TotalCommits)
+		}
+		if !reflect.DeepEqual(streamed.AuthorStats, inMemory.AuthorStats) {
+			t.Errorf("Author statistics differ with %d jobs", jobs)
+		}
+		if !reflect.DeepEqual(streamed.FileStats, inMemory.FileStats) {
+			t.Errorf("File statistics differ with %d jobs", jobs)
+		}
+		if !reflect.DeepEqual(streamed.TimeStats, inMemory.TimeStats) {
+			t.Errorf("Time statistics differ with %d jobs", jobs)
+		}
+		if streamed.CodeHealthMetrics.HealthScore != inMemory.CodeHealthMetrics.HealthScore {
+			t.Errorf("Health score differs: %.2f vs %.2f",
+				streamed.CodeHealthMetrics.HealthScore, inMemory.CodeHealthMetrics.HealthScore)
+		}
+		if !reflect.DeepEqual(streamed.CodeHealthMetrics.StabilityIndicators, inMemory.CodeHealthMetrics.StabilityIndicators) {
+			t.Errorf("Stability indicators differ with %d jobs: %+v vs %+v", jobs,
+				streamed.CodeHealthMetrics.StabilityIndicators, inMemory.CodeHealthMetrics.StabilityIndicators)
+		}
+		if streamed.BranchData != nil {
+			t.Error("Low-memory mode should skip branch structure")
+		}
+	}
+}
+
+func TestAnalyze_GoGitBackendMatchesExec(t *testing.T) {
+	dir := buildHistory(t).Dir
+
+	backend, err := git.OpenGoGitRepository(dir)
+	if err != nil {
+		t.Fatalf("OpenGoGitRepository failed: %v", err)
+	}
+
+	viaExec, err := NewAnalyzerWithOptions(dir, Options{Jobs: 1}).Analyze(context.Background())
+	if err != nil {
+		t.Fatalf("Exec analysis failed: %v", err)
+	}
+	viaGoGit, err := NewAnalyzerWithBackend(backend, Options{Jobs: 4}).Analyze(context.Background())
+	if err != nil {
+		t.Fatalf("go-git analysis failed: %v", err)
+	}
+
+	if !reflect.DeepEqual(viaGoGit.AuthorStats, viaExec.AuthorStats) {
+		t.Error("Author statistics differ between backends")
+	}
+	if !reflect.DeepEqual(viaGoGit.FileStats, viaExec.FileStats) {
+		t.Errorf("File statistics differ: %v vs %v", viaGoGit.FileStats, viaExec.FileStats)
+	}
+	if !reflect.DeepEqual(viaGoGit.TimeStats, viaExec.TimeStats) {
+		t.Error("Time statistics differ between backends")
+	}
+}
+
+// syntheticBackend serves a generated linear history from memory, so the
+// streaming analysis can be measured without spawning git per commit. Each
+// commit touches three of 5,000 paths and the newest commit comes first.
+type syntheticBackend struct {
+	commits int
+}
+
+var _ git.Backend = syntheticBackend{}
+
+func (b syntheticBackend) IsGitRepository(ctx context.Context) bool { return true }
+
+func (b syntheticBackend) GetCommits(ctx context.Context, limit int) ([]git.GitCommit, error) {
+	return nil, fmt.Errorf("synthetic backend only streams commits")
+}
+
+func (b syntheticBackend) StreamCommits(ctx context.Context, limit int, fn func(git.GitCommit) error) error {
+	base := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := b.commits - 1; i >= 0; i-- {
+		if limit > 0 && b.commits-i > limit {
+			return nil
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		commit := git.GitCommit{
+			Hash:    fmt.Sprintf("%040d", i),
+			Author:  fmt.Sprintf("Dev%d", i%50),
+			Email:   fmt.Sprintf("dev%d@example.com", i%50),
+			Date:    base.Add(time.Duration(i) * 10 * time.Minute),
+			Message: fmt.Sprintf("Synthetic commit %d", i),
+		}
+		if err := fn(commit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b syntheticBackend) GetCommitStats(ctx context.Context, hash string) (int, int, []string, error) {
+	i, err := strconv.Atoi(hash)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	files := []string{
+		fmt.Sprintf("pkg%d/file%d.go", i%50, i%5000),
+		fmt.Sprintf("pkg%d/file%d.go", (i/7)%50, (i*7)%5000),
+		fmt.Sprintf("pkg%d/file%d.go", (i/13)%50, (i*13)%5000),
+	}
+	return 10 + i%40, i % 20, files, nil
+}
+
+func (b syntheticBackend) GetBranches(ctx context.Context) ([]string, error) { return nil, nil }
+
+func (b syntheticBackend) GetBranchCommits(ctx context.Context, branch string) ([]git.GitCommit, error) {
+	return nil, nil
+}
+
+func (b syntheticBackend) GetCommitBranch(ctx context.Context, commitHash string) (string, error) {
+	return "", nil
+}
+
+func (b syntheticBackend) GetRangeCommits(ctx context.Context, from, to string) ([]git.GitCommit, error) {
+	return nil, nil
+}
+
+func (b syntheticBackend) GetTags(ctx context.Context) ([]git.Tag, error) { return nil, nil }
+
+// streamBenchCommits returns the history size for BenchmarkAnalyzeLowMemory,
+// a million commits unless GIT_LOG_ANALYZER_BENCH_COMMITS overrides it
+func streamBenchCommits() int {
+	if value, err := strconv.Atoi(os.Getenv("GIT_LOG_ANALYZER_BENCH_COMMITS")); err == nil && value > 0 {
+		return value
+	}
+	return 1000000
+}
+
+// BenchmarkAnalyzeLowMemory runs analyzeStream end to end over a synthetic
+// history and reports allocations and the peak heap growth. The peak should
+// stay flat as the history grows: only per-author, per-file and per-path
+// aggregates are retained.
+func BenchmarkAnalyzeLowMemory(b *testing.B) {
+	n := streamBenchCommits()
+	analyzer := NewAnalyzerWithBackend(syntheticBackend{commits: n}, Options{Jobs: 4, LowMemory: true})
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	var peak uint64
+	for i := 0; i < b.N; i++ {
+		used := fixture.PeakHeap(func() {
+			stats, err := analyzer.Analyze(context.Background())
+			if err != nil {
+				b.Fatal(err)
+			}
+			if stats.TotalCommits != n {
+				b.Fatalf("Expected %d commits, got %d", n, stats.TotalCommits)
+			}
+		})
+		if used > peak {
+			peak = used
+		}
+	}
+	b.ReportMetric(float64(peak)/(1<<20), "peak-heap-MB")
+}
@@ -0,0 +1,53 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// snapshotVersion is bumped when saved statistics can no longer be read
+const snapshotVersion = 1
+
+// snapshot is the file format of SaveStatistics
+type snapshot struct {
+	Version    int         `json:"version"`
+	Saved      time.Time   `json:"saved"`
+	Statistics *Statistics `json:"statistics"`
+}
+
+// SaveStatistics writes stats to path as JSON so a later run can load the
+// analysis instead of reading the history again
+func SaveStatistics(path string, stats *Statistics) error {
+	data, err := json.Marshal(snapshot{Version: snapshotVersion, Saved: time.Now(), Statistics: stats})
+	if err != nil {
+		return fmt.Errorf("failed to encode analysis: %v", err)
+	}
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create directory: %v", err)
+		}
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to save analysis: %v", err)
+	}
+	return nil
+}
+
+// LoadStatistics reads statistics written by SaveStatistics
+func LoadStatistics(path string) (*Statistics, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read analysis: %v", err)
+	}
+	var s snapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("failed to decode analysis %s: %v", path, err)
+	}
+	if s.Version != snapshotVersion || s.Statistics == nil {
+		return nil, fmt.Errorf("unsupported analysis file %s (version %d)", path, s.Version)
+	}
+	return s.Statistics, nil
+}
@@ -0,0 +1,356 @@
+// Package changelog builds release notes from the commits of a tag or
+// revision range. Commits are grouped by their Conventional Commits type,
+// falling back to keywords of the subject, and linked to the pull requests
+// that merged them.
+package changelog
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"git-log-analyzer/internal/conventional"
+	"git-log-analyzer/internal/git"
+	"git-log-analyzer/internal/i18n"
+)
+
+// Types lists the commit types in the order their groups are shown
+var Types = []string{"feat", "fix", "perf", "refactor", "docs", "test", "build", "ci", "chore", "style", "revert", "other"}
+
+// Entry is one commit of the changelog
+type Entry struct {
+	Hash         string    `json:"hash"`
+	Type         string    `json:"type"`
+	Scope        string    `json:"scope,omitempty"`
+	Description  string    `json:"description"`
+	Breaking     bool      `json:"breaking,omitempty"`
+	BreakingNote string    `json:"breaking_note,omitempty"`
+	Author       string    `json:"author"`
+	Email        string    `json:"email"`
+	Date         time.Time `json:"date"`
+	PullRequests []int     `json:"pull_requests,omitempty"`
+}
+
+// ShortHash returns the abbreviated commit hash
+func (e Entry) ShortHash() string {
+	if len(e.Hash) > 7 {
+		return e.Hash[:7]
+	}
+	return e.Hash
+}
+
+// Group is the entries of one commit type, newest first
+type Group struct {
+	Type    string  `json:"type"`
+	Title   string  `json:"title"`
+	Summary string  `json:"summary,omitempty"` // AI summary of the group
+	Entries []Entry `json:"entries"`
+}
+
+// Contributor is an author of the range's commits
+type Contributor struct {
+	Name    string `json:"name"`
+	Email   string `json:"email"`
+	Commits int    `json:"commits"`
+}
+
+// Changelog is the release notes of one range
+type Changelog struct {
+	Version      string        `json:"version"`
+	From         string        `json:"from,omitempty"` // empty when the range starts at the root commit
+	To           string        `json:"to"`
+	Date         time.Time     `json:"date"`
+	Breaking     []Entry       `json:"breaking_changes"`
+	Groups       []Group       `json:"groups"`
+	Contributors []Contributor `json:"contributors"`
+}
+
+var (
+	githubMergePattern = regexp.MustCompile(`^Merge pull request #(\d+)`)
+	gitlabMergePattern = regexp.MustCompile(`See merge request \S*!(\d+)`)
+	squashPRPattern    = regexp.MustCompile(`\s*\(#(\d+)\)$`)
+)
+
+// keywordTypes classifies subjects that aren't conventional by their first word
+var keywordTypes = map[string]string{
+	"add": "feat", "adds": "feat", "added": "feat", "implement": "feat", "implements": "feat",
+	"implemented": "feat", "introduce": "feat", "introduces": "feat", "support": "feat", "feature": "feat",
+	"fix": "fix", "fixes": "fix", "fixed": "fix", "resolve": "fix", "resolves": "fix", "resolved": "fix",
+	"bugfix": "fix", "hotfix": "fix", "correct": "fix", "corrects": "fix",
+	"doc": "docs", "docs": "docs", "document": "docs", "documents": "docs",
+	"refactor": "refactor", "refactors": "refactor", "refactored": "refactor", "clean": "refactor",
+	"cleanup": "refactor", "rename": "refactor", "renames": "refactor", "move": "refactor", "moves": "refactor",
+	"perf": "perf", "optimize": "perf", "optimizes": "perf", "optimise": "perf", "speed": "perf",
+	"test": "test", "tests": "test",
+	"revert": "revert", "reverts": "revert",
+}
+
+// typeAliases maps common non-standard conventional types to the standard ones
+var typeAliases = map[string]string{
+	"features": "feat", "doc": "docs",
+	"tests": "test", "performance": "perf", "refactoring": "refactor",
+}
+
+// Classify returns the type of a commit along with its parsed message
+func Classify(subject, body string) (string, conventional.Commit) {
+	c := conventional.Parse(subject, body)
+	if c.Conventional() {
+		typ := c.Type
+		if alias, ok := typeAliases[typ]; ok {
+			typ = alias
+		}
+		if knownType(typ) {
+			return typ, c
+		}
+		if typ, ok := keywordTypes[typ]; ok {
+			return typ, c
+		}
+		return "other", c
+	}
+
+	word := strings.ToLower(strings.Trim(strings.SplitN(c.Description, " ", 2)[0], ":,."))
+	if typ, ok := keywordTypes[word]; ok {
+		return typ, c
+	}
+	return "other", c
+}
+
+func knownType(typ string) bool {
+	for _, t := range Types {
+		if t == typ {
+			return true
+		}
+	}
+	return false
+}
+
+// mergePR returns the pull request number of a GitHub or GitLab merge commit
+func mergePR(c git.GitCommit) int {
+	if m := githubMergePattern.FindStringSubmatch(c.Subject); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		return n
+	}
+	if m := gitlabMergePattern.FindStringSubmatch(c.Body); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		return n
+	}
+	return 0
+}
+
+// Build groups the commits of a range, newest first as returned by
+// GetRangeCommits. Merge commits don't get entries of their own; the pull
+// request they merged is attributed to the commits they brought in.
+func Build(commits []git.GitCommit) *Changelog {
+	prs := mergedPullRequests(commits)
+	msg := i18n.T()
+
+	cl := &Changelog{Breaking: []Entry{}, Groups: []Group{}, Contributors: []Contributor{}}
+	groups := make(map[string]*Group)
+	contributors := make(map[string]*Contributor)
+	var contributorOrder []string
+
+	for _, c := range commits {
+		if len(c.Parents) > 1 {
+			continue
+		}
+		typ, parsed := Classify(c.Subject, c.Body)
+		e := Entry{
+			Hash:         c.Hash,
+			Type:         typ,
+			Scope:        parsed.Scope,
+			Description:  parsed.Description,
+			Breaking:     parsed.Breaking,
+			BreakingNote: parsed.BreakingNote,
+			Author:       c.Author,
+			Email:        c.Email,
+			Date:         c.Date,
+			PullRequests: prs[c.Hash],
+		}
+		if m := squashPRPattern.FindStringSubmatch(e.Description); m != nil {
+			n, _ := strconv.Atoi(m[1])
+			e.Description = strings.TrimSpace(strings.TrimSuffix(e.Description, m[0]))
+			if !containsInt(e.PullRequests, n) {
+				e.PullRequests = append(e.PullRequests, n)
+			}
+		}
+
+		g, ok := groups[typ]
+		if !ok {
+			g = &Group{Type: typ, Title: msg.ChangelogTitles[typ]}
+			groups[typ] = g
+		}
+		g.Entries = append(g.Entries, e)
+		if e.Breaking {
+			cl.Breaking = append(cl.Breaking, e)
+		}
+
+		key := strings.ToLower(c.Email)
+		if key == "" {
+			key = c.Author
+		}
+		if _, ok := contributors[key]; !ok {
+			contributors[key] = &Contributor{Name: c.Author, Email: c.Email}
+			contributorOrder = append(contributorOrder, key)
+		}
+		contributors[key].Commits++
+	}
+
+	for _, typ := range Types {
+		if g, ok := groups[typ]; ok {
+			cl.Groups = append(cl.Groups, *g)
+		}
+	}
+	for _, key := range contributorOrder {
+		cl.Contributors = append(cl.Contributors, *contributors[key])
+	}
+	sort.SliceStable(cl.Contributors, func(i, j int) bool {
+		return cl.Contributors[i].Commits > cl.Contributors[j].Commits
+	})
+	return cl
+}
+
+// mergedPullRequests maps the commits brought in by pull request merges to
+// the pull request numbers: those reachable from the merge's second parent
+// but not from its first, within the range
+func mergedPullRequests(commits []git.GitCommit) map[string][]int {
+	byHash := make(map[string]git.GitCommit, len(commits))
+	for _, c := range commits {
+		byHash[c.Hash] = c
+	}
+	reachable := func(start string, exclude map[string]bool) map[string]bool {
+		seen := make(map[string]bool)
+		stack := []string{start}
+		for len(stack) > 0 {
+			h := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			c, ok := byHash[h]
+			if !ok || seen[h] || exclude[h] {
+				continue
+			}
+			seen[h] = true
+			stack = append(stack, c.Parents...)
+		}
+		return seen
+	}
+
+	prs := make(map[string][]int)
+	// Oldest merges first so commits list their pull requests in order
+	for i := len(commits) - 1; i >= 0; i-- {
+		c := commits[i]
+		if len(c.Parents) < 2 {
+			continue
+		}
+		n := mergePR(c)
+		if n == 0 {
+			continue
+		}
+		mainline := reachable(c.Parents[0], nil)
+		for h := range reachable(c.Parents[1], mainline) {
+			if !containsInt(prs[h], n) {
+				prs[h] = append(prs[h], n)
+			}
+		}
+	}
+	return prs
+}
+
+func containsInt(values []int, v int) bool {
+	for _, x := range values {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
+// Range is the revisions a changelog covers
+type Range struct {
+	From    string // empty for the whole history up to To
+	To      string
+	Version string
+	Date    time.Time
+}
+
+// ResolveRange turns the changelog argument into a range:
+//
+//	""          the latest tag reachable from HEAD up to HEAD ("unreleased")
+//	"a..b"      the commits reachable from b but not from a
+//	"<tag>"     the previous tag up to the tag (any revision works)
+func ResolveRange(ctx context.Context, backend git.Backend, spec string) (Range, error) {
+	if strings.Contains(spec, "...") {
+		return Range{}, fmt.Errorf("symmetric ranges are not supported: %s", spec)
+	}
+	if from, to, ok := strings.Cut(spec, ".."); ok {
+		if to == "" {
+			to = "HEAD"
+		}
+		r := Range{From: from, To: to, Version: to}
+		if to == "HEAD" {
+			r.Version = i18n.T().ChangelogUnreleased
+		}
+		return r, nil
+	}
+
+	tags, err := backend.GetTags(ctx)
+	if err != nil {
+		return Range{}, err
+	}
+	tagged := make(map[string]string)
+	for _, t := range tags {
+		if _, ok := tagged[t.Hash]; !ok {
+			tagged[t.Hash] = t.Name
+		}
+	}
+
+	r := Range{To: spec, Version: spec}
+	if spec == "" {
+		r.To = "HEAD"
+		r.Version = i18n.T().ChangelogUnreleased
+	}
+	for _, t := range tags {
+		if t.Name == spec {
+			r.Date = t.Date
+		}
+	}
+
+	history, err := backend.GetRangeCommits(ctx, "", r.To)
+	if err != nil {
+		return Range{}, err
+	}
+	for i, c := range history {
+		// A release starts after the previous tag; unreleased changes start
+		// at the latest tag, which may be HEAD itself
+		if i == 0 && spec != "" {
+			continue
+		}
+		if name, ok := tagged[c.Hash]; ok {
+			r.From = name
+			break
+		}
+	}
+	return r, nil
+}
+
+// Generate builds the changelog of the range described by spec (see
+// ResolveRange)
+func Generate(ctx context.Context, backend git.Backend, spec string) (*Changelog, error) {
+	r, err := ResolveRange(ctx, backend, spec)
+	if err != nil {
+		return nil, err
+	}
+	commits, err := backend.GetRangeCommits(ctx, r.From, r.To)
+	if err != nil {
+		return nil, err
+	}
+
+	cl := Build(commits)
+	cl.Version, cl.From, cl.To, cl.Date = r.Version, r.From, r.To, r.Date
+	if cl.Date.IsZero() && len(commits) > 0 {
+		cl.Date = commits[0].Date
+	}
+	return cl, nil
+}
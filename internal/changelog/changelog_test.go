@@ -0,0 +1,219 @@
+package changelog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"git-log-analyzer/internal/fixture"
+	"git-log-analyzer/internal/git"
+)
+
+// buildRelease returns a repository with two releases and unreleased work:
+//
+//	v1.0.0  Initial commit
+//	        feat(api): add search endpoint
+//	        PR #12 merging "Fix crash on empty input" and "docs: explain search"
+//	        refactor!: rename config keys (#15), with a BREAKING CHANGE footer
+//	v1.1.0
+//	        perf: cache search results
+func buildRelease(t *testing.T) *fixture.Repo {
+	t.Helper()
+	day := func(d int) time.Time {
+		return time.Date(2024, 5, d, 12, 0, 0, 0, time.UTC)
+	}
+	return fixture.Build(t, fixture.Script{
+		fixture.Commit{Author: fixture.Alice, Date: day(1), Message: "Initial commit", Files: map[string]string{"main.go": fixture.Lines(3)}},
+		fixture.Tag{Name: "v1.0.0", Message: "Release 1.0.0", Author: fixture.Alice, Date: day(1)},
+		fixture.Commit{ID: "feat", Author: fixture.Alice, Date: day(2), Message: "feat(api): add search endpoint", Files: map[string]string{"api.go": fixture.Lines(5)}},
+		fixture.Branch{Name: "feature"},
+		fixture.Checkout{Ref: "feature"},
+		fixture.Commit{ID: "fix", Author: fixture.Bob, Date: day(3), Message: "Fix crash on empty input", Files: map[string]string{"api.go": fixture.Lines(6)}},
+		fixture.Commit{ID: "docs", Author: fixture.Bob, Date: day(4), Message: "docs: explain search", Files: map[string]string{"README.md": fixture.Lines(2)}},
+		fixture.Checkout{Ref: fixture.DefaultBranch},
+		fixture.Merge{Branch: "feature", Author: fixture.Alice, Date: day(5), Message: "Merge pull request #12 from bob/feature\n\nSearch fixes"},
+		fixture.Commit{ID: "refactor", Author: fixture.Carol, Date: day(6), Files: map[string]string{"config.go": fixture.Lines(4)},
+			Message: "refactor!: rename config keys (#15)\n\nBREAKING CHANGE: search.limit is now search.max"},
+		fixture.Tag{Name: "v1.1.0", Message: "Release 1.1.0", Author: fixture.Alice, Date: day(7)},
+		fixture.Commit{Author: fixture.Alice, Date: day(8), Message: "perf: cache search results", Files: map[string]string{"api.go": fixture.Lines(7)}},
+	})
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct{ subject, want string }{
+		{"feat(api): add search", "feat"},
+		{"feature: add search", "feat"},
+		{"wip: half done", "other"},
+		{"Add search endpoint", "feat"},
+		{"Fixed: crash", "fix"},
+		{"Rename config keys", "refactor"},
+		{"Update dependencies", "other"},
+	}
+	for _, tt := range tests {
+		if got, _ := Classify(tt.subject, ""); got != tt.want {
+			t.Errorf("Classify(%q) = %q, want %q", tt.subject, got, tt.want)
+		}
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	os.Setenv("REPORT_LANGUAGE", "en")
+	defer os.Unsetenv("REPORT_LANGUAGE")
+	repo := buildRelease(t)
+
+	for _, kind := range []string{git.BackendExec, git.BackendGoGit} {
+		t.Run(kind, func(t *testing.T) {
+			backend, err := git.NewBackend(kind, repo.Dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			cl, err := Generate(context.Background(), backend, "v1.1.0")
+			if err != nil {
+				t.Fatalf("Generate failed: %v", err)
+			}
+			if cl.Version != "v1.1.0" || cl.From != "v1.0.0" || cl.Date.Format("2006-01-02") != "2024-05-07" {
+				t.Errorf("Unexpected range %s..%s (%s) at %v", cl.From, cl.To, cl.Version, cl.Date)
+			}
+
+			var types []string
+			for _, g := range cl.Groups {
+				types = append(types, g.Type)
+			}
+			if want := []string{"feat", "fix", "refactor", "docs"}; !reflect.DeepEqual(types, want) {
+				t.Fatalf("Expected groups %v, got %v", want, types)
+			}
+			if g := cl.Groups[0]; g.Title != "Features" || g.Entries[0].Scope != "api" || g.Entries[0].Hash != repo.Hash("feat") || g.Entries[0].PullRequests != nil {
+				t.Errorf("Unexpected features %+v", g)
+			}
+			if e := cl.Groups[1].Entries[0]; e.Hash != repo.Hash("fix") || !reflect.DeepEqual(e.PullRequests, []int{12}) {
+				t.Errorf("Expected the fix to link PR #12, got %+v", e)
+			}
+			if e := cl.Groups[3].Entries[0]; !reflect.DeepEqual(e.PullRequests, []int{12}) {
+				t.Errorf("Expected the docs to link PR #12, got %+v", e)
+			}
+
+			refactor := cl.Groups[2].Entries[0]
+			if refactor.Description != "rename config keys" || !reflect.DeepEqual(refactor.PullRequests, []int{15}) {
+				t.Errorf("Expected the squash PR suffix to be linked, got %+v", refactor)
+			}
+			if len(cl.Breaking) != 1 || cl.Breaking[0].Hash != repo.Hash("refactor") || cl.Breaking[0].BreakingNote != "search.limit is now search.max" {
+				t.Errorf("Unexpected breaking changes %+v", cl.Breaking)
+			}
+
+			want := []Contributor{{"Bob", "bob@example.com", 2}, {"Carol", "carol@example.com", 1}, {"Alice", "alice@example.com", 1}}
+			if !reflect.DeepEqual(cl.Contributors, want) {
+				t.Errorf("Expected contributors %+v, got %+v", want, cl.Contributors)
+			}
+		})
+	}
+}
+
+func TestGenerate_Ranges(t *testing.T) {
+	os.Setenv("REPORT_LANGUAGE", "en")
+	defer os.Unsetenv("REPORT_LANGUAGE")
+	repo := buildRelease(t)
+	backend := git.NewRepository(repo.Dir)
+	ctx := context.Background()
+
+	unreleased, err := Generate(ctx, backend, "")
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if unreleased.Version != "Unreleased" || unreleased.From != "v1.1.0" || len(unreleased.Groups) != 1 || unreleased.Groups[0].Type != "perf" {
+		t.Errorf("Unexpected unreleased changelog %+v", unreleased)
+	}
+
+	first, err := Generate(ctx, backend, "v1.0.0")
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if first.From != "" || len(first.Groups) != 1 || first.Groups[0].Type != "other" {
+		t.Errorf("Expected the first release to cover the whole history, got %+v", first)
+	}
+
+	explicit, err := Generate(ctx, backend, "v1.0.0..v1.1.0")
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if explicit.Version != "v1.1.0" || len(explicit.Groups) != 4 {
+		t.Errorf("Unexpected explicit range %+v", explicit)
+	}
+
+	if _, err := Generate(ctx, backend, "v1.0.0...v1.1.0"); err == nil {
+		t.Error("Expected an error for a symmetric range")
+	}
+	if _, err := Generate(ctx, backend, "v9"); err == nil {
+		t.Error("Expected an error for an unknown revision")
+	}
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	os.Setenv("REPORT_LANGUAGE", "en")
+	defer os.Unsetenv("REPORT_LANGUAGE")
+	repo := buildRelease(t)
+	cl, err := Generate(context.Background(), git.NewRepository(repo.Dir), "v1.1.0")
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	cl.Groups[1].Summary = "Search no longer crashes."
+
+	var buf bytes.Buffer
+	if err := WriteMarkdown(&buf, cl, "https://github.com/org/repo.git"); err != nil {
+		t.Fatalf("WriteMarkdown failed: %v", err)
+	}
+	md := buf.String()
+	short := func(id string) string { return repo.Hash(id)[:7] }
+	for _, want := range []string{
+		"## v1.1.0 (2024-05-07)\n",
+		"### ⚠ BREAKING CHANGES\n\n- rename config keys ([#15](https://github.com/org/repo/pull/15)) ([" + short("refactor") + "](https://github.com/org/repo/commit/" + repo.Hash("refactor") + "))\n  search.limit is now search.max\n",
+		"### Features\n\n- **api:** add search endpoint ([" + short("feat") + "]",
+		"### Bug Fixes\n\nSearch no longer crashes.\n\n- Fix crash on empty input ([#12](https://github.com/org/repo/pull/12))",
+		"### Contributors\n\n- Bob (2)\n- Carol (1)\n- Alice (1)\n",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("Expected Markdown to contain %q, got:\n%s", want, md)
+		}
+	}
+	if strings.Index(md, "### Features") > strings.Index(md, "### Documentation") {
+		t.Error("Expected features before documentation")
+	}
+
+	buf.Reset()
+	if err := WriteMarkdown(&buf, cl, ""); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "- Fix crash on empty input (#12) ("+short("fix")+")\n") {
+		t.Errorf("Expected plain references without a repository URL, got:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := WriteMarkdown(&buf, cl, "https://gitlab.com/org/repo"); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "[#12](https://gitlab.com/org/repo/-/merge_requests/12)") {
+		t.Errorf("Expected GitLab merge request links, got:\n%s", buf.String())
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	cl := Build([]git.GitCommit{{Hash: "abc", Author: "Alice", Email: "a@x", Subject: "fix: x"}})
+	cl.Version = "v1"
+
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, cl); err != nil {
+		t.Fatalf("WriteJSON failed: %v", err)
+	}
+	var decoded Changelog
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	if decoded.Version != "v1" || len(decoded.Groups) != 1 || decoded.Groups[0].Entries[0].Description != "x" || decoded.Breaking == nil {
+		t.Errorf("Unexpected round trip %+v", decoded)
+	}
+}
@@ -0,0 +1,114 @@
+package changelog
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"git-log-analyzer/internal/i18n"
+)
+
+// WriteJSON writes the changelog as indented JSON
+func WriteJSON(w io.Writer, cl *Changelog) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(cl)
+}
+
+// WriteMarkdown writes the changelog as a Markdown section. With a repository
+// URL such as https://github.com/org/repo, pull requests and commits are
+// linked; GitLab URLs get GitLab's link layout.
+func WriteMarkdown(w io.Writer, cl *Changelog, repoURL string) error {
+	msg := i18n.T()
+	links := newLinker(repoURL)
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "## %s", cl.Version)
+	if !cl.Date.IsZero() {
+		fmt.Fprintf(&sb, " (%s)", cl.Date.Format("2006-01-02"))
+	}
+	sb.WriteString("\n\n")
+
+	if len(cl.Groups) == 0 {
+		fmt.Fprintf(&sb, "%s\n", msg.ChangelogEmpty)
+		_, err := io.WriteString(w, sb.String())
+		return err
+	}
+
+	if len(cl.Breaking) > 0 {
+		fmt.Fprintf(&sb, "### %s\n\n", msg.ChangelogBreaking)
+		for _, e := range cl.Breaking {
+			sb.WriteString(links.entry(e))
+			if e.BreakingNote != "" {
+				for _, line := range strings.Split(e.BreakingNote, "\n") {
+					fmt.Fprintf(&sb, "  %s\n", line)
+				}
+			}
+		}
+		sb.WriteString("\n")
+	}
+
+	for _, g := range cl.Groups {
+		fmt.Fprintf(&sb, "### %s\n\n", g.Title)
+		if g.Summary != "" {
+			fmt.Fprintf(&sb, "%s\n\n", g.Summary)
+		}
+		for _, e := range g.Entries {
+			sb.WriteString(links.entry(e))
+		}
+		sb.WriteString("\n")
+	}
+
+	fmt.Fprintf(&sb, "### %s\n\n", msg.ChangelogContributors)
+	for _, c := range cl.Contributors {
+		fmt.Fprintf(&sb, "- %s (%d)\n", c.Name, c.Commits)
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// linker formats pull request and commit references
+type linker struct {
+	base       string
+	prPath     string
+	commitPath string
+}
+
+func newLinker(repoURL string) linker {
+	base := strings.TrimSuffix(strings.TrimSuffix(strings.TrimSpace(repoURL), "/"), ".git")
+	if strings.Contains(base, "gitlab") {
+		return linker{base: base, prPath: "/-/merge_requests/", commitPath: "/-/commit/"}
+	}
+	return linker{base: base, prPath: "/pull/", commitPath: "/commit/"}
+}
+
+// entry formats one list item
+func (l linker) entry(e Entry) string {
+	var sb strings.Builder
+	sb.WriteString("- ")
+	if e.Scope != "" {
+		fmt.Fprintf(&sb, "**%s:** ", e.Scope)
+	}
+	sb.WriteString(e.Description)
+	for _, n := range e.PullRequests {
+		fmt.Fprintf(&sb, " (%s)", l.pr(n))
+	}
+	fmt.Fprintf(&sb, " (%s)\n", l.commit(e))
+	return sb.String()
+}
+
+func (l linker) pr(n int) string {
+	if l.base == "" {
+		return fmt.Sprintf("#%d", n)
+	}
+	return fmt.Sprintf("[#%d](%s%s%d)", n, l.base, l.prPath, n)
+}
+
+func (l linker) commit(e Entry) string {
+	if l.base == "" {
+		return e.ShortHash()
+	}
+	return fmt.Sprintf("[%s](%s%s%s)", e.ShortHash(), l.base, l.commitPath, e.Hash)
+}
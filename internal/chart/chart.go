@@ -0,0 +1,184 @@
+// Package chart renders the core charts of an analysis as static SVG: the
+// commit timeline, the hourly and weekday histograms, the top contributors,
+// the technical debt hotspots and the weekday by hour punch card. The charts
+// need no JavaScript, so they work in print, in text report folders and in
+// documents that cannot run scripts.
+package chart
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"git-log-analyzer/internal/analyzer"
+	"git-log-analyzer/internal/health"
+	"git-log-analyzer/internal/i18n"
+)
+
+// Chart is a rendered chart
+type Chart struct {
+	Name  string // file name without the extension, e.g. "timeline"
+	Title string
+	SVG   string
+}
+
+// Chart names
+const (
+	Timeline     = "timeline"
+	Hourly       = "hourly"
+	Weekdays     = "weekdays"
+	Contributors = "contributors"
+	Hotspots     = "hotspots"
+	Punchcard    = "punchcard"
+)
+
+// Risk colors of the hotspot bubbles, matching the health finding levels
+const (
+	riskHigh   = "#dc3545"
+	riskMedium = "#fd7e14"
+)
+
+// Render draws the charts of stats with the titles of msg. The hotspot
+// chart is left out when there are no hotspots.
+func Render(stats *analyzer.Statistics, msg *i18n.Messages) []Chart {
+	var charts []Chart
+	add := func(name, title, svg string) {
+		charts = append(charts, Chart{Name: name, Title: title, SVG: svg})
+	}
+
+	labels, values := TimelineSeries(stats.CommitFrequency)
+	add(Timeline, msg.CommitTimeline, Line(msg.CommitTimeline, labels, values))
+
+	// Every hour and weekday, including those without commits
+	labels, values = nil, nil
+	for hour := 0; hour < 24; hour++ {
+		labels = append(labels, fmt.Sprint(hour))
+		values = append(values, stats.TimeStats.HourlyPattern[hour])
+	}
+	add(Hourly, msg.HourlyActivity, Columns(msg.HourlyActivity, labels, values))
+
+	labels, values = nil, nil
+	for day, name := range msg.DayNames {
+		labels = append(labels, name)
+		values = append(values, stats.TimeStats.DailyPattern[time.Weekday(day)])
+	}
+	add(Weekdays, msg.DailyActivity, Columns(msg.DailyActivity, labels, values))
+
+	labels, values = topAuthors(stats, 10)
+	add(Contributors, msg.TopContributors, Bars(msg.TopContributors, labels, values))
+
+	if metrics := stats.CodeHealthMetrics; metrics != nil && len(metrics.TechnicalDebtHotspots) > 0 {
+		add(Hotspots, msg.ChartHotspots, Bubbles(msg.ChartHotspots, msg.PrintChanges, msg.Contributors, hotspotBubbles(metrics.TechnicalDebtHotspots)))
+	}
+
+	add(Punchcard, msg.ChartPunchCard, PunchCard(msg.ChartPunchCard, msg.DayNames, stats.TimeStats.PunchCard))
+	return charts
+}
+
+// Calendars draws a calendar heatmap of every year, newest first, named
+// "calendar-<year>"
+func Calendars(years []analyzer.CalendarYear, msg *i18n.Messages) []Chart {
+	var charts []Chart
+	for _, year := range years {
+		start, err := time.Parse("2006-01-02", year.Start)
+		if err != nil {
+			continue
+		}
+		title := fmt.Sprintf("%s %d", msg.ChartCalendar, year.Year)
+		charts = append(charts, Chart{
+			Name:  fmt.Sprintf("calendar-%d", year.Year),
+			Title: title,
+			SVG:   Calendar(title, start, year.Weeks, msg.DayNames, msg.MonthNames, msg.ChartLess, msg.ChartMore),
+		})
+	}
+	return charts
+}
+
+// WriteFiles writes every chart as <prefix><name>.svg into dir and returns
+// the paths
+func WriteFiles(dir, prefix string, charts []Chart) ([]string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create chart directory: %v", err)
+	}
+	var paths []string
+	for _, c := range charts {
+		path := filepath.Join(dir, prefix+c.Name+".svg")
+		if err := os.WriteFile(path, []byte(c.SVG+"\n"), 0644); err != nil {
+			return paths, fmt.Errorf("failed to write chart %s: %v", c.Name, err)
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// TimelineSeries returns the dates and counts of the commit frequency in
+// date order, grouped by month when more than 90 days have commits
+func TimelineSeries(frequency map[string]int) ([]string, []int) {
+	dates := make([]string, 0, len(frequency))
+	for date := range frequency {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+
+	var labels []string
+	var values []int
+	byMonth := len(dates) > 90
+	for _, date := range dates {
+		label := date
+		if byMonth && len(label) >= 7 {
+			label = label[:7]
+		}
+		if n := len(labels); n > 0 && labels[n-1] == label {
+			values[n-1] += frequency[date]
+			continue
+		}
+		labels = append(labels, label)
+		values = append(values, frequency[date])
+	}
+	return labels, values
+}
+
+// topAuthors returns the names and commit counts of the limit authors with
+// the most commits
+func topAuthors(stats *analyzer.Statistics, limit int) ([]string, []int) {
+	var authors []*analyzer.AuthorStat
+	for _, stat := range stats.AuthorStats {
+		authors = append(authors, stat)
+	}
+	sort.Slice(authors, func(i, j int) bool {
+		if authors[i].CommitCount != authors[j].CommitCount {
+			return authors[i].CommitCount > authors[j].CommitCount
+		}
+		return authors[i].Name < authors[j].Name
+	})
+	if len(authors) > limit {
+		authors = authors[:limit]
+	}
+
+	var names []string
+	var counts []int
+	for _, a := range authors {
+		names = append(names, a.Name)
+		counts = append(counts, a.CommitCount)
+	}
+	return names, counts
+}
+
+// hotspotBubbles places the hotspots by changes and authors, sized and
+// colored by their risk
+func hotspotBubbles(hotspots []health.TechnicalDebtHotspot) []Bubble {
+	var bubbles []Bubble
+	for _, h := range hotspots {
+		b := Bubble{Label: h.FilePath, X: h.TotalChanges, Y: h.UniqueAuthors, Size: h.RiskScore}
+		switch {
+		case h.RiskScore > 0.7:
+			b.Color = riskHigh
+		case h.RiskScore > 0.5:
+			b.Color = riskMedium
+		}
+		bubbles = append(bubbles, b)
+	}
+	return bubbles
+}
@@ -0,0 +1,158 @@
+package chart
+
+import (
+	"encoding/xml"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"git-log-analyzer/internal/analyzer"
+	"git-log-analyzer/internal/health"
+	"git-log-analyzer/internal/i18n"
+)
+
+func testStats() *analyzer.Statistics {
+	stats := &analyzer.Statistics{
+		TotalCommits: 3,
+		AuthorStats: map[string]*analyzer.AuthorStat{
+			"Alice <alice@example.com>": {Name: "Alice", CommitCount: 2},
+			"Bob <bob@example.com>":     {Name: "Bob & Co", CommitCount: 1},
+		},
+		CommitFrequency: map[string]int{"2023-03-01": 2, "2023-03-02": 1},
+		TimeStats: &analyzer.TimeStat{
+			HourlyPattern: map[int]int{10: 2, 14: 1},
+			DailyPattern:  map[time.Weekday]int{time.Wednesday: 2, time.Thursday: 1},
+		},
+	}
+	stats.TimeStats.PunchCard[time.Wednesday][10] = 2
+	stats.TimeStats.PunchCard[time.Thursday][14] = 1
+	return stats
+}
+
+// wellFormed reports whether svg parses as XML
+func wellFormed(svg string) error {
+	d := xml.NewDecoder(strings.NewReader(svg))
+	for {
+		if _, err := d.Token(); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	stats := testStats()
+	charts := Render(stats, i18n.GetMessages(i18n.LangEN))
+
+	var names []string
+	for _, c := range charts {
+		names = append(names, c.Name)
+		if err := wellFormed(c.SVG); err != nil {
+			t.Errorf("Chart %s is not well-formed: %v", c.Name, err)
+		}
+	}
+	if got := strings.Join(names, ","); got != "timeline,hourly,weekdays,contributors,punchcard" {
+		t.Errorf("Expected no hotspot chart without hotspots, got %s", got)
+	}
+	if !strings.Contains(charts[3].SVG, "Bob &amp; Co") {
+		t.Error("Expected the contributor names to be escaped")
+	}
+	if n := strings.Count(charts[4].SVG, "<circle"); n != 2 {
+		t.Errorf("Expected a punch card circle per busy hour, got %d", n)
+	}
+
+	stats.CodeHealthMetrics = &health.CodeHealthMetrics{
+		TechnicalDebtHotspots: []health.TechnicalDebtHotspot{
+			{FilePath: "internal/main.go", TotalChanges: 3, UniqueAuthors: 2, RiskScore: 0.8},
+		},
+	}
+	charts = Render(stats, i18n.GetMessages(i18n.LangEN))
+	if len(charts) != 6 || charts[4].Name != Hotspots {
+		t.Fatalf("Expected the hotspot chart before the punch card, got %d charts", len(charts))
+	}
+	if !strings.Contains(charts[4].SVG, riskHigh) || !strings.Contains(charts[4].SVG, ">main.go</text>") {
+		t.Error("Expected a labelled high risk bubble")
+	}
+}
+
+func TestWriteFiles(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "out")
+	paths, err := WriteFiles(dir, "report-", Render(testStats(), i18n.GetMessages(i18n.LangZH)))
+	if err != nil {
+		t.Fatalf("WriteFiles failed: %v", err)
+	}
+	if len(paths) != 5 || paths[0] != filepath.Join(dir, "report-timeline.svg") {
+		t.Fatalf("Unexpected paths: %v", paths)
+	}
+	data, err := os.ReadFile(paths[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), `<svg xmlns="http://www.w3.org/2000/svg"`) {
+		t.Errorf("Expected a standalone SVG file, got %.40s", data)
+	}
+}
+
+func TestTimelineSeries(t *testing.T) {
+	frequency := make(map[string]int)
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	for day := start; day.Month() <= time.April; day = day.AddDate(0, 0, 1) {
+		frequency[day.Format("2006-01-02")] = 1
+	}
+
+	labels, values := TimelineSeries(frequency)
+	if strings.Join(labels, ",") != "2023-01,2023-02,2023-03,2023-04" || values[1] != 28 {
+		t.Errorf("Expected the commits per month, got %v %v", labels, values)
+	}
+
+	labels, values = TimelineSeries(map[string]int{"2023-01-02": 3, "2023-01-01": 1})
+	if strings.Join(labels, ",") != "2023-01-01,2023-01-02" || values[1] != 3 {
+		t.Errorf("Expected the days of a short timeline, got %v %v", labels, values)
+	}
+}
+
+func TestCalendars(t *testing.T) {
+	years := analyzer.Calendar(map[string]int{"2023-03-01": 4, "2023-03-02": 1, "2022-12-31": 2})
+	charts := Calendars(years, i18n.GetMessages(i18n.LangEN))
+	if len(charts) != 2 || charts[0].Name != "calendar-2023" || charts[1].Title != "Commit Calendar 2022" {
+		t.Fatalf("Expected a calendar per year, newest first, got %d", len(charts))
+	}
+
+	svg := charts[0].SVG
+	if err := wellFormed(svg); err != nil {
+		t.Fatalf("Calendar is not well-formed: %v", err)
+	}
+	// 365 days and the five legend colors
+	if n := strings.Count(svg, "<rect"); n != 365+len(calendarColors) {
+		t.Errorf("Expected a square per day of 2023, got %d squares", n)
+	}
+	if !strings.Contains(svg, `fill="`+calendarColors[4]+`"><title>2023-03-01: 4</title>`) ||
+		!strings.Contains(svg, `fill="`+calendarColors[1]+`"><title>2023-03-02: 1</title>`) {
+		t.Error("Expected the busiest day darkest and the others by their share")
+	}
+	if strings.Count(svg, ">Mar</text>") != 1 || !strings.Contains(svg, ">Less</text>") {
+		t.Error("Expected month and legend labels")
+	}
+}
+
+func TestCalendarLevel(t *testing.T) {
+	for _, tc := range []struct{ count, max, want int }{
+		{0, 10, 0}, {1, 10, 1}, {3, 10, 2}, {6, 10, 3}, {10, 10, 4}, {5, 0, 0},
+	} {
+		if got := CalendarLevel(tc.count, tc.max); got != tc.want {
+			t.Errorf("CalendarLevel(%d, %d) = %d, want %d", tc.count, tc.max, got, tc.want)
+		}
+	}
+}
+
+func TestNiceStep(t *testing.T) {
+	for raw, want := range map[float64]float64{0: 1, 1.5: 2, 3: 5, 7: 10, 12: 20, 450: 500} {
+		if got := niceStep(raw); got != want {
+			t.Errorf("niceStep(%v) = %v, want %v", raw, got, want)
+		}
+	}
+}
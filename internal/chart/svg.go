@@ -0,0 +1,385 @@
+package chart
+
+import (
+	"fmt"
+	"html"
+	"math"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	width     = 640
+	height    = 220
+	color     = "#667eea"
+	gridColor = "#e0e0e0"
+	textColor = "#555"
+)
+
+// Bubble is a point of a bubble chart. Size is relative to the other
+// bubbles; Color is optional.
+type Bubble struct {
+	Label string
+	X, Y  int
+	Size  float64
+	Color string
+}
+
+// plot is the plot area of a chart with a value axis on the left
+type plot struct {
+	left, top, right, bottom float64
+	max                      int
+}
+
+// newPlot lays out a plot of the given height for values up to max,
+// leaving room for labels of labelHeight below it
+func newPlot(max int, chartHeight, labelHeight float64) plot {
+	step := niceStep(float64(max) / 4)
+	top := int(math.Ceil(float64(max)/step) * step)
+	if top == 0 {
+		top = int(step)
+	}
+	return plot{
+		left:   float64(len(fmt.Sprint(top)))*7 + 12,
+		top:    10,
+		right:  width - 10,
+		bottom: chartHeight - labelHeight,
+		max:    top,
+	}
+}
+
+// y returns the position of value v
+func (p plot) y(v int) float64 {
+	return p.bottom - (p.bottom-p.top)*float64(v)/float64(p.max)
+}
+
+// writeGrid draws the horizontal grid lines and the value axis labels
+func (p plot) writeGrid(b *strings.Builder) {
+	step := niceStep(float64(p.max) / 4)
+	for v := 0.0; v <= float64(p.max)+step/2; v += step {
+		y := p.y(int(v))
+		fmt.Fprintf(b, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="%s"/>`, p.left, y, p.right, y, gridColor)
+		fmt.Fprintf(b, `<text x="%.1f" y="%.1f" text-anchor="end" dominant-baseline="middle">%d</text>`, p.left-6, y, int(v))
+	}
+}
+
+// niceStep rounds raw up to 1, 2 or 5 times a power of ten, at least 1
+func niceStep(raw float64) float64 {
+	if raw <= 1 {
+		return 1
+	}
+	power := math.Pow(10, math.Floor(math.Log10(raw)))
+	switch fraction := raw / power; {
+	case fraction <= 1:
+		return power
+	case fraction <= 2:
+		return 2 * power
+	case fraction <= 5:
+		return 5 * power
+	default:
+		return 10 * power
+	}
+}
+
+// open starts an SVG document with the given accessible title
+func open(b *strings.Builder, title string, h float64) {
+	openSized(b, title, width, h)
+}
+
+// openSized starts an SVG document of the given size
+func openSized(b *strings.Builder, title string, w, h float64) {
+	fmt.Fprintf(b, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %.0f %.0f" width="%.0f" height="%.0f" role="img" font-family="sans-serif" font-size="10" fill="%s">`,
+		w, h, w, h, textColor)
+	fmt.Fprintf(b, `<title>%s</title>`, html.EscapeString(title))
+}
+
+// maxValue returns the largest of values, at least 0
+func maxValue(values []int) int {
+	max := 0
+	for _, v := range values {
+		if v > max {
+			max = v
+		}
+	}
+	return max
+}
+
+// labelStride returns how many labels to skip so that labels of width
+// labelWidth fit into slots of slotWidth
+func labelStride(slotWidth, labelWidth float64) int {
+	if slotWidth <= 0 {
+		return 1
+	}
+	return int(math.Max(1, math.Ceil(labelWidth/slotWidth)))
+}
+
+// longestLabel returns the length of the longest label in characters
+func longestLabel(labels []string) float64 {
+	longest := 0
+	for _, l := range labels {
+		if n := len([]rune(l)); n > longest {
+			longest = n
+		}
+	}
+	return float64(longest)
+}
+
+// truncate shortens label to at most n characters
+func truncate(label string, n int) string {
+	runes := []rune(label)
+	if len(runes) <= n || n < 2 {
+		return label
+	}
+	return string(runes[:n-1]) + "…"
+}
+
+// Columns draws values as vertical bars with the labels below
+func Columns(title string, labels []string, values []int) string {
+	var b strings.Builder
+	open(&b, title, height)
+	p := newPlot(maxValue(values), height, 24)
+	p.writeGrid(&b)
+
+	slot := (p.right - p.left) / math.Max(float64(len(values)), 1)
+	stride := labelStride(slot, longestLabel(labels)*6+6)
+	for i, v := range values {
+		x := p.left + slot*float64(i)
+		fmt.Fprintf(&b, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s"><title>%s: %d</title></rect>`,
+			x+slot*0.15, p.y(v), slot*0.7, p.bottom-p.y(v), color, html.EscapeString(labels[i]), v)
+		if i%stride == 0 {
+			fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" text-anchor="middle">%s</text>`, x+slot/2, p.bottom+14, html.EscapeString(labels[i]))
+		}
+	}
+	b.WriteString(`</svg>`)
+	return b.String()
+}
+
+// Line draws values as a line over a filled area, with as many of the
+// labels below as fit
+func Line(title string, labels []string, values []int) string {
+	var b strings.Builder
+	open(&b, title, height)
+	p := newPlot(maxValue(values), height, 24)
+	p.writeGrid(&b)
+
+	x := func(i int) float64 {
+		if len(values) < 2 {
+			return (p.left + p.right) / 2
+		}
+		return p.left + (p.right-p.left)*float64(i)/float64(len(values)-1)
+	}
+	var points []string
+	for i, v := range values {
+		points = append(points, fmt.Sprintf("%.1f,%.1f", x(i), p.y(v)))
+	}
+	if len(points) > 0 {
+		area := fmt.Sprintf("%.1f,%.1f %s %.1f,%.1f", x(0), p.bottom, strings.Join(points, " "), x(len(values)-1), p.bottom)
+		fmt.Fprintf(&b, `<polygon points="%s" fill="%s" fill-opacity="0.15"/>`, area, color)
+		fmt.Fprintf(&b, `<polyline points="%s" fill="none" stroke="%s" stroke-width="2"/>`, strings.Join(points, " "), color)
+	}
+
+	slot := (p.right - p.left) / math.Max(float64(len(values)), 1)
+	stride := labelStride(slot, longestLabel(labels)*6+10)
+	for i := 0; i < len(labels); i += stride {
+		fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" text-anchor="middle">%s</text>`, x(i), p.bottom+14, html.EscapeString(labels[i]))
+	}
+	b.WriteString(`</svg>`)
+	return b.String()
+}
+
+// Bars draws values as horizontal bars with the labels on the left and the
+// values at the end of the bars
+func Bars(title string, labels []string, values []int) string {
+	const rowHeight = 22
+	h := float64(len(values)*rowHeight + 10)
+
+	var b strings.Builder
+	open(&b, title, h)
+	max := math.Max(float64(maxValue(values)), 1)
+	left := math.Min(longestLabel(labels)*6+10, width/3)
+	barWidth := width - left - 50
+	for i, v := range values {
+		y := float64(5 + i*rowHeight)
+		fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" text-anchor="end" dominant-baseline="middle">%s</text>`,
+			left-6, y+rowHeight/2, html.EscapeString(truncate(labels[i], int(left/6))))
+		fmt.Fprintf(&b, `<rect x="%.1f" y="%.1f" width="%.1f" height="%d" fill="%s"/>`, left, y+4, barWidth*float64(v)/max, rowHeight-8, color)
+		fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" dominant-baseline="middle">%d</text>`, left+barWidth*float64(v)/max+4, y+rowHeight/2, v)
+	}
+	b.WriteString(`</svg>`)
+	return b.String()
+}
+
+// Bubbles draws a bubble chart with linear x and y axes. The area of a
+// bubble is proportional to its size; the largest bubbles are labelled with
+// the base name of their label.
+func Bubbles(title, xLabel, yLabel string, bubbles []Bubble) string {
+	const maxRadius = 18.0
+	const labelled = 5
+
+	var b strings.Builder
+	open(&b, title, height+20)
+	xs := make([]int, len(bubbles))
+	ys := make([]int, len(bubbles))
+	maxSize := 0.0
+	for i, bubble := range bubbles {
+		xs[i], ys[i] = bubble.X, bubble.Y
+		maxSize = math.Max(maxSize, bubble.Size)
+	}
+	p := newPlot(maxValue(ys), height+20, 40)
+	p.left += 14
+	p.top += maxRadius
+	p.right -= maxRadius
+	p.writeGrid(&b)
+	xStep := niceStep(float64(maxValue(xs)) / 5)
+	xMax := math.Max(math.Ceil(float64(maxValue(xs))/xStep)*xStep, xStep)
+	x := func(v int) float64 {
+		return p.left + (p.right-p.left)*float64(v)/xMax
+	}
+	for v := 0.0; v <= xMax+xStep/2; v += xStep {
+		fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" text-anchor="middle">%d</text>`, x(int(v)), p.bottom+14, int(v))
+	}
+	fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" text-anchor="middle">%s</text>`, (p.left+p.right)/2, p.bottom+32, html.EscapeString(xLabel))
+	fmt.Fprintf(&b, `<text transform="translate(10 %.1f) rotate(-90)" text-anchor="middle">%s</text>`, (p.top+p.bottom)/2, html.EscapeString(yLabel))
+
+	// The largest bubbles are drawn first so that the smaller stay visible
+	order := make([]int, len(bubbles))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return bubbles[order[i]].Size > bubbles[order[j]].Size
+	})
+	for rank, i := range order {
+		bubble := bubbles[i]
+		r := 4.0
+		if maxSize > 0 {
+			r = math.Max(4, maxRadius*math.Sqrt(bubble.Size/maxSize))
+		}
+		fill := bubble.Color
+		if fill == "" {
+			fill = color
+		}
+		cx, cy := x(bubble.X), p.y(bubble.Y)
+		fmt.Fprintf(&b, `<circle cx="%.1f" cy="%.1f" r="%.1f" fill="%s" fill-opacity="0.6" stroke="%s"><title>%s (%d, %d)</title></circle>`,
+			cx, cy, r, fill, fill, html.EscapeString(bubble.Label), bubble.X, bubble.Y)
+		if rank < labelled {
+			fmt.Fprintf(&b, `<text x="%.1f" y="%.1f">%s</text>`, cx+r+3, cy+3, html.EscapeString(truncate(path.Base(bubble.Label), 24)))
+		}
+	}
+	b.WriteString(`</svg>`)
+	return b.String()
+}
+
+// PunchCard draws counts by weekday (rows, Sunday first) and hour (columns)
+// as circles whose area is proportional to the count
+func PunchCard(title string, dayNames []string, counts [7][24]int) string {
+	const rowHeight = 26.0
+	left := longestLabel(dayNames)*7 + 16
+	slot := (width - left - 10) / 24
+	h := 7*rowHeight + 30
+
+	var b strings.Builder
+	open(&b, title, h)
+	max := 0
+	for _, day := range counts {
+		if m := maxValue(day[:]); m > max {
+			max = m
+		}
+	}
+	maxRadius := math.Min(slot, rowHeight)/2 - 1
+	for day, hours := range counts {
+		y := 10 + rowHeight*(float64(day)+0.5)
+		name := ""
+		if day < len(dayNames) {
+			name = dayNames[day]
+		}
+		fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" text-anchor="end" dominant-baseline="middle">%s</text>`, left-8, y, html.EscapeString(name))
+		fmt.Fprintf(&b, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="%s"/>`, left, y, left+slot*24, y, gridColor)
+		for hour, count := range hours {
+			if count == 0 {
+				continue
+			}
+			r := math.Max(1.5, maxRadius*math.Sqrt(float64(count)/float64(max)))
+			fmt.Fprintf(&b, `<circle cx="%.1f" cy="%.1f" r="%.1f" fill="%s"><title>%s %02d:00: %d</title></circle>`,
+				left+slot*(float64(hour)+0.5), y, r, color, html.EscapeString(name), hour, count)
+		}
+	}
+	for hour := 0; hour < 24; hour++ {
+		fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" text-anchor="middle">%d</text>`, left+slot*(float64(hour)+0.5), 10+rowHeight*7+16, hour)
+	}
+	b.WriteString(`</svg>`)
+	return b.String()
+}
+
+// calendarColors are the colors of the calendar levels, from no commits to
+// the busiest days
+var calendarColors = []string{"#ebedf0", "#c6cdf7", "#9aa8f1", "#667eea", "#3f4fc7"}
+
+// CalendarLevel returns the color level, 0 to 4, of a day with count
+// commits when the busiest day has max
+func CalendarLevel(count, max int) int {
+	if count <= 0 || max <= 0 {
+		return 0
+	}
+	level := int(math.Ceil(float64(count) / float64(max) * 4))
+	if level > 4 {
+		level = 4
+	}
+	return level
+}
+
+// Calendar draws a GitHub-style calendar heatmap: a column of squares per
+// week starting at start (a Sunday), a row per weekday and the color by the
+// commits of the day. Days that are negative are left out. Every other
+// weekday is labelled, the months above the columns where they begin, and
+// less and more label the color legend.
+func Calendar(title string, start time.Time, weeks [][7]int, dayNames, monthNames []string, less, more string) string {
+	const cell, step = 10.0, 12.0
+	left := longestLabel(dayNames)*7 + 8
+	top := 16.0
+	w := left + step*float64(len(weeks)) + 8
+	h := top + step*7 + 24
+
+	max := 0
+	for _, week := range weeks {
+		if m := maxValue(week[:]); m > max {
+			max = m
+		}
+	}
+
+	var b strings.Builder
+	openSized(&b, title, w, h)
+	for day, name := range dayNames {
+		if day%2 == 1 {
+			fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" text-anchor="end" dominant-baseline="middle">%s</text>`,
+				left-6, top+step*float64(day)+cell/2, html.EscapeString(name))
+		}
+	}
+	for i, week := range weeks {
+		x := left + step*float64(i)
+		for day, count := range week {
+			date := start.AddDate(0, 0, i*7+day)
+			if date.Day() == 1 && int(date.Month()) <= len(monthNames) {
+				fmt.Fprintf(&b, `<text x="%.1f" y="%.1f">%s</text>`, x, top-5, html.EscapeString(monthNames[date.Month()-1]))
+			}
+			if count < 0 {
+				continue
+			}
+			fmt.Fprintf(&b, `<rect x="%.1f" y="%.1f" width="%.0f" height="%.0f" rx="2" fill="%s"><title>%s: %d</title></rect>`,
+				x, top+step*float64(day), cell, cell, calendarColors[CalendarLevel(count, max)], date.Format("2006-01-02"), count)
+		}
+	}
+
+	// Legend in the bottom right corner
+	y := top + step*7 + 8
+	x := w - 8 - step*float64(len(calendarColors)) - float64(len([]rune(more)))*6 - 4
+	fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" text-anchor="end" dominant-baseline="middle">%s</text>`, x-4, y+cell/2, html.EscapeString(less))
+	for i, c := range calendarColors {
+		fmt.Fprintf(&b, `<rect x="%.1f" y="%.1f" width="%.0f" height="%.0f" rx="2" fill="%s"/>`, x+step*float64(i), y, cell, cell, c)
+	}
+	fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" dominant-baseline="middle">%s</text>`, x+step*float64(len(calendarColors))+2, y+cell/2, html.EscapeString(more))
+	b.WriteString(`</svg>`)
+	return b.String()
+}
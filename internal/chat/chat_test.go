@@ -0,0 +1,201 @@
+package chat
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"git-log-analyzer/internal/ai"
+	"git-log-analyzer/internal/analyzer"
+	"git-log-analyzer/internal/fixture"
+)
+
+// analyzeHistory returns the analysis of a history in which Alice owns the
+// payments module and Bob the docs:
+//
+//	Alice  03-01  payments/charge.go, payments/refund.go
+//	Bob    03-02  docs/intro.md
+//	Alice  03-10  payments/charge.go
+//	Bob    04-02  payments/charge.go, docs/intro.md
+func analyzeHistory(t *testing.T) *analyzer.Statistics {
+	t.Helper()
+	day := func(month time.Month, d int) time.Time {
+		return time.Date(2024, month, d, 12, 0, 0, 0, time.Local)
+	}
+	repo := fixture.Build(t, fixture.Script{
+		fixture.Commit{Author: fixture.Alice, Date: day(3, 1), Message: "Add payments",
+			Files: map[string]string{"payments/charge.go": fixture.Lines(20), "payments/refund.go": fixture.Lines(10)}},
+		fixture.Commit{Author: fixture.Bob, Date: day(3, 2), Message: "Write intro",
+			Files: map[string]string{"docs/intro.md": fixture.Lines(5)}},
+		fixture.Commit{Author: fixture.Alice, Date: day(3, 10), Message: "Retry failed charges",
+			Files: map[string]string{"payments/charge.go": fixture.Lines(25)}},
+		fixture.Commit{Author: fixture.Bob, Date: day(4, 2), Message: "Fix charge rounding",
+			Files: map[string]string{"payments/charge.go": fixture.Lines(26), "docs/intro.md": fixture.Lines(6)}},
+	})
+
+	stats, err := analyzer.NewAnalyzerWithOptions(repo.Dir, analyzer.Options{KeepCommits: true}).Analyze(context.Background())
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	return stats
+}
+
+// runTool calls a tool and decodes its result
+func runTool(t *testing.T, stats *analyzer.Statistics, name, args string, results any) int {
+	t.Helper()
+	out, err := RunTool(stats, ai.ToolCall{Name: name, Arguments: args})
+	if err != nil {
+		t.Fatalf("%s %s failed: %v", name, args, err)
+	}
+	var result struct {
+		Total   int             `json:"total"`
+		Results json.RawMessage `json:"results"`
+	}
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("%s returned invalid JSON %s: %v", name, out, err)
+	}
+	if err := json.Unmarshal(result.Results, results); err != nil {
+		t.Fatalf("%s returned unexpected results %s: %v", name, out, err)
+	}
+	return result.Total
+}
+
+func TestRunTool_Authors(t *testing.T) {
+	stats := analyzeHistory(t)
+
+	var authors []authorResult
+	if total := runTool(t, stats, ToolAuthors, `{"path": "Payments/"}`, &authors); total != 2 {
+		t.Fatalf("Expected both authors to have changed payments, got %d", total)
+	}
+	if authors[0].Name != "Alice" || authors[0].FileChanges != 3 || authors[0].TopFiles[0] != "payments/charge.go" {
+		t.Errorf("Expected Alice to know payments best, got %+v", authors[0])
+	}
+	if authors[1].Name != "Bob" || authors[1].FileChanges != 1 {
+		t.Errorf("Unexpected second author %+v", authors[1])
+	}
+
+	if total := runTool(t, stats, ToolAuthors, `{"path": "docs", "limit": 1}`, &authors); total != 1 || len(authors) != 1 || authors[0].Name != "Bob" {
+		t.Errorf("Expected only Bob for docs, got %d: %+v", total, authors)
+	}
+}
+
+func TestRunTool_Files(t *testing.T) {
+	stats := analyzeHistory(t)
+
+	var files []fileResult
+	if total := runTool(t, stats, ToolFiles, `{"limit": 2}`, &files); total != 3 || len(files) != 2 {
+		t.Fatalf("Expected 2 of 3 files, got %d: %+v", total, files)
+	}
+	if files[0].Path != "payments/charge.go" || files[0].Changes != 3 || files[0].Authors["Alice"] != 2 || files[0].Authors["Bob"] != 1 {
+		t.Errorf("Unexpected most changed file %+v", files[0])
+	}
+}
+
+func TestRunTool_Commits(t *testing.T) {
+	stats := analyzeHistory(t)
+
+	var commits []commitResult
+	if total := runTool(t, stats, ToolCommits, `{"since": "2024-03-02", "until": "2024-03-10"}`, &commits); total != 2 {
+		t.Fatalf("Expected 2 commits in range, got %d: %+v", total, commits)
+	}
+	if commits[0].Subject != "Retry failed charges" || commits[1].Subject != "Write intro" {
+		t.Errorf("Expected the commits newest first, got %+v", commits)
+	}
+
+	if total := runTool(t, stats, ToolCommits, `{"author": "bob", "path": "payments"}`, &commits); total != 1 || commits[0].Subject != "Fix charge rounding" {
+		t.Errorf("Expected Bob's payments commit, got %+v", commits)
+	}
+
+	if _, err := RunTool(stats, ai.ToolCall{Name: ToolCommits, Arguments: `{"since": "March"}`}); err == nil {
+		t.Error("Expected an error for an invalid date")
+	}
+	if _, err := RunTool(stats, ai.ToolCall{Name: "deploys", Arguments: `{}`}); err == nil {
+		t.Error("Expected an error for an unknown tool")
+	}
+}
+
+func TestRunTool_Hotspots(t *testing.T) {
+	stats := analyzeHistory(t)
+
+	var hotspots []hotspotResult
+	total := runTool(t, stats, ToolHotspots, ``, &hotspots)
+	if total != len(stats.CodeHealthMetrics.TechnicalDebtHotspots) || len(hotspots) != total {
+		t.Errorf("Expected all %d hotspots, got %d", len(stats.CodeHealthMetrics.TechnicalDebtHotspots), total)
+	}
+}
+
+func TestSession_Scripted(t *testing.T) {
+	stats := analyzeHistory(t)
+	provider := ai.NewScriptedFakeProvider(
+		ai.ChatResponse{ToolCalls: []ai.ToolCall{{ID: "1", Name: ToolAuthors, Arguments: `{"path":"payments"}`}}},
+		ai.ChatResponse{Content: "Alice knows payments best."},
+		ai.ChatResponse{Content: "Bob fixed the rounding on 2024-04-02."},
+	)
+	client := ai.NewAIClientWithProvider(ai.WithDefaults(ai.AIConfig{Provider: ai.ProviderFake, Model: "m"}), provider)
+	session := NewSession(client, stats, "shop")
+
+	script := "# questions\nWho knows the payments module best?\n\nAnd what changed in April?\nexit\nnot asked\n"
+	var out bytes.Buffer
+	session.Trace = &out
+	if err := session.Run(context.Background(), strings.NewReader(script), &out, true); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	want := "> Who knows the payments module best?\n" +
+		"  🔧 authors {\"path\":\"payments\"}\n" +
+		"Alice knows payments best.\n\n" +
+		"> And what changed in April?\n" +
+		"Bob fixed the rounding on 2024-04-02.\n\n"
+	if out.String() != want {
+		t.Errorf("Unexpected transcript:\n%s\nwant:\n%s", out.String(), want)
+	}
+
+	requests := provider.Requests()
+	if len(requests) != 3 {
+		t.Fatalf("Expected 3 requests, got %d", len(requests))
+	}
+	if system := requests[0].Messages[0].Content; !strings.Contains(system, "Repository: shop") || !strings.Contains(system, "Commits: 4 by 2 authors") {
+		t.Errorf("Expected the overview in the system prompt, got:\n%s", system)
+	}
+	if result := requests[1].Messages[3]; result.Role != "tool" || !strings.Contains(result.Content, `"name":"Alice"`) {
+		t.Errorf("Expected the tool result to be sent back, got %+v", result)
+	}
+	// The follow-up question carries the earlier conversation
+	if n := len(requests[2].Messages); n != 6 {
+		t.Errorf("Expected the follow-up to include the history, got %d messages", n)
+	}
+}
+
+func TestSession_FakeProviderTools(t *testing.T) {
+	stats := analyzeHistory(t)
+	client := ai.NewAIClientWithProvider(ai.WithDefaults(ai.AIConfig{Provider: ai.ProviderFake, Model: "m"}), ai.NewFakeProvider())
+	session := NewSession(client, stats, "shop")
+
+	var trace bytes.Buffer
+	session.Trace = &trace
+	answer, err := session.Ask(context.Background(), "List the hotspots and recent commits")
+	if err != nil {
+		t.Fatalf("Ask failed: %v", err)
+	}
+	if !strings.HasPrefix(answer, "Fake analysis by m") || trace.String() != "  🔧 hotspots {}\n  🔧 commits {}\n" {
+		t.Errorf("Expected the fake provider to call the named tools, got %q, trace %q", answer, trace.String())
+	}
+}
+
+func TestSession_RunStopsOnCancel(t *testing.T) {
+	stats := analyzeHistory(t)
+	client := ai.NewAIClientWithProvider(ai.WithDefaults(ai.AIConfig{Provider: ai.ProviderFake, Model: "m"}), ai.NewFakeProvider())
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// A reader that never delivers a line must not block a canceled session
+	in, w := io.Pipe()
+	defer w.Close()
+	if err := NewSession(client, stats, "shop").Run(ctx, in, &bytes.Buffer{}, false); err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,137 @@
+// Package chat answers questions about an analyzed repository. The model
+// looks up authors, files, hotspots and commits through tool calls instead
+// of receiving the whole analysis up front.
+package chat
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"git-log-analyzer/internal/ai"
+	"git-log-analyzer/internal/analyzer"
+	"git-log-analyzer/internal/i18n"
+)
+
+// Session is a conversation about one analyzed repository. Earlier questions,
+// tool results and answers are kept so follow-up questions have context.
+type Session struct {
+	client   *ai.AIClient
+	stats    *analyzer.Statistics
+	messages []ai.ChatMessage
+
+	// Trace, when set, receives a line for every tool call of the model
+	Trace io.Writer
+}
+
+// NewSession starts a conversation about the repository named name
+func NewSession(client *ai.AIClient, stats *analyzer.Statistics, name string) *Session {
+	system := fmt.Sprintf(i18n.T().AIChatSystemPrompt, overview(stats, name))
+	return &Session{
+		client:   client,
+		stats:    stats,
+		messages: []ai.ChatMessage{{Role: "system", Content: system}},
+	}
+}
+
+// overview describes the repository in a few lines for the system prompt
+func overview(stats *analyzer.Statistics, name string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Repository: %s\n", name)
+	fmt.Fprintf(&sb, "Commits: %d by %d authors, %d files changed\n", stats.TotalCommits, len(stats.AuthorStats), len(stats.FileStats))
+	if ts := stats.TimeStats; ts != nil && !ts.FirstCommit.IsZero() {
+		fmt.Fprintf(&sb, "Period: %s to %s (%d active days)\n", ts.FirstCommit.Format("2006-01-02"), ts.LastCommit.Format("2006-01-02"), ts.ActiveDays)
+	}
+	if health := stats.CodeHealthMetrics; health != nil {
+		fmt.Fprintf(&sb, "Code health score: %.0f/100\n", health.HealthScore*100)
+	}
+	return sb.String()
+}
+
+// Ask sends a question and returns the model's answer. A failed question is
+// not kept in the conversation.
+func (s *Session) Ask(ctx context.Context, question string) (string, error) {
+	messages := append(append([]ai.ChatMessage(nil), s.messages...), ai.ChatMessage{Role: "user", Content: question})
+	added, err := s.client.Converse(ctx, messages, Tools(), s.runTool)
+	if err != nil {
+		return "", err
+	}
+	s.messages = append(messages, added...)
+	return added[len(added)-1].Content, nil
+}
+
+// runTool answers a tool call of the model and traces it
+func (s *Session) runTool(ctx context.Context, call ai.ToolCall) (string, error) {
+	if s.Trace != nil {
+		fmt.Fprintf(s.Trace, "  🔧 %s %s\n", call.Name, call.Arguments)
+	}
+	return RunTool(s.stats, call)
+}
+
+// Run reads one question per line from in and writes the answers to out
+// until the input ends or the user types "exit" or "quit".
+//
+// Interactive sessions prompt for each question and report failed questions
+// without ending the conversation. Scripted sessions echo each question,
+// skip lines starting with "#" and stop at the first failure, which makes
+// them suitable for tests with the fake provider.
+func (s *Session) Run(ctx context.Context, in io.Reader, out io.Writer, scripted bool) error {
+	lines := readLines(ctx, in)
+	for {
+		if !scripted {
+			fmt.Fprint(out, "> ")
+		}
+
+		var line string
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case l, ok := <-lines:
+			if !ok {
+				return nil
+			}
+			line = l
+		}
+
+		question := strings.TrimSpace(line)
+		if question == "" || (scripted && strings.HasPrefix(question, "#")) {
+			continue
+		}
+		if question == "exit" || question == "quit" {
+			return nil
+		}
+		if scripted {
+			fmt.Fprintf(out, "> %s\n", question)
+		}
+
+		answer, err := s.Ask(ctx, question)
+		if err != nil {
+			if scripted || ctx.Err() != nil {
+				return err
+			}
+			fmt.Fprintf(out, "❌ %v\n\n", err)
+			continue
+		}
+		fmt.Fprintf(out, "%s\n\n", strings.TrimSpace(answer))
+	}
+}
+
+// readLines delivers the lines of in until it ends or ctx is done, so a
+// blocked read doesn't keep Run from noticing Ctrl-C
+func readLines(ctx context.Context, in io.Reader) <-chan string {
+	lines := make(chan string)
+	go func() {
+		defer close(lines)
+		scanner := bufio.NewScanner(in)
+		for scanner.Scan() {
+			select {
+			case lines <- scanner.Text():
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return lines
+}
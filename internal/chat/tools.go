@@ -0,0 +1,366 @@
+package chat
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"git-log-analyzer/internal/ai"
+	"git-log-analyzer/internal/analyzer"
+)
+
+// Result limits of the tools
+const (
+	defaultLimit   = 10
+	maxLimit       = 50
+	maxCommitFiles = 10
+)
+
+// Tool names
+const (
+	ToolAuthors  = "authors"
+	ToolFiles    = "files"
+	ToolHotspots = "hotspots"
+	ToolCommits  = "commits"
+)
+
+// object returns the JSON Schema of an arguments object
+func object(properties map[string]any) map[string]any {
+	return map[string]any{"type": "object", "properties": properties}
+}
+
+var (
+	limitParam = map[string]any{"type": "integer", "description": fmt.Sprintf("Maximum number of results (default %d, at most %d)", defaultLimit, maxLimit)}
+	pathParam  = map[string]any{"type": "string", "description": "Only consider files whose path contains this text, e.g. a directory or module name"}
+)
+
+// Tools returns the repository queries offered to the model
+func Tools() []ai.Tool {
+	return []ai.Tool{
+		{
+			Name:        ToolAuthors,
+			Description: "List authors with their commits, changed lines and active period. With a path, rank the authors by how often they changed matching files, to find who knows a module best.",
+			Parameters:  object(map[string]any{"path": pathParam, "limit": limitParam}),
+		},
+		{
+			Name:        ToolFiles,
+			Description: "List the most changed files with their change counts and main authors.",
+			Parameters:  object(map[string]any{"path": pathParam, "limit": limitParam}),
+		},
+		{
+			Name:        ToolHotspots,
+			Description: "List technical debt hotspots: frequently changed files with a risk score and the reason.",
+			Parameters:  object(map[string]any{"limit": limitParam}),
+		},
+		{
+			Name:        ToolCommits,
+			Description: "List commits, newest first, optionally within a date range and filtered by author or path.",
+			Parameters: object(map[string]any{
+				"since":  map[string]any{"type": "string", "description": "First day, YYYY-MM-DD"},
+				"until":  map[string]any{"type": "string", "description": "Last day (inclusive), YYYY-MM-DD"},
+				"author": map[string]any{"type": "string", "description": "Only commits whose author name or email contains this text"},
+				"path":   pathParam,
+				"limit":  limitParam,
+			}),
+		},
+	}
+}
+
+// toolArgs are the arguments of all tools; each tool reads the ones it offers
+type toolArgs struct {
+	Path   string `json:"path"`
+	Limit  int    `json:"limit"`
+	Since  string `json:"since"`
+	Until  string `json:"until"`
+	Author string `json:"author"`
+}
+
+// toolResult is the reply of every tool. Total counts all matches so the
+// model knows when Results was cut off by the limit.
+type toolResult struct {
+	Total   int `json:"total"`
+	Results any `json:"results"`
+}
+
+// RunTool answers a tool call from the analysis
+func RunTool(stats *analyzer.Statistics, call ai.ToolCall) (string, error) {
+	var args toolArgs
+	if strings.TrimSpace(call.Arguments) != "" {
+		if err := json.Unmarshal([]byte(call.Arguments), &args); err != nil {
+			return "", fmt.Errorf("invalid arguments for %s: %v", call.Name, err)
+		}
+	}
+	if args.Limit <= 0 {
+		args.Limit = defaultLimit
+	}
+	if args.Limit > maxLimit {
+		args.Limit = maxLimit
+	}
+
+	var result toolResult
+	var err error
+	switch call.Name {
+	case ToolAuthors:
+		result = queryAuthors(stats, args)
+	case ToolFiles:
+		result = queryFiles(stats, args)
+	case ToolHotspots:
+		result = queryHotspots(stats, args)
+	case ToolCommits:
+		result, err = queryCommits(stats, args)
+	default:
+		return "", fmt.Errorf("unknown tool %q", call.Name)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode %s result: %v", call.Name, err)
+	}
+	return string(data), nil
+}
+
+// matchPath reports whether file matches a path filter, ignoring case
+func matchPath(file, path string) bool {
+	return path == "" || strings.Contains(strings.ToLower(file), strings.ToLower(path))
+}
+
+type authorResult struct {
+	Name        string   `json:"name"`
+	Email       string   `json:"email"`
+	Commits     int      `json:"commits"`
+	Additions   int      `json:"additions"`
+	Deletions   int      `json:"deletions"`
+	FirstCommit string   `json:"first_commit"`
+	LastCommit  string   `json:"last_commit"`
+	FileChanges int      `json:"file_changes,omitempty"` // changes to files matching the path
+	TopFiles    []string `json:"top_files,omitempty"`
+}
+
+func queryAuthors(stats *analyzer.Statistics, args toolArgs) toolResult {
+	var authors []authorResult
+	for _, as := range stats.AuthorStats {
+		a := authorResult{
+			Name:        as.Name,
+			Email:       as.Email,
+			Commits:     as.CommitCount,
+			Additions:   as.Additions,
+			Deletions:   as.Deletions,
+			FirstCommit: as.FirstCommit.Format("2006-01-02"),
+			LastCommit:  as.LastCommit.Format("2006-01-02"),
+		}
+		if args.Path != "" {
+			matching := make(map[string]int)
+			for file, changes := range as.Files {
+				if matchPath(file, args.Path) {
+					matching[file] = changes
+					a.FileChanges += changes
+				}
+			}
+			if a.FileChanges == 0 {
+				continue
+			}
+			a.TopFiles = topFiles(matching, 3)
+		}
+		authors = append(authors, a)
+	}
+
+	sort.Slice(authors, func(i, j int) bool {
+		if authors[i].FileChanges != authors[j].FileChanges {
+			return authors[i].FileChanges > authors[j].FileChanges
+		}
+		if authors[i].Commits != authors[j].Commits {
+			return authors[i].Commits > authors[j].Commits
+		}
+		return authors[i].Email < authors[j].Email
+	})
+	return limited(len(authors), args.Limit, func(n int) any { return authors[:n] })
+}
+
+type fileResult struct {
+	Path    string         `json:"path"`
+	Changes int            `json:"changes"`
+	Authors map[string]int `json:"authors"` // main authors and their changes
+}
+
+func queryFiles(stats *analyzer.Statistics, args toolArgs) toolResult {
+	matching := make(map[string]int)
+	for file, changes := range stats.FileStats {
+		if matchPath(file, args.Path) {
+			matching[file] = changes
+		}
+	}
+
+	paths := topFiles(matching, args.Limit)
+	files := make([]fileResult, len(paths))
+	for i, path := range paths {
+		byAuthor := make(map[string]int)
+		for _, as := range stats.AuthorStats {
+			if changes := as.Files[path]; changes > 0 {
+				byAuthor[as.Name] += changes
+			}
+		}
+		main := make(map[string]int)
+		for _, name := range topFiles(byAuthor, 3) {
+			main[name] = byAuthor[name]
+		}
+		files[i] = fileResult{Path: path, Changes: matching[path], Authors: main}
+	}
+	return toolResult{Total: len(matching), Results: files}
+}
+
+type hotspotResult struct {
+	Path          string  `json:"path"`
+	RiskScore     float64 `json:"risk_score"`
+	Modifications int     `json:"modifications"`
+	Authors       int     `json:"authors"`
+	LastModified  string  `json:"last_modified"`
+	Reason        string  `json:"reason"`
+}
+
+func queryHotspots(stats *analyzer.Statistics, args toolArgs) toolResult {
+	var hotspots []hotspotResult
+	if stats.CodeHealthMetrics != nil {
+		for _, h := range stats.CodeHealthMetrics.TechnicalDebtHotspots {
+			hotspots = append(hotspots, hotspotResult{
+				Path:          h.FilePath,
+				RiskScore:     h.RiskScore,
+				Modifications: h.ModificationFreq,
+				Authors:       h.UniqueAuthors,
+				LastModified:  h.LastModified.Format("2006-01-02"),
+				Reason:        h.Reason,
+			})
+		}
+	}
+	return limited(len(hotspots), args.Limit, func(n int) any { return hotspots[:n] })
+}
+
+type commitResult struct {
+	Hash      string   `json:"hash"`
+	Date      string   `json:"date"`
+	Author    string   `json:"author"`
+	Subject   string   `json:"subject"`
+	Additions int      `json:"additions"`
+	Deletions int      `json:"deletions"`
+	Files     []string `json:"files,omitempty"`
+	Merge     bool     `json:"merge,omitempty"`
+}
+
+func queryCommits(stats *analyzer.Statistics, args toolArgs) (toolResult, error) {
+	if stats.Commits == nil {
+		return toolResult{}, fmt.Errorf("the commit history is not part of this analysis")
+	}
+	since, err := parseDay(args.Since)
+	if err != nil {
+		return toolResult{}, err
+	}
+	until, err := parseDay(args.Until)
+	if err != nil {
+		return toolResult{}, err
+	}
+	author := strings.ToLower(args.Author)
+
+	var matches []analyzer.CommitRecord
+	for _, c := range stats.Commits {
+		if !since.IsZero() && c.Date.Before(since) {
+			continue
+		}
+		if !until.IsZero() && !c.Date.Before(until.AddDate(0, 0, 1)) {
+			continue
+		}
+		if author != "" && !strings.Contains(strings.ToLower(c.Author), author) {
+			continue
+		}
+		if args.Path != "" && !anyMatch(c.Files, args.Path) {
+			continue
+		}
+		matches = append(matches, c)
+	}
+	total := len(matches)
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Date.After(matches[j].Date)
+	})
+	if len(matches) > args.Limit {
+		matches = matches[:args.Limit]
+	}
+
+	commits := make([]commitResult, len(matches))
+	for i, c := range matches {
+		files := c.Files
+		if len(files) > maxCommitFiles {
+			files = files[:maxCommitFiles]
+		}
+		commits[i] = commitResult{
+			Hash:      shortHash(c.Hash),
+			Date:      c.Date.Format("2006-01-02 15:04"),
+			Author:    c.Author,
+			Subject:   c.Subject,
+			Additions: c.Additions,
+			Deletions: c.Deletions,
+			Files:     files,
+			Merge:     c.Merge,
+		}
+	}
+	return toolResult{Total: total, Results: commits}, nil
+}
+
+// parseDay reads a YYYY-MM-DD date in local time; empty means no bound
+func parseDay(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	day, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q, expected YYYY-MM-DD", value)
+	}
+	return day, nil
+}
+
+func anyMatch(files []string, path string) bool {
+	for _, f := range files {
+		if matchPath(f, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// limited returns the first limit of total results
+func limited(total, limit int, slice func(n int) any) toolResult {
+	if total == 0 {
+		return toolResult{Results: []any{}}
+	}
+	if limit > total {
+		limit = total
+	}
+	return toolResult{Total: total, Results: slice(limit)}
+}
+
+// topFiles returns the n keys with the highest counts, ties broken by key
+func topFiles(counts map[string]int, n int) []string {
+	keys := make([]string, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] != counts[keys[j]] {
+			return counts[keys[i]] > counts[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	if len(keys) > n {
+		keys = keys[:n]
+	}
+	return keys
+}
+
+func shortHash(hash string) string {
+	if len(hash) > 8 {
+		return hash[:8]
+	}
+	return hash
+}
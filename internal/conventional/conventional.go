@@ -0,0 +1,98 @@
+// Package conventional parses commit messages following the Conventional
+// Commits specification (https://www.conventionalcommits.org): a
+// "type(scope)!: description" header and "Token: value" footers.
+package conventional
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Commit is a parsed commit message
+type Commit struct {
+	Type         string // lower case, e.g. "feat"; empty when the header isn't conventional
+	Scope        string
+	Description  string // the subject without the type prefix
+	Breaking     bool   // "!" in the header or a BREAKING CHANGE footer
+	BreakingNote string // text of the BREAKING CHANGE footer
+	Trailers     []Trailer
+}
+
+// Trailer is a "Token: value" or "Token #value" footer of the message body
+type Trailer struct {
+	Key   string
+	Value string
+}
+
+var (
+	headerPattern  = regexp.MustCompile(`^(\w[\w-]*)(?:\(([^()]*)\))?(!)?: (\S.*)$`)
+	trailerPattern = regexp.MustCompile(`^(BREAKING[ -]CHANGE|[A-Za-z][\w-]*)(?:: | #)(.*)$`)
+)
+
+// Conventional reports whether the header follows the specification
+func (c Commit) Conventional() bool {
+	return c.Type != ""
+}
+
+// Trailer returns the value of the first trailer with the key, ignoring case
+func (c Commit) Trailer(key string) (string, bool) {
+	for _, t := range c.Trailers {
+		if strings.EqualFold(t.Key, key) {
+			return t.Value, true
+		}
+	}
+	return "", false
+}
+
+// Parse parses a commit subject and body. Subjects that aren't conventional
+// keep the whole subject as the description; trailers are parsed either way.
+func Parse(subject, body string) Commit {
+	subject = strings.TrimSpace(subject)
+	c := Commit{Description: subject, Trailers: ParseTrailers(body)}
+
+	if m := headerPattern.FindStringSubmatch(subject); m != nil {
+		c.Type = strings.ToLower(m[1])
+		c.Scope = strings.TrimSpace(m[2])
+		c.Breaking = m[3] == "!"
+		c.Description = strings.TrimSpace(m[4])
+	}
+
+	for _, t := range c.Trailers {
+		if isBreakingKey(t.Key) {
+			c.Breaking = true
+			c.BreakingNote = t.Value
+			break
+		}
+	}
+	return c
+}
+
+// ParseTrailers returns the trailers of the body's last paragraph. The
+// paragraph must start with a trailer; lines that aren't trailers continue
+// the value of the previous one.
+func ParseTrailers(body string) []Trailer {
+	body = strings.TrimSpace(strings.ReplaceAll(body, "\r\n", "\n"))
+	if body == "" {
+		return nil
+	}
+	paragraphs := strings.Split(body, "\n\n")
+	last := strings.TrimSpace(paragraphs[len(paragraphs)-1])
+
+	var trailers []Trailer
+	for _, line := range strings.Split(last, "\n") {
+		if m := trailerPattern.FindStringSubmatch(line); m != nil {
+			trailers = append(trailers, Trailer{Key: m[1], Value: strings.TrimSpace(m[2])})
+			continue
+		}
+		if len(trailers) == 0 {
+			return nil
+		}
+		t := &trailers[len(trailers)-1]
+		t.Value = strings.TrimSpace(t.Value + "\n" + strings.TrimSpace(line))
+	}
+	return trailers
+}
+
+func isBreakingKey(key string) bool {
+	return key == "BREAKING CHANGE" || key == "BREAKING-CHANGE"
+}
@@ -0,0 +1,47 @@
+package conventional
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		subject, body string
+		want          Commit
+	}{
+		{"feat(parser): support trailers", "", Commit{Type: "feat", Scope: "parser", Description: "support trailers"}},
+		{"Fix!: drop the v1 API", "", Commit{Type: "fix", Description: "drop the v1 API", Breaking: true}},
+		{"refactor(api)!: rename handlers", "", Commit{Type: "refactor", Scope: "api", Description: "rename handlers", Breaking: true}},
+		{"Update README", "", Commit{Description: "Update README"}},
+		{"feat:missing space", "", Commit{Description: "feat:missing space"}},
+		{
+			"feat: new config format", "Longer explanation.\n\nBREAKING CHANGE: the old keys\nare no longer read\nRefs: #12",
+			Commit{Type: "feat", Description: "new config format", Breaking: true, BreakingNote: "the old keys\nare no longer read",
+				Trailers: []Trailer{{"BREAKING CHANGE", "the old keys\nare no longer read"}, {"Refs", "#12"}}},
+		},
+	}
+	for _, tt := range tests {
+		if got := Parse(tt.subject, tt.body); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Parse(%q, %q) = %+v, want %+v", tt.subject, tt.body, got, tt.want)
+		}
+	}
+}
+
+func TestParseTrailers(t *testing.T) {
+	body := "Explain the change.\n\nSigned-off-by: Alice <alice@example.com>\nFixes #42\nBREAKING-CHANGE: config moved"
+	want := []Trailer{{"Signed-off-by", "Alice <alice@example.com>"}, {"Fixes", "42"}, {"BREAKING-CHANGE", "config moved"}}
+	if got := ParseTrailers(body); !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseTrailers = %+v, want %+v", got, want)
+	}
+
+	// A last paragraph of prose has no trailers
+	if got := ParseTrailers("First paragraph.\n\nThis is prose: not a trailer block"); got != nil {
+		t.Errorf("Expected no trailers, got %+v", got)
+	}
+
+	c := Parse("feat: x", body)
+	if v, ok := c.Trailer("signed-off-by"); !ok || v != "Alice <alice@example.com>" || !c.Breaking {
+		t.Errorf("Unexpected trailer lookup %q, %v (breaking %v)", v, ok, c.Breaking)
+	}
+}
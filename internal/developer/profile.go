@@ -1,6 +1,7 @@
 package developer
 
 import (
+	"context"
 	"fmt"
 	"math"
 	"sort"
@@ -11,15 +12,22 @@ import (
 
 // DeveloperProfile represents a developer's work style profile
 type DeveloperProfile struct {
-	Name                string                 `json:"name"`
-	Email               string                 `json:"email"`
-	WorkStyleMetrics    WorkStyleMetrics       `json:"work_style_metrics"`
-	CodingPatterns      CodingPatterns         `json:"coding_patterns"`
-	CollaborationStyle  CollaborationStyle     `json:"collaboration_style"`
-	TimeManagement      TimeManagement         `json:"time_management"`
-	QualityIndicators   QualityIndicators      `json:"quality_indicators"`
-	TechnicalProfile    TechnicalProfile       `json:"technical_profile"`
-	PersonalityTraits   PersonalityTraits      `json:"personality_traits"`
+	Name               string             `json:"name"`
+	Email              string             `json:"email"`
+	WorkStyleMetrics   WorkStyleMetrics   `json:"work_style_metrics"`
+	CodingPatterns     CodingPatterns     `json:"coding_patterns"`
+	CollaborationStyle CollaborationStyle `json:"collaboration_style"`
+	TimeManagement     TimeManagement     `json:"time_management"`
+	QualityIndicators  QualityIndicators  `json:"quality_indicators"`
+	TechnicalProfile   TechnicalProfile   `json:"technical_profile"`
+	PersonalityTraits  PersonalityTraits  `json:"personality_traits"`
+	AINarrative        *AINarrative       `json:"ai_narrative,omitempty"` // set by the optional AI pass
+}
+
+// AINarrative is the model's description of a developer's work
+type AINarrative struct {
+	Narrative   string   `json:"narrative"`
+	Suggestions []string `json:"suggestions"` // growth suggestions
 }
 
 // WorkStyleMetrics contains metrics about work style
@@ -97,10 +105,13 @@ func NewProfileAnalyzer(stats *analyzer.Statistics) *ProfileAnalyzer {
 }
 
 // AnalyzeAllDevelopers analyzes all developers and returns their profiles
-func (pa *ProfileAnalyzer) AnalyzeAllDevelopers() []*DeveloperProfile {
+func (pa *ProfileAnalyzer) AnalyzeAllDevelopers(ctx context.Context) ([]*DeveloperProfile, error) {
 	var profiles []*DeveloperProfile
 	
 	for _, authorStat := range pa.stats.AuthorStats {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		profile := pa.AnalyzeDeveloper(authorStat)
 		profiles = append(profiles, profile)
 	}
@@ -110,7 +121,7 @@ func (pa *ProfileAnalyzer) AnalyzeAllDevelopers() []*DeveloperProfile {
 		return profiles[i].WorkStyleMetrics.CommitFrequency > profiles[j].WorkStyleMetrics.CommitFrequency
 	})
 	
-	return profiles
+	return profiles, nil
 }
 
 // AnalyzeDeveloper analyzes a single developer
@@ -187,6 +198,19 @@ func (dp *DeveloperProfile) GenerateReport() string {
 	report.WriteString(fmt.Sprintf("  细节导向: %s\n", dp.PersonalityTraits.DetailOrientation))
 	report.WriteString(fmt.Sprintf("  完美主义程度: %.1f/100\n", dp.PersonalityTraits.PerfectionismLevel))
 	report.WriteString("\n")
+
+	// AI narrative
+	if dp.AINarrative != nil {
+		report.WriteString("🤖 AI 画像解读:\n")
+		report.WriteString(fmt.Sprintf("  %s\n", dp.AINarrative.Narrative))
+		if len(dp.AINarrative.Suggestions) > 0 {
+			report.WriteString("  成长建议:\n")
+			for _, s := range dp.AINarrative.Suggestions {
+				report.WriteString(fmt.Sprintf("  - %s\n", s))
+			}
+		}
+		report.WriteString("\n")
+	}
 	
 	return report.String()
 }
@@ -464,6 +488,14 @@ func (pa *ProfileAnalyzer) getPreferredFileTypes(authorStat *analyzer.AuthorStat
 }
 
 func (pa *ProfileAnalyzer) estimateCommitMessageQuality(authorStat *analyzer.AuthorStat) float64 {
+	// Use the scored commit messages when available
+	if mq := pa.stats.MessageQuality; mq != nil {
+		key := fmt.Sprintf("%s <%s>", authorStat.Name, authorStat.Email)
+		if score, ok := mq.AuthorScores[key]; ok {
+			return score
+		}
+	}
+
 	// Simplified: estimate based on activity level
 	if authorStat.CommitCount > 100 {
 		return 75.0
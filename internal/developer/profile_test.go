@@ -0,0 +1,96 @@
+package developer
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"git-log-analyzer/internal/analyzer"
+	"git-log-analyzer/internal/fixture"
+	"git-log-analyzer/internal/quality"
+)
+
+func TestAnalyzeAllDevelopers(t *testing.T) {
+	day := func(d int) time.Time {
+		return time.Date(2023, 3, d, 10, 0, 0, 0, time.UTC)
+	}
+	repo := fixture.Build(t, fixture.Script{
+		fixture.Commit{Author: fixture.Alice, Date: day(1), Message: "Initial commit",
+			Files: map[string]string{"app.go": fixture.Lines(10)}},
+		fixture.Commit{Author: fixture.Bob, Date: day(2), Message: "Extend app",
+			Files: map[string]string{"app.go": fixture.Lines(12)}},
+		fixture.Commit{Author: fixture.Alice, Date: day(3), Message: "Trim app",
+			Files: map[string]string{"app.go": fixture.Lines(5)}},
+	})
+
+	stats, err := analyzer.NewAnalyzer(repo.Dir).Analyze(context.Background())
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+
+	profiles, err := NewProfileAnalyzer(stats).AnalyzeAllDevelopers(context.Background())
+	if err != nil {
+		t.Fatalf("AnalyzeAllDevelopers failed: %v", err)
+	}
+	if len(profiles) != 2 {
+		t.Fatalf("Expected 2 profiles, got %d", len(profiles))
+	}
+
+	// Alice: 2 commits over 2 days, +10/-7
+	alice := profiles[0]
+	if alice.Name != "Alice" || alice.Email != "alice@example.com" {
+		t.Fatalf("Expected the most active developer to be Alice, got %s <%s>", alice.Name, alice.Email)
+	}
+	assertFloat(t, "Alice commit frequency", alice.WorkStyleMetrics.CommitFrequency, 1.0)
+	assertFloat(t, "Alice average commit size", alice.WorkStyleMetrics.AverageCommitSize, 8.5)
+	assertFloat(t, "Alice code stability", alice.QualityIndicators.CodeStabilityScore, 100-700.0/11)
+	assertFloat(t, "Alice technical debt", alice.QualityIndicators.TechnicalDebtRatio, 50)
+	if alice.CodingPatterns.PreferredCommitSize != "atomic" || alice.PersonalityTraits.PlanningOrientation != "planner" {
+		t.Errorf("Expected atomic commits and a planner, got %s / %s",
+			alice.CodingPatterns.PreferredCommitSize, alice.PersonalityTraits.PlanningOrientation)
+	}
+	if alice.PersonalityTraits.WorkStyleType != "balanced" || alice.CollaborationStyle.MentorshipLevel != "learner" {
+		t.Errorf("Expected a balanced learner, got %s / %s",
+			alice.PersonalityTraits.WorkStyleType, alice.CollaborationStyle.MentorshipLevel)
+	}
+
+	// Bob: 1 commit, +2/-0
+	bob := profiles[1]
+	if bob.Name != "Bob" {
+		t.Fatalf("Expected Bob second, got %s", bob.Name)
+	}
+	assertFloat(t, "Bob commit frequency", bob.WorkStyleMetrics.CommitFrequency, 0.5)
+	assertFloat(t, "Bob average commit size", bob.WorkStyleMetrics.AverageCommitSize, 2)
+	assertFloat(t, "Bob code stability", bob.QualityIndicators.CodeStabilityScore, 100)
+	assertFloat(t, "Bob technical debt", bob.QualityIndicators.TechnicalDebtRatio, 0)
+	assertFloat(t, "Bob perfectionism", bob.PersonalityTraits.PerfectionismLevel, (45+100+100)/3.0)
+
+	// Scored commit messages replace the activity-based estimate
+	stats.MessageQuality = &quality.MessageQuality{AuthorScores: map[string]float64{"Bob <bob@example.com>": 90}}
+	pa := NewProfileAnalyzer(stats)
+	bob = pa.AnalyzeDeveloper(stats.AuthorStats["Bob <bob@example.com>"])
+	assertFloat(t, "Bob scored message quality", bob.QualityIndicators.CommitMessageQuality, 90)
+	alice = pa.AnalyzeDeveloper(stats.AuthorStats["Alice <alice@example.com>"])
+	assertFloat(t, "Alice estimated message quality", alice.QualityIndicators.CommitMessageQuality, 45)
+}
+
+func TestAnalyzeAllDevelopers_Canceled(t *testing.T) {
+	stats := &analyzer.Statistics{AuthorStats: map[string]*analyzer.AuthorStat{
+		"Alice <alice@example.com>": {Name: "Alice", CommitCount: 1},
+	}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := NewProfileAnalyzer(stats).AnalyzeAllDevelopers(ctx); err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func assertFloat(t *testing.T, name string, got, want float64) {
+	t.Helper()
+	if math.Abs(got-want) > 1e-9 {
+		t.Errorf("%s: expected %.4f, got %.4f", name, want, got)
+	}
+}
@@ -0,0 +1,258 @@
+// Package fixture builds throwaway git repositories from a declarative script
+// so tests can assert exact numbers instead of depending on the host checkout.
+//
+//	repo := fixture.Build(t, fixture.Script{
+//		fixture.Commit{Author: fixture.Alice, Date: day(1), Message: "init", Files: map[string]string{"main.go": fixture.Lines(3)}},
+//		fixture.Branch{Name: "feature"},
+//		fixture.Checkout{Ref: "feature"},
+//		fixture.Commit{ID: "feat", Author: fixture.Bob, Date: day(2), Message: "feat", Files: map[string]string{"api.go": fixture.Lines(5)}},
+//		fixture.Checkout{Ref: "main"},
+//		fixture.Merge{Branch: "feature", Author: fixture.Alice, Date: day(3)},
+//		fixture.Tag{Name: "v1.0.0"},
+//	})
+package fixture
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// DefaultBranch is the name of the branch a fixture repository starts on
+const DefaultBranch = "main"
+
+// Author identifies a commit author
+type Author struct {
+	Name  string
+	Email string
+}
+
+// Commonly used authors
+var (
+	Alice = Author{Name: "Alice", Email: "alice@example.com"}
+	Bob   = Author{Name: "Bob", Email: "bob@example.com"}
+	Carol = Author{Name: "Carol", Email: "carol@example.com"}
+)
+
+// Script is an ordered list of steps applied to a fresh repository
+type Script []Step
+
+// Step is a single instruction of a Script
+type Step interface {
+	apply(b *builder)
+}
+
+// Commit writes, deletes and renames files, then commits them. A commit
+// without any file change is recorded as an empty commit.
+type Commit struct {
+	ID      string // optional label for Repo.Hash
+	Author  Author
+	Date    time.Time // author and committer date
+	Message string
+	Files   map[string]string // path -> full file content
+	Delete  []string
+	Rename  map[string]string // old path -> new path
+}
+
+// Branch creates a branch at HEAD without checking it out
+type Branch struct {
+	Name string
+}
+
+// Checkout switches to an existing branch, tag or commit
+type Checkout struct {
+	Ref string
+}
+
+// Merge merges a branch into the current one, always creating a merge commit
+type Merge struct {
+	ID      string // optional label for Repo.Hash
+	Branch  string
+	Author  Author
+	Date    time.Time
+	Message string // defaults to git's "Merge branch '<name>'"
+}
+
+// Tag creates a tag at HEAD. A non-empty Message creates an annotated tag.
+type Tag struct {
+	Name    string
+	Message string
+	Author  Author
+	Date    time.Time
+}
+
+// Repo is a repository built from a Script
+type Repo struct {
+	Dir    string
+	hashes map[string]string
+}
+
+// Hash returns the commit hash recorded for a Commit or Merge ID
+func (r *Repo) Hash(id string) string {
+	return r.hashes[id]
+}
+
+// Apply runs more of a script on an existing repository, e.g. to add
+// commits after a first analysis
+func (r *Repo) Apply(tb testing.TB, script Script) {
+	tb.Helper()
+	b := &builder{tb: tb, repo: r}
+	for _, step := range script {
+		step.apply(b)
+	}
+}
+
+// Lines returns n numbered lines. Growing a file from Lines(3) to Lines(5)
+// is exactly two additions and no deletions.
+func Lines(n int) string {
+	var sb strings.Builder
+	for i := 1; i <= n; i++ {
+		fmt.Fprintf(&sb, "line %d\n", i)
+	}
+	return sb.String()
+}
+
+// Build creates a repository in a temporary directory and applies script.
+// The test is skipped when git is not installed.
+func Build(tb testing.TB, script Script) *Repo {
+	tb.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		tb.Skip("Git is not installed, skipping test")
+	}
+
+	b := &builder{
+		tb:   tb,
+		repo: &Repo{Dir: tb.TempDir(), hashes: make(map[string]string)},
+	}
+	b.git(nil, "init", "-q", "-b", DefaultBranch)
+	for _, step := range script {
+		step.apply(b)
+	}
+	return b.repo
+}
+
+// builder applies steps to a repository, failing the test on any error
+type builder struct {
+	tb   testing.TB
+	repo *Repo
+}
+
+// git runs a git command in the repository with an isolated configuration
+func (b *builder) git(env []string, args ...string) string {
+	b.tb.Helper()
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = b.repo.Dir
+	cmd.Env = append(os.Environ(),
+		"GIT_CONFIG_GLOBAL="+os.DevNull,
+		"GIT_CONFIG_NOSYSTEM=1",
+		"GIT_AUTHOR_NAME=Fixture", "GIT_AUTHOR_EMAIL=fixture@example.com",
+		"GIT_COMMITTER_NAME=Fixture", "GIT_COMMITTER_EMAIL=fixture@example.com",
+	)
+	cmd.Env = append(cmd.Env, env...)
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		b.tb.Fatalf("fixture: git %s failed: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// identity returns the environment that sets author and committer
+func identity(author Author, date time.Time) []string {
+	stamp := date.Format(time.RFC3339)
+	return []string{
+		"GIT_AUTHOR_NAME=" + author.Name, "GIT_AUTHOR_EMAIL=" + author.Email,
+		"GIT_COMMITTER_NAME=" + author.Name, "GIT_COMMITTER_EMAIL=" + author.Email,
+		"GIT_AUTHOR_DATE=" + stamp, "GIT_COMMITTER_DATE=" + stamp,
+	}
+}
+
+// record stores the HEAD hash under id
+func (b *builder) record(id string) {
+	if id != "" {
+		b.repo.hashes[id] = b.git(nil, "rev-parse", "HEAD")
+	}
+}
+
+func (c Commit) apply(b *builder) {
+	b.tb.Helper()
+
+	// Sort paths so the steps run in a stable order
+	olds := make([]string, 0, len(c.Rename))
+	for old := range c.Rename {
+		olds = append(olds, old)
+	}
+	sort.Strings(olds)
+	for _, old := range olds {
+		target := filepath.Join(b.repo.Dir, c.Rename[old])
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			b.tb.Fatal(err)
+		}
+		b.git(nil, "mv", old, c.Rename[old])
+	}
+
+	paths := make([]string, 0, len(c.Files))
+	for path := range c.Files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		target := filepath.Join(b.repo.Dir, path)
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			b.tb.Fatal(err)
+		}
+		if err := os.WriteFile(target, []byte(c.Files[path]), 0644); err != nil {
+			b.tb.Fatal(err)
+		}
+		b.git(nil, "add", "--", path)
+	}
+
+	for _, path := range c.Delete {
+		b.git(nil, "rm", "-q", "--", path)
+	}
+
+	message := c.Message
+	if message == "" {
+		message = "commit"
+	}
+	b.git(identity(c.Author, c.Date), "commit", "-q", "--allow-empty", "-m", message)
+	b.record(c.ID)
+}
+
+func (s Branch) apply(b *builder) {
+	b.tb.Helper()
+	b.git(nil, "branch", s.Name)
+}
+
+func (s Checkout) apply(b *builder) {
+	b.tb.Helper()
+	b.git(nil, "checkout", "-q", s.Ref)
+}
+
+func (m Merge) apply(b *builder) {
+	b.tb.Helper()
+	args := []string{"merge", "-q", "--no-ff"}
+	if m.Message != "" {
+		args = append(args, "-m", m.Message)
+	} else {
+		args = append(args, "--no-edit")
+	}
+	args = append(args, m.Branch)
+	b.git(identity(m.Author, m.Date), args...)
+	b.record(m.ID)
+}
+
+func (t Tag) apply(b *builder) {
+	b.tb.Helper()
+	if t.Message == "" {
+		b.git(nil, "tag", t.Name)
+		return
+	}
+	b.git(identity(t.Author, t.Date), "tag", "-a", t.Name, "-m", t.Message)
+}
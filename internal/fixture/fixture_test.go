@@ -0,0 +1,72 @@
+package fixture
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+func gitOutput(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("git %s failed: %v", strings.Join(args, " "), err)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func TestBuild(t *testing.T) {
+	date := time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC)
+	repo := Build(t, Script{
+		Commit{ID: "init", Author: Alice, Date: date, Message: "Initial commit",
+			Files: map[string]string{"main.go": Lines(3)}},
+		Branch{Name: "feature"},
+		Checkout{Ref: "feature"},
+		Commit{ID: "feat", Author: Bob, Date: date.Add(time.Hour), Message: "Add api",
+			Files: map[string]string{"api/api.go": Lines(5)}},
+		Checkout{Ref: DefaultBranch},
+		Merge{ID: "merge", Branch: "feature", Author: Alice, Date: date.Add(2 * time.Hour)},
+		Commit{Author: Carol, Date: date.Add(3 * time.Hour), Message: "Move api",
+			Rename: map[string]string{"api/api.go": "api.go"}, Delete: []string{"main.go"}},
+		Tag{Name: "v1.0.0", Message: "Release", Author: Alice, Date: date.Add(4 * time.Hour)},
+	})
+
+	log := gitOutput(t, repo.Dir, "log", "--format=%an|%ae|%aI|%s")
+	expected := strings.Join([]string{
+		"Carol|carol@example.com|2023-03-01T13:00:00+00:00|Move api",
+		"Alice|alice@example.com|2023-03-01T12:00:00+00:00|Merge branch 'feature'",
+		"Bob|bob@example.com|2023-03-01T11:00:00+00:00|Add api",
+		"Alice|alice@example.com|2023-03-01T10:00:00+00:00|Initial commit",
+	}, "\n")
+	if log != expected {
+		t.Errorf("Unexpected history:\n%s\nexpected:\n%s", log, expected)
+	}
+
+	if parents := gitOutput(t, repo.Dir, "rev-list", "--parents", "-n", "1", repo.Hash("merge")); parents != strings.Join([]string{repo.Hash("merge"), repo.Hash("init"), repo.Hash("feat")}, " ") {
+		t.Errorf("Merge should have init and feat as parents, got %s", parents)
+	}
+	if files := gitOutput(t, repo.Dir, "ls-files"); files != "api.go" {
+		t.Errorf("Expected only api.go to remain, got %q", files)
+	}
+	if tagged := gitOutput(t, repo.Dir, "rev-parse", "v1.0.0^{commit}"); tagged != gitOutput(t, repo.Dir, "rev-parse", "HEAD") {
+		t.Errorf("Tag should point at HEAD")
+	}
+	if kind := gitOutput(t, repo.Dir, "cat-file", "-t", "v1.0.0"); kind != "tag" {
+		t.Errorf("Expected an annotated tag, got %s", kind)
+	}
+	if repo.Hash("unknown") != "" {
+		t.Errorf("Unknown IDs should have no hash")
+	}
+}
+
+func TestLines(t *testing.T) {
+	if got := Lines(2); got != "line 1\nline 2\n" {
+		t.Errorf("Unexpected lines %q", got)
+	}
+	if got := Lines(0); got != "" {
+		t.Errorf("Expected empty content, got %q", got)
+	}
+}
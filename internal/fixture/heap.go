@@ -0,0 +1,48 @@
+package fixture
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// PeakHeap runs fn while sampling the live heap and returns the peak heap
+// growth over the baseline in bytes
+func PeakHeap(fn func()) uint64 {
+	runtime.GC()
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	baseline := stats.HeapAlloc
+
+	var peak atomic.Uint64
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(time.Millisecond)
+		defer ticker.Stop()
+		var sample runtime.MemStats
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				runtime.ReadMemStats(&sample)
+				if sample.HeapAlloc > peak.Load() {
+					peak.Store(sample.HeapAlloc)
+				}
+			}
+		}
+	}()
+
+	fn()
+	close(done)
+	wg.Wait()
+
+	if peak.Load() < baseline {
+		return 0
+	}
+	return peak.Load() - baseline
+}
@@ -0,0 +1,61 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Backend provides read access to a repository's history. The exec-based
+// Repository shells out to the git binary; GoGitRepository reads the object
+// database directly and needs no git executable.
+type Backend interface {
+	// IsGitRepository reports whether the backend points at a valid repository
+	IsGitRepository(ctx context.Context) bool
+	// GetCommits returns up to limit commits reachable from HEAD (0 means all)
+	GetCommits(ctx context.Context, limit int) ([]GitCommit, error)
+	// StreamCommits calls fn for each commit reachable from HEAD, one at a time
+	StreamCommits(ctx context.Context, limit int, fn func(GitCommit) error) error
+	// GetCommitStats returns additions, deletions and changed files of a commit
+	GetCommitStats(ctx context.Context, hash string) (int, int, []string, error)
+	// GetBranches returns local and remote branch names without duplicates
+	GetBranches(ctx context.Context) ([]string, error)
+	// GetBranchCommits returns the commits reachable from a branch
+	GetBranchCommits(ctx context.Context, branch string) ([]GitCommit, error)
+	// GetCommitBranch returns the name of a local branch containing a commit
+	GetCommitBranch(ctx context.Context, commitHash string) (string, error)
+	// GetRangeCommits returns the commits reachable from revision to but not
+	// from revision from, newest first and with complete message bodies. An
+	// empty from returns the whole history of to.
+	GetRangeCommits(ctx context.Context, from, to string) ([]GitCommit, error)
+	// GetTags returns the repository's tags with the commits they point to
+	GetTags(ctx context.Context) ([]Tag, error)
+}
+
+// Tag is a tag and the commit it points to
+type Tag struct {
+	Name string
+	Hash string    // commit hash; annotated tags are peeled
+	Date time.Time // tagger date of annotated tags, commit date otherwise
+}
+
+// Backend kinds accepted by NewBackend
+const (
+	BackendExec  = "exec"
+	BackendGoGit = "go"
+)
+
+var _ Backend = (*Repository)(nil)
+var _ Backend = (*GoGitRepository)(nil)
+
+// NewBackend creates the git backend of the given kind for the repository at path
+func NewBackend(kind, path string) (Backend, error) {
+	switch kind {
+	case "", BackendExec:
+		return NewRepository(path), nil
+	case BackendGoGit:
+		return OpenGoGitRepository(path)
+	default:
+		return nil, fmt.Errorf("unknown git backend %q (expected %q or %q)", kind, BackendExec, BackendGoGit)
+	}
+}
@@ -2,6 +2,7 @@ package git
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
@@ -35,120 +36,210 @@ func NewRepository(path string) *Repository {
 	return &Repository{Path: path}
 }
 
+// command builds a git command bound to ctx and rooted at the repository path
+func (r *Repository) command(ctx context.Context, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = r.Path
+	return cmd
+}
+
 // IsGitRepository checks if the given path is a valid git repository
-func (r *Repository) IsGitRepository() bool {
+func (r *Repository) IsGitRepository(ctx context.Context) bool {
 	gitDir := filepath.Join(r.Path, ".git")
 	if _, err := os.Stat(gitDir); err == nil {
 		return true
 	}
 
 	// Check if it's inside a git repository
-	cmd := exec.Command("git", "rev-parse", "--git-dir")
-	cmd.Dir = r.Path
+	cmd := r.command(ctx, "rev-parse", "--git-dir")
 	err := cmd.Run()
 	return err == nil
 }
 
 // IsGitInstalled checks if git command is available
-func IsGitInstalled() bool {
-	cmd := exec.Command("git", "--version")
+func IsGitInstalled(ctx context.Context) bool {
+	cmd := exec.CommandContext(ctx, "git", "--version")
 	err := cmd.Run()
 	return err == nil
 }
 
+// logFormat is the git log format: hash|author|email|date|subject|body|parents
+const logFormat = "--pretty=format:%H|%an|%ae|%ai|%s|%b|%P"
+
+// isoDateLayout matches the %ai date placeholder
+const isoDateLayout = "2006-01-02 15:04:05 -0700"
+
+// maxLogLineSize bounds a single line of git log output when streaming
+const maxLogLineSize = 16 * 1024 * 1024
+
 // GetCommits retrieves git commits from the repository
-func (r *Repository) GetCommits(limit int) ([]GitCommit, error) {
-	if !IsGitInstalled() {
+func (r *Repository) GetCommits(ctx context.Context, limit int) ([]GitCommit, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if !IsGitInstalled(ctx) {
 		return nil, fmt.Errorf("git is not installed or not available in PATH")
 	}
 
-	if !r.IsGitRepository() {
+	if !r.IsGitRepository(ctx) {
 		return nil, fmt.Errorf("not a git repository: %s", r.Path)
 	}
 
-	// Git log format: hash|author|email|date|subject|body|parents
-	format := "--pretty=format:%H|%an|%ae|%ai|%s|%b|%P"
-	args := []string{"log", format}
+	args := []string{"log", logFormat}
 	if limit > 0 {
 		args = append(args, fmt.Sprintf("-%d", limit))
 	}
 
-	cmd := exec.Command("git", args...)
-	cmd.Dir = r.Path
+	cmd := r.command(ctx, args...)
 	output, err := cmd.Output()
 	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
 		return nil, fmt.Errorf("failed to get git log: %v", err)
 	}
 
 	return parseCommits(string(output))
 }
 
+// StreamCommits reads git log through a pipe and calls fn for each commit as
+// soon as it is parsed, so only one commit is held in memory at a time. An
+// error returned by fn stops the stream and is returned as is.
+func (r *Repository) StreamCommits(ctx context.Context, limit int, fn func(GitCommit) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if !IsGitInstalled(ctx) {
+		return fmt.Errorf("git is not installed or not available in PATH")
+	}
+
+	if !r.IsGitRepository(ctx) {
+		return fmt.Errorf("not a git repository: %s", r.Path)
+	}
+
+	args := []string{"log", logFormat}
+	if limit > 0 {
+		args = append(args, fmt.Sprintf("-%d", limit))
+	}
+
+	// The stream context lets us stop git early when fn fails
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	cmd := r.command(streamCtx, args...)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("failed to open git log pipe: %v", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start git log: %v", err)
+	}
+
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 64*1024), maxLogLineSize)
+	for scanner.Scan() {
+		commit, ok := parseCommitLine(scanner.Text())
+		if !ok {
+			continue
+		}
+		if err := fn(commit); err != nil {
+			cancel()
+			cmd.Wait()
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		cancel()
+		cmd.Wait()
+		return fmt.Errorf("failed to read git log: %v", err)
+	}
+
+	if err := cmd.Wait(); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("failed to get git log: %v", err)
+	}
+
+	return nil
+}
+
 // parseCommits parses git log output into GitCommit structs
 func parseCommits(output string) ([]GitCommit, error) {
 	var commits []GitCommit
 	lines := strings.Split(output, "\n")
 	
 	for i := 0; i < len(lines); i++ {
-		line := strings.TrimSpace(lines[i])
-		if line == "" {
-			continue
+		if commit, ok := parseCommitLine(lines[i]); ok {
+			commits = append(commits, commit)
 		}
+	}
 
-		parts := strings.SplitN(line, "|", 7)
-		if len(parts) < 5 {
-			continue
-		}
+	return commits, nil
+}
 
-		date, err := time.Parse("2006-01-02 15:04:05 -0700", parts[3])
-		if err != nil {
-			// Try alternative format
-			date, err = time.Parse("2006-01-02T15:04:05-07:00", parts[3])
-			if err != nil {
-				continue
-			}
-		}
+// parseCommitLine parses a single line of logFormat output. It reports false
+// for lines that are not commit records.
+func parseCommitLine(line string) (GitCommit, bool) {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return GitCommit{}, false
+	}
 
-		body := ""
-		if len(parts) > 5 {
-			body = parts[5]
-		}
+	parts := strings.SplitN(line, "|", 7)
+	if len(parts) < 5 {
+		return GitCommit{}, false
+	}
 
-		// Parse parent commits
-		var parents []string
-		if len(parts) > 6 && strings.TrimSpace(parts[6]) != "" {
-			parentHashes := strings.Fields(strings.TrimSpace(parts[6]))
-			parents = parentHashes
+	date, err := time.Parse(isoDateLayout, parts[3])
+	if err != nil {
+		// Try alternative format
+		date, err = time.Parse("2006-01-02T15:04:05-07:00", parts[3])
+		if err != nil {
+			return GitCommit{}, false
 		}
+	}
 
-		// Create full message
-		message := parts[4]
-		if body != "" {
-			message = parts[4] + "\n\n" + body
-		}
+	body := ""
+	if len(parts) > 5 {
+		body = parts[5]
+	}
 
-		commit := GitCommit{
-			Hash:    parts[0],
-			Author:  parts[1],
-			Email:   parts[2],
-			Date:    date,
-			Subject: parts[4],
-			Body:    body,
-			Message: message,
-			Parents: parents,
-		}
+	// Parse parent commits
+	var parents []string
+	if len(parts) > 6 && strings.TrimSpace(parts[6]) != "" {
+		parentHashes := strings.Fields(strings.TrimSpace(parts[6]))
+		parents = parentHashes
+	}
 
-		commits = append(commits, commit)
+	// Create full message
+	message := parts[4]
+	if body != "" {
+		message = parts[4] + "\n\n" + body
 	}
 
-	return commits, nil
+	return GitCommit{
+		Hash:    parts[0],
+		Author:  parts[1],
+		Email:   parts[2],
+		Date:    date,
+		Subject: parts[4],
+		Body:    body,
+		Message: message,
+		Parents: parents,
+	}, true
 }
 
 // GetCommitStats gets detailed statistics for a commit
-func (r *Repository) GetCommitStats(hash string) (int, int, []string, error) {
-	cmd := exec.Command("git", "show", "--stat", "--format=", hash)
-	cmd.Dir = r.Path
+func (r *Repository) GetCommitStats(ctx context.Context, hash string) (int, int, []string, error) {
+	cmd := r.command(ctx, "show", "--stat", "--format=", hash)
 	output, err := cmd.Output()
 	if err != nil {
+		if ctx.Err() != nil {
+			return 0, 0, nil, ctx.Err()
+		}
 		return 0, 0, nil, err
 	}
 
@@ -191,19 +282,25 @@ func (r *Repository) GetCommitStats(hash string) (int, int, []string, error) {
 }
 
 // GetBranches retrieves all branches in the repository
-func (r *Repository) GetBranches() ([]string, error) {
-	if !IsGitInstalled() {
+func (r *Repository) GetBranches(ctx context.Context) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if !IsGitInstalled(ctx) {
 		return nil, fmt.Errorf("git is not installed or not available in PATH")
 	}
 
-	if !r.IsGitRepository() {
+	if !r.IsGitRepository(ctx) {
 		return nil, fmt.Errorf("not a git repository: %s", r.Path)
 	}
 
-	cmd := exec.Command("git", "branch", "-a", "--format=%(refname:short)")
-	cmd.Dir = r.Path
+	cmd := r.command(ctx, "branch", "-a", "--format=%(refname:short)")
 	output, err := cmd.Output()
 	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
 		return nil, fmt.Errorf("failed to get branches: %v", err)
 	}
 
@@ -235,23 +332,27 @@ func (r *Repository) GetBranches() ([]string, error) {
 }
 
 // GetBranchCommits retrieves commits for a specific branch
-func (r *Repository) GetBranchCommits(branch string) ([]GitCommit, error) {
-	if !IsGitInstalled() {
+func (r *Repository) GetBranchCommits(ctx context.Context, branch string) ([]GitCommit, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if !IsGitInstalled(ctx) {
 		return nil, fmt.Errorf("git is not installed or not available in PATH")
 	}
 
-	if !r.IsGitRepository() {
+	if !r.IsGitRepository(ctx) {
 		return nil, fmt.Errorf("not a git repository: %s", r.Path)
 	}
 
-	// Git log format: hash|author|email|date|subject|body|parents
-	format := "--pretty=format:%H|%an|%ae|%ai|%s|%b|%P"
-	args := []string{"log", format, branch}
+	args := []string{"log", logFormat, branch}
 
-	cmd := exec.Command("git", args...)
-	cmd.Dir = r.Path
+	cmd := r.command(ctx, args...)
 	output, err := cmd.Output()
 	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
 		return nil, fmt.Errorf("failed to get commits for branch %s: %v", branch, err)
 	}
 
@@ -259,20 +360,26 @@ func (r *Repository) GetBranchCommits(branch string) ([]GitCommit, error) {
 }
 
 // GetCommitBranch determines which branch a commit belongs to
-func (r *Repository) GetCommitBranch(commitHash string) (string, error) {
-	if !IsGitInstalled() {
+func (r *Repository) GetCommitBranch(ctx context.Context, commitHash string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	if !IsGitInstalled(ctx) {
 		return "", fmt.Errorf("git is not installed or not available in PATH")
 	}
 
-	if !r.IsGitRepository() {
+	if !r.IsGitRepository(ctx) {
 		return "", fmt.Errorf("not a git repository: %s", r.Path)
 	}
 
 	// Try to find which branch contains this commit
-	cmd := exec.Command("git", "branch", "--contains", commitHash)
-	cmd.Dir = r.Path
+	cmd := r.command(ctx, "branch", "--contains", commitHash)
 	output, err := cmd.Output()
 	if err != nil {
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
 		return "unknown", nil // Don't fail, just return unknown
 	}
 
@@ -290,3 +397,118 @@ func (r *Repository) GetCommitBranch(commitHash string) (string, error) {
 
 	return "main", nil // Default to main if not found
 }
+
+// rangeLogFormat separates fields with US and records with RS, so multi-line
+// bodies survive: hash, author, email, date, parents, subject, body
+const rangeLogFormat = "--pretty=format:%H%x1f%an%x1f%ae%x1f%ai%x1f%P%x1f%s%x1f%b%x1e"
+
+// checkRevision rejects revisions git would read as options
+func checkRevision(rev string) error {
+	if strings.HasPrefix(rev, "-") {
+		return fmt.Errorf("invalid revision %q", rev)
+	}
+	return nil
+}
+
+// GetRangeCommits retrieves the commits of from..to, newest first
+func (r *Repository) GetRangeCommits(ctx context.Context, from, to string) ([]GitCommit, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if err := checkRevision(from); err != nil {
+		return nil, err
+	}
+	if err := checkRevision(to); err != nil {
+		return nil, err
+	}
+
+	if !IsGitInstalled(ctx) {
+		return nil, fmt.Errorf("git is not installed or not available in PATH")
+	}
+
+	if !r.IsGitRepository(ctx) {
+		return nil, fmt.Errorf("not a git repository: %s", r.Path)
+	}
+
+	rev := to
+	if from != "" {
+		rev = from + ".." + to
+	}
+	cmd := r.command(ctx, "log", rangeLogFormat, rev, "--")
+	output, err := cmd.Output()
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("failed to get commits for %s: %v", rev, err)
+	}
+
+	var commits []GitCommit
+	for _, record := range strings.Split(string(output), "\x1e") {
+		fields := strings.Split(strings.TrimLeft(record, "\n"), "\x1f")
+		if len(fields) != 7 {
+			continue
+		}
+		date, err := time.Parse(isoDateLayout, fields[3])
+		if err != nil {
+			continue
+		}
+		subject := fields[5]
+		body := strings.TrimSpace(fields[6])
+		message := subject
+		if body != "" {
+			message = subject + "\n\n" + body
+		}
+		commits = append(commits, GitCommit{
+			Hash:    fields[0],
+			Author:  fields[1],
+			Email:   fields[2],
+			Date:    date,
+			Subject: subject,
+			Body:    body,
+			Message: message,
+			Parents: strings.Fields(fields[4]),
+		})
+	}
+	return commits, nil
+}
+
+// GetTags retrieves the repository's tags
+func (r *Repository) GetTags(ctx context.Context) ([]Tag, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if !IsGitInstalled(ctx) {
+		return nil, fmt.Errorf("git is not installed or not available in PATH")
+	}
+
+	if !r.IsGitRepository(ctx) {
+		return nil, fmt.Errorf("not a git repository: %s", r.Path)
+	}
+
+	// *objectname is the peeled commit of annotated tags and empty otherwise
+	cmd := r.command(ctx, "for-each-ref", "--format=%(refname:short)%1f%(objectname)%1f%(*objectname)%1f%(creatordate:iso)", "refs/tags")
+	output, err := cmd.Output()
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("failed to get tags: %v", err)
+	}
+
+	var tags []Tag
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		fields := strings.Split(line, "\x1f")
+		if len(fields) != 4 {
+			continue
+		}
+		tag := Tag{Name: fields[0], Hash: fields[1]}
+		if fields[2] != "" {
+			tag.Hash = fields[2]
+		}
+		tag.Date, _ = time.Parse(isoDateLayout, fields[3])
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
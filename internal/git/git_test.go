@@ -1,13 +1,17 @@
 package git
 
 import (
+	"context"
+	"errors"
 	"testing"
 	"time"
+
+	"git-log-analyzer/internal/fixture"
 )
 
 func TestIsGitInstalled(t *testing.T) {
 	// Test that git is installed (this should pass on most development machines)
-	if !IsGitInstalled() {
+	if !IsGitInstalled(context.Background()) {
 		t.Skip("Git is not installed, skipping test")
 	}
 }
@@ -52,21 +56,38 @@ def456|Jane Smith|jane@example.com|2023-01-02 11:30:00 +0000|Add feature|Added n
 }
 
 func TestRepository_IsGitRepository(t *testing.T) {
-	// Test with current directory (should be a git repo now)
-	repo := NewRepository(".")
-	if !repo.IsGitRepository() {
-		t.Error("Current directory should be a git repository")
-	}
+	fx := fixture.Build(t, fixture.Script{
+		fixture.Commit{Author: fixture.Alice, Date: time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC), Message: "Initial commit"},
+	})
 
-	// Test with non-git directory
-	repo = NewRepository("/tmp")
-	// This might pass if /tmp is inside a git repo, so we won't assert false
+	repo := NewRepository(fx.Dir)
+	if !repo.IsGitRepository(context.Background()) {
+		t.Error("Fixture directory should be a git repository")
+	}
 }
 
 func TestRepository_IsGitRepository_NonExistent(t *testing.T) {
 	// Test with non-existent directory
 	repo := NewRepository("/non/existent/path")
-	if repo.IsGitRepository() {
+	if repo.IsGitRepository(context.Background()) {
 		t.Error("Non-existent directory should not be a git repository")
 	}
 }
+
+func TestRepository_GetCommits_Canceled(t *testing.T) {
+	if !IsGitInstalled(context.Background()) {
+		t.Skip("Git is not installed, skipping test")
+	}
+
+	fx := fixture.Build(t, fixture.Script{
+		fixture.Commit{Author: fixture.Alice, Date: time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC), Message: "Initial commit"},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	repo := NewRepository(fx.Dir)
+	if _, err := repo.GetCommits(ctx, 0); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
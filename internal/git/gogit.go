@@ -0,0 +1,460 @@
+package git
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+)
+
+// GoGitRepository is a Backend implemented with go-git, so it works without
+// the git executable and can wrap in-memory repositories
+type GoGitRepository struct {
+	repo *gogit.Repository
+
+	branchOnce sync.Once
+	branchOf   map[plumbing.Hash]string // commit -> first local branch containing it
+	branchErr  error
+}
+
+// OpenGoGitRepository opens the repository at path (or one of its parents)
+func OpenGoGitRepository(path string) (*GoGitRepository, error) {
+	repo, err := gogit.PlainOpenWithOptions(path, &gogit.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return nil, fmt.Errorf("not a git repository: %s (%v)", path, err)
+	}
+	return NewGoGitRepository(repo), nil
+}
+
+// NewGoGitRepository wraps an already opened go-git repository, for example
+// one backed by in-memory storage
+func NewGoGitRepository(repo *gogit.Repository) *GoGitRepository {
+	return &GoGitRepository{repo: repo}
+}
+
+// IsGitRepository reports whether the repository has a resolvable HEAD
+func (r *GoGitRepository) IsGitRepository(ctx context.Context) bool {
+	_, err := r.repo.Head()
+	return err == nil
+}
+
+// GetCommits retrieves commits reachable from HEAD, newest first
+func (r *GoGitRepository) GetCommits(ctx context.Context, limit int) ([]GitCommit, error) {
+	var commits []GitCommit
+	err := r.StreamCommits(ctx, limit, func(commit GitCommit) error {
+		commits = append(commits, commit)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return commits, nil
+}
+
+// StreamCommits walks the history from HEAD and calls fn for each commit
+func (r *GoGitRepository) StreamCommits(ctx context.Context, limit int, fn func(GitCommit) error) error {
+	head, err := r.repo.Head()
+	if err != nil {
+		return fmt.Errorf("failed to resolve HEAD: %v", err)
+	}
+	return r.walk(ctx, head.Hash(), limit, fn)
+}
+
+// walk iterates commits reachable from start in committer time order
+func (r *GoGitRepository) walk(ctx context.Context, start plumbing.Hash, limit int, fn func(GitCommit) error) error {
+	iter, err := r.repo.Log(&gogit.LogOptions{From: start, Order: gogit.LogOrderCommitterTime})
+	if err != nil {
+		return fmt.Errorf("failed to get git log: %v", err)
+	}
+	defer iter.Close()
+
+	count := 0
+	err = iter.ForEach(func(c *object.Commit) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if limit > 0 && count >= limit {
+			return storer.ErrStop
+		}
+		count++
+		return fn(convertCommit(c))
+	})
+	return err
+}
+
+// convertCommit maps a go-git commit onto GitCommit the way logFormat does
+func convertCommit(c *object.Commit) GitCommit {
+	subject, body, _ := strings.Cut(strings.TrimRight(c.Message, "\n"), "\n")
+	body = strings.TrimSpace(body)
+
+	message := subject
+	if body != "" {
+		message = subject + "\n\n" + body
+	}
+
+	var parents []string
+	for _, parent := range c.ParentHashes {
+		parents = append(parents, parent.String())
+	}
+
+	// Round-trip through the %ai layout so dates carry the same location as
+	// the ones parsed from git log output
+	date, err := time.Parse(isoDateLayout, c.Author.When.Format(isoDateLayout))
+	if err != nil {
+		date = c.Author.When
+	}
+
+	return GitCommit{
+		Hash:    c.Hash.String(),
+		Author:  c.Author.Name,
+		Email:   c.Author.Email,
+		Date:    date,
+		Subject: subject,
+		Body:    body,
+		Message: message,
+		Parents: parents,
+	}
+}
+
+// GetCommitStats gets detailed statistics for a commit, diffed against its
+// first parent like `git show --stat`
+func (r *GoGitRepository) GetCommitStats(ctx context.Context, hash string) (int, int, []string, error) {
+	commit, err := r.repo.CommitObject(plumbing.NewHash(hash))
+	if err != nil {
+		return 0, 0, nil, err
+	}
+
+	fileStats, err := commit.StatsContext(ctx)
+	if err != nil {
+		if ctx.Err() != nil {
+			return 0, 0, nil, ctx.Err()
+		}
+		return 0, 0, nil, err
+	}
+
+	var files []string
+	additions := 0
+	deletions := 0
+	for _, stat := range fileStats {
+		files = append(files, stat.Name)
+		additions += stat.Addition
+		deletions += stat.Deletion
+	}
+
+	return additions, deletions, files, nil
+}
+
+// GetBranches retrieves local and remote branches, dropping the "origin/" prefix
+func (r *GoGitRepository) GetBranches(ctx context.Context) ([]string, error) {
+	refs, err := r.repo.References()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get branches: %v", err)
+	}
+	defer refs.Close()
+
+	var branches []string
+	seen := make(map[string]bool)
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		name := ref.Name()
+		if !name.IsBranch() && !name.IsRemote() {
+			return nil
+		}
+		if ref.Type() == plumbing.SymbolicReference {
+			return nil // origin/HEAD
+		}
+
+		branch := strings.TrimPrefix(name.Short(), "origin/")
+		if !seen[branch] {
+			seen[branch] = true
+			branches = append(branches, branch)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get branches: %v", err)
+	}
+
+	sort.Strings(branches)
+	return branches, nil
+}
+
+// GetBranchCommits retrieves commits for a specific branch
+func (r *GoGitRepository) GetBranchCommits(ctx context.Context, branch string) ([]GitCommit, error) {
+	hash, err := r.repo.ResolveRevision(plumbing.Revision(branch))
+	if err != nil {
+		hash, err = r.repo.ResolveRevision(plumbing.Revision("origin/" + branch))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get commits for branch %s: %v", branch, err)
+		}
+	}
+
+	var commits []GitCommit
+	err = r.walk(ctx, *hash, 0, func(commit GitCommit) error {
+		commits = append(commits, commit)
+		return nil
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("failed to get commits for branch %s: %v", branch, err)
+	}
+	return commits, nil
+}
+
+// GetCommitBranch determines which branch a commit belongs to. Like
+// `git branch --contains`, the alphabetically first local branch wins. The
+// commit-to-branch index is built once on first use.
+func (r *GoGitRepository) GetCommitBranch(ctx context.Context, commitHash string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	r.branchOnce.Do(func() {
+		r.branchOf, r.branchErr = r.buildBranchIndex(ctx)
+	})
+	if r.branchErr != nil {
+		return "unknown", nil // Don't fail, just return unknown
+	}
+
+	if branch, ok := r.branchOf[plumbing.NewHash(commitHash)]; ok {
+		return branch, nil
+	}
+	return "main", nil // Default to main if not found
+}
+
+// buildBranchIndex maps every commit to the first local branch containing it
+func (r *GoGitRepository) buildBranchIndex(ctx context.Context) (map[plumbing.Hash]string, error) {
+	iter, err := r.repo.Branches()
+	if err != nil {
+		return nil, err
+	}
+
+	var branches []*plumbing.Reference
+	iter.ForEach(func(ref *plumbing.Reference) error {
+		branches = append(branches, ref)
+		return nil
+	})
+	sort.Slice(branches, func(i, j int) bool {
+		return branches[i].Name().Short() < branches[j].Name().Short()
+	})
+
+	index := make(map[plumbing.Hash]string)
+	for _, ref := range branches {
+		name := ref.Name().Short()
+		logIter, err := r.repo.Log(&gogit.LogOptions{From: ref.Hash()})
+		if err != nil {
+			return nil, err
+		}
+		err = logIter.ForEach(func(c *object.Commit) error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if _, ok := index[c.Hash]; !ok {
+				index[c.Hash] = name
+			}
+			return nil
+		})
+		logIter.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return index, nil
+}
+
+// GetRangeCommits retrieves the commits of from..to, newest first. Like git
+// rev-list, it walks both ends together in committer time order and marks
+// everything reachable from from as hidden, so the walk stops where the
+// histories meet instead of reading both histories to the root.
+func (r *GoGitRepository) GetRangeCommits(ctx context.Context, from, to string) ([]GitCommit, error) {
+	end, err := r.repo.ResolveRevision(plumbing.Revision(to))
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %v", to, err)
+	}
+	if from == "" {
+		var commits []GitCommit
+		err = r.walk(ctx, *end, 0, func(commit GitCommit) error {
+			commits = append(commits, commit)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		return commits, nil
+	}
+	start, err := r.repo.ResolveRevision(plumbing.Revision(from))
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %v", from, err)
+	}
+
+	w := newRangeWalk(r.repo)
+	listed, err := w.run(ctx, *end, *start)
+	if err != nil {
+		return nil, err
+	}
+
+	// A commit listed before it was found to be hidden (clock skew) is dropped
+	var commits []GitCommit
+	for _, c := range listed {
+		if w.flags[c.Hash]&rangeHidden == 0 {
+			commits = append(commits, convertCommit(c))
+		}
+	}
+	return commits, nil
+}
+
+// Commit flags of a rangeWalk
+const (
+	rangeSeen uint8 = 1 << iota
+	rangeQueued
+	rangeHidden
+)
+
+// rangeWalk is the state of a GetRangeCommits walk. live counts the queued
+// commits that are not hidden.
+type rangeWalk struct {
+	repo  *gogit.Repository
+	queue commitQueue
+	flags map[plumbing.Hash]uint8
+	live  int
+}
+
+// newRangeWalk creates an empty walk over repo
+func newRangeWalk(repo *gogit.Repository) *rangeWalk {
+	return &rangeWalk{repo: repo, flags: make(map[plumbing.Hash]uint8)}
+}
+
+// run walks the commits reachable from end and start, newest first, and
+// returns the ones walked before they were known to be hidden
+func (w *rangeWalk) run(ctx context.Context, end, start plumbing.Hash) ([]*object.Commit, error) {
+	if err := w.push(end, false); err != nil {
+		return nil, err
+	}
+	if err := w.push(start, true); err != nil {
+		return nil, err
+	}
+
+	// Walk until only hidden commits are queued; every commit still
+	// reachable from end is then reachable from start as well
+	var listed []*object.Commit
+	for w.live > 0 {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		c := heap.Pop(&w.queue).(*object.Commit)
+		w.flags[c.Hash] &^= rangeQueued
+		hidden := w.flags[c.Hash]&rangeHidden != 0
+		if !hidden {
+			w.live--
+			listed = append(listed, c)
+		}
+		for _, parent := range c.ParentHashes {
+			if err := w.push(parent, hidden); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return listed, nil
+}
+
+// push queues a commit unless it was seen before. Hiding a commit that was
+// already walked hides its ancestors too.
+func (w *rangeWalk) push(hash plumbing.Hash, hide bool) error {
+	flags := w.flags[hash]
+	if flags&rangeSeen == 0 {
+		c, err := w.repo.CommitObject(hash)
+		if err != nil {
+			return fmt.Errorf("failed to read commit %s: %v", hash, err)
+		}
+		w.flags[hash] = rangeSeen | rangeQueued
+		if hide {
+			w.flags[hash] |= rangeHidden
+		} else {
+			w.live++
+		}
+		heap.Push(&w.queue, c)
+		return nil
+	}
+	if !hide || flags&rangeHidden != 0 {
+		return nil
+	}
+
+	// Hide a seen commit; if it was walked already, its parents are queued
+	// or walked too and are hidden in turn
+	pending := []plumbing.Hash{hash}
+	for len(pending) > 0 {
+		hash := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		flags := w.flags[hash]
+		if flags&rangeSeen == 0 || flags&rangeHidden != 0 {
+			continue
+		}
+		w.flags[hash] |= rangeHidden
+		if flags&rangeQueued != 0 {
+			w.live--
+			continue
+		}
+		c, err := w.repo.CommitObject(hash)
+		if err != nil {
+			return fmt.Errorf("failed to read commit %s: %v", hash, err)
+		}
+		pending = append(pending, c.ParentHashes...)
+	}
+	return nil
+}
+
+// commitQueue is a heap of commits, newest committer time first
+type commitQueue []*object.Commit
+
+func (q commitQueue) Len() int           { return len(q) }
+func (q commitQueue) Less(i, j int) bool { return q[i].Committer.When.After(q[j].Committer.When) }
+func (q commitQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+func (q *commitQueue) Push(x any)        { *q = append(*q, x.(*object.Commit)) }
+func (q *commitQueue) Pop() any {
+	old := *q
+	c := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return c
+}
+
+// GetTags retrieves the repository's tags
+func (r *GoGitRepository) GetTags(ctx context.Context) ([]Tag, error) {
+	iter, err := r.repo.Tags()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tags: %v", err)
+	}
+	defer iter.Close()
+
+	var tags []Tag
+	err = iter.ForEach(func(ref *plumbing.Reference) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		tag := Tag{Name: ref.Name().Short(), Hash: ref.Hash().String()}
+		if annotated, err := r.repo.TagObject(ref.Hash()); err == nil {
+			tag.Date = annotated.Tagger.When
+			if commit, err := annotated.Commit(); err == nil {
+				tag.Hash = commit.Hash.String()
+			}
+		} else if commit, err := r.repo.CommitObject(ref.Hash()); err == nil {
+			tag.Date = commit.Committer.When
+		}
+		tags = append(tags, tag)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(tags, func(i, j int) bool { return tags[i].Name < tags[j].Name })
+	return tags, nil
+}
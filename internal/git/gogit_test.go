@@ -0,0 +1,362 @@
+package git
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+
+	"github.com/go-git/go-billy/v5/memfs"
+
+	"git-log-analyzer/internal/fixture"
+)
+
+// newMemoryRepo builds an in-memory repository with three commits
+func newMemoryRepo(t *testing.T) *gogit.Repository {
+	t.Helper()
+
+	fs := memfs.New()
+	repo, err := gogit.Init(memory.NewStorage(), fs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	write := func(name, content string) {
+		f, err := fs.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Write([]byte(content))
+		f.Close()
+		if _, err := wt.Add(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	commit := func(author, message string, when time.Time) {
+		sig := &object.Signature{Name: author, Email: author + "@example.com", When: when}
+		if _, err := wt.Commit(message, &gogit.CommitOptions{Author: sig, Committer: sig}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	base := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+	write("main.go", "package main\n")
+	commit("Alice", "Initial commit", base)
+	write("main.go", "package main\n\nfunc main() {}\n")
+	write("README.md", "# demo\n")
+	commit("Bob", "Add main function\n\nAlso adds a README.", base.Add(time.Hour))
+	write("README.md", "# demo project\n")
+	commit("Alice", "Update README", base.Add(2*time.Hour))
+
+	return repo
+}
+
+func TestGoGitRepository_GetCommits(t *testing.T) {
+	repo := NewGoGitRepository(newMemoryRepo(t))
+	ctx := context.Background()
+
+	if !repo.IsGitRepository(ctx) {
+		t.Fatal("In-memory repository should be a git repository")
+	}
+
+	commits, err := repo.GetCommits(ctx, 0)
+	if err != nil {
+		t.Fatalf("GetCommits failed: %v", err)
+	}
+	if len(commits) != 3 {
+		t.Fatalf("Expected 3 commits, got %d", len(commits))
+	}
+
+	// Newest first, like git log
+	if commits[0].Subject != "Update README" {
+		t.Errorf("Expected newest commit 'Update README', got '%s'", commits[0].Subject)
+	}
+	if commits[1].Author != "Bob" || commits[1].Body != "Also adds a README." {
+		t.Errorf("Unexpected second commit: %+v", commits[1])
+	}
+	if commits[1].Message != "Add main function\n\nAlso adds a README." {
+		t.Errorf("Unexpected message: %q", commits[1].Message)
+	}
+	if len(commits[2].Parents) != 0 || len(commits[1].Parents) != 1 {
+		t.Error("Unexpected parent hashes")
+	}
+
+	limited, err := repo.GetCommits(ctx, 2)
+	if err != nil {
+		t.Fatalf("GetCommits with limit failed: %v", err)
+	}
+	if len(limited) != 2 {
+		t.Errorf("Expected 2 commits with limit, got %d", len(limited))
+	}
+}
+
+func TestGoGitRepository_GetCommitStats(t *testing.T) {
+	repo := NewGoGitRepository(newMemoryRepo(t))
+	ctx := context.Background()
+
+	commits, err := repo.GetCommits(ctx, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	additions, deletions, files, err := repo.GetCommitStats(ctx, commits[1].Hash)
+	if err != nil {
+		t.Fatalf("GetCommitStats failed: %v", err)
+	}
+	if additions != 3 || deletions != 0 {
+		t.Errorf("Expected +3/-0, got +%d/-%d", additions, deletions)
+	}
+	if len(files) != 2 {
+		t.Errorf("Expected 2 files, got %v", files)
+	}
+
+	// Root commit is diffed against the empty tree
+	additions, _, files, err = repo.GetCommitStats(ctx, commits[2].Hash)
+	if err != nil {
+		t.Fatalf("GetCommitStats on root commit failed: %v", err)
+	}
+	if additions != 1 || len(files) != 1 || files[0] != "main.go" {
+		t.Errorf("Unexpected root commit stats: +%d %v", additions, files)
+	}
+}
+
+func TestGoGitRepository_Branches(t *testing.T) {
+	memRepo := newMemoryRepo(t)
+	head, err := memRepo.Head()
+	if err != nil {
+		t.Fatal(err)
+	}
+	feature := plumbing.NewHashReference(plumbing.NewBranchReferenceName("feature"), head.Hash())
+	if err := memRepo.Storer.SetReference(feature); err != nil {
+		t.Fatal(err)
+	}
+
+	repo := NewGoGitRepository(memRepo)
+	ctx := context.Background()
+
+	branches, err := repo.GetBranches(ctx)
+	if err != nil {
+		t.Fatalf("GetBranches failed: %v", err)
+	}
+	if len(branches) != 2 || branches[0] != "feature" || branches[1] != "master" {
+		t.Errorf("Expected [feature master], got %v", branches)
+	}
+
+	commits, err := repo.GetBranchCommits(ctx, "feature")
+	if err != nil {
+		t.Fatalf("GetBranchCommits failed: %v", err)
+	}
+	if len(commits) != 3 {
+		t.Errorf("Expected 3 commits on feature, got %d", len(commits))
+	}
+
+	branch, err := repo.GetCommitBranch(ctx, commits[0].Hash)
+	if err != nil {
+		t.Fatalf("GetCommitBranch failed: %v", err)
+	}
+	if branch != "feature" {
+		t.Errorf("Expected alphabetically first branch 'feature', got '%s'", branch)
+	}
+}
+
+func TestNewBackend(t *testing.T) {
+	backend, err := NewBackend(BackendExec, "/tmp")
+	if err != nil {
+		t.Fatalf("NewBackend(exec) failed: %v", err)
+	}
+	if _, ok := backend.(*Repository); !ok {
+		t.Errorf("Expected *Repository, got %T", backend)
+	}
+
+	if _, err := NewBackend(BackendGoGit, "/non/existent/path"); err == nil {
+		t.Error("NewBackend(go) should fail for a non-existent path")
+	}
+
+	if _, err := NewBackend("svn", "."); err == nil {
+		t.Error("NewBackend should reject unknown backends")
+	}
+}
+
+func TestBackendsAgree(t *testing.T) {
+	dir := buildSyntheticRepo(t, 30)
+	ctx := context.Background()
+
+	goBackend, err := OpenGoGitRepository(dir)
+	if err != nil {
+		t.Fatalf("OpenGoGitRepository failed: %v", err)
+	}
+	execCommits, err := NewRepository(dir).GetCommits(ctx, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	goCommits, err := goBackend.GetCommits(ctx, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(execCommits) != len(goCommits) {
+		t.Fatalf("Backends disagree on commit count: %d vs %d", len(execCommits), len(goCommits))
+	}
+	for i := range execCommits {
+		e, g := execCommits[i], goCommits[i]
+		if e.Hash != g.Hash || e.Author != g.Author || e.Email != g.Email || !e.Date.Equal(g.Date) || e.Subject != g.Subject {
+			t.Errorf("Commit %d differs between backends: %+v vs %+v", i, e, g)
+		}
+	}
+}
+
+func TestBackends_RangeCommitsAndTags(t *testing.T) {
+	dir := buildSyntheticRepo(t, 10)
+	ctx := context.Background()
+	git := func(args ...string) string {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GIT_COMMITTER_NAME=Tagger", "GIT_COMMITTER_EMAIL=tagger@example.com")
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	// Commits are numbered from 0, so HEAD~6 is commit 3
+	git("tag", "v0.1", "HEAD~6")
+	git("tag", "-a", "v0.2", "-m", "Release 0.2", "HEAD~3")
+
+	goBackend, err := OpenGoGitRepository(dir)
+	if err != nil {
+		t.Fatalf("OpenGoGitRepository failed: %v", err)
+	}
+	for name, backend := range map[string]Backend{"exec": NewRepository(dir), "go": goBackend} {
+		commits, err := backend.GetRangeCommits(ctx, "v0.1", "v0.2")
+		if err != nil {
+			t.Fatalf("%s: GetRangeCommits failed: %v", name, err)
+		}
+		var subjects []string
+		for _, c := range commits {
+			subjects = append(subjects, c.Subject)
+		}
+		if strings.Join(subjects, ",") != "Synthetic commit 6,Synthetic commit 5,Synthetic commit 4" {
+			t.Errorf("%s: unexpected range %v", name, subjects)
+		}
+		if len(commits) > 0 && (commits[0].Body != "Body of commit 6 with some detail." || len(commits[0].Parents) != 1) {
+			t.Errorf("%s: expected the complete commit, got %+v", name, commits[0])
+		}
+
+		all, err := backend.GetRangeCommits(ctx, "", "HEAD")
+		if err != nil || len(all) != 10 {
+			t.Errorf("%s: expected the whole history, got %d commits, %v", name, len(all), err)
+		}
+
+		tags, err := backend.GetTags(ctx)
+		if err != nil {
+			t.Fatalf("%s: GetTags failed: %v", name, err)
+		}
+		if len(tags) != 2 || tags[0].Name != "v0.1" || tags[1].Name != "v0.2" {
+			t.Fatalf("%s: unexpected tags %+v", name, tags)
+		}
+		if tags[0].Hash != git("rev-parse", "HEAD~6") || tags[1].Hash != git("rev-parse", "HEAD~3") {
+			t.Errorf("%s: expected tags to point to their commits, got %+v", name, tags)
+		}
+		if tags[1].Date.IsZero() {
+			t.Errorf("%s: expected the tag date", name)
+		}
+
+		if _, err := backend.GetRangeCommits(ctx, "", "--all"); err == nil {
+			t.Errorf("%s: expected an error for an invalid revision", name)
+		}
+	}
+}
+
+func TestBackends_RangeCommitsAcrossMerges(t *testing.T) {
+	day := func(d int) time.Time {
+		return time.Date(2023, 3, d, 10, 0, 0, 0, time.UTC)
+	}
+	commit := func(id string, author fixture.Author, d int) fixture.Commit {
+		return fixture.Commit{ID: id, Author: author, Date: day(d), Message: id, Files: map[string]string{id + ".go": fixture.Lines(d)}}
+	}
+	repo := fixture.Build(t, fixture.Script{
+		commit("init", fixture.Alice, 1),
+		fixture.Tag{Name: "v1"},
+		fixture.Branch{Name: "feature"},
+		fixture.Checkout{Ref: "feature"},
+		commit("feat1", fixture.Bob, 2),
+		commit("feat2", fixture.Bob, 4),
+		fixture.Checkout{Ref: fixture.DefaultBranch},
+		commit("main1", fixture.Alice, 3),
+		fixture.Merge{ID: "merge", Branch: "feature", Author: fixture.Alice, Date: day(5)},
+		commit("main2", fixture.Carol, 6),
+		fixture.Checkout{Ref: "feature"},
+		commit("feat3", fixture.Bob, 7),
+	})
+	ctx := context.Background()
+	goBackend, err := OpenGoGitRepository(repo.Dir)
+	if err != nil {
+		t.Fatalf("OpenGoGitRepository failed: %v", err)
+	}
+
+	ranges := map[[2]string][]string{
+		{"v1", fixture.DefaultBranch}:      {"main2", "merge", "feat2", "main1", "feat1"},
+		{"feature", fixture.DefaultBranch}: {"main2", "merge", "main1"},
+		{fixture.DefaultBranch, "feature"}: {"feat3"},
+		{"HEAD", "HEAD"}:                   nil,
+	}
+	for r, ids := range ranges {
+		var want []string
+		for _, id := range ids {
+			want = append(want, repo.Hash(id))
+		}
+		for name, backend := range map[string]Backend{"exec": NewRepository(repo.Dir), "go": goBackend} {
+			commits, err := backend.GetRangeCommits(ctx, r[0], r[1])
+			if err != nil {
+				t.Fatalf("%s: GetRangeCommits(%s, %s) failed: %v", name, r[0], r[1], err)
+			}
+			var got []string
+			for _, c := range commits {
+				got = append(got, c.Hash)
+			}
+			if strings.Join(got, ",") != strings.Join(want, ",") {
+				t.Errorf("%s: %s..%s expected %v, got %v", name, r[0], r[1], ids, got)
+			}
+		}
+	}
+}
+
+func TestRangeWalk_StopsWhereHistoriesMeet(t *testing.T) {
+	dir := buildSyntheticRepo(t, 200)
+	backend, err := OpenGoGitRepository(dir)
+	if err != nil {
+		t.Fatalf("OpenGoGitRepository failed: %v", err)
+	}
+	end, err := backend.repo.ResolveRevision("HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	start, err := backend.repo.ResolveRevision("HEAD~2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := newRangeWalk(backend.repo)
+	listed, err := w.run(context.Background(), *end, *start)
+	if err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	if len(listed) != 2 {
+		t.Errorf("Expected 2 commits, got %d", len(listed))
+	}
+	// HEAD, HEAD~1, HEAD~2 and at most the parent of HEAD~2 are read
+	if len(w.flags) > 4 {
+		t.Errorf("Expected the walk to stop at HEAD~2, read %d of 200 commits", len(w.flags))
+	}
+}
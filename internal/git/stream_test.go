@@ -0,0 +1,172 @@
+package git
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"testing"
+	"time"
+
+	"git-log-analyzer/internal/fixture"
+)
+
+// buildSyntheticRepo creates a repository with n linear commits using
+// git fast-import, which is fast enough to generate very large histories.
+func buildSyntheticRepo(tb testing.TB, n int) string {
+	tb.Helper()
+	if !IsGitInstalled(context.Background()) {
+		tb.Skip("Git is not installed, skipping test")
+	}
+
+	dir := tb.TempDir()
+	if out, err := exec.Command("git", "init", "-q", dir).CombinedOutput(); err != nil {
+		tb.Fatalf("git init failed: %v\n%s", err, out)
+	}
+
+	cmd := exec.Command("git", "fast-import", "--quiet")
+	cmd.Dir = dir
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		tb.Fatal(err)
+	}
+	if err := cmd.Start(); err != nil {
+		tb.Fatal(err)
+	}
+
+	w := bufio.NewWriter(stdin)
+	base := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC).Unix()
+	for i := 0; i < n; i++ {
+		author := fmt.Sprintf("Dev%d <dev%d@example.com>", i%50, i%50)
+		message := fmt.Sprintf("Synthetic commit %d\n\nBody of commit %d with some detail.", i, i)
+		fmt.Fprintf(w, "commit refs/heads/master\n")
+		fmt.Fprintf(w, "author %s %d +0000\n", author, base+int64(i)*600)
+		fmt.Fprintf(w, "committer %s %d +0000\n", author, base+int64(i)*600)
+		fmt.Fprintf(w, "data %d\n%s\n\n", len(message), message)
+	}
+	if err := w.Flush(); err != nil {
+		tb.Fatal(err)
+	}
+	stdin.Close()
+	if err := cmd.Wait(); err != nil {
+		tb.Fatalf("git fast-import failed: %v", err)
+	}
+
+	return dir
+}
+
+// benchCommitCount returns the synthetic history size for the git layer
+// benchmarks. They build a real repository, so the default stays small; set
+// GIT_LOG_ANALYZER_BENCH_COMMITS=1000000 to reproduce the million-commit run.
+// BenchmarkAnalyzeLowMemory in the analyzer package measures the whole
+// streaming analysis and defaults to a million commits.
+func benchCommitCount() int {
+	if value, err := strconv.Atoi(os.Getenv("GIT_LOG_ANALYZER_BENCH_COMMITS")); err == nil && value > 0 {
+		return value
+	}
+	return 20000
+}
+
+func TestStreamCommits(t *testing.T) {
+	dir := buildSyntheticRepo(t, 200)
+	repo := NewRepository(dir)
+
+	commits, err := repo.GetCommits(context.Background(), 0)
+	if err != nil {
+		t.Fatalf("GetCommits failed: %v", err)
+	}
+
+	var streamed []GitCommit
+	err = repo.StreamCommits(context.Background(), 0, func(commit GitCommit) error {
+		streamed = append(streamed, commit)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("StreamCommits failed: %v", err)
+	}
+
+	if len(streamed) != 200 {
+		t.Fatalf("Expected 200 streamed commits, got %d", len(streamed))
+	}
+	if len(streamed) != len(commits) {
+		t.Fatalf("Streamed %d commits, GetCommits returned %d", len(streamed), len(commits))
+	}
+	for i := range commits {
+		if streamed[i].Hash != commits[i].Hash || !streamed[i].Date.Equal(commits[i].Date) {
+			t.Errorf("Commit %d differs: %s vs %s", i, streamed[i].Hash, commits[i].Hash)
+		}
+	}
+}
+
+func TestStreamCommits_StopsOnCallbackError(t *testing.T) {
+	dir := buildSyntheticRepo(t, 50)
+	repo := NewRepository(dir)
+
+	stop := fmt.Errorf("stop")
+	seen := 0
+	err := repo.StreamCommits(context.Background(), 0, func(commit GitCommit) error {
+		seen++
+		if seen == 10 {
+			return stop
+		}
+		return nil
+	})
+	if err != stop {
+		t.Errorf("Expected callback error, got %v", err)
+	}
+	if seen != 10 {
+		t.Errorf("Expected stream to stop after 10 commits, saw %d", seen)
+	}
+}
+
+func BenchmarkStreamCommits(b *testing.B) {
+	n := benchCommitCount()
+	repo := NewRepository(buildSyntheticRepo(b, n))
+	b.ResetTimer()
+
+	var peak uint64
+	for i := 0; i < b.N; i++ {
+		count := 0
+		used := fixture.PeakHeap(func() {
+			err := repo.StreamCommits(context.Background(), 0, func(commit GitCommit) error {
+				count++
+				return nil
+			})
+			if err != nil {
+				b.Fatal(err)
+			}
+		})
+		if count != n {
+			b.Fatalf("Expected %d commits, got %d", n, count)
+		}
+		if used > peak {
+			peak = used
+		}
+	}
+	b.ReportMetric(float64(peak)/(1<<20), "peak-heap-MB")
+}
+
+func BenchmarkGetCommits(b *testing.B) {
+	n := benchCommitCount()
+	repo := NewRepository(buildSyntheticRepo(b, n))
+	b.ResetTimer()
+
+	var peak uint64
+	for i := 0; i < b.N; i++ {
+		used := fixture.PeakHeap(func() {
+			commits, err := repo.GetCommits(context.Background(), 0)
+			if err != nil {
+				b.Fatal(err)
+			}
+			if len(commits) != n {
+				b.Fatalf("Expected %d commits, got %d", n, len(commits))
+			}
+		})
+		if used > peak {
+			peak = used
+		}
+	}
+	b.ReportMetric(float64(peak)/(1<<20), "peak-heap-MB")
+}
@@ -0,0 +1,128 @@
+package health
+
+import (
+	"fmt"
+)
+
+// Finding rules
+const (
+	RuleTechDebtHotspot   = "tech-debt-hotspot"
+	RuleGodFile           = "god-file"
+	RuleRefactoringSignal = "refactoring-signal"
+	RuleUnstableFile      = "unstable-file"
+)
+
+// Finding levels, as in SARIF
+const (
+	LevelError   = "error"
+	LevelWarning = "warning"
+	LevelNote    = "note"
+)
+
+// Rule describes a kind of finding
+type Rule struct {
+	ID          string
+	Name        string
+	Description string
+	Level       string // default level
+}
+
+// Rules lists the finding rules in report order
+var Rules = []Rule{
+	{RuleTechDebtHotspot, "TechDebtHotspot", "File changed often and by many authors, a likely source of technical debt", LevelWarning},
+	{RuleGodFile, "GodFile", "File concentrates a large share of all changes in the repository", LevelWarning},
+	{RuleRefactoringSignal, "RefactoringSignal", "File changed intensively within the last 7 days", LevelNote},
+	{RuleUnstableFile, "UnstableFile", "File changes frequently and at irregular intervals", LevelNote},
+}
+
+// Finding is a file flagged by the health analysis
+type Finding struct {
+	RuleID  string `json:"ruleId"`
+	Level   string `json:"level"`
+	File    string `json:"file"`
+	Message string `json:"message"`
+}
+
+// Findings turns the metrics into findings: every hotspot, concentration
+// issue and refactoring signal, and the unstable files among the stability
+// indicators
+func Findings(metrics *CodeHealthMetrics) []Finding {
+	if metrics == nil {
+		return nil
+	}
+	var findings []Finding
+
+	for _, h := range metrics.TechnicalDebtHotspots {
+		level := LevelNote
+		if h.RiskScore > 0.7 {
+			level = LevelError
+		} else if h.RiskScore > 0.5 {
+			level = LevelWarning
+		}
+		findings = append(findings, Finding{
+			RuleID:  RuleTechDebtHotspot,
+			Level:   level,
+			File:    h.FilePath,
+			Message: fmt.Sprintf("Technical debt hotspot: %d changes by %d authors, risk score %.2f", h.TotalChanges, h.UniqueAuthors, h.RiskScore),
+		})
+	}
+
+	for _, c := range metrics.CodeConcentrationIssues {
+		level := LevelNote
+		switch c.ConcentrationLevel {
+		case ConcentrationSevere:
+			level = LevelError
+		case ConcentrationHigh:
+			level = LevelWarning
+		}
+		findings = append(findings, Finding{
+			RuleID:  RuleGodFile,
+			Level:   level,
+			File:    c.FilePath,
+			Message: fmt.Sprintf("God file: %d changes by %d authors, %.1f%% of all changes", c.TotalChanges, c.AuthorCount, c.ChangeRatio*100),
+		})
+	}
+
+	for _, s := range metrics.RefactoringSignals {
+		level := LevelNote
+		if s.RefactoringSignal == SignalStrong {
+			level = LevelWarning
+		}
+		findings = append(findings, Finding{
+			RuleID:  RuleRefactoringSignal,
+			Level:   level,
+			File:    s.FilePath,
+			Message: fmt.Sprintf("Refactoring signal: %d changes on %d days within %s", s.ShortTermChanges, s.IntensiveModDays, s.TimeWindow),
+		})
+	}
+
+	for _, s := range metrics.StabilityIndicators {
+		level, unstable := stabilityLevel(s.StabilityLevel)
+		if !unstable {
+			continue
+		}
+		findings = append(findings, Finding{
+			RuleID:  RuleUnstableFile,
+			Level:   level,
+			File:    s.FilePath,
+			Message: stabilityMessage(s),
+		})
+	}
+	return findings
+}
+
+// stabilityLevel returns the finding level of a stability level and whether
+// the file counts as unstable
+func stabilityLevel(level StabilityLevel) (string, bool) {
+	switch level {
+	case StabilityVeryUnstable:
+		return LevelWarning, true
+	case StabilityUnstable:
+		return LevelNote, true
+	}
+	return "", false
+}
+
+func stabilityMessage(s StabilityIndicator) string {
+	return fmt.Sprintf("Unstable file: %.2f changes per day over %.0f days, interval deviation %.1f days", s.ShakeIndex, s.TimeSpread, s.ModificationGap)
+}
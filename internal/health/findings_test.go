@@ -0,0 +1,143 @@
+package health
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testMetrics has one finding of every rule and a stable file
+func testMetrics() *CodeHealthMetrics {
+	return &CodeHealthMetrics{
+		TechnicalDebtHotspots: []TechnicalDebtHotspot{
+			{FilePath: "core.go", TotalChanges: 18, UniqueAuthors: 5, RiskScore: 0.94},
+		},
+		CodeConcentrationIssues: []CodeConcentrationIssue{
+			{FilePath: "core.go", TotalChanges: 18, AuthorCount: 5, ChangeRatio: 0.35, ConcentrationLevel: ConcentrationSevere},
+		},
+		RefactoringSignals: []RefactoringSignal{
+			{FilePath: "api/handler.go", ShortTermChanges: 4, IntensiveModDays: 2, RefactoringSignal: SignalModerate, TimeWindow: "7 days"},
+		},
+		StabilityIndicators: []StabilityIndicator{
+			{FilePath: "core.go", ShakeIndex: 3, TimeSpread: 6, ModificationGap: 4, StabilityLevel: StabilityVeryUnstable},
+			{FilePath: "docs/read me.md", ShakeIndex: 0.1, TimeSpread: 90, StabilityLevel: StabilityStable},
+		},
+	}
+}
+
+func TestFindings(t *testing.T) {
+	findings := Findings(testMetrics())
+
+	var got []string
+	for _, f := range findings {
+		got = append(got, f.RuleID+" "+f.Level+" "+f.File)
+	}
+	want := []string{
+		"tech-debt-hotspot error core.go",
+		"god-file error core.go",
+		"refactoring-signal note api/handler.go",
+		"unstable-file warning core.go",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Unexpected findings:\n%v\nwant\n%v", got, want)
+	}
+	if !strings.Contains(findings[0].Message, "18 changes by 5 authors") {
+		t.Errorf("Unexpected message %q", findings[0].Message)
+	}
+
+	if Findings(nil) != nil {
+		t.Error("Expected no findings without metrics")
+	}
+}
+
+func TestWriteSARIF(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteSARIF(&buf, testMetrics()); err != nil {
+		t.Fatalf("WriteSARIF failed: %v", err)
+	}
+
+	var log struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Tool struct {
+				Driver struct {
+					Name  string `json:"name"`
+					Rules []struct {
+						ID string `json:"id"`
+					} `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+			Results []struct {
+				RuleID    string `json:"ruleId"`
+				RuleIndex int    `json:"ruleIndex"`
+				Level     string `json:"level"`
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct {
+							URI       string `json:"uri"`
+							URIBaseID string `json:"uriBaseId"`
+						} `json:"artifactLocation"`
+					} `json:"physicalLocation"`
+				} `json:"locations"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatalf("Invalid SARIF: %v", err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("Unexpected log %+v", log)
+	}
+	run := log.Runs[0]
+	if run.Tool.Driver.Name != ToolName || len(run.Tool.Driver.Rules) != len(Rules) {
+		t.Errorf("Unexpected driver %+v", run.Tool.Driver)
+	}
+	if len(run.Results) != 4 {
+		t.Fatalf("Expected 4 results, got %d", len(run.Results))
+	}
+	r := run.Results[2]
+	if r.RuleID != RuleRefactoringSignal || run.Tool.Driver.Rules[r.RuleIndex].ID != r.RuleID || r.Level != LevelNote {
+		t.Errorf("Unexpected result %+v", r)
+	}
+	if loc := r.Locations[0].PhysicalLocation.ArtifactLocation; loc.URI != "api/handler.go" || loc.URIBaseID != "%SRCROOT%" {
+		t.Errorf("Unexpected location %+v", loc)
+	}
+
+	// An empty analysis still has an empty result list
+	buf.Reset()
+	WriteSARIF(&buf, &CodeHealthMetrics{})
+	if !strings.Contains(buf.String(), `"results": []`) {
+		t.Errorf("Expected an empty result list, got %s", buf.String())
+	}
+}
+
+func TestWriteJUnit(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteJUnit(&buf, testMetrics()); err != nil {
+		t.Fatalf("WriteJUnit failed: %v", err)
+	}
+
+	var suites junitTestSuites
+	if err := xml.Unmarshal(buf.Bytes(), &suites); err != nil {
+		t.Fatalf("Invalid JUnit XML: %v\n%s", err, buf.String())
+	}
+	// 4 findings and the stable file; the note passes
+	if suites.Tests != 5 || suites.Failures != 3 || len(suites.Suites) != len(Rules) {
+		t.Errorf("Expected 5 tests with 3 failures in %d suites, got %d/%d in %d",
+			len(Rules), suites.Tests, suites.Failures, len(suites.Suites))
+	}
+
+	unstable := suites.Suites[3]
+	if unstable.Name != RuleUnstableFile || unstable.Tests != 2 || unstable.Failures != 1 {
+		t.Errorf("Unexpected unstable-file suite %+v", unstable)
+	}
+	if tc := unstable.Cases[1]; tc.Name != "docs/read me.md" || tc.Failure != nil {
+		t.Errorf("Expected the stable file to pass, got %+v", tc)
+	}
+	if tc := suites.Suites[0].Cases[0]; tc.Failure == nil || tc.Failure.Type != LevelError || tc.ClassName != "health.tech-debt-hotspot" {
+		t.Errorf("Unexpected hotspot case %+v", tc)
+	}
+}
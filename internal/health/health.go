@@ -1,6 +1,7 @@
 package health
 
 import (
+	"context"
 	"fmt"
 	"math"
 	"sort"
@@ -10,15 +11,103 @@ import (
 	"git-log-analyzer/internal/git"
 )
 
-// CodeHealthAnalyzer performs code health analysis
+// CodeHealthAnalyzer performs code health analysis. It aggregates per-file
+// history incrementally, so commits can be fed one at a time with AddCommit
+// instead of keeping the whole commit slice in memory.
 type CodeHealthAnalyzer struct {
-	commits []git.GitCommit
+	files        map[string]*fileHistory
+	totalChanges int
+}
+
+// refactoringWindow is the period in which intensive changes are refactoring
+// signals
+const refactoringWindow = 7 * 24 * time.Hour
+
+// fileHistory is the per-file history the health metrics are derived from.
+// Its size does not grow with the number of changes: only the changes inside
+// the refactoring window are kept individually.
+type fileHistory struct {
+	Authors map[string]bool
+	Changes int          // 修改次数
+	First   time.Time    // 最早修改时间
+	Last    time.Time    // 最近修改时间
+	Gaps    runningStats // 相邻两次修改的间隔（小时）
+	Recent  []time.Time  // 重构窗口内的修改时间
+}
+
+// runningStats is the count, sum and sum of squares of a series of durations
+// in whole seconds. The sums are exact, so the result does not depend on the
+// order the values are added in. Squared intervals fit in an int64 for
+// histories spanning up to about 90 years.
+type runningStats struct {
+	n     int64
+	sum   int64
+	sumSq int64
+}
+
+// add adds a value to the series
+func (rs *runningStats) add(d time.Duration) {
+	seconds := int64(d / time.Second)
+	rs.n++
+	rs.sum += seconds
+	rs.sumSq += seconds * seconds
+}
+
+// stddev returns the population standard deviation of the series in hours
+func (rs *runningStats) stddev() float64 {
+	if rs.n == 0 {
+		return 0
+	}
+	mean := float64(rs.sum) / float64(rs.n)
+	variance := float64(rs.sumSq)/float64(rs.n) - mean*mean
+	if variance <= 0 {
+		return 0
+	}
+	return math.Sqrt(variance) / 3600
 }
 
 // NewCodeHealthAnalyzer creates a new code health analyzer
 func NewCodeHealthAnalyzer(commits []git.GitCommit) *CodeHealthAnalyzer {
-	return &CodeHealthAnalyzer{
-		commits: commits,
+	cha := &CodeHealthAnalyzer{
+		files: make(map[string]*fileHistory),
+	}
+	for i := range commits {
+		cha.AddCommit(&commits[i])
+	}
+	return cha
+}
+
+// AddCommit records the files touched by a commit. A change before the first
+// or after the last known one adds its interval to that end, so the intervals
+// match the sorted history whenever commits come in date order, oldest or
+// newest first. A change falling between known ones adds no interval.
+func (cha *CodeHealthAnalyzer) AddCommit(commit *git.GitCommit) {
+	if len(commit.Files) == 0 {
+		return
+	}
+	recent := commit.Date.After(time.Now().Add(-refactoringWindow))
+	for _, file := range commit.Files {
+		history := cha.files[file]
+		if history == nil {
+			history = &fileHistory{Authors: make(map[string]bool), First: commit.Date, Last: commit.Date}
+			cha.files[file] = history
+		} else if !commit.Date.Before(history.Last) {
+			history.Gaps.add(commit.Date.Sub(history.Last))
+		} else if !commit.Date.After(history.First) {
+			history.Gaps.add(history.First.Sub(commit.Date))
+		}
+		history.Authors[commit.Author] = true
+		history.Changes++
+		if commit.Date.Before(history.First) {
+			history.First = commit.Date
+		}
+		if commit.Date.After(history.Last) {
+			history.Last = commit.Date
+		}
+		if recent {
+			history.Recent = append(history.Recent, commit.Date)
+		}
+		cha.totalChanges++
 	}
 }
 
@@ -32,6 +121,48 @@ type CodeHealthMetrics struct {
 	HealthSummary           string                   `json:"healthSummary"`
 }
 
+// StabilityLevel classifies how steadily a file changes
+type StabilityLevel string
+
+// Stability levels, from least to most stable
+const (
+	StabilityVeryUnstable StabilityLevel = "极不稳定"
+	StabilityUnstable     StabilityLevel = "不稳定"
+	StabilityModerate     StabilityLevel = "中等稳定"
+	StabilityStable       StabilityLevel = "稳定"
+)
+
+// SignalStrength is how strongly recent changes suggest a refactoring
+type SignalStrength string
+
+// Refactoring signal strengths
+const (
+	SignalStrong   SignalStrength = "强烈"
+	SignalModerate SignalStrength = "中等"
+	SignalWeak     SignalStrength = "轻微"
+)
+
+// ConcentrationLevel is how much of all changes a file concentrates
+type ConcentrationLevel string
+
+// Concentration levels, from most to least concentrated
+const (
+	ConcentrationSevere   ConcentrationLevel = "严重集中"
+	ConcentrationHigh     ConcentrationLevel = "高度集中"
+	ConcentrationModerate ConcentrationLevel = "中度集中"
+	ConcentrationLow      ConcentrationLevel = "轻度集中"
+)
+
+// ImpactLevel is how many changes and authors a concentrated file affects
+type ImpactLevel string
+
+// Impact levels
+const (
+	ImpactHigh   ImpactLevel = "高影响"
+	ImpactMedium ImpactLevel = "中影响"
+	ImpactLow    ImpactLevel = "低影响"
+)
+
 // TechnicalDebtHotspot represents a file with potential technical debt
 type TechnicalDebtHotspot struct {
 	FilePath         string    `json:"filePath"`
@@ -45,47 +176,60 @@ type TechnicalDebtHotspot struct {
 
 // StabilityIndicator represents file stability metrics
 type StabilityIndicator struct {
-	FilePath        string  `json:"filePath"`
-	ShakeIndex      float64 `json:"shakeIndex"`      // 震荡指数
-	TimeSpread      float64 `json:"timeSpread"`      // 时间分布
-	ModificationGap float64 `json:"modificationGap"` // 修改间隔方差
-	StabilityLevel  string  `json:"stabilityLevel"`
+	FilePath        string         `json:"filePath"`
+	ShakeIndex      float64        `json:"shakeIndex"`      // 震荡指数
+	TimeSpread      float64        `json:"timeSpread"`      // 时间分布
+	ModificationGap float64        `json:"modificationGap"` // 修改间隔方差
+	StabilityLevel  StabilityLevel `json:"stabilityLevel"`
 }
 
 // RefactoringSignal represents potential refactoring needs
 type RefactoringSignal struct {
-	FilePath          string    `json:"filePath"`
-	IntensiveModDays  int       `json:"intensiveModDays"`  // 密集修改天数
-	ShortTermChanges  int       `json:"shortTermChanges"`  // 短期内修改次数
-	RefactoringSignal string    `json:"refactoringSignal"` // 重构信号强度
-	TimeWindow        string    `json:"timeWindow"`
-	FirstChange       time.Time `json:"firstChange"`
-	LastChange        time.Time `json:"lastChange"`
+	FilePath          string         `json:"filePath"`
+	IntensiveModDays  int            `json:"intensiveModDays"`  // 密集修改天数
+	ShortTermChanges  int            `json:"shortTermChanges"`  // 短期内修改次数
+	RefactoringSignal SignalStrength `json:"refactoringSignal"` // 重构信号强度
+	TimeWindow        string         `json:"timeWindow"`
+	FirstChange       time.Time      `json:"firstChange"`
+	LastChange        time.Time      `json:"lastChange"`
 }
 
 // CodeConcentrationIssue represents "God File" issues
 type CodeConcentrationIssue struct {
-	FilePath       string  `json:"filePath"`
-	TotalChanges   int     `json:"totalChanges"`
-	AuthorCount    int     `json:"authorCount"`
-	ChangeRatio    float64 `json:"changeRatio"`    // 占总变更的比例
-	ConcentrationLevel string `json:"concentrationLevel"`
-	ImpactLevel    string  `json:"impactLevel"`
+	FilePath           string             `json:"filePath"`
+	TotalChanges       int                `json:"totalChanges"`
+	AuthorCount        int                `json:"authorCount"`
+	ChangeRatio        float64            `json:"changeRatio"` // 占总变更的比例
+	ConcentrationLevel ConcentrationLevel `json:"concentrationLevel"`
+	ImpactLevel        ImpactLevel        `json:"impactLevel"`
 }
 
-// AnalyzeCodeHealth performs comprehensive code health analysis
-func (cha *CodeHealthAnalyzer) AnalyzeCodeHealth() *CodeHealthMetrics {
+// AnalyzeCodeHealth performs comprehensive code health analysis.
+// It returns ctx.Err() if the context is canceled between analysis phases.
+func (cha *CodeHealthAnalyzer) AnalyzeCodeHealth(ctx context.Context) (*CodeHealthMetrics, error) {
 	// 分析技术债务热点
 	techDebtHotspots := cha.analyzeTechnicalDebtHotspots()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	
 	// 分析代码稳定性指标
 	stabilityIndicators := cha.analyzeStabilityIndicators()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	
 	// 分析重构信号
 	refactoringSignals := cha.analyzeRefactoringSignals()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	
 	// 分析代码集中度问题
 	concentrationIssues := cha.analyzeCodeConcentration()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	
 	// 计算总体健康分数
 	healthScore := cha.calculateHealthScore(techDebtHotspots, stabilityIndicators, refactoringSignals, concentrationIssues)
@@ -100,7 +244,7 @@ func (cha *CodeHealthAnalyzer) AnalyzeCodeHealth() *CodeHealthMetrics {
 		CodeConcentrationIssues: concentrationIssues,
 		HealthScore:             healthScore,
 		HealthSummary:           healthSummary,
-	}
+	}, nil
 }
 
 // analyzeTechnicalDebtHotspots identifies files with potential technical debt
@@ -108,28 +252,13 @@ func (cha *CodeHealthAnalyzer) analyzeTechnicalDebtHotspots() []TechnicalDebtHot
 	fileStats := make(map[string]*fileStatistic)
 	
 	// 统计每个文件的修改信息
-	for _, commit := range cha.commits {
-		for _, file := range commit.Files {
-			if fileStats[file] == nil {
-				fileStats[file] = &fileStatistic{
-					FilePath:      file,
-					Authors:       make(map[string]bool),
-					Changes:       0,
-					FirstModified: commit.Date,
-					LastModified:  commit.Date,
-				}
-			}
-			
-			stat := fileStats[file]
-			stat.Authors[commit.Author] = true
-			stat.Changes++
-			
-			if commit.Date.Before(stat.FirstModified) {
-				stat.FirstModified = commit.Date
-			}
-			if commit.Date.After(stat.LastModified) {
-				stat.LastModified = commit.Date
-			}
+	for file, history := range cha.files {
+		fileStats[file] = &fileStatistic{
+			FilePath:      file,
+			Authors:       history.Authors,
+			Changes:       history.Changes,
+			FirstModified: history.First,
+			LastModified:  history.Last,
 		}
 	}
 	
@@ -159,7 +288,10 @@ func (cha *CodeHealthAnalyzer) analyzeTechnicalDebtHotspots() []TechnicalDebtHot
 	
 	// 按风险分数排序
 	sort.Slice(hotspots, func(i, j int) bool {
-		return hotspots[i].RiskScore > hotspots[j].RiskScore
+		if hotspots[i].RiskScore != hotspots[j].RiskScore {
+			return hotspots[i].RiskScore > hotspots[j].RiskScore
+		}
+		return hotspots[i].FilePath < hotspots[j].FilePath
 	})
 	
 	// 限制返回数量
@@ -172,35 +304,21 @@ func (cha *CodeHealthAnalyzer) analyzeTechnicalDebtHotspots() []TechnicalDebtHot
 
 // analyzeStabilityIndicators calculates file stability metrics
 func (cha *CodeHealthAnalyzer) analyzeStabilityIndicators() []StabilityIndicator {
-	fileChanges := make(map[string][]time.Time)
-	
-	// 收集每个文件的修改时间
-	for _, commit := range cha.commits {
-		for _, file := range commit.Files {
-			fileChanges[file] = append(fileChanges[file], commit.Date)
-		}
-	}
-	
 	var indicators []StabilityIndicator
 	
-	for filePath, changes := range fileChanges {
-		if len(changes) < 2 {
+	for filePath, history := range cha.files {
+		if history.Changes < 2 {
 			continue
 		}
 		
-		// 排序时间
-		sort.Slice(changes, func(i, j int) bool {
-			return changes[i].Before(changes[j])
-		})
-		
 		// 计算震荡指数
-		shakeIndex := cha.calculateShakeIndex(changes)
+		shakeIndex := cha.calculateShakeIndex(history)
 		
 		// 计算时间分布
-		timeSpread := cha.calculateTimeSpread(changes)
+		timeSpread := cha.calculateTimeSpread(history)
 		
 		// 计算修改间隔方差
-		modGap := cha.calculateModificationGap(changes)
+		modGap := cha.calculateModificationGap(history)
 		
 		// 确定稳定性等级
 		stabilityLevel := cha.getStabilityLevel(shakeIndex, timeSpread, modGap)
@@ -216,7 +334,10 @@ func (cha *CodeHealthAnalyzer) analyzeStabilityIndicators() []StabilityIndicator
 	
 	// 按震荡指数排序
 	sort.Slice(indicators, func(i, j int) bool {
-		return indicators[i].ShakeIndex > indicators[j].ShakeIndex
+		if indicators[i].ShakeIndex != indicators[j].ShakeIndex {
+			return indicators[i].ShakeIndex > indicators[j].ShakeIndex
+		}
+		return indicators[i].FilePath < indicators[j].FilePath
 	})
 	
 	// 限制返回数量
@@ -231,19 +352,16 @@ func (cha *CodeHealthAnalyzer) analyzeStabilityIndicators() []StabilityIndicator
 func (cha *CodeHealthAnalyzer) analyzeRefactoringSignals() []RefactoringSignal {
 	var signals []RefactoringSignal
 	
-	// 分析短期内密集修改的文件
-	recentWindow := 7 * 24 * time.Hour // 7天窗口
-	
+	// 分析短期内密集修改的文件（7天窗口）
 	fileRecentChanges := make(map[string][]time.Time)
 	
-	now := time.Now()
-	cutoff := now.Add(-recentWindow)
+	cutoff := time.Now().Add(-refactoringWindow)
 	
 	// 收集最近的修改
-	for _, commit := range cha.commits {
-		if commit.Date.After(cutoff) {
-			for _, file := range commit.Files {
-				fileRecentChanges[file] = append(fileRecentChanges[file], commit.Date)
+	for file, history := range cha.files {
+		for _, date := range history.Recent {
+			if date.After(cutoff) {
+				fileRecentChanges[file] = append(fileRecentChanges[file], date)
 			}
 		}
 	}
@@ -273,7 +391,10 @@ func (cha *CodeHealthAnalyzer) analyzeRefactoringSignals() []RefactoringSignal {
 	
 	// 按修改频率排序
 	sort.Slice(signals, func(i, j int) bool {
-		return signals[i].ShortTermChanges > signals[j].ShortTermChanges
+		if signals[i].ShortTermChanges != signals[j].ShortTermChanges {
+			return signals[i].ShortTermChanges > signals[j].ShortTermChanges
+		}
+		return signals[i].FilePath < signals[j].FilePath
 	})
 	
 	return signals
@@ -282,22 +403,14 @@ func (cha *CodeHealthAnalyzer) analyzeRefactoringSignals() []RefactoringSignal {
 // analyzeCodeConcentration identifies "God Files" with excessive changes
 func (cha *CodeHealthAnalyzer) analyzeCodeConcentration() []CodeConcentrationIssue {
 	fileStats := make(map[string]*fileStatistic)
-	totalChanges := 0
+	totalChanges := cha.totalChanges
 	
 	// 统计文件修改信息
-	for _, commit := range cha.commits {
-		for _, file := range commit.Files {
-			if fileStats[file] == nil {
-				fileStats[file] = &fileStatistic{
-					FilePath: file,
-					Authors:  make(map[string]bool),
-					Changes:  0,
-				}
-			}
-			
-			fileStats[file].Authors[commit.Author] = true
-			fileStats[file].Changes++
-			totalChanges++
+	for file, history := range cha.files {
+		fileStats[file] = &fileStatistic{
+			FilePath: file,
+			Authors:  history.Authors,
+			Changes:  history.Changes,
 		}
 	}
 	
@@ -324,7 +437,10 @@ func (cha *CodeHealthAnalyzer) analyzeCodeConcentration() []CodeConcentrationIss
 	
 	// 按变更比例排序
 	sort.Slice(issues, func(i, j int) bool {
-		return issues[i].ChangeRatio > issues[j].ChangeRatio
+		if issues[i].ChangeRatio != issues[j].ChangeRatio {
+			return issues[i].ChangeRatio > issues[j].ChangeRatio
+		}
+		return issues[i].FilePath < issues[j].FilePath
 	})
 	
 	return issues
@@ -373,69 +489,50 @@ func (cha *CodeHealthAnalyzer) getTechDebtReason(stat *fileStatistic, riskScore
 }
 
 // calculateShakeIndex calculates how frequently a file changes
-func (cha *CodeHealthAnalyzer) calculateShakeIndex(changes []time.Time) float64 {
-	if len(changes) < 2 {
+func (cha *CodeHealthAnalyzer) calculateShakeIndex(history *fileHistory) float64 {
+	if history.Changes < 2 {
 		return 0
 	}
 	
 	// 计算变更频率的变异性
-	totalDuration := changes[len(changes)-1].Sub(changes[0]).Hours()
+	totalDuration := history.Last.Sub(history.First).Hours()
 	if totalDuration == 0 {
-		return float64(len(changes))
+		return float64(history.Changes)
 	}
 	
-	changeRate := float64(len(changes)) / (totalDuration / 24) // changes per day
+	changeRate := float64(history.Changes) / (totalDuration / 24) // changes per day
 	return math.Min(changeRate, 10.0) // 限制最大值
 }
 
 // calculateTimeSpread calculates the time distribution of changes
-func (cha *CodeHealthAnalyzer) calculateTimeSpread(changes []time.Time) float64 {
-	if len(changes) < 2 {
+func (cha *CodeHealthAnalyzer) calculateTimeSpread(history *fileHistory) float64 {
+	if history.Changes < 2 {
 		return 0
 	}
 	
-	totalSpan := changes[len(changes)-1].Sub(changes[0]).Hours()
+	totalSpan := history.Last.Sub(history.First).Hours()
 	return totalSpan / 24 // 返回天数
 }
 
-// calculateModificationGap calculates variance in modification intervals
-func (cha *CodeHealthAnalyzer) calculateModificationGap(changes []time.Time) float64 {
-	if len(changes) < 3 {
+// calculateModificationGap calculates the standard deviation of the
+// modification intervals
+func (cha *CodeHealthAnalyzer) calculateModificationGap(history *fileHistory) float64 {
+	if history.Changes < 3 {
 		return 0
 	}
-	
-	var gaps []float64
-	for i := 1; i < len(changes); i++ {
-		gap := changes[i].Sub(changes[i-1]).Hours()
-		gaps = append(gaps, gap)
-	}
-	
-	// 计算方差
-	mean := 0.0
-	for _, gap := range gaps {
-		mean += gap
-	}
-	mean /= float64(len(gaps))
-	
-	variance := 0.0
-	for _, gap := range gaps {
-		variance += (gap - mean) * (gap - mean)
-	}
-	variance /= float64(len(gaps))
-	
-	return math.Sqrt(variance) / 24 // 转换为天数
+	return history.Gaps.stddev() / 24 // 转换为天数
 }
 
 // getStabilityLevel determines stability level based on metrics
-func (cha *CodeHealthAnalyzer) getStabilityLevel(shakeIndex, timeSpread, modGap float64) string {
+func (cha *CodeHealthAnalyzer) getStabilityLevel(shakeIndex, timeSpread, modGap float64) StabilityLevel {
 	if shakeIndex > 2.0 && modGap > 3.0 {
-		return "极不稳定"
+		return StabilityVeryUnstable
 	} else if shakeIndex > 1.0 && modGap > 1.5 {
-		return "不稳定"
+		return StabilityUnstable
 	} else if shakeIndex > 0.5 {
-		return "中等稳定"
+		return StabilityModerate
 	} else {
-		return "稳定"
+		return StabilityStable
 	}
 }
 
@@ -452,39 +549,39 @@ func (cha *CodeHealthAnalyzer) calculateIntensiveModificationDays(changes []time
 }
 
 // getRefactoringSignalStrength determines refactoring signal strength
-func (cha *CodeHealthAnalyzer) getRefactoringSignalStrength(changes, days int) string {
+func (cha *CodeHealthAnalyzer) getRefactoringSignalStrength(changes, days int) SignalStrength {
 	ratio := float64(changes) / float64(days)
 	
 	if ratio >= 3.0 {
-		return "强烈"
+		return SignalStrong
 	} else if ratio >= 2.0 {
-		return "中等"
+		return SignalModerate
 	} else {
-		return "轻微"
+		return SignalWeak
 	}
 }
 
 // getConcentrationLevel determines code concentration level
-func (cha *CodeHealthAnalyzer) getConcentrationLevel(ratio float64, changes int) string {
+func (cha *CodeHealthAnalyzer) getConcentrationLevel(ratio float64, changes int) ConcentrationLevel {
 	if ratio > 0.3 || changes > 50 {
-		return "严重集中"
+		return ConcentrationSevere
 	} else if ratio > 0.2 || changes > 30 {
-		return "高度集中"
+		return ConcentrationHigh
 	} else if ratio > 0.1 || changes > 20 {
-		return "中度集中"
+		return ConcentrationModerate
 	} else {
-		return "轻度集中"
+		return ConcentrationLow
 	}
 }
 
 // getImpactLevel determines impact level based on changes and authors
-func (cha *CodeHealthAnalyzer) getImpactLevel(changes, authors int) string {
+func (cha *CodeHealthAnalyzer) getImpactLevel(changes, authors int) ImpactLevel {
 	if changes > 30 && authors > 3 {
-		return "高影响"
+		return ImpactHigh
 	} else if changes > 20 || authors > 2 {
-		return "中影响"
+		return ImpactMedium
 	} else {
-		return "低影响"
+		return ImpactLow
 	}
 }
 
@@ -0,0 +1,171 @@
+package health
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"git-log-analyzer/internal/fixture"
+	"git-log-analyzer/internal/git"
+)
+
+// loadCommits reads the history of a fixture repository including the files
+// touched by each commit
+func loadCommits(t *testing.T, dir string) []git.GitCommit {
+	t.Helper()
+	ctx := context.Background()
+	repo := git.NewRepository(dir)
+
+	commits, err := repo.GetCommits(ctx, 0)
+	if err != nil {
+		t.Fatalf("GetCommits failed: %v", err)
+	}
+	for i := range commits {
+		_, _, files, err := repo.GetCommitStats(ctx, commits[i].Hash)
+		if err != nil {
+			t.Fatalf("GetCommitStats failed: %v", err)
+		}
+		commits[i].Files = files
+	}
+	return commits
+}
+
+func TestAnalyzeCodeHealth(t *testing.T) {
+	// Midnight two days ago, so the last three changes fall on the same
+	// day inside the 7 day refactoring window
+	base := time.Now().UTC().Truncate(24 * time.Hour).Add(-48 * time.Hour)
+	at := func(days, hour int) time.Time {
+		return base.AddDate(0, 0, days).Add(time.Duration(hour) * time.Hour)
+	}
+
+	repo := fixture.Build(t, fixture.Script{
+		fixture.Commit{Author: fixture.Alice, Date: at(-30, 9), Message: "Initial commit",
+			Files: map[string]string{"core.go": fixture.Lines(1), "docs.md": fixture.Lines(1)}},
+		fixture.Commit{Author: fixture.Bob, Date: at(-20, 9), Message: "Extend core",
+			Files: map[string]string{"core.go": fixture.Lines(2)}},
+		fixture.Commit{Author: fixture.Alice, Date: at(0, 9), Message: "Rework core",
+			Files: map[string]string{"core.go": fixture.Lines(3)}},
+		fixture.Commit{Author: fixture.Bob, Date: at(0, 10), Message: "Rework core again",
+			Files: map[string]string{"core.go": fixture.Lines(4)}},
+		fixture.Commit{Author: fixture.Carol, Date: at(0, 11), Message: "Fix core",
+			Files: map[string]string{"core.go": fixture.Lines(5)}},
+	})
+
+	metrics, err := NewCodeHealthAnalyzer(loadCommits(t, repo.Dir)).AnalyzeCodeHealth(context.Background())
+	if err != nil {
+		t.Fatalf("AnalyzeCodeHealth failed: %v", err)
+	}
+
+	// core.go: 5 changes by 3 authors, risk 5/20*0.6 + 3/5*0.4 = 0.39
+	if len(metrics.TechnicalDebtHotspots) != 1 {
+		t.Fatalf("Expected 1 hotspot, got %+v", metrics.TechnicalDebtHotspots)
+	}
+	hotspot := metrics.TechnicalDebtHotspots[0]
+	if hotspot.FilePath != "core.go" || hotspot.TotalChanges != 5 || hotspot.UniqueAuthors != 3 {
+		t.Errorf("Unexpected hotspot: %+v", hotspot)
+	}
+	if math.Abs(hotspot.RiskScore-0.39) > 1e-9 || hotspot.Reason != "潜在技术债务" {
+		t.Errorf("Expected risk 0.39 (潜在技术债务), got %.2f (%s)", hotspot.RiskScore, hotspot.Reason)
+	}
+	if !hotspot.LastModified.Equal(at(0, 11)) {
+		t.Errorf("Expected last modification %v, got %v", at(0, 11), hotspot.LastModified)
+	}
+
+	// Three changes within a single day
+	if len(metrics.RefactoringSignals) != 1 {
+		t.Fatalf("Expected 1 refactoring signal, got %+v", metrics.RefactoringSignals)
+	}
+	signal := metrics.RefactoringSignals[0]
+	if signal.FilePath != "core.go" || signal.ShortTermChanges != 3 || signal.IntensiveModDays != 1 || signal.RefactoringSignal != "强烈" {
+		t.Errorf("Unexpected refactoring signal: %+v", signal)
+	}
+
+	// Only core.go changed more than once; 5 changes over ~30 days is stable
+	if len(metrics.StabilityIndicators) != 1 {
+		t.Fatalf("Expected 1 stability indicator, got %+v", metrics.StabilityIndicators)
+	}
+	if indicator := metrics.StabilityIndicators[0]; indicator.FilePath != "core.go" || indicator.StabilityLevel != StabilityStable {
+		t.Errorf("Unexpected stability indicator: %+v", indicator)
+	}
+
+	expectedIssues := []struct {
+		file          string
+		changes       int
+		concentration ConcentrationLevel
+		impact        ImpactLevel
+	}{
+		{"core.go", 5, ConcentrationSevere, ImpactMedium},
+		{"docs.md", 1, ConcentrationModerate, ImpactLow},
+	}
+	if len(metrics.CodeConcentrationIssues) != len(expectedIssues) {
+		t.Fatalf("Expected %d concentration issues, got %+v", len(expectedIssues), metrics.CodeConcentrationIssues)
+	}
+	for i, want := range expectedIssues {
+		got := metrics.CodeConcentrationIssues[i]
+		if got.FilePath != want.file || got.TotalChanges != want.changes || got.ConcentrationLevel != want.concentration || got.ImpactLevel != want.impact {
+			t.Errorf("Issue %d: expected %+v, got %+v", i, want, got)
+		}
+	}
+
+	// 1 - 0.05 (hotspot) - 0.08 (signal) - 2*0.1 (issues)
+	if math.Abs(metrics.HealthScore-0.67) > 1e-9 {
+		t.Errorf("Expected health score 0.67, got %.2f", metrics.HealthScore)
+	}
+}
+
+func TestAnalyzeCodeHealth_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := NewCodeHealthAnalyzer(nil).AnalyzeCodeHealth(ctx); err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestAddCommit_BoundedHistory(t *testing.T) {
+	now := time.Now()
+	// Irregular gaps of 1, 3 and 8 days, the last two changes inside the
+	// refactoring window
+	dates := []time.Time{
+		now.Add(-12 * 24 * time.Hour),
+		now.Add(-11 * 24 * time.Hour),
+		now.Add(-8 * 24 * time.Hour),
+		now.Add(-2 * time.Hour),
+		now.Add(-1 * time.Hour),
+	}
+	var oldestFirst, newestFirst []git.GitCommit
+	for i, date := range dates {
+		oldestFirst = append(oldestFirst, git.GitCommit{Author: "Alice", Date: date, Files: []string{"main.go"}})
+		newestFirst = append(newestFirst, git.GitCommit{Author: "Bob", Date: dates[len(dates)-1-i], Files: []string{"main.go"}})
+	}
+
+	// Population standard deviation of the gaps in hours: 24, 72, 190, 1
+	gaps := []float64{24, 72, 190, 1}
+	var mean, variance float64
+	for _, gap := range gaps {
+		mean += gap / float64(len(gaps))
+	}
+	for _, gap := range gaps {
+		variance += (gap - mean) * (gap - mean) / float64(len(gaps))
+	}
+	wantGap := math.Sqrt(variance) / 24
+
+	for name, commits := range map[string][]git.GitCommit{"oldest first": oldestFirst, "newest first": newestFirst} {
+		cha := NewCodeHealthAnalyzer(commits)
+		history := cha.files["main.go"]
+
+		if history.Changes != 5 {
+			t.Errorf("%s: expected 5 changes, got %d", name, history.Changes)
+		}
+		if !history.First.Equal(dates[0]) || !history.Last.Equal(dates[4]) {
+			t.Errorf("%s: expected span %v - %v, got %v - %v", name, dates[0], dates[4], history.First, history.Last)
+		}
+		if got := cha.calculateModificationGap(history); math.Abs(got-wantGap) > 1e-9 {
+			t.Errorf("%s: expected modification gap %.4f, got %.4f", name, wantGap, got)
+		}
+		if len(history.Recent) != 2 {
+			t.Errorf("%s: expected 2 changes inside the refactoring window, got %d", name, len(history.Recent))
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package health
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	ClassName string        `xml:"classname,attr"`
+	Name      string        `xml:"name,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Type    string `xml:"type,attr"`
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// WriteJUnit writes the findings of metrics as JUnit XML with a test suite
+// per rule and a test case per file. Errors and warnings fail their test
+// case; notes pass with the message as output. The unstable-file suite also
+// has a passing case for every stable file among the stability indicators.
+func WriteJUnit(w io.Writer, metrics *CodeHealthMetrics) error {
+	suites := make(map[string]*junitTestSuite)
+	root := junitTestSuites{Name: ToolName + " health"}
+	for _, rule := range Rules {
+		root.Suites = append(root.Suites, junitTestSuite{Name: rule.ID})
+	}
+	for i := range root.Suites {
+		suites[root.Suites[i].Name] = &root.Suites[i]
+	}
+
+	for _, f := range Findings(metrics) {
+		tc := junitTestCase{ClassName: "health." + f.RuleID, Name: f.File}
+		if f.Level == LevelNote {
+			tc.SystemOut = f.Message
+		} else {
+			tc.Failure = &junitFailure{Type: f.Level, Message: f.Message, Text: f.Message}
+		}
+		suites[f.RuleID].add(tc)
+	}
+	if metrics != nil {
+		for _, s := range metrics.StabilityIndicators {
+			if _, unstable := stabilityLevel(s.StabilityLevel); !unstable {
+				suites[RuleUnstableFile].add(junitTestCase{ClassName: "health." + RuleUnstableFile, Name: s.FilePath})
+			}
+		}
+	}
+
+	for _, s := range root.Suites {
+		root.Tests += s.Tests
+		root.Failures += s.Failures
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(root); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func (s *junitTestSuite) add(tc junitTestCase) {
+	s.Cases = append(s.Cases, tc)
+	s.Tests++
+	if tc.Failure != nil {
+		s.Failures++
+	}
+}
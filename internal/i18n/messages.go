@@ -16,36 +16,128 @@ const (
 // Messages contains all translatable strings
 type Messages struct {
 	// Report titles
-	ReportTitle             string
-	TopContributors         string
-	MostActiveHours         string
-	MostModifiedFiles       string
-	
+	ReportTitle       string
+	TopContributors   string
+	MostActiveHours   string
+	MostModifiedFiles string
+
 	// Report fields
-	TotalCommits            string
-	ActivePeriod            string
-	ActiveDays              string
-	ActiveWeeks             string
-	ActiveMonths            string
-	Contributors            string
-	CommitTimeline          string
-	HourlyActivity          string
-	DailyActivity           string
-	CommitForest            string
-	GeneratedOn             string
-	
+	TotalCommits   string
+	ActivePeriod   string
+	ActiveDays     string
+	ActiveWeeks    string
+	ActiveMonths   string
+	Contributors   string
+	CommitTimeline string
+	HourlyActivity string
+	DailyActivity  string
+	CommitForest   string
+	GeneratedOn    string
+
 	// Units
-	Commits                 string
-	Lines                   string
-	Modifications           string
-	
+	Commits       string
+	Lines         string
+	Modifications string
+
 	// Days of week
-	DayNames                []string
-	
+	DayNames []string
+
 	// AI Prompts
-	AIPromptTemplate        string
-	AISystemMessage         string
-	AIAnalysisTitle         string
+	AIPromptTemplate string
+	AISystemMessage  string
+	AIAnalysisTitle  string
+
+	// Structured AI output
+	AIStructuredInstructions string
+	AIRepairPrompt           string
+	AISummaryTitle           string
+	AIRisksTitle             string
+	AIRecommendationsTitle   string
+	AIFilesLabel             string
+	AIAuthorsLabel           string
+	AIConfidenceLabel        string
+
+	// AI commit message scoring
+	AIMessageScoringPrompt string
+
+	// AI developer narrative
+	AIDeveloperPrompt string
+
+	// AI repository chat
+	AIChatSystemPrompt string
+
+	// AI changelog summaries
+	AIChangelogPrompt string
+
+	// Changelog
+	ChangelogTitles       map[string]string // section titles by commit type
+	ChangelogBreaking     string
+	ChangelogContributors string
+	ChangelogUnreleased   string
+	ChangelogEmpty        string
+
+	// Print and PDF reports
+	PrintContents    string
+	PrintSummary     string
+	PrintActivity    string
+	PrintHealth      string
+	PrintHealthScore string
+	PrintNoFindings  string
+	PrintAuthor      string
+	PrintCommits     string
+	PrintShare       string
+	PrintAdditions   string
+	PrintDeletions   string
+	PrintFile        string
+	PrintChanges     string
+	PrintLevel       string
+	PrintRule        string
+	PrintMessage     string
+	PrintPage        string // page number and page count
+
+	// Static charts
+	ChartHotspots  string
+	ChartPunchCard string
+	ChartCalendar  string
+	ChartLess      string // legend of the least active days
+	ChartMore      string
+	MonthNames     []string
+
+	// Terminal dashboard
+	TUITabOverview   string
+	TUITabFiles      string
+	TUITabBranches   string
+	TUITabDevelopers string
+	TUIFiles         string
+	TUIAuthors       string
+	TUIMainAuthor    string
+	TUILastCommit    string
+	TUIFirstCommit   string
+	TUIBranch        string
+	TUIActive        string
+	TUIBranchSummary string // branch count and merge count
+	TUINoBranches    string
+	TUIWorkStyle     string
+	TUICommitsPerDay string
+	TUICommitSize    string
+	TUIConsistency   string
+	TUIFocus         string
+	TUILanguages     string
+	TUIAuthorFiles   string // author name
+	TUIFileAuthors   string // file path
+	TUIRecentCommits string
+	TUIEmpty         string
+	TUIHelp          string
+	TUIHelpDetail    string
+
+	// AI prompt fact sections
+	AIFactsOverview      string
+	AIFactsHotspots      string
+	AIFactsRefactoring   string
+	AIFactsConcentration string
+	AIFactsTimeline      string
+	AIFactsDevelopers    string
+	AIFactsFiles         string
 }
 
 // translations contains all language translations
@@ -74,17 +166,10 @@ var translations = map[Language]*Messages{
 		
 		DayNames:                []string{"周日", "周一", "周二", "周三", "周四", "周五", "周六"},
 		
-		AIPromptTemplate: `请分析以下Git仓库统计数据并提供见解：
+		AIPromptTemplate: `请分析以下Git仓库的关键数据并提供见解：
 
-基础统计:
 %s
 
-详细数据:
-- 总提交数: %d
-- 贡献者数量: %d
-- 活跃周期: %s 到 %s
-- 活跃天数: %d
-
 请提供:
 1. 开发模式分析
 2. 团队协作见解
@@ -96,6 +181,123 @@ var translations = map[Language]*Messages{
 
 		AISystemMessage: "你是一位专业的软件开发分析师。请分析Git仓库数据并提供可行的见解。请用中文回答。",
 		AIAnalysisTitle: "智能分析",
+
+		AIStructuredInstructions: `请只返回一个符合以下结构的 JSON 对象，不要输出任何其他内容：
+{
+  "summary": "整体结论（字符串）",
+  "risks": [{"title": "风险标题", "severity": "low|medium|high|critical", "description": "说明"}],
+  "recommendations": [{"title": "建议标题", "description": "具体做法", "files": ["相关文件路径"], "authors": ["相关作者"]}],
+  "confidence": 0.0 到 1.0 之间的数字
+}
+字符串内容请用中文。`,
+		AIRepairPrompt: `你的回复不符合要求的 JSON 结构：
+%s
+请只返回修正后的 JSON 对象。`,
+		AISummaryTitle:         "总结",
+		AIRisksTitle:           "风险",
+		AIRecommendationsTitle: "建议",
+		AIFilesLabel:           "相关文件",
+		AIAuthorsLabel:         "相关作者",
+		AIConfidenceLabel:      "置信度",
+
+		AIMessageScoringPrompt: `请从清晰度、范围说明、祈使语气和关联引用（如 issue 编号）四个方面，为以下每条提交信息打分（0-100）。
+请只返回一个 JSON 对象，不要输出任何其他内容：
+{"scores": [{"hash": "提交哈希", "score": 0 到 100 的数字, "issues": ["主要问题（中文，简短）"]}]}
+
+%s`,
+
+		AIDeveloperPrompt: `以下是一位开发者在该仓库中的真实指标和最近的提交。请用 3 到 5 句话客观描述其工作方式和贡献，并给出 2 到 4 条具体、可执行的成长建议。不要猜测指标之外的个人信息。
+请只返回一个 JSON 对象，不要输出任何其他内容：
+{"narrative": "描述（中文）", "suggestions": ["建议（中文）"]}
+
+%s`,
+
+		AIChatSystemPrompt: `你是一位熟悉该 Git 仓库历史的助手。请根据工具查询到的真实数据回答用户关于作者、文件、技术债务热点和提交的问题；需要数据时先调用工具，不要编造数据。回答要简洁，引用具体的作者、文件和提交。请用中文回答。
+
+仓库概览：
+%s`,
+
+		AIChangelogPrompt: `以下是版本 %s 中“%s”分组的提交。请用 1 到 3 句话为发布说明总结这一组变更，面向使用者，突出最重要的变化，不要逐条罗列，不要编造提交中没有的内容。只返回总结文字。请用中文回答。
+
+%s`,
+
+		ChangelogTitles: map[string]string{
+			"feat":     "新功能",
+			"fix":      "问题修复",
+			"perf":     "性能优化",
+			"refactor": "重构",
+			"docs":     "文档",
+			"test":     "测试",
+			"build":    "构建",
+			"ci":       "持续集成",
+			"chore":    "杂项",
+			"style":    "代码风格",
+			"revert":   "回滚",
+			"other":    "其他",
+		},
+		ChangelogBreaking:     "⚠ 破坏性变更",
+		ChangelogContributors: "贡献者",
+		ChangelogUnreleased:   "未发布",
+		ChangelogEmpty:        "无变更。",
+
+		PrintContents:    "目录",
+		PrintSummary:     "概要",
+		PrintActivity:    "提交活动",
+		PrintHealth:      "代码健康",
+		PrintHealthScore: "健康评分",
+		PrintNoFindings:  "没有发现问题。",
+		PrintAuthor:      "作者",
+		PrintCommits:     "提交",
+		PrintShare:       "占比",
+		PrintAdditions:   "新增行",
+		PrintDeletions:   "删除行",
+		PrintFile:        "文件",
+		PrintChanges:     "修改次数",
+		PrintLevel:       "级别",
+		PrintRule:        "规则",
+		PrintMessage:     "说明",
+		PrintPage:        "第 %d 页，共 %d 页",
+
+		ChartHotspots:  "技术债务热点",
+		ChartPunchCard: "提交打卡图",
+		ChartCalendar:  "提交日历",
+		ChartLess:      "少",
+		ChartMore:      "多",
+		MonthNames:     []string{"1月", "2月", "3月", "4月", "5月", "6月", "7月", "8月", "9月", "10月", "11月", "12月"},
+
+		TUITabOverview:   "概览",
+		TUITabFiles:      "文件",
+		TUITabBranches:   "分支",
+		TUITabDevelopers: "开发者画像",
+		TUIFiles:         "文件数",
+		TUIAuthors:       "作者数",
+		TUIMainAuthor:    "主要作者",
+		TUILastCommit:    "最近提交",
+		TUIFirstCommit:   "首次提交",
+		TUIBranch:        "分支",
+		TUIActive:        "活跃",
+		TUIBranchSummary: "%d 个分支，%d 次合并",
+		TUINoBranches:    "没有分支数据（低内存模式和监视模式不生成分支结构）",
+		TUIWorkStyle:     "工作风格",
+		TUICommitsPerDay: "每日提交",
+		TUICommitSize:    "平均规模",
+		TUIConsistency:   "一致性",
+		TUIFocus:         "方向",
+		TUILanguages:     "主要语言",
+		TUIAuthorFiles:   "%s 修改的文件",
+		TUIFileAuthors:   "%s 的作者",
+		TUIRecentCommits: "最近的提交",
+		TUIEmpty:         "暂无数据",
+		TUIHelp:          "←/→ 切换  ↑/↓ 选择  s 排序  r 反转  Enter 详情  q 退出",
+		TUIHelpDetail:    "↑/↓ 滚动  s 排序  r 反转  Esc 返回  q 退出",
+
+		AIFactsOverview:      "概览",
+		AIFactsHotspots:      "技术债务热点",
+		AIFactsRefactoring:   "重构信号",
+		AIFactsConcentration: "代码集中度问题",
+		AIFactsTimeline:      "提交趋势",
+		AIFactsDevelopers:    "主要开发者",
+		AIFactsFiles:         "修改最多的文件",
 	},
 	
 	LangEN: {
@@ -122,17 +324,10 @@ var translations = map[Language]*Messages{
 		
 		DayNames:                []string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
 		
-		AIPromptTemplate: `Please analyze the following Git repository statistics and provide insights:
+		AIPromptTemplate: `Please analyze the following key facts about a Git repository and provide insights:
 
-BASIC STATISTICS:
 %s
 
-DETAILED DATA:
-- Total commits: %d
-- Number of contributors: %d
-- Active period: %s to %s
-- Active days: %d
-
 Please provide:
 1. Development pattern analysis
 2. Team collaboration insights
@@ -144,6 +339,122 @@ Focus on actionable insights that can help improve the development process.`,
 
 		AISystemMessage: "You are an expert software development analyst. Analyze git repository data and provide actionable insights.",
 		AIAnalysisTitle: "Intelligent Analysis",
+
+		AIStructuredInstructions: `Reply with a single JSON object using this structure and nothing else:
+{
+  "summary": "overall conclusion (string)",
+  "risks": [{"title": "risk title", "severity": "low|medium|high|critical", "description": "details"}],
+  "recommendations": [{"title": "recommendation title", "description": "what to do", "files": ["related file paths"], "authors": ["related authors"]}],
+  "confidence": a number between 0.0 and 1.0
+}`,
+		AIRepairPrompt: `Your reply did not match the required JSON structure:
+%s
+Reply with the corrected JSON object only.`,
+		AISummaryTitle:         "Summary",
+		AIRisksTitle:           "Risks",
+		AIRecommendationsTitle: "Recommendations",
+		AIFilesLabel:           "Files",
+		AIAuthorsLabel:         "Authors",
+		AIConfidenceLabel:      "Confidence",
+
+		AIMessageScoringPrompt: `Score each of the following commit messages from 0 to 100 for clarity, scope, imperative mood and references (e.g. issue numbers).
+Reply with a single JSON object and nothing else:
+{"scores": [{"hash": "commit hash", "score": a number from 0 to 100, "issues": ["main problems, short"]}]}
+
+%s`,
+
+		AIDeveloperPrompt: `Below are the real metrics and recent commits of one developer in this repository. In 3 to 5 sentences, describe their way of working and their contribution objectively, then give 2 to 4 concrete, actionable growth suggestions. Do not speculate about personal details beyond the metrics.
+Reply with a single JSON object and nothing else:
+{"narrative": "description", "suggestions": ["suggestion"]}
+
+%s`,
+
+		AIChatSystemPrompt: `You are an assistant who knows the history of this git repository. Answer the user's questions about authors, files, technical debt hotspots and commits from the real data returned by the tools; call a tool whenever you need data and never make data up. Keep answers short and cite concrete authors, files and commits.
+
+Repository overview:
+%s`,
+
+		AIChangelogPrompt: `Below are the commits of the "%[2]s" group of version %[1]s. Summarize this group of changes for the release notes in 1 to 3 sentences, written for users, highlighting the most important changes. Do not list the commits one by one and do not invent anything the commits don't say. Reply with the summary text only.
+
+%[3]s`,
+
+		ChangelogTitles: map[string]string{
+			"feat":     "Features",
+			"fix":      "Bug Fixes",
+			"perf":     "Performance",
+			"refactor": "Refactoring",
+			"docs":     "Documentation",
+			"test":     "Tests",
+			"build":    "Build",
+			"ci":       "Continuous Integration",
+			"chore":    "Chores",
+			"style":    "Style",
+			"revert":   "Reverts",
+			"other":    "Other Changes",
+		},
+		ChangelogBreaking:     "⚠ BREAKING CHANGES",
+		ChangelogContributors: "Contributors",
+		ChangelogUnreleased:   "Unreleased",
+		ChangelogEmpty:        "No changes.",
+
+		PrintContents:    "Contents",
+		PrintSummary:     "Summary",
+		PrintActivity:    "Commit Activity",
+		PrintHealth:      "Code Health",
+		PrintHealthScore: "Health score",
+		PrintNoFindings:  "No findings.",
+		PrintAuthor:      "Author",
+		PrintCommits:     "Commits",
+		PrintShare:       "Share",
+		PrintAdditions:   "Added",
+		PrintDeletions:   "Deleted",
+		PrintFile:        "File",
+		PrintChanges:     "Changes",
+		PrintLevel:       "Level",
+		PrintRule:        "Rule",
+		PrintMessage:     "Message",
+		PrintPage:        "Page %d of %d",
+
+		ChartHotspots:  "Technical Debt Hotspots",
+		ChartPunchCard: "Punch Card",
+		ChartCalendar:  "Commit Calendar",
+		ChartLess:      "Less",
+		ChartMore:      "More",
+		MonthNames:     []string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+
+		TUITabOverview:   "Overview",
+		TUITabFiles:      "Files",
+		TUITabBranches:   "Branches",
+		TUITabDevelopers: "Developers",
+		TUIFiles:         "Files",
+		TUIAuthors:       "Authors",
+		TUIMainAuthor:    "Main author",
+		TUILastCommit:    "Last commit",
+		TUIFirstCommit:   "First commit",
+		TUIBranch:        "Branch",
+		TUIActive:        "Active",
+		TUIBranchSummary: "%d branches, %d merges",
+		TUINoBranches:    "No branch data (not collected in low memory and watch mode)",
+		TUIWorkStyle:     "Work style",
+		TUICommitsPerDay: "Commits/day",
+		TUICommitSize:    "Avg. size",
+		TUIConsistency:   "Consistency",
+		TUIFocus:         "Focus",
+		TUILanguages:     "Languages",
+		TUIAuthorFiles:   "Files changed by %s",
+		TUIFileAuthors:   "Authors of %s",
+		TUIRecentCommits: "Recent commits",
+		TUIEmpty:         "No data",
+		TUIHelp:          "←/→ tabs  ↑/↓ select  s sort  r reverse  Enter details  q quit",
+		TUIHelpDetail:    "↑/↓ scroll  s sort  r reverse  Esc back  q quit",
+
+		AIFactsOverview:      "Overview",
+		AIFactsHotspots:      "Technical debt hotspots",
+		AIFactsRefactoring:   "Refactoring signals",
+		AIFactsConcentration: "Code concentration issues",
+		AIFactsTimeline:      "Commit trend",
+		AIFactsDevelopers:    "Main developers",
+		AIFactsFiles:         "Most modified files",
 	},
 }
 
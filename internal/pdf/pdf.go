@@ -0,0 +1,319 @@
+// Package pdf writes simple PDF documents: text, lines and filled
+// rectangles on A4 pages. It needs no fonts or external tools. Latin text uses
+// the standard Helvetica fonts, and all other characters (e.g. Chinese) use
+// the Adobe STSong-Light CJK font that PDF viewers supply themselves, so no
+// font is embedded. Scripts that font lacks, such as Hangul, are drawn as "?".
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image/color"
+	"io"
+	"strings"
+	"unicode/utf16"
+)
+
+// A4 page size in points
+const (
+	PageWidth  = 595.28
+	PageHeight = 841.89
+)
+
+// Document is a PDF document built page by page. Coordinates are in points
+// from the top-left corner of the page; text is positioned by its baseline.
+type Document struct {
+	title   string
+	author  string
+	pages   []*bytes.Buffer
+	current *bytes.Buffer
+}
+
+// New creates an empty document
+func New() *Document {
+	return &Document{}
+}
+
+// SetTitle sets the title shown by PDF viewers
+func (d *Document) SetTitle(title string) {
+	d.title = title
+}
+
+// SetAuthor sets the author of the document information
+func (d *Document) SetAuthor(author string) {
+	d.author = author
+}
+
+// AddPage starts a new page and makes it the current one
+func (d *Document) AddPage() {
+	d.current = &bytes.Buffer{}
+	d.pages = append(d.pages, d.current)
+}
+
+// PageCount returns the number of pages
+func (d *Document) PageCount() int {
+	return len(d.pages)
+}
+
+// SetPage makes the page with index i the current one, e.g. to add page
+// numbers once all pages exist
+func (d *Document) SetPage(i int) {
+	d.current = d.pages[i]
+}
+
+// page returns the current page, adding the first one if needed
+func (d *Document) page() *bytes.Buffer {
+	if d.current == nil {
+		d.AddPage()
+	}
+	return d.current
+}
+
+// Text draws s with its baseline at (x, y)
+func (d *Document) Text(x, y, size float64, bold bool, c color.Color, s string) {
+	runs := splitRuns(s)
+	if len(runs) == 0 {
+		return
+	}
+	p := d.page()
+	fmt.Fprintf(p, "BT %s rg 1 0 0 1 %s %s Tm\n", rgb(c), num(x), num(PageHeight-y))
+	for _, r := range runs {
+		fmt.Fprintf(p, "/%s %s Tf %s Tj\n", r.font(bold), num(size), r.encode())
+	}
+	p.WriteString("ET\n")
+}
+
+// Line draws a line from (x1, y1) to (x2, y2)
+func (d *Document) Line(x1, y1, x2, y2, width float64, c color.Color) {
+	fmt.Fprintf(d.page(), "%s w %s RG %s %s m %s %s l S\n",
+		num(width), rgb(c), num(x1), num(PageHeight-y1), num(x2), num(PageHeight-y2))
+}
+
+// Rect fills the rectangle with the top-left corner (x, y)
+func (d *Document) Rect(x, y, w, h float64, fill color.Color) {
+	fmt.Fprintf(d.page(), "%s rg %s %s %s %s re f\n",
+		rgb(fill), num(x), num(PageHeight-y-h), num(w), num(h))
+}
+
+// Font resource names
+const (
+	fontRegular = "F1"
+	fontBold    = "F2"
+	fontCJK     = "F3"
+)
+
+// run is a part of a string drawn with one font
+type run struct {
+	cjk  bool
+	text []rune
+}
+
+func (r run) font(bold bool) string {
+	switch {
+	case r.cjk:
+		return fontCJK
+	case bold:
+		return fontBold
+	default:
+		return fontRegular
+	}
+}
+
+// encode returns the run as a PDF string: WinAnsi bytes for Helvetica and
+// UCS-2 code units for the UniGB-UCS2-H encoding of the CJK font
+func (r run) encode() string {
+	var b strings.Builder
+	if r.cjk {
+		b.WriteByte('<')
+		for _, c := range r.text {
+			fmt.Fprintf(&b, "%04X", c)
+		}
+		b.WriteByte('>')
+		return b.String()
+	}
+	b.WriteByte('(')
+	for _, c := range r.text {
+		switch c {
+		case '(', ')', '\\':
+			b.WriteByte('\\')
+			b.WriteByte(byte(c))
+		default:
+			if c < 0x80 {
+				b.WriteByte(byte(c))
+			} else {
+				fmt.Fprintf(&b, "\\%03o", c)
+			}
+		}
+	}
+	b.WriteByte(')')
+	return b.String()
+}
+
+// cjkFontRanges are the blocks outside Latin-1 that STSong-Light (Adobe-GB1)
+// has glyphs for: Greek, Cyrillic, punctuation and symbols, kana, bopomofo,
+// CJK ideographs and full-width forms. Hangul, Arabic, Hebrew, Thai,
+// Devanagari and other scripts are missing from the font.
+var cjkFontRanges = []struct{ lo, hi rune }{
+	{0x0391, 0x03C9}, // Greek
+	{0x0401, 0x0451}, // Cyrillic
+	{0x2000, 0x27BF}, // punctuation, letterlike, arrows, math, box drawing, shapes
+	{0x3000, 0x312F}, // CJK punctuation, kana, bopomofo
+	{0x3200, 0x33FF}, // enclosed and compatibility CJK
+	{0x3400, 0x9FFF}, // CJK ideographs
+	{0xF900, 0xFAFF}, // CJK compatibility ideographs
+	{0xFE30, 0xFE4F}, // CJK compatibility forms
+	{0xFF00, 0xFFEF}, // full-width forms
+}
+
+// inCJKFont reports whether STSong-Light can draw c
+func inCJKFont(c rune) bool {
+	for _, r := range cjkFontRanges {
+		if c >= r.lo && c <= r.hi {
+			return true
+		}
+	}
+	return false
+}
+
+// splitRuns splits s into runs of Helvetica and CJK characters. Control
+// characters become spaces, characters outside the Basic Multilingual Plane
+// (e.g. emoji) are dropped, and characters neither font can draw (e.g.
+// Hangul) become "?" so they are not silently left blank.
+func splitRuns(s string) []run {
+	var runs []run
+	for _, c := range s {
+		if c < ' ' {
+			c = ' '
+		}
+		if c > 0xFFFF || (c >= 0x7F && c < 0xA0) {
+			continue
+		}
+		if c > 0xFF && !inCJKFont(c) {
+			c = '?'
+		}
+		cjk := c > 0xFF
+		if n := len(runs); n > 0 && runs[n-1].cjk == cjk {
+			runs[n-1].text = append(runs[n-1].text, c)
+		} else {
+			runs = append(runs, run{cjk: cjk, text: []rune{c}})
+		}
+	}
+	return runs
+}
+
+// helveticaWidths are the widths of the printable ASCII characters in
+// Helvetica, in thousandths of the font size
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278, // space to /
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556, // 0 to ?
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778, // @ to O
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556, // P to _
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556, // ` to o
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584, // p to ~
+}
+
+// TextWidth returns the width of s in points. Bold Latin text is estimated
+// from the regular widths.
+func TextWidth(s string, size float64, bold bool) float64 {
+	total := 0
+	for _, r := range splitRuns(s) {
+		for _, c := range r.text {
+			switch {
+			case r.cjk:
+				total += 1000
+			case c >= ' ' && c <= '~':
+				total += helveticaWidths[c-' ']
+			default:
+				total += 556
+			}
+		}
+	}
+	width := float64(total) * size / 1000
+	if bold {
+		width *= 1.06
+	}
+	return width
+}
+
+// WriteTo writes the document. A document without pages gets one empty page.
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	d.page()
+
+	// Objects are numbered from 1 in the order they are added
+	var objects []string
+	add := func(body string) int {
+		objects = append(objects, body)
+		return len(objects)
+	}
+
+	catalog := add("")
+	pages := add("")
+	info := add(fmt.Sprintf("<< /Title %s /Author %s /Producer (git-log-analyzer) >>", textString(d.title), textString(d.author)))
+	regular := add("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	bold := add("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	descriptor := add("<< /Type /FontDescriptor /FontName /STSong-Light /Flags 6 /FontBBox [-25 -254 1000 880] " +
+		"/ItalicAngle 0 /Ascent 880 /Descent -120 /CapHeight 880 /StemV 93 >>")
+	cidFont := add(fmt.Sprintf("<< /Type /Font /Subtype /CIDFontType0 /BaseFont /STSong-Light "+
+		"/CIDSystemInfo << /Registry (Adobe) /Ordering (GB1) /Supplement 2 >> /FontDescriptor %d 0 R /DW 1000 >>", descriptor))
+	cjk := add(fmt.Sprintf("<< /Type /Font /Subtype /Type0 /BaseFont /STSong-Light /Encoding /UniGB-UCS2-H /DescendantFonts [%d 0 R] >>", cidFont))
+	resources := fmt.Sprintf("<< /Font << /%s %d 0 R /%s %d 0 R /%s %d 0 R >> >>", fontRegular, regular, fontBold, bold, fontCJK, cjk)
+
+	var kids []string
+	for _, content := range d.pages {
+		var compressed bytes.Buffer
+		zw := zlib.NewWriter(&compressed)
+		zw.Write(content.Bytes())
+		zw.Close()
+		stream := add(fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream", compressed.Len(), compressed.String()))
+		page := add(fmt.Sprintf("<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %s %s] /Resources %s /Contents %d 0 R >>",
+			pages, num(PageWidth), num(PageHeight), resources, stream))
+		kids = append(kids, fmt.Sprintf("%d 0 R", page))
+	}
+	objects[catalog-1] = fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pages)
+	objects[pages-1] = fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(kids))
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	offsets := make([]int, len(objects))
+	for i, body := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, body)
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root %d 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, catalog, info, xref)
+
+	n, err := w.Write(buf.Bytes())
+	return int64(n), err
+}
+
+// textString encodes s as a PDF text string in UTF-16BE with a byte order mark
+func textString(s string) string {
+	var b strings.Builder
+	b.WriteString("<FEFF")
+	for _, u := range utf16.Encode([]rune(s)) {
+		fmt.Fprintf(&b, "%04X", u)
+	}
+	b.WriteByte('>')
+	return b.String()
+}
+
+// rgb formats c as the operands of the rg and RG operators
+func rgb(c color.Color) string {
+	r, g, b, _ := c.RGBA()
+	return fmt.Sprintf("%s %s %s", num(float64(r)/0xffff), num(float64(g)/0xffff), num(float64(b)/0xffff))
+}
+
+// num formats a number with at most two decimals
+func num(v float64) string {
+	s := fmt.Sprintf("%.2f", v)
+	s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	if s == "" || s == "-0" {
+		return "0"
+	}
+	return s
+}
@@ -0,0 +1,119 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image/color"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+var black = color.Gray{}
+
+// contents returns the decompressed content streams of a written document
+func contents(t *testing.T, data []byte) []string {
+	t.Helper()
+	var streams []string
+	re := regexp.MustCompile(`(?s)/Length (\d+) /Filter /FlateDecode >>\nstream\n`)
+	for _, m := range re.FindAllSubmatchIndex(data, -1) {
+		n, _ := strconv.Atoi(string(data[m[2]:m[3]]))
+		zr, err := zlib.NewReader(bytes.NewReader(data[m[1] : m[1]+n]))
+		if err != nil {
+			t.Fatalf("Invalid stream: %v", err)
+		}
+		content, err := io.ReadAll(zr)
+		if err != nil {
+			t.Fatalf("Invalid stream: %v", err)
+		}
+		streams = append(streams, string(content))
+	}
+	return streams
+}
+
+func TestDocument(t *testing.T) {
+	doc := New()
+	doc.SetTitle("报告")
+	doc.Text(50, 100, 12, true, black, "Hi (there) 张三")
+	doc.Line(50, 110, 200, 110, 0.5, color.RGBA{R: 255, A: 255})
+	doc.AddPage()
+	doc.Rect(10, 20, 30, 40, color.Gray{Y: 128})
+	doc.SetPage(0)
+	doc.Text(50, 800, 8, false, black, "Page 1 🎉")
+
+	var buf bytes.Buffer
+	if _, err := doc.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo failed: %v", err)
+	}
+	data := buf.Bytes()
+	if !bytes.HasPrefix(data, []byte("%PDF-1.4\n")) || !bytes.HasSuffix(data, []byte("%%EOF\n")) {
+		t.Fatal("Missing the PDF header or trailer")
+	}
+
+	// Every xref entry points at its object
+	start := bytes.LastIndex(data, []byte("startxref\n"))
+	xref, _ := strconv.Atoi(strings.Fields(string(data[start+len("startxref\n"):]))[0])
+	lines := strings.Split(string(data[xref:]), "\n")
+	count, _ := strconv.Atoi(strings.Fields(lines[1])[1])
+	for i := 1; i < count; i++ {
+		offset, _ := strconv.Atoi(strings.Fields(lines[2+i])[0])
+		if want := fmt.Sprintf("%d 0 obj\n", i); !bytes.HasPrefix(data[offset:], []byte(want)) {
+			t.Errorf("xref entry %d does not point at its object", i)
+		}
+	}
+	if !bytes.Contains(data, []byte("/Count 2")) || !bytes.Contains(data, []byte("/Title <FEFF62A5544A>")) {
+		t.Error("Expected two pages and the UTF-16 title")
+	}
+
+	streams := contents(t, data)
+	if len(streams) != 2 {
+		t.Fatalf("Expected 2 content streams, got %d", len(streams))
+	}
+	for _, want := range []string{
+		"/F2 12 Tf (Hi \\(there\\) ) Tj\n/F3 12 Tf <5F204E09> Tj",
+		"1 0 0 1 50 741.89 Tm",
+		"0.5 w 1 0 0 RG 50 731.89 m 200 731.89 l S",
+		"/F1 8 Tf (Page 1 ) Tj",
+	} {
+		if !strings.Contains(streams[0], want) {
+			t.Errorf("First page is missing %q:\n%s", want, streams[0])
+		}
+	}
+	if want := "0.5 0.5 0.5 rg 10 781.89 30 40 re f"; !strings.Contains(streams[1], want) {
+		t.Errorf("Second page is missing %q:\n%s", want, streams[1])
+	}
+}
+
+func TestTextWidth(t *testing.T) {
+	if got := TextWidth("Hi", 10, false); math.Abs(got-9.44) > 1e-9 {
+		t.Errorf("TextWidth(Hi) = %v", got)
+	}
+	if got := TextWidth("张三", 10, false); got != 20 {
+		t.Errorf("TextWidth(张三) = %v", got)
+	}
+	if TextWidth("Hi", 10, true) <= TextWidth("Hi", 10, false) {
+		t.Error("Bold text should be wider")
+	}
+}
+
+func TestText_ScriptsOutsideTheFonts(t *testing.T) {
+	doc := New()
+	// Cyrillic is in STSong-Light, Hangul and Arabic are in neither font
+	doc.Text(50, 100, 10, false, black, "Иван 김민준 علي")
+
+	var buf bytes.Buffer
+	if _, err := doc.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo failed: %v", err)
+	}
+	want := "/F3 10 Tf <041804320430043D> Tj\n/F1 10 Tf ( ??? ???) Tj"
+	if streams := contents(t, buf.Bytes()); !strings.Contains(streams[0], want) {
+		t.Errorf("Expected %q in:\n%s", want, streams[0])
+	}
+	if got := TextWidth("김민준", 10, false); math.Abs(got-3*5.56) > 1e-9 {
+		t.Errorf("Expected Hangul to be measured as question marks, got %v", got)
+	}
+}
@@ -0,0 +1,298 @@
+// Package policy evaluates quality gates against an analysis so a CI
+// pipeline can fail when, for example, the health score drops or a change
+// edits a hotspot without touching tests.
+package policy
+
+import (
+	"fmt"
+	"math"
+	"path"
+	"sort"
+	"strings"
+
+	"git-log-analyzer/internal/analyzer"
+)
+
+// Policies
+const (
+	PolicyHealthScore   = "health-score"
+	PolicyConcentration = "concentration"
+	PolicyHotspotTests  = "hotspot-tests"
+	PolicyBusFactor     = "bus-factor"
+)
+
+// DefaultTestPatterns identify test files: base name globs, and directory
+// names ending in a slash that match any path component
+var DefaultTestPatterns = []string{
+	"*_test.go", "*_test.py", "test_*.py", "*.test.*", "*.spec.*", "*Test.java", "*Tests.cs",
+	"test/", "tests/", "__tests__/", "spec/",
+}
+
+// Config holds the thresholds of the policies. Zero values disable a policy.
+type Config struct {
+	MinHealthScore   float64  // minimum code health score (0-100)
+	MaxConcentration float64  // maximum share of all file changes in a single file, in percent
+	HotspotCount     int      // a change editing one of the top N hotspots must also change a test
+	TestPatterns     []string // test file patterns; DefaultTestPatterns when empty
+	MinBusFactor     int      // minimum number of authors behind half of the file changes
+}
+
+// DefaultConfig requires a health score of 60 and tests with edits of the
+// top 5 hotspots
+func DefaultConfig() Config {
+	return Config{
+		MinHealthScore: 60,
+		HotspotCount:   5,
+		TestPatterns:   DefaultTestPatterns,
+	}
+}
+
+// Status is the outcome of a policy
+type Status string
+
+const (
+	StatusPass Status = "pass"
+	StatusFail Status = "fail"
+	StatusSkip Status = "skip" // disabled, or nothing to evaluate
+)
+
+// Result is the outcome of one policy
+type Result struct {
+	Policy    string   `json:"policy"`
+	Status    Status   `json:"status"`
+	Actual    float64  `json:"actual"`
+	Threshold float64  `json:"threshold"`
+	Message   string   `json:"message"`
+	Details   []string `json:"details,omitempty"`
+}
+
+// Report is the outcome of all policies
+type Report struct {
+	Passed  bool     `json:"passed"`
+	Failed  int      `json:"failed"`
+	Range   string   `json:"range,omitempty"`
+	Results []Result `json:"results"`
+}
+
+// Change is the part of the history under review, e.g. a pull request
+type Change struct {
+	Range   string   // as given, e.g. "origin/main..HEAD"
+	Commits int      // commits in the range
+	Files   []string // files changed by the range's commits
+}
+
+// Evaluate checks every policy of config against stats. The hotspot policy
+// needs the change under review and is skipped when change is nil.
+func Evaluate(config Config, stats *analyzer.Statistics, change *Change) *Report {
+	report := &Report{
+		Results: []Result{
+			checkHealthScore(config, stats),
+			checkConcentration(config, stats),
+			checkHotspotTests(config, stats, change),
+			checkBusFactor(config, stats),
+		},
+	}
+	if change != nil {
+		report.Range = change.Range
+	}
+	for _, r := range report.Results {
+		if r.Status == StatusFail {
+			report.Failed++
+		}
+	}
+	report.Passed = report.Failed == 0
+	return report
+}
+
+// checkHealthScore compares the code health score with the minimum
+func checkHealthScore(config Config, stats *analyzer.Statistics) Result {
+	r := Result{Policy: PolicyHealthScore, Threshold: config.MinHealthScore}
+	if config.MinHealthScore <= 0 {
+		return skip(r, "未启用")
+	}
+	if stats.CodeHealthMetrics == nil {
+		return skip(r, "缺少代码健康数据")
+	}
+	// The score is kept as 0-1 and shown as 0-100 everywhere else
+	r.Actual = math.Round(stats.CodeHealthMetrics.HealthScore*1000) / 10
+	if r.Actual < config.MinHealthScore {
+		return fail(r, fmt.Sprintf("健康分 %.1f 低于 %.1f", r.Actual, config.MinHealthScore))
+	}
+	return pass(r, fmt.Sprintf("健康分 %.1f (最低 %.1f)", r.Actual, config.MinHealthScore))
+}
+
+// checkConcentration compares the largest share of file changes in a single
+// file with the maximum
+func checkConcentration(config Config, stats *analyzer.Statistics) Result {
+	r := Result{Policy: PolicyConcentration, Threshold: config.MaxConcentration}
+	if config.MaxConcentration <= 0 {
+		return skip(r, "未启用")
+	}
+	total := 0
+	for _, changes := range stats.FileStats {
+		total += changes
+	}
+	if total == 0 {
+		return skip(r, "没有文件变更")
+	}
+
+	var over []string
+	top := ""
+	for file, changes := range stats.FileStats {
+		share := float64(changes) / float64(total) * 100
+		if share > r.Actual || (share == r.Actual && file < top) {
+			r.Actual, top = share, file
+		}
+		if share > config.MaxConcentration {
+			over = append(over, fmt.Sprintf("%s: %.1f%%", file, share))
+		}
+	}
+	sort.Strings(over)
+	if len(over) > 0 {
+		r.Details = over
+		return fail(r, fmt.Sprintf("%d 个文件的变更占比超过 %.1f%%", len(over), config.MaxConcentration))
+	}
+	return pass(r, fmt.Sprintf("变更最集中的文件 %s 占 %.1f%% (最多 %.1f%%)", top, r.Actual, config.MaxConcentration))
+}
+
+// checkHotspotTests requires a change that edits one of the top hotspots to
+// also change a test file
+func checkHotspotTests(config Config, stats *analyzer.Statistics, change *Change) Result {
+	r := Result{Policy: PolicyHotspotTests, Threshold: float64(config.HotspotCount)}
+	if config.HotspotCount <= 0 {
+		return skip(r, "未启用")
+	}
+	if change == nil {
+		return skip(r, "未指定待检查的提交范围")
+	}
+	if stats.CodeHealthMetrics == nil || len(stats.CodeHealthMetrics.TechnicalDebtHotspots) == 0 {
+		return skip(r, "没有技术债务热点")
+	}
+
+	hotspots := make(map[string]bool)
+	for i, h := range stats.CodeHealthMetrics.TechnicalDebtHotspots {
+		if i == config.HotspotCount {
+			break
+		}
+		hotspots[h.FilePath] = true
+	}
+	patterns := config.TestPatterns
+	if len(patterns) == 0 {
+		patterns = DefaultTestPatterns
+	}
+
+	var touched, tests []string
+	for _, file := range change.Files {
+		if IsTestFile(file, patterns) {
+			tests = append(tests, file)
+		} else if hotspots[file] {
+			touched = append(touched, file)
+		}
+	}
+	sort.Strings(touched)
+	r.Actual = float64(len(touched))
+	r.Details = touched
+	switch {
+	case len(touched) == 0:
+		return pass(r, fmt.Sprintf("%s 未修改前 %d 个热点文件", change.Range, config.HotspotCount))
+	case len(tests) == 0:
+		return fail(r, fmt.Sprintf("%s 修改了 %d 个热点文件但没有修改测试", change.Range, len(touched)))
+	}
+	return pass(r, fmt.Sprintf("%s 修改了 %d 个热点文件, 同时修改了 %d 个测试文件", change.Range, len(touched), len(tests)))
+}
+
+// checkBusFactor compares the bus factor with the minimum
+func checkBusFactor(config Config, stats *analyzer.Statistics) Result {
+	r := Result{Policy: PolicyBusFactor, Threshold: float64(config.MinBusFactor)}
+	if config.MinBusFactor <= 0 {
+		return skip(r, "未启用")
+	}
+	factor, authors := BusFactor(stats)
+	if factor == 0 {
+		return skip(r, "没有作者数据")
+	}
+	r.Actual = float64(factor)
+	r.Details = authors
+	if factor < config.MinBusFactor {
+		return fail(r, fmt.Sprintf("巴士因子 %d 低于 %d", factor, config.MinBusFactor))
+	}
+	return pass(r, fmt.Sprintf("巴士因子 %d (最低 %d)", factor, config.MinBusFactor))
+}
+
+// BusFactor returns the smallest number of authors who together made more
+// than half of the file changes, along with those authors. Authors without
+// file statistics are weighed by their commits.
+func BusFactor(stats *analyzer.Statistics) (int, []string) {
+	type weight struct {
+		author  string
+		changes int
+	}
+	var weights []weight
+	total := 0
+	for key, a := range stats.AuthorStats {
+		changes := 0
+		for _, n := range a.Files {
+			changes += n
+		}
+		if changes == 0 {
+			changes = a.CommitCount
+		}
+		weights = append(weights, weight{key, changes})
+		total += changes
+	}
+	if total == 0 {
+		return 0, nil
+	}
+	sort.Slice(weights, func(i, j int) bool {
+		if weights[i].changes != weights[j].changes {
+			return weights[i].changes > weights[j].changes
+		}
+		return weights[i].author < weights[j].author
+	})
+
+	var authors []string
+	sum := 0
+	for _, w := range weights {
+		authors = append(authors, w.author)
+		sum += w.changes
+		if sum*2 > total {
+			break
+		}
+	}
+	return len(authors), authors
+}
+
+// IsTestFile reports whether file matches one of the test patterns. A
+// pattern ending in a slash matches a directory anywhere in the path; other
+// patterns are matched against the base name.
+func IsTestFile(file string, patterns []string) bool {
+	file = strings.TrimPrefix(path.Clean("/"+file), "/")
+	base := path.Base(file)
+	for _, p := range patterns {
+		if dir, ok := strings.CutSuffix(p, "/"); ok {
+			if strings.HasPrefix(file, dir+"/") || strings.Contains(file, "/"+dir+"/") {
+				return true
+			}
+			continue
+		}
+		if ok, _ := path.Match(p, base); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func pass(r Result, message string) Result {
+	r.Status, r.Message = StatusPass, message
+	return r
+}
+
+func fail(r Result, message string) Result {
+	r.Status, r.Message = StatusFail, message
+	return r
+}
+
+func skip(r Result, message string) Result {
+	r.Status, r.Message = StatusSkip, message
+	return r
+}
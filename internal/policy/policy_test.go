@@ -0,0 +1,137 @@
+package policy
+
+import (
+	"reflect"
+	"testing"
+
+	"git-log-analyzer/internal/analyzer"
+	"git-log-analyzer/internal/health"
+)
+
+// testStats has three authors, a health score of 55 and two hotspots
+func testStats() *analyzer.Statistics {
+	return &analyzer.Statistics{
+		AuthorStats: map[string]*analyzer.AuthorStat{
+			"Alice <alice@example.com>": {Name: "Alice", CommitCount: 6, Files: map[string]int{"core.go": 5, "core_test.go": 1}},
+			"Bob <bob@example.com>":     {Name: "Bob", CommitCount: 3, Files: map[string]int{"api.go": 3}},
+			"Carol <carol@example.com>": {Name: "Carol", CommitCount: 1, Files: map[string]int{"docs.md": 1}},
+		},
+		FileStats: map[string]int{"core.go": 5, "core_test.go": 1, "api.go": 3, "docs.md": 1},
+		CodeHealthMetrics: &health.CodeHealthMetrics{
+			HealthScore: 0.55,
+			TechnicalDebtHotspots: []health.TechnicalDebtHotspot{
+				{FilePath: "core.go", RiskScore: 0.9},
+				{FilePath: "api.go", RiskScore: 0.5},
+			},
+		},
+	}
+}
+
+func result(t *testing.T, report *Report, policy string) Result {
+	t.Helper()
+	for _, r := range report.Results {
+		if r.Policy == policy {
+			return r
+		}
+	}
+	t.Fatalf("No result for %s", policy)
+	return Result{}
+}
+
+func TestEvaluate_Defaults(t *testing.T) {
+	report := Evaluate(DefaultConfig(), testStats(), nil)
+
+	if report.Passed || report.Failed != 1 {
+		t.Errorf("Expected only the health score to fail, got %+v", report)
+	}
+	if r := result(t, report, PolicyHealthScore); r.Status != StatusFail || r.Actual != 55 || r.Threshold != 60 {
+		t.Errorf("Unexpected health result %+v", r)
+	}
+	if r := result(t, report, PolicyHotspotTests); r.Status != StatusSkip {
+		t.Errorf("Expected the hotspot policy to be skipped without a range, got %+v", r)
+	}
+	for _, p := range []string{PolicyConcentration, PolicyBusFactor} {
+		if r := result(t, report, p); r.Status != StatusSkip {
+			t.Errorf("Expected %s to be disabled, got %+v", p, r)
+		}
+	}
+}
+
+func TestEvaluate_Thresholds(t *testing.T) {
+	config := Config{MinHealthScore: 50, MaxConcentration: 40, MinBusFactor: 2}
+	report := Evaluate(config, testStats(), nil)
+
+	if r := result(t, report, PolicyHealthScore); r.Status != StatusPass {
+		t.Errorf("Expected the health score to pass, got %+v", r)
+	}
+	r := result(t, report, PolicyConcentration)
+	if r.Status != StatusFail || r.Actual != 50 || !reflect.DeepEqual(r.Details, []string{"core.go: 50.0%"}) {
+		t.Errorf("Expected core.go to exceed the concentration, got %+v", r)
+	}
+	// Alice made 6 of 10 file changes
+	if r := result(t, report, PolicyBusFactor); r.Status != StatusFail || r.Actual != 1 {
+		t.Errorf("Expected a bus factor of 1, got %+v", r)
+	}
+	if report.Failed != 2 {
+		t.Errorf("Expected 2 failures, got %d", report.Failed)
+	}
+}
+
+func TestEvaluate_HotspotTests(t *testing.T) {
+	config := Config{HotspotCount: 1}
+	tests := []struct {
+		name   string
+		files  []string
+		status Status
+	}{
+		{"no hotspot", []string{"api.go", "docs.md"}, StatusPass},
+		{"hotspot without tests", []string{"core.go", "docs.md"}, StatusFail},
+		{"hotspot with tests", []string{"core.go", "core_test.go"}, StatusPass},
+		{"hotspot with a test directory", []string{"core.go", "internal/tests/fixture.json"}, StatusPass},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			change := &Change{Range: "main..HEAD", Commits: 1, Files: tt.files}
+			report := Evaluate(config, testStats(), change)
+			if r := result(t, report, PolicyHotspotTests); r.Status != tt.status {
+				t.Errorf("Expected %s, got %+v", tt.status, r)
+			}
+			if report.Range != "main..HEAD" {
+				t.Errorf("Expected the range in the report, got %q", report.Range)
+			}
+		})
+	}
+}
+
+func TestBusFactor(t *testing.T) {
+	stats := testStats()
+	stats.AuthorStats["Bob <bob@example.com>"].Files["api.go"] = 7
+
+	factor, authors := BusFactor(stats)
+	want := []string{"Bob <bob@example.com>", "Alice <alice@example.com>"}
+	if factor != 2 || !reflect.DeepEqual(authors, want) {
+		t.Errorf("Expected Bob and Alice, got %d %v", factor, authors)
+	}
+
+	if factor, _ := BusFactor(&analyzer.Statistics{}); factor != 0 {
+		t.Errorf("Expected 0 without authors, got %d", factor)
+	}
+}
+
+func TestIsTestFile(t *testing.T) {
+	tests := map[string]bool{
+		"internal/policy/policy_test.go": true,
+		"src/app.spec.ts":                true,
+		"tests/test_api.py":              true,
+		"web/__tests__/App.jsx":          true,
+		"src/main/FooTest.java":          true,
+		"internal/policy/policy.go":      false,
+		"contest/main.go":                false,
+		"latest.md":                      false,
+	}
+	for file, want := range tests {
+		if got := IsTestFile(file, DefaultTestPatterns); got != want {
+			t.Errorf("IsTestFile(%q) = %v, want %v", file, got, want)
+		}
+	}
+}
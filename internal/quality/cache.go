@@ -0,0 +1,109 @@
+package quality
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// CachedScore is a stored AI score of a commit message
+type CachedScore struct {
+	Score  float64  `json:"score"`
+	Issues []string `json:"issues,omitempty"`
+}
+
+// ScoreCache keeps AI message scores on disk, keyed by commit hash and model,
+// so messages are only sent to the model once
+type ScoreCache struct {
+	// Refresh skips lookups so every message is scored again; the new scores
+	// still replace the stored ones
+	Refresh bool
+
+	path    string
+	mu      sync.Mutex
+	entries map[string]CachedScore
+	dirty   bool
+}
+
+// DefaultScoreCachePath returns the cache file in the user's cache directory
+func DefaultScoreCachePath() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "git-log-analyzer", "message-scores.json")
+}
+
+// OpenScoreCache loads the cache at path. A missing file yields an empty
+// cache; an empty path yields a cache that is never saved.
+func OpenScoreCache(path string) (*ScoreCache, error) {
+	cache := &ScoreCache{path: path, entries: make(map[string]CachedScore)}
+	if path == "" {
+		return cache, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cache, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read message score cache: %v", err)
+	}
+	if err := json.Unmarshal(data, &cache.entries); err != nil {
+		return nil, fmt.Errorf("failed to parse message score cache %s: %v", path, err)
+	}
+	return cache, nil
+}
+
+func cacheKey(hash, model string) string {
+	return model + ":" + hash
+}
+
+// Get returns the cached score of a commit for a model
+func (c *ScoreCache) Get(hash, model string) (CachedScore, bool) {
+	if c.Refresh {
+		return CachedScore{}, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	score, ok := c.entries[cacheKey(hash, model)]
+	return score, ok
+}
+
+// Put stores the score of a commit for a model
+func (c *ScoreCache) Put(hash, model string, score CachedScore) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[cacheKey(hash, model)] = score
+	c.dirty = true
+}
+
+// Save writes the cache back to disk if it changed
+func (c *ScoreCache) Save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.path == "" || !c.dirty {
+		return nil
+	}
+
+	data, err := json.Marshal(c.entries)
+	if err != nil {
+		return fmt.Errorf("failed to encode message score cache: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %v", err)
+	}
+
+	// Write then rename, so an interrupted run never leaves a truncated cache
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write message score cache: %v", err)
+	}
+	if err := os.Rename(tmp, c.path); err != nil {
+		return fmt.Errorf("failed to write message score cache: %v", err)
+	}
+	c.dirty = false
+	return nil
+}
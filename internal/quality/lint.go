@@ -0,0 +1,204 @@
+package quality
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"git-log-analyzer/internal/conventional"
+	"git-log-analyzer/internal/git"
+)
+
+// Lint rules
+const (
+	RuleConventional   = "conventional"
+	RuleType           = "type"
+	RulePattern        = "pattern"
+	RuleSubjectLength  = "subject-length"
+	RuleBodyLineLength = "body-line-length"
+	RuleTrailer        = "trailer"
+)
+
+// DefaultLintTypes are the Conventional Commits types accepted by default
+var DefaultLintTypes = []string{"feat", "fix", "docs", "style", "refactor", "perf", "test", "build", "ci", "chore", "revert"}
+
+// LintConfig is the commit message convention checked by a Linter. Zero
+// values disable a check.
+type LintConfig struct {
+	Conventional      bool     // require a "type(scope): description" header
+	Types             []string // types accepted with Conventional; empty accepts any
+	Pattern           string   // regular expression the subject must match
+	MaxSubjectLength  int      // in display columns; wide (CJK) runes count as two
+	MaxBodyLineLength int      // lines without whitespace, such as URLs, are exempt
+	RequiredTrailers  []string // e.g. "Signed-off-by"
+	IncludeMerges     bool     // merge commits are skipped unless set
+}
+
+// DefaultLintConfig returns the Conventional Commits convention with 72
+// column subjects and body lines
+func DefaultLintConfig() LintConfig {
+	return LintConfig{
+		Conventional:      true,
+		Types:             DefaultLintTypes,
+		MaxSubjectLength:  72,
+		MaxBodyLineLength: 72,
+	}
+}
+
+// Violation is a broken rule of a commit message
+type Violation struct {
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// LintResult is a commit that broke at least one rule
+type LintResult struct {
+	Hash       string      `json:"hash"`
+	Author     string      `json:"author"` // "Name <email>", as in Statistics.AuthorStats
+	Subject    string      `json:"subject"`
+	Violations []Violation `json:"violations"`
+}
+
+// AuthorCompliance is the share of an author's commits that follow the convention
+type AuthorCompliance struct {
+	Author  string  `json:"author"`
+	Commits int     `json:"commits"`
+	Passed  int     `json:"passed"`
+	Rate    float64 `json:"rate"` // percent
+}
+
+// LintReport is the outcome of linting a range of commits
+type LintReport struct {
+	Commits    int                `json:"commits"`
+	Passed     int                `json:"passed"`
+	Rate       float64            `json:"rate"` // percent; 100 without commits
+	Authors    []AuthorCompliance `json:"authors"`
+	Failures   []LintResult       `json:"failures"`
+	RuleCounts map[string]int     `json:"ruleCounts"`
+}
+
+// Linter checks commit messages against a LintConfig
+type Linter struct {
+	config  LintConfig
+	pattern *regexp.Regexp
+	types   map[string]bool
+}
+
+// NewLinter creates a linter, validating the custom pattern
+func NewLinter(config LintConfig) (*Linter, error) {
+	l := &Linter{config: config}
+	if config.Pattern != "" {
+		pattern, err := regexp.Compile(config.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid lint pattern: %v", err)
+		}
+		l.pattern = pattern
+	}
+	if len(config.Types) > 0 {
+		l.types = make(map[string]bool)
+		for _, t := range config.Types {
+			l.types[strings.ToLower(strings.TrimSpace(t))] = true
+		}
+	}
+	return l, nil
+}
+
+// Check returns the rules a commit message breaks
+func (l *Linter) Check(subject, body string) []Violation {
+	var violations []Violation
+	add := func(rule, format string, args ...any) {
+		violations = append(violations, Violation{Rule: rule, Message: fmt.Sprintf(format, args...)})
+	}
+
+	parsed := conventional.Parse(subject, body)
+	if l.config.Conventional {
+		if !parsed.Conventional() {
+			add(RuleConventional, "标题不符合 Conventional Commits 格式 type(scope): description")
+		} else if l.types != nil && !l.types[parsed.Type] {
+			add(RuleType, "不允许的类型 %q (允许: %s)", parsed.Type, strings.Join(l.config.Types, ", "))
+		}
+	}
+
+	if l.pattern != nil && !l.pattern.MatchString(subject) {
+		add(RulePattern, "标题不匹配规则 %s", l.pattern.String())
+	}
+
+	if max := l.config.MaxSubjectLength; max > 0 {
+		if width := displayWidth(strings.TrimSpace(subject)); width > max {
+			add(RuleSubjectLength, "标题长度 %d 超过 %d", width, max)
+		}
+	}
+
+	if max := l.config.MaxBodyLineLength; max > 0 {
+		for i, line := range strings.Split(body, "\n") {
+			line = strings.TrimRight(line, " \r")
+			if width := displayWidth(line); width > max && strings.ContainsAny(strings.TrimSpace(line), " \t") {
+				add(RuleBodyLineLength, "正文第 %d 行长度 %d 超过 %d", i+1, width, max)
+			}
+		}
+	}
+
+	for _, key := range l.config.RequiredTrailers {
+		if _, ok := parsed.Trailer(key); !ok {
+			add(RuleTrailer, "缺少 %s 尾注", key)
+		}
+	}
+	return violations
+}
+
+// Lint checks the commits and aggregates compliance per author. Failures
+// keep the order of commits.
+func (l *Linter) Lint(commits []git.GitCommit) *LintReport {
+	report := &LintReport{Authors: []AuthorCompliance{}, Failures: []LintResult{}, RuleCounts: make(map[string]int)}
+	authors := make(map[string]*AuthorCompliance)
+	var order []string
+
+	for _, c := range commits {
+		if len(c.Parents) > 1 && !l.config.IncludeMerges {
+			continue
+		}
+		author := fmt.Sprintf("%s <%s>", c.Author, c.Email)
+		a, ok := authors[author]
+		if !ok {
+			a = &AuthorCompliance{Author: author}
+			authors[author] = a
+			order = append(order, author)
+		}
+
+		report.Commits++
+		a.Commits++
+		violations := l.Check(c.Subject, c.Body)
+		if len(violations) == 0 {
+			report.Passed++
+			a.Passed++
+			continue
+		}
+		report.Failures = append(report.Failures, LintResult{Hash: c.Hash, Author: author, Subject: c.Subject, Violations: violations})
+		for _, v := range violations {
+			report.RuleCounts[v.Rule]++
+		}
+	}
+
+	report.Rate = complianceRate(report.Passed, report.Commits)
+	for _, author := range order {
+		a := authors[author]
+		a.Rate = complianceRate(a.Passed, a.Commits)
+		report.Authors = append(report.Authors, *a)
+	}
+	// Least compliant authors first
+	sort.SliceStable(report.Authors, func(i, j int) bool {
+		if report.Authors[i].Rate != report.Authors[j].Rate {
+			return report.Authors[i].Rate < report.Authors[j].Rate
+		}
+		return report.Authors[i].Commits > report.Authors[j].Commits
+	})
+	return report
+}
+
+func complianceRate(passed, total int) float64 {
+	if total == 0 {
+		return 100
+	}
+	return float64(passed) / float64(total) * 100
+}
@@ -0,0 +1,96 @@
+package quality
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"git-log-analyzer/internal/git"
+)
+
+// rules returns the rules of the violations
+func rules(violations []Violation) []string {
+	var names []string
+	for _, v := range violations {
+		names = append(names, v.Rule)
+	}
+	return names
+}
+
+func TestLinterCheck(t *testing.T) {
+	config := DefaultLintConfig()
+	config.RequiredTrailers = []string{"Signed-off-by"}
+	linter, err := NewLinter(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signed := "\n\nSigned-off-by: Alice <alice@example.com>"
+	long := strings.Repeat("word ", 20)
+	tests := []struct {
+		subject, body string
+		want          []string
+	}{
+		{"feat(api): add search", "Explain why." + signed, nil},
+		{"Add search", signed, []string{RuleConventional}},
+		{"wip: half done", signed, []string{RuleType}},
+		{"fix: " + strings.Repeat("x", 70), signed, []string{RuleSubjectLength}},
+		{"docs: 说明" + strings.Repeat("文", 34), signed, []string{RuleSubjectLength}},
+		{"fix: wrap body", long + "\nhttps://example.com/" + strings.Repeat("a", 80) + signed, []string{RuleBodyLineLength}},
+		{"fix: unsigned", "", []string{RuleTrailer}},
+	}
+	for _, tt := range tests {
+		if got := rules(linter.Check(tt.subject, tt.body)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Check(%q) = %v, want %v", tt.subject, got, tt.want)
+		}
+	}
+}
+
+func TestLinterCheck_Pattern(t *testing.T) {
+	linter, err := NewLinter(LintConfig{Pattern: `^[A-Z]+-\d+ `})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := linter.Check("PAY-12 Retry charges", ""); len(v) != 0 {
+		t.Errorf("Expected no violations, got %v", v)
+	}
+	if got := rules(linter.Check("Retry charges", "")); !reflect.DeepEqual(got, []string{RulePattern}) {
+		t.Errorf("Expected a pattern violation, got %v", got)
+	}
+
+	if _, err := NewLinter(LintConfig{Pattern: "("}); err == nil {
+		t.Error("Expected an error for an invalid pattern")
+	}
+}
+
+func TestLinterLint(t *testing.T) {
+	linter, err := NewLinter(DefaultLintConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := linter.Lint([]git.GitCommit{
+		{Hash: "e", Author: "Alice", Email: "alice@example.com", Subject: "Merge branch 'x'", Parents: []string{"c", "d"}},
+		{Hash: "d", Author: "Bob", Email: "bob@example.com", Subject: "Update stuff", Parents: []string{"a"}},
+		{Hash: "c", Author: "Alice", Email: "alice@example.com", Subject: "fix: handle empty input", Parents: []string{"b"}},
+		{Hash: "b", Author: "Bob", Email: "bob@example.com", Subject: "feat: add search", Parents: []string{"a"}},
+		{Hash: "a", Author: "Alice", Email: "alice@example.com", Subject: "chore: initial commit"},
+	})
+
+	if report.Commits != 4 || report.Passed != 3 || report.Rate != 75 {
+		t.Errorf("Expected 3 of 4 commits to pass, got %d of %d (%.1f%%)", report.Passed, report.Commits, report.Rate)
+	}
+	want := []AuthorCompliance{
+		{Author: "Bob <bob@example.com>", Commits: 2, Passed: 1, Rate: 50},
+		{Author: "Alice <alice@example.com>", Commits: 2, Passed: 2, Rate: 100},
+	}
+	if !reflect.DeepEqual(report.Authors, want) {
+		t.Errorf("Expected authors %+v, got %+v", want, report.Authors)
+	}
+	if len(report.Failures) != 1 || report.Failures[0].Hash != "d" || report.RuleCounts[RuleConventional] != 1 {
+		t.Errorf("Unexpected failures %+v, counts %v", report.Failures, report.RuleCounts)
+	}
+
+	if empty := linter.Lint(nil); empty.Rate != 100 || empty.Commits != 0 {
+		t.Errorf("Expected an empty range to be compliant, got %+v", empty)
+	}
+}
@@ -0,0 +1,273 @@
+// Package quality scores commit messages for clarity, scope, imperative mood
+// and references.
+package quality
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"git-log-analyzer/internal/git"
+)
+
+// Score sources
+const (
+	SourceHeuristic = "heuristic"
+	SourceAI        = "ai"
+)
+
+// maxWorstMessages is the number of lowest-scoring messages reported
+const maxWorstMessages = 10
+
+// MessageScore is the quality score of a single commit message
+type MessageScore struct {
+	Hash    string    `json:"hash"`
+	Author  string    `json:"author"` // "Name <email>", as in Statistics.AuthorStats
+	Date    time.Time `json:"date"`
+	Subject string    `json:"subject"`
+	Body    string    `json:"-"`
+	Score   float64   `json:"score"` // 0-100
+	Issues  []string  `json:"issues,omitempty"`
+	Source  string    `json:"source"`
+}
+
+// MessageQuality aggregates the scores of all scored commit messages
+type MessageQuality struct {
+	Messages     []MessageScore     `json:"-"`
+	Average      float64            `json:"average"`
+	AuthorScores map[string]float64 `json:"authorScores"`
+	Worst        []MessageScore     `json:"worst"`
+}
+
+// MessageScorer scores commit messages as they are added
+type MessageScorer struct {
+	messages []MessageScore
+}
+
+// NewMessageScorer creates an empty scorer
+func NewMessageScorer() *MessageScorer {
+	return &MessageScorer{}
+}
+
+// AddCommit scores the message of a commit. Merge commits are skipped since
+// their messages are generated by git.
+func (s *MessageScorer) AddCommit(commit *git.GitCommit) {
+	if len(commit.Parents) > 1 {
+		return
+	}
+
+	score, issues := ScoreMessage(commit.Subject, commit.Body)
+	s.messages = append(s.messages, MessageScore{
+		Hash:    commit.Hash,
+		Author:  fmt.Sprintf("%s <%s>", commit.Author, commit.Email),
+		Date:    commit.Date,
+		Subject: commit.Subject,
+		Body:    commit.Body,
+		Score:   score,
+		Issues:  issues,
+		Source:  SourceHeuristic,
+	})
+}
+
+// Result returns the aggregated scores
+func (s *MessageScorer) Result() *MessageQuality {
+	mq := &MessageQuality{Messages: s.messages}
+	mq.Recalculate()
+	return mq
+}
+
+// Recent returns the limit most recent messages, newest first; all of them
+// when limit is not positive. Messages are added in the order of the history
+// walk, which is not always newest first, so they are ordered by date.
+func (mq *MessageQuality) Recent(limit int) []*MessageScore {
+	recent := make([]*MessageScore, len(mq.Messages))
+	for i := range mq.Messages {
+		recent[i] = &mq.Messages[i]
+	}
+	sort.SliceStable(recent, func(i, j int) bool {
+		return recent[i].Date.After(recent[j].Date)
+	})
+	if limit > 0 && len(recent) > limit {
+		recent = recent[:limit]
+	}
+	return recent
+}
+
+// Recalculate refreshes the averages and the worst messages after scores
+// have changed
+func (mq *MessageQuality) Recalculate() {
+	mq.Average = 0
+	mq.AuthorScores = make(map[string]float64)
+	mq.Worst = nil
+	if len(mq.Messages) == 0 {
+		return
+	}
+
+	counts := make(map[string]int)
+	total := 0.0
+	for _, m := range mq.Messages {
+		total += m.Score
+		mq.AuthorScores[m.Author] += m.Score
+		counts[m.Author]++
+	}
+	mq.Average = total / float64(len(mq.Messages))
+	for author, count := range counts {
+		mq.AuthorScores[author] /= float64(count)
+	}
+
+	worst := append([]MessageScore(nil), mq.Messages...)
+	sort.SliceStable(worst, func(i, j int) bool {
+		return worst[i].Score < worst[j].Score
+	})
+	if len(worst) > maxWorstMessages {
+		worst = worst[:maxWorstMessages]
+	}
+	mq.Worst = worst
+}
+
+var (
+	// scopePrefix matches "type(scope)!: ", "area: " and "pkg/sub: " prefixes
+	scopePrefix = regexp.MustCompile(`^[\w./-]+(\([^)]*\))?!?:\s*`)
+	// references matches issue and PR numbers, ticket keys and links
+	references = regexp.MustCompile(`#\d+|\b[A-Z][A-Z0-9]+-\d+\b|https?://`)
+)
+
+// vagueWords are words that say nothing about a change on their own
+var vagueWords = map[string]bool{
+	"fix": true, "fixes": true, "fixed": true, "bug": true, "bugs": true,
+	"update": true, "updates": true, "updated": true, "change": true, "changes": true, "changed": true,
+	"wip": true, "tmp": true, "temp": true, "misc": true, "minor": true, "small": true,
+	"stuff": true, "things": true, "some": true, "various": true, "more": true,
+	"code": true, "file": true, "files": true, "commit": true, "test": true, "cleanup": true,
+}
+
+// commonVerbs are recognized in their third person form ("adds", "fixes")
+var commonVerbs = map[string]bool{
+	"add": true, "fix": true, "update": true, "remove": true, "change": true,
+	"implement": true, "improve": true, "refactor": true, "move": true, "rename": true,
+	"use": true, "make": true, "create": true, "delete": true, "bump": true,
+	"support": true, "handle": true, "allow": true, "clean": true, "merge": true,
+}
+
+// ScoreMessage scores a commit message from 0 to 100 and lists its problems:
+// clarity of the subject (40), imperative mood (25), scope via a prefix or a
+// body (20) and references to issues or tickets (15)
+func ScoreMessage(subject, body string) (float64, []string) {
+	subject = strings.TrimSpace(subject)
+	body = strings.TrimSpace(body)
+	if subject == "" {
+		return 0, []string{"提交信息为空"}
+	}
+
+	var issues []string
+	score := 0.0
+
+	prefix := scopePrefix.FindString(subject)
+	rest := strings.TrimSpace(subject[len(prefix):])
+	words := strings.Fields(strings.ToLower(rest))
+
+	// Clarity
+	switch width := displayWidth(subject); {
+	case isVague(words):
+		score += 10
+		issues = append(issues, "描述过于笼统")
+	case width < 10:
+		score += 20
+		issues = append(issues, "标题过短")
+	case width > 72:
+		score += 30
+		issues = append(issues, "标题过长(超过72字符)")
+	default:
+		score += 40
+	}
+
+	// Imperative mood
+	mood := 25.0
+	if len(words) > 0 && isLatinWord(words[0]) {
+		first := strings.TrimRight(words[0], ".,:;!")
+		switch {
+		case isPastTense(first):
+			mood = 5
+		case strings.HasSuffix(first, "ing") && len(first) > 4 && first != "bring" && first != "string":
+			mood = 10
+		case isThirdPerson(first):
+			mood = 15
+		}
+	}
+	if mood < 25 {
+		issues = append(issues, "未使用祈使语气")
+	}
+	score += mood
+
+	// Scope
+	hasBody := displayWidth(body) >= 10
+	if prefix != "" {
+		score += 10
+	}
+	if hasBody {
+		score += 10
+	}
+	if prefix == "" && !hasBody {
+		issues = append(issues, "缺少范围前缀或正文说明")
+	}
+
+	// References
+	if references.MatchString(subject) || references.MatchString(body) {
+		score += 15
+	}
+
+	return score, issues
+}
+
+// isVague reports whether every word of the description is a vague word
+func isVague(words []string) bool {
+	if len(words) == 0 {
+		return true
+	}
+	for _, w := range words {
+		if !vagueWords[strings.Trim(w, ".,:;!")] {
+			return false
+		}
+	}
+	return true
+}
+
+func isPastTense(word string) bool {
+	switch word {
+	case "need", "embed", "feed", "speed", "seed", "proceed", "succeed", "exceed":
+		return false
+	}
+	return len(word) > 3 && strings.HasSuffix(word, "ed")
+}
+
+func isThirdPerson(word string) bool {
+	if strings.HasSuffix(word, "es") && commonVerbs[strings.TrimSuffix(word, "es")] {
+		return true
+	}
+	return strings.HasSuffix(word, "s") && commonVerbs[strings.TrimSuffix(word, "s")]
+}
+
+func isLatinWord(word string) bool {
+	for _, r := range word {
+		if r > unicode.MaxASCII || !unicode.IsLetter(r) && !strings.ContainsRune(".,:;!-'", r) {
+			return false
+		}
+	}
+	return true
+}
+
+// displayWidth counts wide (CJK) runes as two columns
+func displayWidth(s string) int {
+	width := 0
+	for _, r := range s {
+		if r > unicode.MaxASCII && unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) {
+			width += 2
+		} else {
+			width++
+		}
+	}
+	return width
+}
@@ -0,0 +1,103 @@
+package quality
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"git-log-analyzer/internal/git"
+)
+
+func TestScoreMessage(t *testing.T) {
+	tests := []struct {
+		subject string
+		body    string
+		score   float64
+		issues  []string
+	}{
+		{"feat(api): add streaming ingest", "Stream commits instead of loading them all.\n\nFixes #12", 100, nil},
+		{"Add streaming ingest", "", 65, []string{"缺少范围前缀或正文说明"}},
+		{"Added streaming ingest", "", 45, []string{"未使用祈使语气", "缺少范围前缀或正文说明"}},
+		{"Adding streaming ingest", "", 50, []string{"未使用祈使语气", "缺少范围前缀或正文说明"}},
+		{"Adds streaming ingest for JIRA-42", "", 70, []string{"未使用祈使语气", "缺少范围前缀或正文说明"}},
+		{"fix", "", 35, []string{"描述过于笼统", "缺少范围前缀或正文说明"}},
+		{"updated stuff", "", 15, []string{"描述过于笼统", "未使用祈使语气", "缺少范围前缀或正文说明"}},
+		{"ui: tidy", "", 55, []string{"标题过短"}},
+		{"修复并发写入导致的数据丢失", "", 65, []string{"缺少范围前缀或正文说明"}},
+		{"Need a retry when the remote is slow", "", 65, []string{"缺少范围前缀或正文说明"}},
+		{"Rewrite the commit processing pipeline so that workers share a bounded queue and stream results", "", 55, []string{"标题过长(超过72字符)", "缺少范围前缀或正文说明"}},
+		{"   ", "", 0, []string{"提交信息为空"}},
+	}
+
+	for _, tt := range tests {
+		score, issues := ScoreMessage(tt.subject, tt.body)
+		if score != tt.score {
+			t.Errorf("ScoreMessage(%q) = %.0f, want %.0f", tt.subject, score, tt.score)
+		}
+		if !reflect.DeepEqual(issues, tt.issues) {
+			t.Errorf("ScoreMessage(%q) issues = %v, want %v", tt.subject, issues, tt.issues)
+		}
+	}
+}
+
+func TestMessageScorer(t *testing.T) {
+	scorer := NewMessageScorer()
+	commits := []git.GitCommit{
+		{Hash: "a1", Author: "Alice", Email: "alice@example.com", Subject: "feat: add login page", Body: "Closes #3 with the new form."},
+		{Hash: "b1", Author: "Bob", Email: "bob@example.com", Subject: "wip"},
+		{Hash: "a2", Author: "Alice", Email: "alice@example.com", Subject: "Fixed typo in login form"},
+		{Hash: "m1", Author: "Bob", Email: "bob@example.com", Subject: "Merge branch 'login'", Parents: []string{"a2", "b1"}},
+	}
+	for i := range commits {
+		scorer.AddCommit(&commits[i])
+	}
+
+	mq := scorer.Result()
+	if len(mq.Messages) != 3 {
+		t.Fatalf("Expected merge commits to be skipped, got %d messages", len(mq.Messages))
+	}
+	// 100, 35 and 45
+	if mq.Average != 60 {
+		t.Errorf("Expected average 60, got %.2f", mq.Average)
+	}
+	if got := mq.AuthorScores["Alice <alice@example.com>"]; got != 72.5 {
+		t.Errorf("Expected Alice to average 72.5, got %.2f", got)
+	}
+	if mq.Worst[0].Hash != "b1" || mq.Worst[1].Hash != "a2" || mq.Worst[0].Source != SourceHeuristic {
+		t.Errorf("Expected the worst messages in ascending order, got %+v", mq.Worst)
+	}
+
+	mq.Messages[1].Score = 90
+	mq.Recalculate()
+	if mq.Worst[0].Hash != "a2" || mq.AuthorScores["Bob <bob@example.com>"] != 90 {
+		t.Errorf("Recalculate did not pick up the new score: %+v", mq)
+	}
+}
+
+func TestScoreCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache", "scores.json")
+
+	cache, err := OpenScoreCache(path)
+	if err != nil {
+		t.Fatalf("OpenScoreCache failed: %v", err)
+	}
+	if _, ok := cache.Get("abc", "m"); ok {
+		t.Fatal("Expected an empty cache")
+	}
+	cache.Put("abc", "m", CachedScore{Score: 80, Issues: []string{"too long"}})
+	if err := cache.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	reopened, err := OpenScoreCache(path)
+	if err != nil {
+		t.Fatalf("OpenScoreCache failed: %v", err)
+	}
+	got, ok := reopened.Get("abc", "m")
+	if !ok || got.Score != 80 || !reflect.DeepEqual(got.Issues, []string{"too long"}) {
+		t.Errorf("Expected the saved score, got %+v, %v", got, ok)
+	}
+	if _, ok := reopened.Get("abc", "other-model"); ok {
+		t.Error("Scores should be cached per model")
+	}
+}
@@ -0,0 +1,341 @@
+package report
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"git-log-analyzer/internal/analyzer"
+	"git-log-analyzer/internal/developer"
+)
+
+// CSVReportGenerator writes every tabular dataset of an analysis as a CSV
+// file, for spreadsheets. Column headers are fixed, and a dataset without
+// rows (e.g. branches in low-memory mode) still gets its header.
+type CSVReportGenerator struct {
+	outputDir string
+}
+
+// NewCSVReportGenerator creates a CSV report generator writing to outputDir
+func NewCSVReportGenerator(outputDir string) *CSVReportGenerator {
+	return &CSVReportGenerator{outputDir: outputDir}
+}
+
+// csvTable is one dataset: its file name, header and rows
+type csvTable struct {
+	name   string
+	header []string
+	rows   [][]string
+}
+
+// GenerateReport writes the CSV files of stats and the developer profiles
+// and returns their paths
+func (g *CSVReportGenerator) GenerateReport(ctx context.Context, stats *analyzer.Statistics, profiles []*developer.DeveloperProfile) ([]string, error) {
+	if err := os.MkdirAll(g.outputDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %v", err)
+	}
+
+	var paths []string
+	for _, table := range csvTables(stats, profiles) {
+		if err := ctx.Err(); err != nil {
+			return paths, err
+		}
+		path := filepath.Join(g.outputDir, table.name)
+		if err := writeCSV(path, table); err != nil {
+			return paths, err
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// utf8BOM lets Excel detect UTF-8, so CJK names and paths are not garbled
+const utf8BOM = "\ufeff"
+
+// writeCSV writes a table to path, starting with a UTF-8 byte order mark
+func writeCSV(path string, table csvTable) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %v", path, err)
+	}
+	if _, err := f.WriteString(utf8BOM); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write %s: %v", path, err)
+	}
+	w := csv.NewWriter(f)
+	w.Write(table.header)
+	for _, row := range table.rows {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			cells[i] = csvCell(cell)
+		}
+		w.Write(cells)
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write %s: %v", path, err)
+	}
+	return f.Close()
+}
+
+// csvCell neutralizes a cell a spreadsheet would evaluate as a formula, such
+// as an author named "=HYPERLINK(...)", by prefixing it with a quote.
+// Numbers, including negative ones, are left alone.
+func csvCell(cell string) string {
+	if cell == "" || !strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+		return cell
+	}
+	if _, err := strconv.ParseFloat(cell, 64); err == nil {
+		return cell
+	}
+	return "'" + cell
+}
+
+// csvTables builds every dataset in file order
+func csvTables(stats *analyzer.Statistics, profiles []*developer.DeveloperProfile) []csvTable {
+	return []csvTable{
+		authorsTable(stats),
+		filesTable(stats),
+		commitFrequencyTable(stats),
+		hourlyPatternTable(stats),
+		dailyPatternTable(stats),
+		hotspotsTable(stats),
+		stabilityTable(stats),
+		refactoringSignalsTable(stats),
+		concentrationTable(stats),
+		branchesTable(stats),
+		mergesTable(stats),
+		developersTable(profiles),
+	}
+}
+
+func authorsTable(stats *analyzer.Statistics) csvTable {
+	t := csvTable{
+		name:   "authors.csv",
+		header: []string{"name", "email", "commits", "additions", "deletions", "files", "first_commit", "last_commit"},
+	}
+	authors := make([]*analyzer.AuthorStat, 0, len(stats.AuthorStats))
+	for _, a := range stats.AuthorStats {
+		authors = append(authors, a)
+	}
+	sort.Slice(authors, func(i, j int) bool {
+		if authors[i].CommitCount != authors[j].CommitCount {
+			return authors[i].CommitCount > authors[j].CommitCount
+		}
+		if authors[i].Name != authors[j].Name {
+			return authors[i].Name < authors[j].Name
+		}
+		return authors[i].Email < authors[j].Email
+	})
+	for _, a := range authors {
+		t.rows = append(t.rows, []string{
+			a.Name, a.Email, csvInt(a.CommitCount), csvInt(a.Additions), csvInt(a.Deletions),
+			csvInt(len(a.Files)), csvTime(a.FirstCommit), csvTime(a.LastCommit),
+		})
+	}
+	return t
+}
+
+func filesTable(stats *analyzer.Statistics) csvTable {
+	t := csvTable{name: "files.csv", header: []string{"path", "changes"}}
+	paths := make([]string, 0, len(stats.FileStats))
+	for path := range stats.FileStats {
+		paths = append(paths, path)
+	}
+	sort.Slice(paths, func(i, j int) bool {
+		if stats.FileStats[paths[i]] != stats.FileStats[paths[j]] {
+			return stats.FileStats[paths[i]] > stats.FileStats[paths[j]]
+		}
+		return paths[i] < paths[j]
+	})
+	for _, path := range paths {
+		t.rows = append(t.rows, []string{path, csvInt(stats.FileStats[path])})
+	}
+	return t
+}
+
+func commitFrequencyTable(stats *analyzer.Statistics) csvTable {
+	t := csvTable{name: "commit_frequency.csv", header: []string{"date", "commits"}}
+	dates := make([]string, 0, len(stats.CommitFrequency))
+	for date := range stats.CommitFrequency {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+	for _, date := range dates {
+		t.rows = append(t.rows, []string{date, csvInt(stats.CommitFrequency[date])})
+	}
+	return t
+}
+
+func hourlyPatternTable(stats *analyzer.Statistics) csvTable {
+	t := csvTable{name: "hourly_pattern.csv", header: []string{"hour", "commits"}}
+	for hour := 0; hour < 24; hour++ {
+		t.rows = append(t.rows, []string{csvInt(hour), csvInt(stats.TimeStats.HourlyPattern[hour])})
+	}
+	return t
+}
+
+func dailyPatternTable(stats *analyzer.Statistics) csvTable {
+	t := csvTable{name: "daily_pattern.csv", header: []string{"weekday", "commits"}}
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		t.rows = append(t.rows, []string{day.String(), csvInt(stats.TimeStats.DailyPattern[day])})
+	}
+	return t
+}
+
+func hotspotsTable(stats *analyzer.Statistics) csvTable {
+	t := csvTable{
+		name:   "hotspots.csv",
+		header: []string{"path", "changes", "authors", "risk_score", "last_modified", "reason"},
+	}
+	if stats.CodeHealthMetrics != nil {
+		for _, h := range stats.CodeHealthMetrics.TechnicalDebtHotspots {
+			t.rows = append(t.rows, []string{
+				h.FilePath, csvInt(h.TotalChanges), csvInt(h.UniqueAuthors), csvFloat(h.RiskScore), csvTime(h.LastModified), h.Reason,
+			})
+		}
+	}
+	return t
+}
+
+func stabilityTable(stats *analyzer.Statistics) csvTable {
+	t := csvTable{
+		name:   "stability.csv",
+		header: []string{"path", "shake_index", "time_spread_days", "modification_gap_days", "stability_level"},
+	}
+	if stats.CodeHealthMetrics != nil {
+		for _, s := range stats.CodeHealthMetrics.StabilityIndicators {
+			t.rows = append(t.rows, []string{
+				s.FilePath, csvFloat(s.ShakeIndex), csvFloat(s.TimeSpread), csvFloat(s.ModificationGap), string(s.StabilityLevel),
+			})
+		}
+	}
+	return t
+}
+
+func refactoringSignalsTable(stats *analyzer.Statistics) csvTable {
+	t := csvTable{
+		name:   "refactoring_signals.csv",
+		header: []string{"path", "short_term_changes", "intensive_days", "signal", "time_window", "first_change", "last_change"},
+	}
+	if stats.CodeHealthMetrics != nil {
+		for _, s := range stats.CodeHealthMetrics.RefactoringSignals {
+			t.rows = append(t.rows, []string{
+				s.FilePath, csvInt(s.ShortTermChanges), csvInt(s.IntensiveModDays), string(s.RefactoringSignal), s.TimeWindow,
+				csvTime(s.FirstChange), csvTime(s.LastChange),
+			})
+		}
+	}
+	return t
+}
+
+func concentrationTable(stats *analyzer.Statistics) csvTable {
+	t := csvTable{
+		name:   "concentration.csv",
+		header: []string{"path", "changes", "authors", "change_ratio", "concentration_level", "impact_level"},
+	}
+	if stats.CodeHealthMetrics != nil {
+		for _, c := range stats.CodeHealthMetrics.CodeConcentrationIssues {
+			t.rows = append(t.rows, []string{
+				c.FilePath, csvInt(c.TotalChanges), csvInt(c.AuthorCount), csvFloat(c.ChangeRatio), string(c.ConcentrationLevel), string(c.ImpactLevel),
+			})
+		}
+	}
+	return t
+}
+
+func branchesTable(stats *analyzer.Statistics) csvTable {
+	t := csvTable{
+		name:   "branches.csv",
+		header: []string{"name", "commits", "first_commit", "last_commit", "active", "main_authors"},
+	}
+	if stats.BranchData != nil {
+		for _, b := range stats.BranchData.Branches {
+			t.rows = append(t.rows, []string{
+				b.Name, csvInt(b.CommitCount), csvTime(b.FirstCommit), csvTime(b.LastCommit),
+				strconv.FormatBool(b.IsActive), csvList(b.MainAuthors),
+			})
+		}
+	}
+	return t
+}
+
+func mergesTable(stats *analyzer.Statistics) csvTable {
+	t := csvTable{
+		name:   "merges.csv",
+		header: []string{"merge_commit", "source_branch", "target_branch", "date", "author", "commits"},
+	}
+	if stats.BranchData != nil {
+		for _, m := range stats.BranchData.MergePatterns {
+			t.rows = append(t.rows, []string{
+				m.MergeCommit, m.SourceBranch, m.TargetBranch, csvTime(m.Date), m.Author, csvInt(m.CommitCount),
+			})
+		}
+	}
+	return t
+}
+
+func developersTable(profiles []*developer.DeveloperProfile) csvTable {
+	t := csvTable{
+		name: "developers.csv",
+		header: []string{
+			"name", "email",
+			"commit_frequency", "average_commit_size", "work_session_length", "consistency_score", "burst_work_ratio",
+			"preferred_commit_size", "refactoring_tendency", "bug_fix_ratio", "feature_focus_ratio", "documentation_ratio", "testing_engagement",
+			"files_ownership_ratio", "cross_team_work", "specialization_level", "mentorship_level", "preferred_file_types",
+			"preferred_work_hours", "weekend_worker", "night_owl", "early_bird", "work_life_balance",
+			"commit_message_quality", "code_stability_score", "technical_debt_ratio", "review_attentiveness",
+			"primary_languages", "technology_stack", "architectural_focus", "learning_velocity", "innovation_tendency",
+			"work_style_type", "planning_orientation", "risk_tolerance", "detail_orientation", "collaboration_style", "perfectionism_level",
+		},
+	}
+	for _, p := range profiles {
+		ws, cp, cs := p.WorkStyleMetrics, p.CodingPatterns, p.CollaborationStyle
+		tm, qi, tp, pt := p.TimeManagement, p.QualityIndicators, p.TechnicalProfile, p.PersonalityTraits
+		hours := make([]string, len(tm.PreferredWorkHours))
+		for i, h := range tm.PreferredWorkHours {
+			hours[i] = csvInt(h)
+		}
+		t.rows = append(t.rows, []string{
+			p.Name, p.Email,
+			csvFloat(ws.CommitFrequency), csvFloat(ws.AverageCommitSize), csvFloat(ws.WorkSessionLength), csvFloat(ws.ConsistencyScore), csvFloat(ws.BurstWorkRatio),
+			cp.PreferredCommitSize, csvFloat(cp.RefactoringTendency), csvFloat(cp.BugFixRatio), csvFloat(cp.FeatureFocusRatio), csvFloat(cp.DocumentationRatio), csvFloat(cp.TestingEngagement),
+			csvFloat(cs.FilesOwnershipRatio), csvFloat(cs.CrossTeamWork), csvFloat(cs.SpecializationLevel), cs.MentorshipLevel, csvList(cs.PreferredFileTypes),
+			csvList(hours), strconv.FormatBool(tm.WeekendWorker), strconv.FormatBool(tm.NightOwl), strconv.FormatBool(tm.EarlyBird), csvFloat(tm.WorkLifeBalance),
+			csvFloat(qi.CommitMessageQuality), csvFloat(qi.CodeStabilityScore), csvFloat(qi.TechnicalDebtRatio), csvFloat(qi.ReviewAttentiveness),
+			csvList(tp.PrimaryLanguages), csvList(tp.TechnologyStack), tp.ArchitecturalFocus, csvFloat(tp.LearningVelocity), csvFloat(tp.InnovationTendency),
+			pt.WorkStyleType, pt.PlanningOrientation, pt.RiskTolerance, pt.DetailOrientation, pt.CollaborationStyle, csvFloat(pt.PerfectionismLevel),
+		})
+	}
+	return t
+}
+
+func csvInt(n int) string {
+	return strconv.Itoa(n)
+}
+
+// csvFloat formats a number with at most four decimals
+func csvFloat(f float64) string {
+	return strconv.FormatFloat(math.Round(f*1e4)/1e4, 'f', -1, 64)
+}
+
+// csvTime formats a timestamp as RFC 3339; the zero time is empty
+func csvTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// csvList joins a list into one cell
+func csvList(values []string) string {
+	return strings.Join(values, ";")
+}
@@ -0,0 +1,142 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"git-log-analyzer/internal/analyzer"
+	"git-log-analyzer/internal/developer"
+)
+
+// readCSV reads a generated CSV file, checking for the byte order mark
+func readCSV(t *testing.T, path string) [][]string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(data, []byte(utf8BOM)) {
+		t.Errorf("%s does not start with a UTF-8 byte order mark", path)
+	}
+	records, err := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte(utf8BOM)))).ReadAll()
+	if err != nil {
+		t.Fatalf("Invalid CSV %s: %v", path, err)
+	}
+	return records
+}
+
+func TestCSVReportGenerator(t *testing.T) {
+	stats := analyzeFixture(t)
+	profiles, err := developer.NewProfileAnalyzer(stats).AnalyzeAllDevelopers(context.Background())
+	if err != nil {
+		t.Fatalf("AnalyzeAllDevelopers failed: %v", err)
+	}
+
+	dir := t.TempDir()
+	paths, err := NewCSVReportGenerator(dir).GenerateReport(context.Background(), stats, profiles)
+	if err != nil {
+		t.Fatalf("GenerateReport failed: %v", err)
+	}
+	var names []string
+	for _, p := range paths {
+		names = append(names, filepath.Base(p))
+	}
+	want := []string{
+		"authors.csv", "files.csv", "commit_frequency.csv", "hourly_pattern.csv", "daily_pattern.csv",
+		"hotspots.csv", "stability.csv", "refactoring_signals.csv", "concentration.csv",
+		"branches.csv", "merges.csv", "developers.csv",
+	}
+	if !reflect.DeepEqual(names, want) {
+		t.Fatalf("Unexpected files %v", names)
+	}
+
+	authors := readCSV(t, filepath.Join(dir, "authors.csv"))
+	wantAuthors := [][]string{
+		{"name", "email", "commits", "additions", "deletions", "files", "first_commit", "last_commit"},
+		{"Alice", "alice@example.com", "2", "10", "7", "1", "2023-03-01T10:00:00Z", "2023-03-03T10:00:00Z"},
+		{"Bob", "bob@example.com", "1", "2", "0", "1", "2023-03-02T10:00:00Z", "2023-03-02T10:00:00Z"},
+	}
+	if !reflect.DeepEqual(authors, wantAuthors) {
+		t.Errorf("Unexpected authors.csv:\n%v", authors)
+	}
+
+	if files := readCSV(t, filepath.Join(dir, "files.csv")); !reflect.DeepEqual(files, [][]string{{"path", "changes"}, {"main.go", "3"}}) {
+		t.Errorf("Unexpected files.csv: %v", files)
+	}
+	if hours := readCSV(t, filepath.Join(dir, "hourly_pattern.csv")); len(hours) != 25 || hours[11][1] != "3" {
+		t.Errorf("Expected 24 hours with 3 commits at 10:00, got %v", hours)
+	}
+	if days := readCSV(t, filepath.Join(dir, "daily_pattern.csv")); len(days) != 8 || days[1][0] != "Sunday" || days[4][1] != "1" {
+		t.Errorf("Unexpected daily_pattern.csv: %v", days)
+	}
+
+	developers := readCSV(t, filepath.Join(dir, "developers.csv"))
+	if len(developers) != 3 {
+		t.Fatalf("Expected a row per profile, got %d rows", len(developers))
+	}
+	for _, row := range developers {
+		if len(row) != len(developers[0]) {
+			t.Errorf("Row has %d columns instead of %d: %v", len(row), len(developers[0]), row)
+		}
+	}
+}
+
+func TestCSVReportGenerator_EmptyDatasets(t *testing.T) {
+	stats := analyzeFixture(t)
+	stats.BranchData = nil
+	stats.CodeHealthMetrics = nil
+
+	dir := t.TempDir()
+	if _, err := NewCSVReportGenerator(dir).GenerateReport(context.Background(), stats, nil); err != nil {
+		t.Fatalf("GenerateReport failed: %v", err)
+	}
+	for name, header := range map[string]string{"branches.csv": "name", "hotspots.csv": "path", "developers.csv": "name"} {
+		records := readCSV(t, filepath.Join(dir, name))
+		if len(records) != 1 || records[0][0] != header {
+			t.Errorf("Expected only the header in %s, got %v", name, records)
+		}
+	}
+}
+
+func TestCSVFormatting(t *testing.T) {
+	if got := csvFloat(0.123456); got != "0.1235" {
+		t.Errorf("csvFloat = %q", got)
+	}
+	if got := csvFloat(2); got != "2" {
+		t.Errorf("csvFloat = %q", got)
+	}
+	if got := csvTime(analyzer.AuthorStat{}.FirstCommit); got != "" {
+		t.Errorf("Expected the zero time to be empty, got %q", got)
+	}
+}
+
+func TestWriteCSV_NeutralizesFormulas(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "authors.csv")
+	table := csvTable{
+		name:   "authors.csv",
+		header: []string{"name", "email", "commits"},
+		rows: [][]string{
+			{"=HYPERLINK(\"http://evil.example\")", "@evil.example", "-1"},
+			{"+cmd", "-x", "1.5"},
+			{"张伟", "zhang@example.com", "3"},
+		},
+	}
+	if err := writeCSV(path, table); err != nil {
+		t.Fatalf("writeCSV failed: %v", err)
+	}
+
+	want := [][]string{
+		{"name", "email", "commits"},
+		{"'=HYPERLINK(\"http://evil.example\")", "'@evil.example", "-1"},
+		{"'+cmd", "'-x", "1.5"},
+		{"张伟", "zhang@example.com", "3"},
+	}
+	if got := readCSV(t, path); !reflect.DeepEqual(got, want) {
+		t.Errorf("Unexpected records:\n%q", got)
+	}
+}
@@ -0,0 +1,319 @@
+package report
+
+import (
+	"context"
+	"fmt"
+	"image/color"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"git-log-analyzer/internal/analyzer"
+	"git-log-analyzer/internal/health"
+	"git-log-analyzer/internal/i18n"
+	"git-log-analyzer/internal/pdf"
+)
+
+// PDFReportGenerator writes report.pdf with the summary, the code health
+// findings and the contributor and file tables. It uses the pure Go PDF
+// writer, so no headless browser is needed.
+type PDFReportGenerator struct {
+	outputDir string
+}
+
+// NewPDFReportGenerator creates a PDF report generator writing to outputDir
+func NewPDFReportGenerator(outputDir string) *PDFReportGenerator {
+	return &PDFReportGenerator{outputDir: outputDir}
+}
+
+// Page layout in points
+const (
+	pdfMargin     = 50.0
+	pdfFooter     = 40.0
+	pdfBodySize   = 9.5
+	pdfLineHeight = 13.0
+	pdfCellPad    = 4.0
+)
+
+var (
+	pdfText      = color.Gray{Y: 0x22}
+	pdfMuted     = color.Gray{Y: 0x77}
+	pdfAccent    = color.RGBA{R: 0x4a, G: 0x4f, B: 0xb5, A: 0xff}
+	pdfRule      = color.Gray{Y: 0xdd}
+	pdfHeaderBg  = color.RGBA{R: 0xf0, G: 0xf1, B: 0xfb, A: 0xff}
+	pdfBarColor  = color.RGBA{R: 0x66, G: 0x7e, B: 0xea, A: 0xff}
+	pdfLevelText = map[string]color.Color{
+		health.LevelError:   color.RGBA{R: 0xc0, G: 0x39, B: 0x2b, A: 0xff},
+		health.LevelWarning: color.RGBA{R: 0xd6, G: 0x89, B: 0x10, A: 0xff},
+	}
+)
+
+// GenerateReport writes the PDF report of stats and returns its path
+func (g *PDFReportGenerator) GenerateReport(ctx context.Context, stats *analyzer.Statistics, projectName string) (string, error) {
+	if err := os.MkdirAll(g.outputDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create output directory: %v", err)
+	}
+	msg := i18n.T()
+
+	l := &pdfLayout{doc: pdf.New()}
+	l.doc.SetTitle(msg.ReportTitle + " - " + projectName)
+	l.doc.SetAuthor("git-log-analyzer")
+	l.newPage()
+
+	l.doc.Text(pdfMargin, l.y+22, 22, true, pdfAccent, msg.ReportTitle)
+	l.y += 34
+	l.doc.Text(pdfMargin, l.y+10, 10, false, pdfMuted, fmt.Sprintf("%s · %s %s", projectName, msg.GeneratedOn, time.Now().Format("2006-01-02 15:04")))
+	l.y += 30
+
+	// Summary
+	l.heading(msg.PrintSummary)
+	summary := [][]string{
+		{msg.TotalCommits, fmt.Sprint(stats.TotalCommits)},
+		{msg.Contributors, fmt.Sprint(len(stats.AuthorStats))},
+		{msg.ActivePeriod, stats.TimeStats.FirstCommit.Format("2006-01-02") + " – " + stats.TimeStats.LastCommit.Format("2006-01-02")},
+		{msg.ActiveDays, fmt.Sprint(stats.TimeStats.ActiveDays)},
+	}
+	if metrics := stats.CodeHealthMetrics; metrics != nil {
+		summary = append(summary, []string{msg.PrintHealthScore, fmt.Sprintf("%.0f/100", metrics.HealthScore*100)})
+	}
+	l.table([]pdfColumn{{width: 160, bold: true}, {width: pdf.PageWidth - 2*pdfMargin - 160}}, summary, nil)
+	if metrics := stats.CodeHealthMetrics; metrics != nil && metrics.HealthSummary != "" {
+		l.paragraph(metrics.HealthSummary)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	// Code health findings
+	if stats.CodeHealthMetrics != nil {
+		l.heading(msg.PrintHealth)
+		findings := health.Findings(stats.CodeHealthMetrics)
+		if len(findings) == 0 {
+			l.paragraph(msg.PrintNoFindings)
+		} else {
+			var rows [][]string
+			for _, f := range findings {
+				rows = append(rows, []string{f.Level, f.RuleID, f.File, f.Message})
+			}
+			l.table([]pdfColumn{
+				{title: msg.PrintLevel, width: 50},
+				{title: msg.PrintRule, width: 95},
+				{title: msg.PrintFile, width: 140},
+				{title: msg.PrintMessage, width: pdf.PageWidth - 2*pdfMargin - 285},
+			}, rows, func(row []string, col int) color.Color {
+				if col == 0 {
+					return pdfLevelText[row[0]]
+				}
+				return nil
+			})
+		}
+	}
+
+	// Contributors, with a bar for the share of commits
+	l.heading(msg.TopContributors)
+	var rows [][]string
+	authors := topAuthors(stats, 20)
+	for _, a := range authors {
+		rows = append(rows, []string{a.Name, fmt.Sprint(a.CommitCount), fmt.Sprintf("%.1f%%", a.Percentage),
+			fmt.Sprintf("+%d", a.Additions), fmt.Sprintf("-%d", a.Deletions)})
+	}
+	l.table([]pdfColumn{
+		{title: msg.PrintAuthor, width: 195},
+		{title: msg.PrintCommits, width: 70, right: true},
+		{title: msg.PrintShare, width: 90, right: true, bar: true},
+		{title: msg.PrintAdditions, width: 70, right: true},
+		{title: msg.PrintDeletions, width: pdf.PageWidth - 2*pdfMargin - 425, right: true},
+	}, rows, nil)
+
+	// Most modified files
+	l.heading(msg.MostModifiedFiles)
+	rows = nil
+	for _, f := range topFiles(stats, 15) {
+		rows = append(rows, []string{f.Name, fmt.Sprint(f.Count)})
+	}
+	l.table([]pdfColumn{
+		{title: msg.PrintFile, width: pdf.PageWidth - 2*pdfMargin - 80},
+		{title: msg.PrintChanges, width: 80, right: true},
+	}, rows, nil)
+
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	// Page numbers
+	pages := l.doc.PageCount()
+	for i := 0; i < pages; i++ {
+		l.doc.SetPage(i)
+		footer := fmt.Sprintf(msg.PrintPage, i+1, pages)
+		y := pdf.PageHeight - pdfFooter + 16
+		l.doc.Line(pdfMargin, y-12, pdf.PageWidth-pdfMargin, y-12, 0.5, pdfRule)
+		l.doc.Text(pdfMargin, y, 8, false, pdfMuted, projectName)
+		l.doc.Text(pdf.PageWidth-pdfMargin-pdf.TextWidth(footer, 8, false), y, 8, false, pdfMuted, footer)
+	}
+
+	path := filepath.Join(g.outputDir, "report.pdf")
+	file, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to create PDF report: %v", err)
+	}
+	defer file.Close()
+	if _, err := l.doc.WriteTo(file); err != nil {
+		return "", fmt.Errorf("failed to write PDF report: %v", err)
+	}
+	return path, nil
+}
+
+// pdfLayout flows headings, paragraphs and tables down the pages
+type pdfLayout struct {
+	doc *pdf.Document
+	y   float64
+}
+
+// pdfColumn is a table column. A bar column draws its value, a percentage,
+// as a bar behind the text.
+type pdfColumn struct {
+	title string
+	width float64
+	right bool
+	bold  bool
+	bar   bool
+}
+
+func (l *pdfLayout) newPage() {
+	l.doc.AddPage()
+	l.y = pdfMargin
+}
+
+// ensure starts a new page unless height fits on the current one
+func (l *pdfLayout) ensure(height float64) bool {
+	if l.y+height <= pdf.PageHeight-pdfFooter-10 {
+		return false
+	}
+	l.newPage()
+	return true
+}
+
+func (l *pdfLayout) heading(title string) {
+	l.ensure(60)
+	l.y += 10
+	l.doc.Text(pdfMargin, l.y+14, 14, true, pdfAccent, title)
+	l.y += 20
+	l.doc.Line(pdfMargin, l.y, pdf.PageWidth-pdfMargin, l.y, 1, pdfBarColor)
+	l.y += 8
+}
+
+func (l *pdfLayout) paragraph(text string) {
+	for _, line := range wrapText(text, pdf.PageWidth-2*pdfMargin, pdfBodySize, false) {
+		l.ensure(pdfLineHeight)
+		l.doc.Text(pdfMargin, l.y+pdfBodySize, pdfBodySize, false, pdfText, line)
+		l.y += pdfLineHeight
+	}
+	l.y += 6
+}
+
+// table draws rows with wrapped cells, repeating the header on every page.
+// cellColor may return a text color for a cell, or nil for the default.
+func (l *pdfLayout) table(columns []pdfColumn, rows [][]string, cellColor func(row []string, col int) color.Color) {
+	hasHeader := false
+	for _, c := range columns {
+		hasHeader = hasHeader || c.title != ""
+	}
+	header := func() {
+		if !hasHeader {
+			return
+		}
+		height := pdfLineHeight + 2*pdfCellPad
+		l.doc.Rect(pdfMargin, l.y, pdf.PageWidth-2*pdfMargin, height, pdfHeaderBg)
+		x := pdfMargin
+		for _, c := range columns {
+			l.cell(x, c, c.title, true, pdfText)
+			x += c.width
+		}
+		l.y += height
+	}
+	l.ensure(2 * (pdfLineHeight + 2*pdfCellPad))
+	header()
+
+	for _, row := range rows {
+		lines := make([][]string, len(columns))
+		height := 0.0
+		for i, c := range columns {
+			lines[i] = wrapText(row[i], c.width-2*pdfCellPad, pdfBodySize, c.bold)
+			if h := float64(len(lines[i]))*pdfLineHeight + 2*pdfCellPad; h > height {
+				height = h
+			}
+		}
+		if l.ensure(height) {
+			header()
+		}
+
+		x := pdfMargin
+		for i, c := range columns {
+			if c.bar {
+				var share float64
+				fmt.Sscanf(row[i], "%f", &share)
+				l.doc.Rect(x+pdfCellPad, l.y+pdfCellPad, (c.width-2*pdfCellPad)*share/100, pdfLineHeight, pdfHeaderBg)
+			}
+			textColor := color.Color(pdfText)
+			if cellColor != nil {
+				if cc := cellColor(row, i); cc != nil {
+					textColor = cc
+				}
+			}
+			y := l.y
+			for _, line := range lines[i] {
+				l.cell(x, c, line, c.bold, textColor)
+				l.y += pdfLineHeight
+			}
+			l.y = y
+			x += c.width
+		}
+		l.y += height
+		l.doc.Line(pdfMargin, l.y, pdf.PageWidth-pdfMargin, l.y, 0.5, pdfRule)
+	}
+	l.y += 12
+}
+
+// cell draws one line of a cell in the row at the current position
+func (l *pdfLayout) cell(x float64, c pdfColumn, text string, bold bool, textColor color.Color) {
+	if c.right {
+		x += c.width - pdfCellPad - pdf.TextWidth(text, pdfBodySize, bold)
+	} else {
+		x += pdfCellPad
+	}
+	l.doc.Text(x, l.y+pdfCellPad+pdfBodySize, pdfBodySize, bold, textColor, text)
+}
+
+// wrapText breaks text into lines no wider than width, between words where
+// possible and anywhere in words (or text without spaces) that are too long
+func wrapText(text string, width, size float64, bold bool) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(text) {
+		candidate := word
+		if line != "" {
+			candidate = line + " " + word
+		}
+		if pdf.TextWidth(candidate, size, bold) <= width {
+			line = candidate
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+		line = ""
+		for _, r := range word {
+			if line != "" && pdf.TextWidth(line+string(r), size, bold) > width {
+				lines = append(lines, line)
+				line = ""
+			}
+			line += string(r)
+		}
+	}
+	if line != "" || len(lines) == 0 {
+		lines = append(lines, line)
+	}
+	return lines
+}
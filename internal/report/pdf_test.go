@@ -0,0 +1,60 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"git-log-analyzer/internal/health"
+)
+
+func TestPDFReportGenerator(t *testing.T) {
+	stats := analyzeFixture(t)
+	stats.CodeHealthMetrics = &health.CodeHealthMetrics{
+		HealthScore: 0.42,
+		TechnicalDebtHotspots: []health.TechnicalDebtHotspot{
+			{FilePath: "main.go", TotalChanges: 3, UniqueAuthors: 2, RiskScore: 0.8},
+		},
+	}
+
+	dir := t.TempDir()
+	path, err := NewPDFReportGenerator(dir).GenerateReport(context.Background(), stats, "demo")
+	if err != nil {
+		t.Fatalf("GenerateReport failed: %v", err)
+	}
+	if path != filepath.Join(dir, "report.pdf") {
+		t.Errorf("Unexpected path %s", path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(data, []byte("%PDF-1.4")) || !bytes.HasSuffix(data, []byte("%%EOF\n")) {
+		t.Fatal("Not a PDF document")
+	}
+	if !bytes.Contains(data, []byte("/BaseFont /STSong-Light")) || !bytes.Contains(data, []byte("/Count 1")) {
+		t.Error("Expected one page and the CJK font for the Chinese labels")
+	}
+}
+
+func TestWrapText(t *testing.T) {
+	lines := wrapText("Changed 18 times by 5 authors with a risk of 0.94", 100, 10, false)
+	if len(lines) < 2 {
+		t.Fatalf("Expected the message to wrap, got %q", lines)
+	}
+	for _, line := range lines {
+		if line == "" || line[0] == ' ' {
+			t.Errorf("Unexpected line %q", line)
+		}
+	}
+
+	// Text without spaces breaks anywhere
+	if lines := wrapText("修改最多的文件修改最多的文件", 50, 10, false); len(lines) != 3 || lines[0] != "修改最多的" {
+		t.Errorf("Unexpected lines %q", lines)
+	}
+	if lines := wrapText("", 50, 10, false); len(lines) != 1 {
+		t.Errorf("Expected one empty line, got %q", lines)
+	}
+}
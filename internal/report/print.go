@@ -0,0 +1,66 @@
+package report
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+
+	"git-log-analyzer/internal/ai"
+	"git-log-analyzer/internal/analyzer"
+	"git-log-analyzer/internal/health"
+)
+
+//go:embed templates/print.html
+var printTemplate string
+
+// printReportData is the data of the print template
+type printReportData struct {
+	*ReportData
+	Findings []health.Finding
+	HasAI    bool
+}
+
+// GeneratePrintReport writes report-print.html, a print-ready rendering of
+// the report: no scripts, static SVG charts, a table of contents and page
+// breaks between the sections, for printing or saving as PDF in a browser
+func (w *WebReportGenerator) GeneratePrintReport(ctx context.Context, stats *analyzer.Statistics, aiAnalysis *ai.AnalysisResult, projectName string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(w.outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %v", err)
+	}
+
+	base := w.prepareReportData(stats, aiAnalysis, AIStatus{}, projectName, nil)
+	data := printReportData{
+		ReportData: base,
+		Findings:   health.Findings(stats.CodeHealthMetrics),
+		HasAI:      base.AIStructured != nil || base.AIAnalysis != "",
+	}
+
+	funcMap := template.FuncMap{
+		"mul": func(a, b float64) float64 {
+			return a * b
+		},
+	}
+	t, err := template.New("print").Funcs(funcMap).Parse(printTemplate)
+	if err != nil {
+		return fmt.Errorf("failed to parse print template: %v", err)
+	}
+
+	file, err := os.Create(w.GetPrintReportPath())
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return t.Execute(file, data)
+}
+
+// GetPrintReportPath returns the path to the print report
+func (w *WebReportGenerator) GetPrintReportPath() string {
+	return filepath.Join(w.outputDir, "report-print.html")
+}
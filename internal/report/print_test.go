@@ -0,0 +1,54 @@
+package report
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"git-log-analyzer/internal/health"
+)
+
+func TestGeneratePrintReport(t *testing.T) {
+	stats := analyzeFixture(t)
+	stats.CodeHealthMetrics = &health.CodeHealthMetrics{
+		HealthScore: 0.42,
+		TechnicalDebtHotspots: []health.TechnicalDebtHotspot{
+			{FilePath: "main.go", TotalChanges: 3, UniqueAuthors: 2, RiskScore: 0.8},
+		},
+	}
+
+	gen := NewWebReportGenerator(t.TempDir())
+	if err := gen.GeneratePrintReport(context.Background(), stats, nil, "demo"); err != nil {
+		t.Fatalf("GeneratePrintReport failed: %v", err)
+	}
+	data, err := os.ReadFile(gen.GetPrintReportPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	html := string(data)
+
+	if strings.Contains(html, "<script") || strings.Contains(html, "<canvas") {
+		t.Error("The print report should not need scripts")
+	}
+	for _, want := range []string{
+		`<a href="#summary">`,
+		`<section id="health">`,
+		"page-break-before: always",
+		"<svg",
+		"<td>Alice</td>",
+		"42/100",
+		`<td class="level-error">error</td>`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("Print report is missing %q", want)
+		}
+	}
+	if strings.Contains(html, `<section id="ai">`) {
+		t.Error("Expected no AI section without an analysis")
+	}
+	// The timeline, hours, weekdays, punch card, contributors and hotspots
+	if n := strings.Count(html, "<svg"); n != 6 {
+		t.Errorf("Expected 6 charts, got %d", n)
+	}
+}
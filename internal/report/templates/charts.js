@@ -290,6 +290,12 @@ function initCommitForest(branchData) {
     }
 
     console.log('Initializing commit forest with data:', branchData);
+
+    if (typeof d3 === 'undefined') {
+        // 离线时没有D3.js，绘制不带缩放的静态森林图
+        drawStaticCommitForest(branchData);
+        return;
+    }
     
     const svg = d3.select('#commitForest');
     if (svg.empty()) {
@@ -426,6 +432,84 @@ function initCommitForest(branchData) {
     setupForestControls(svg, g, zoom, branches, commitGraph, branchIndexMap);
 }
 
+// 不依赖D3.js的提交森林图：与initCommitForest相同的布局，用title显示提交信息
+function drawStaticCommitForest(branchData) {
+    const svg = document.getElementById('commitForest');
+    if (!svg) {
+        console.error('Forest SVG element not found');
+        return;
+    }
+    const ns = 'http://www.w3.org/2000/svg';
+    const create = (name, attrs, parent) => {
+        const el = document.createElementNS(ns, name);
+        Object.keys(attrs).forEach(key => el.setAttribute(key, attrs[key]));
+        parent.appendChild(el);
+        return el;
+    };
+
+    while (svg.firstChild) {
+        svg.removeChild(svg.firstChild);
+    }
+
+    const margin = { top: 40, right: 40, bottom: 40, left: 100 };
+    const width = 800 - margin.left - margin.right;
+    const height = 500 - margin.top - margin.bottom;
+    const g = create('g', { transform: `translate(${margin.left},${margin.top})` }, svg);
+
+    const branches = branchData.branches || [];
+    const commitGraph = (branchData.commit_graph || []).slice();
+    if (commitGraph.length === 0) {
+        const text = create('text', { x: width / 2, y: height / 2, 'text-anchor': 'middle', fill: '#666', 'font-size': '16px' }, g);
+        text.textContent = '暂无提交数据';
+        return;
+    }
+
+    commitGraph.sort((a, b) => new Date(a.date) - new Date(b.date));
+    const times = commitGraph.map(c => new Date(c.date).getTime());
+    const minTime = Math.min(...times);
+    const span = Math.max(Math.max(...times) - minTime, 1);
+    const x = date => (new Date(date).getTime() - minTime) / span * width;
+    const y = index => branches.length > 1 ? (height - 100) - index * (height - 150) / (branches.length - 1) : (height - 50) / 2;
+
+    const branchIndexMap = {};
+    branches.forEach((branch, index) => {
+        branchIndexMap[branch.name] = index;
+        const points = commitGraph.filter(c => c.branch === branch.name).map(c => `${x(c.date)},${y(index)}`);
+        if (points.length === 0) return;
+        create('polyline', { class: 'branch-line', points: points.join(' '), stroke: getBranchColor(index), 'stroke-width': 3, fill: 'none', opacity: 0.7 }, g);
+        const label = create('text', { x: -10, y: y(index), dy: '0.35em', 'text-anchor': 'end', 'font-size': '12px', 'font-weight': 'bold', fill: getBranchColor(index) }, g);
+        label.textContent = branch.name;
+    });
+
+    commitGraph.forEach(commit => {
+        const node = create('circle', {
+            class: 'commit-node',
+            cx: x(commit.date),
+            cy: y(branchIndexMap[commit.branch] || 0),
+            r: commit.is_merge ? 6 : 4,
+            fill: commit.is_merge ? '#dc3545' : '#28a745',
+            stroke: commit.is_merge ? '#c82333' : '#1e7e34',
+            'stroke-width': 2
+        }, g);
+        const title = create('title', {}, node);
+        title.textContent = `${commit.branch} · ${commit.author} · ${new Date(commit.date).toLocaleString()}\n${commit.message || commit.short_hash}`;
+        node.addEventListener('click', () => showCommitDetails(commit));
+    });
+
+    const branchFilter = document.getElementById('branchFilter');
+    if (branchFilter) {
+        branchFilter.addEventListener('change', (e) => {
+            const selected = e.target.value;
+            g.querySelectorAll('.commit-node').forEach((node, i) => {
+                node.style.opacity = selected === 'all' || commitGraph[i].branch === selected ? 1 : 0.1;
+            });
+            g.querySelectorAll('.branch-line').forEach(line => {
+                line.style.opacity = selected === 'all' || line.nextSibling.textContent === selected ? 0.7 : 0.1;
+            });
+        });
+    }
+}
+
 function getBranchColor(index) {
     const colors = [
         '#007bff', '#28a745', '#dc3545', '#ffc107', 
@@ -523,8 +607,8 @@ function filterBranchView(g, selectedBranch, branches, commitGraph, branchIndexM
     }
 }
 
-// 确保D3.js库被加载
-if (typeof d3 === 'undefined') {
+// 确保D3.js库被加载（单文件离线报告不访问网络）
+if (typeof d3 === 'undefined' && !window.offlineReport) {
     // 动态加载D3.js
     const script = document.createElement('script');
     script.src = 'https://d3js.org/d3.v7.min.js';
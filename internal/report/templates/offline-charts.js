@@ -0,0 +1,345 @@
+// 离线图表渲染器
+// 单文件报告不能从CDN加载Chart.js，这里用canvas实现报告中用到的
+// doughnut、pie、polarArea、line和bar图表，接口与Chart.js相同：new Chart(canvas, config)
+(function (global) {
+    'use strict';
+
+    // 告诉charts.js不要再从网络加载D3.js
+    global.offlineReport = true;
+
+    const FONT = '12px -apple-system, BlinkMacSystemFont, "Segoe UI", Roboto, sans-serif';
+    const TEXT_COLOR = '#666';
+    const GRID_COLOR = 'rgba(0, 0, 0, 0.1)';
+    const DEFAULT_COLOR = 'rgba(102, 126, 234, 0.8)';
+
+    function Chart(item, config) {
+        this.canvas = item.canvas || item;
+        this.config = config;
+        this.type = config.type;
+        this.data = config.data || { labels: [], datasets: [] };
+        this.options = config.options || {};
+        this.regions = [];
+
+        this.resizeHandler = () => this.update();
+        this.moveHandler = (event) => this.showTooltip(event);
+        global.addEventListener('resize', this.resizeHandler);
+        this.canvas.addEventListener('mousemove', this.moveHandler);
+        this.update();
+    }
+
+    Chart.offline = true;
+
+    Chart.prototype.destroy = function () {
+        global.removeEventListener('resize', this.resizeHandler);
+        this.canvas.removeEventListener('mousemove', this.moveHandler);
+    };
+
+    // update 按容器大小重新绘制，隐藏的图表在显示后随resize事件绘制
+    Chart.prototype.update = function () {
+        const size = this.size();
+        if (size.width <= 0 || size.height <= 0) {
+            return;
+        }
+        const ratio = global.devicePixelRatio || 1;
+        const canvas = this.canvas;
+        canvas.style.width = size.width + 'px';
+        canvas.style.height = size.height + 'px';
+        canvas.width = Math.round(size.width * ratio);
+        canvas.height = Math.round(size.height * ratio);
+
+        const ctx = canvas.getContext('2d');
+        ctx.setTransform(ratio, 0, 0, ratio, 0, 0);
+        ctx.clearRect(0, 0, size.width, size.height);
+        ctx.font = FONT;
+        this.regions = [];
+
+        const area = { left: 8, top: 8, right: size.width - 8, bottom: size.height - 8 };
+        switch (this.type) {
+            case 'doughnut':
+            case 'pie':
+            case 'polarArea':
+                this.drawLegend(ctx, area, this.labelItems(), 'bottom');
+                this.drawRadial(ctx, area);
+                break;
+            case 'line':
+            case 'bar':
+                this.drawLegend(ctx, area, this.datasetItems(), 'top');
+                this.drawCartesian(ctx, area);
+                break;
+            default:
+                console.warn('Offline charts: unsupported chart type', this.type);
+        }
+    };
+
+    // size 与Chart.js的maintainAspectRatio: false一致，填满父元素中其余内容以外的空间
+    Chart.prototype.size = function () {
+        const parent = this.canvas.parentElement;
+        if (!parent) {
+            return { width: this.canvas.width, height: this.canvas.height };
+        }
+        const style = global.getComputedStyle(parent);
+        const width = parent.clientWidth - parseFloat(style.paddingLeft) - parseFloat(style.paddingRight);
+        let height = parent.clientHeight - parseFloat(style.paddingTop) - parseFloat(style.paddingBottom);
+        Array.prototype.forEach.call(parent.children, (child) => {
+            if (child !== this.canvas) {
+                height -= child.offsetHeight;
+            }
+        });
+        if (this.options.maintainAspectRatio !== false || height < 150) {
+            height = Math.max(width / 2, 150);
+        }
+        return { width: Math.floor(width), height: Math.floor(height) };
+    };
+
+    Chart.prototype.dataset = function () {
+        return this.data.datasets[0] || { data: [] };
+    };
+
+    Chart.prototype.labelItems = function () {
+        const dataset = this.dataset();
+        return (this.data.labels || []).map((label, i) => ({ label: String(label), color: colorAt(dataset.backgroundColor, i) }));
+    };
+
+    Chart.prototype.datasetItems = function () {
+        return this.data.datasets
+            .filter((dataset) => dataset.label)
+            .map((dataset) => ({ label: dataset.label, color: dataset.borderColor || colorAt(dataset.backgroundColor, 0) }));
+    };
+
+    // drawLegend 在area的顶部或底部绘制图例，并把area缩小到剩余的空间
+    Chart.prototype.drawLegend = function (ctx, area, items, position) {
+        const legend = this.options.plugins && this.options.plugins.legend;
+        if (items.length === 0 || (legend && legend.display === false)) {
+            return;
+        }
+        position = (legend && legend.position) || position;
+
+        const box = 12;
+        const rowHeight = 18;
+        const rows = [[]];
+        let rowWidth = 0;
+        items.forEach((item) => {
+            const width = box + 6 + ctx.measureText(item.label).width + 12;
+            if (rowWidth + width > area.right - area.left && rows[rows.length - 1].length > 0) {
+                rows.push([]);
+                rowWidth = 0;
+            }
+            rows[rows.length - 1].push({ item: item, width: width });
+            rowWidth += width;
+        });
+
+        const height = rows.length * rowHeight + 6;
+        let y = position === 'top' ? area.top : area.bottom - height + 6;
+        ctx.textBaseline = 'middle';
+        ctx.textAlign = 'left';
+        rows.forEach((row) => {
+            const total = row.reduce((sum, entry) => sum + entry.width, 0);
+            let x = area.left + (area.right - area.left - total) / 2;
+            row.forEach((entry) => {
+                ctx.fillStyle = entry.item.color;
+                ctx.fillRect(x, y + (rowHeight - box) / 2, box, box);
+                ctx.fillStyle = TEXT_COLOR;
+                ctx.fillText(entry.item.label, x + box + 6, y + rowHeight / 2);
+                x += entry.width;
+            });
+            y += rowHeight;
+        });
+
+        if (position === 'top') {
+            area.top += height;
+        } else {
+            area.bottom -= height;
+        }
+    };
+
+    Chart.prototype.drawRadial = function (ctx, area) {
+        const dataset = this.dataset();
+        const values = dataset.data.map((v) => Math.max(Number(v) || 0, 0));
+        const cx = (area.left + area.right) / 2;
+        const cy = (area.top + area.bottom) / 2;
+        const radius = Math.max(Math.min(area.right - area.left, area.bottom - area.top) / 2, 0);
+        const total = values.reduce((sum, v) => sum + v, 0);
+        const max = Math.max.apply(null, values.concat([0]));
+        if (radius === 0 || total === 0) {
+            return;
+        }
+
+        if (this.type === 'polarArea') {
+            ctx.strokeStyle = GRID_COLOR;
+            for (let i = 1; i <= 4; i++) {
+                ctx.beginPath();
+                ctx.arc(cx, cy, radius * i / 4, 0, Math.PI * 2);
+                ctx.stroke();
+            }
+        }
+
+        const inner = this.type === 'doughnut' ? radius * 0.5 : 0;
+        let start = -Math.PI / 2;
+        values.forEach((value, i) => {
+            let end;
+            let outer = radius;
+            if (this.type === 'polarArea') {
+                end = start + Math.PI * 2 / values.length;
+                outer = radius * value / max;
+            } else {
+                end = start + Math.PI * 2 * value / total;
+            }
+            ctx.beginPath();
+            ctx.arc(cx, cy, outer, start, end);
+            ctx.arc(cx, cy, inner, end, start, true);
+            ctx.closePath();
+            ctx.fillStyle = colorAt(dataset.backgroundColor, i);
+            ctx.fill();
+            ctx.strokeStyle = '#fff';
+            ctx.lineWidth = 2;
+            ctx.stroke();
+            this.regions.push({ arc: true, cx: cx, cy: cy, inner: inner, outer: outer, start: start, end: end, index: i });
+            start = end;
+        });
+    };
+
+    Chart.prototype.drawCartesian = function (ctx, area) {
+        const labels = (this.data.labels || []).map(String);
+        const datasets = this.data.datasets;
+        const count = labels.length;
+        if (count === 0) {
+            return;
+        }
+
+        let max = 0;
+        datasets.forEach((dataset) => dataset.data.forEach((v) => { max = Math.max(max, Number(v) || 0); }));
+        const step = niceStep(max / 5);
+        const top = Math.max(step * Math.ceil(max / step), step);
+
+        ctx.textBaseline = 'middle';
+        ctx.textAlign = 'right';
+        const axisWidth = ctx.measureText(String(top)).width + 8;
+        const plot = { left: area.left + axisWidth, top: area.top + 6, right: area.right, bottom: area.bottom - 20 };
+        const y = (v) => plot.bottom - (plot.bottom - plot.top) * v / top;
+
+        // y轴刻度和网格线
+        ctx.strokeStyle = GRID_COLOR;
+        ctx.lineWidth = 1;
+        ctx.fillStyle = TEXT_COLOR;
+        for (let v = 0; v <= top + step / 2; v += step) {
+            ctx.beginPath();
+            ctx.moveTo(plot.left, y(v));
+            ctx.lineTo(plot.right, y(v));
+            ctx.stroke();
+            ctx.fillText(formatTick(v), plot.left - 6, y(v));
+        }
+
+        // x轴标签，放不下时跳过一部分
+        const slot = (plot.right - plot.left) / count;
+        const x = (i) => this.type === 'bar' ? plot.left + slot * (i + 0.5) : plot.left + (count > 1 ? (plot.right - plot.left) * i / (count - 1) : (plot.right - plot.left) / 2);
+        const labelWidth = labels.reduce((w, label) => Math.max(w, ctx.measureText(label).width), 0) + 8;
+        const every = Math.max(1, Math.ceil(labelWidth / Math.max((plot.right - plot.left) / count, 1)));
+        ctx.textAlign = 'center';
+        ctx.textBaseline = 'top';
+        labels.forEach((label, i) => {
+            if (i % every === 0) {
+                ctx.fillText(label, x(i), plot.bottom + 6);
+            }
+        });
+
+        datasets.forEach((dataset) => {
+            const values = dataset.data.map((v) => Number(v) || 0);
+            if (this.type === 'bar') {
+                const width = Math.max(slot * 0.7 / datasets.length, 1);
+                const offset = datasets.indexOf(dataset) * width - slot * 0.35;
+                values.forEach((value, i) => {
+                    ctx.fillStyle = colorAt(dataset.backgroundColor, i);
+                    ctx.fillRect(x(i) + offset, y(value), width, plot.bottom - y(value));
+                });
+            } else {
+                const points = values.map((value, i) => ({ x: x(i), y: y(value) }));
+                const tension = dataset.tension || 0;
+                ctx.beginPath();
+                tracePath(ctx, points, tension);
+                if (dataset.fill) {
+                    ctx.lineTo(points[points.length - 1].x, plot.bottom);
+                    ctx.lineTo(points[0].x, plot.bottom);
+                    ctx.closePath();
+                    ctx.fillStyle = colorAt(dataset.backgroundColor, 0);
+                    ctx.fill();
+                    ctx.beginPath();
+                    tracePath(ctx, points, tension);
+                }
+                ctx.strokeStyle = dataset.borderColor || DEFAULT_COLOR;
+                ctx.lineWidth = 2;
+                ctx.stroke();
+            }
+        });
+
+        labels.forEach((label, i) => {
+            this.regions.push({ left: x(i) - slot / 2, right: x(i) + slot / 2, index: i });
+        });
+    };
+
+    // showTooltip 把鼠标下的数据显示为canvas的title
+    Chart.prototype.showTooltip = function (event) {
+        const rect = this.canvas.getBoundingClientRect();
+        const px = event.clientX - rect.left;
+        const py = event.clientY - rect.top;
+        const region = this.regions.find((r) => {
+            if (!r.arc) {
+                return px >= r.left && px < r.right;
+            }
+            const distance = Math.hypot(px - r.cx, py - r.cy);
+            let angle = Math.atan2(py - r.cy, px - r.cx);
+            while (angle < r.start) {
+                angle += Math.PI * 2;
+            }
+            return distance >= r.inner && distance <= r.outer && angle <= r.end;
+        });
+        if (!region) {
+            this.canvas.title = '';
+            return;
+        }
+        const label = (this.data.labels || [])[region.index];
+        this.canvas.title = this.data.datasets
+            .map((dataset) => (dataset.label ? dataset.label + ' ' : '') + label + ': ' + dataset.data[region.index])
+            .join('\n');
+    };
+
+    function colorAt(color, i) {
+        if (Array.isArray(color)) {
+            return color.length > 0 ? color[i % color.length] : DEFAULT_COLOR;
+        }
+        return color || DEFAULT_COLOR;
+    }
+
+    // tracePath 连接各点，tension大于0时用水平控制点的贝塞尔曲线平滑
+    function tracePath(ctx, points, tension) {
+        points.forEach((p, i) => {
+            if (i === 0) {
+                ctx.moveTo(p.x, p.y);
+                return;
+            }
+            const prev = points[i - 1];
+            if (tension > 0) {
+                const dx = (p.x - prev.x) * tension;
+                ctx.bezierCurveTo(prev.x + dx, prev.y, p.x - dx, p.y, p.x, p.y);
+            } else {
+                ctx.lineTo(p.x, p.y);
+            }
+        });
+    }
+
+    // niceStep 把刻度间隔取整到1、2或5乘以10的幂
+    function niceStep(raw) {
+        if (raw <= 0) {
+            return 1;
+        }
+        const power = Math.pow(10, Math.floor(Math.log10(raw)));
+        const fraction = raw / power;
+        const nice = fraction <= 1 ? 1 : fraction <= 2 ? 2 : fraction <= 5 ? 5 : 10;
+        return Math.max(nice * power, 1);
+    }
+
+    function formatTick(v) {
+        return Number.isInteger(v) ? String(v) : v.toFixed(1);
+    }
+
+    global.Chart = Chart;
+})(window);
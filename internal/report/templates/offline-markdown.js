@@ -0,0 +1,112 @@
+// 离线Markdown渲染器
+// 单文件报告不能从CDN加载marked，这里实现AI分析用到的Markdown子集：
+// 标题、段落、列表、引用、代码块、分隔线、粗体、斜体、行内代码和链接，
+// 接口与marked相同：marked.parse(text)。原文中的HTML会被转义。
+(function (global) {
+    'use strict';
+
+    function escapeHTML(text) {
+        return text
+            .replace(/&/g, '&amp;')
+            .replace(/</g, '&lt;')
+            .replace(/>/g, '&gt;')
+            .replace(/"/g, '&quot;');
+    }
+
+    // inline 渲染一行内的格式，先取出行内代码，避免其中的符号被当作格式
+    function inline(text) {
+        const codes = [];
+        let html = escapeHTML(text).replace(/`([^`]+)`/g, (_, code) => {
+            codes.push('<code>' + code + '</code>');
+            return '\u0000' + (codes.length - 1) + '\u0000';
+        });
+        html = html
+            .replace(/\[([^\]]+)\]\((https?:\/\/[^\s)]+)\)/g, '<a href="$2" target="_blank" rel="noopener">$1</a>')
+            .replace(/\*\*([^*]+)\*\*|__([^_]+)__/g, (_, a, b) => '<strong>' + (a || b) + '</strong>')
+            .replace(/\*([^*\s][^*]*)\*|\b_([^_\s][^_]*)_\b/g, (_, a, b) => '<em>' + (a || b) + '</em>');
+        return html.replace(/\u0000(\d+)\u0000/g, (_, i) => codes[Number(i)]);
+    }
+
+    function parse(text) {
+        const lines = String(text).replace(/\r\n?/g, '\n').split('\n');
+        const out = [];
+        let paragraph = [];
+        let list = null; // { tag, items }
+        let quote = [];
+
+        const flushParagraph = () => {
+            if (paragraph.length > 0) {
+                out.push('<p>' + paragraph.map(inline).join('<br>') + '</p>');
+                paragraph = [];
+            }
+        };
+        const flushList = () => {
+            if (list) {
+                out.push('<' + list.tag + '>' + list.items.map((item) => '<li>' + inline(item) + '</li>').join('') + '</' + list.tag + '>');
+                list = null;
+            }
+        };
+        const flushQuote = () => {
+            if (quote.length > 0) {
+                out.push('<blockquote>' + parse(quote.join('\n')) + '</blockquote>');
+                quote = [];
+            }
+        };
+        const flush = () => {
+            flushParagraph();
+            flushList();
+            flushQuote();
+        };
+
+        for (let i = 0; i < lines.length; i++) {
+            const line = lines[i];
+            let match;
+
+            if (/^\s*```/.test(line)) {
+                flush();
+                const code = [];
+                for (i++; i < lines.length && !/^\s*```/.test(lines[i]); i++) {
+                    code.push(lines[i]);
+                }
+                out.push('<pre><code>' + escapeHTML(code.join('\n')) + '</code></pre>');
+            } else if (/^\s*$/.test(line)) {
+                flush();
+            } else if ((match = /^\s*>\s?(.*)$/.exec(line))) {
+                flushParagraph();
+                flushList();
+                quote.push(match[1]);
+            } else if ((match = /^(#{1,6})\s+(.*?)\s*#*\s*$/.exec(line))) {
+                flush();
+                const level = match[1].length;
+                out.push('<h' + level + '>' + inline(match[2]) + '</h' + level + '>');
+            } else if (/^\s*([-*_])(\s*\1){2,}\s*$/.test(line)) {
+                flush();
+                out.push('<hr>');
+            } else if ((match = /^\s*(?:([-*+])|(\d+)[.)])\s+(.*)$/.exec(line))) {
+                flushParagraph();
+                flushQuote();
+                const tag = match[1] ? 'ul' : 'ol';
+                if (list && list.tag !== tag) {
+                    flushList();
+                }
+                list = list || { tag: tag, items: [] };
+                list.items.push(match[3]);
+            } else if (list && /^\s+\S/.test(line)) {
+                // 缩进的续行属于上一个列表项
+                list.items[list.items.length - 1] += ' ' + line.trim();
+            } else {
+                flushList();
+                flushQuote();
+                paragraph.push(line.trim());
+            }
+        }
+        flush();
+        return out.join('\n');
+    }
+
+    global.marked = {
+        offline: true,
+        setOptions: function () {},
+        parse: parse
+    };
+})(window);
@@ -1,6 +1,8 @@
 package report
 
 import (
+	"bytes"
+	"context"
 	_ "embed"
 	"encoding/json"
 	"fmt"
@@ -11,7 +13,9 @@ import (
 	"strings"
 	"time"
 
+	"git-log-analyzer/internal/ai"
 	"git-log-analyzer/internal/analyzer"
+	"git-log-analyzer/internal/chart"
 	"git-log-analyzer/internal/developer"
 	"git-log-analyzer/internal/health"
 	"git-log-analyzer/internal/i18n"
@@ -29,9 +33,17 @@ var cssTemplate string
 //go:embed templates/charts.js
 var jsTemplate string
 
+//go:embed templates/offline-charts.js
+var offlineChartsTemplate string
+
+//go:embed templates/offline-markdown.js
+var offlineMarkdownTemplate string
+
 // WebReportGenerator generates HTML reports
 type WebReportGenerator struct {
-	outputDir string
+	outputDir  string
+	live       *LiveFilter
+	singleFile bool
 }
 
 // NewWebReportGenerator creates a new web report generator
@@ -41,30 +53,92 @@ func NewWebReportGenerator(outputDir string) *WebReportGenerator {
 	}
 }
 
+// LiveFilter is the filter panel of a report hosted by the serve command.
+// Dates are YYYY-MM-DD with an inclusive end; authors and paths are comma
+// separated.
+type LiveFilter struct {
+	Since    string
+	Until    string
+	Authors  string
+	Paths    string
+	Endpoint string // URL the panel posts the new filter to as JSON
+	Error    string // why the last re-analysis failed, if it did
+}
+
+// SetLiveFilter adds a filter panel to the reports generated from now on;
+// nil removes it
+func (w *WebReportGenerator) SetLiveFilter(live *LiveFilter) {
+	w.live = live
+}
+
+// SetSingleFile makes the reports generated from now on a single index.html
+// that works offline: the styles and scripts are inlined, a built-in renderer
+// replaces the chart libraries from the CDN and the developer profiles become
+// views of the page
+func (w *WebReportGenerator) SetSingleFile(singleFile bool) {
+	w.singleFile = singleFile
+}
+
 // ReportData contains all data for web report
 type ReportData struct {
-	GeneratedAt         time.Time
-	ProjectName         string
-	Stats               *analyzer.Statistics
-	TopAuthors          []AuthorData
-	HourlyData          []HourData
-	DailyData           []DayData
-	FileData            []FileData
-	CommitTimeline      []TimelineData
-	AIAnalysis          string
-	AIStatus            AIStatus
-	CodeHealthMetrics   *health.CodeHealthMetrics
-	DeveloperProfiles   []*developer.DeveloperProfile
-	Messages            *i18n.Messages
-	Language            i18n.Language
+	GeneratedAt       time.Time
+	ProjectName       string
+	Stats             *analyzer.Statistics
+	TopAuthors        []AuthorData
+	HourlyData        []HourData
+	DailyData         []DayData
+	FileData          []FileData
+	CommitTimeline    []TimelineData
+	AIAnalysis        string                 // Markdown, rendered client-side
+	AIStructured      *ai.StructuredAnalysis // nil in free-text mode
+	AIStatus          AIStatus
+	CodeHealthMetrics *health.CodeHealthMetrics
+	DeveloperProfiles []*developer.DeveloperProfile
+	Live              *LiveFilter // nil for static reports
+	Messages          *i18n.Messages
+	Language          i18n.Language
+	Charts            map[string]template.HTML // static SVG charts by chart name
+	Calendars         []StaticChart            // commit calendar heatmaps, newest year first
+
+	// Inlined content of single-file reports
+	SingleFile      bool
+	InlineStyles    template.CSS
+	ChartLibrary    template.JS // the offline chart renderer
+	MarkdownLibrary template.JS // the offline Markdown renderer for the AI analysis
+	ChartScript     template.JS
+	DeveloperViews  []DeveloperView
+}
+
+// DeveloperView is a developer profile shown inside a single-file report
+type DeveloperView struct {
+	ID   string // element id, the link target is "#" + ID
+	Name string
+	HTML template.HTML
+}
+
+// StaticChart is a titled static SVG chart
+type StaticChart struct {
+	Title string
+	SVG   template.HTML
+}
+
+// profilePageData is the data of the developer profile template
+type profilePageData struct {
+	DeveloperProfile *developer.DeveloperProfile
+	Language         i18n.Language
+	HomeLink         string // where the back buttons lead
+	Messages         *i18n.Messages
+	PunchCard        template.HTML // empty when the author has no commits
+	Calendars        []StaticChart
 }
 
 // AIStatus represents the status of AI analysis
 type AIStatus struct {
-	Enabled       bool
-	Available     bool
-	ErrorType     string // "disabled", "config_error", "analysis_error"
-	ErrorMessage  string
+	Enabled      bool
+	Available    bool
+	ErrorType    string // "disabled", "config_error", "analysis_error"
+	ErrorMessage string
+	Usage        *ai.UsageSummary // nil when no AI client was used
 }
 
 // AuthorData represents author statistics for web display
@@ -100,41 +174,71 @@ type TimelineData struct {
 	Count int
 }
 
-// GenerateReport generates a complete HTML report
-func (w *WebReportGenerator) GenerateReport(stats *analyzer.Statistics, aiAnalysis string, aiStatus AIStatus, projectName string, developerProfiles []*developer.DeveloperProfile) error {
-	// Create output directory
-	if err := os.MkdirAll(w.outputDir, 0755); err != nil {
-		return fmt.Errorf("failed to create output directory: %v", err)
+// reportFile is a rendered file of the report
+type reportFile struct {
+	name string
+	data []byte
+}
+
+// GenerateReport generates a complete HTML report. All files are rendered in
+// memory first and written only if ctx is still live then, so a canceled
+// generation leaves no partial report behind.
+func (w *WebReportGenerator) GenerateReport(ctx context.Context, stats *analyzer.Statistics, aiAnalysis *ai.AnalysisResult, aiStatus AIStatus, projectName string, developerProfiles []*developer.DeveloperProfile) error {
+	if err := ctx.Err(); err != nil {
+		return err
 	}
 
 	// Prepare report data
 	reportData := w.prepareReportData(stats, aiAnalysis, aiStatus, projectName, developerProfiles)
 
-	// Generate HTML report
-	if err := w.generateHTMLReport(reportData); err != nil {
-		return err
+	// A single-file report inlines everything into index.html
+	if w.singleFile {
+		if err := w.inlineAssets(ctx, reportData); err != nil {
+			return err
+		}
 	}
 
-	// Generate developer profile pages
-	if err := w.generateDeveloperProfilePages(reportData); err != nil {
+	// Render HTML report
+	index, err := w.renderHTMLReport(reportData)
+	if err != nil {
 		return err
 	}
+	files := []reportFile{{name: "index.html", data: index}}
 
-	// Generate CSS
-	if err := w.generateCSS(); err != nil {
-		return err
+	if !w.singleFile {
+		// Render developer profile pages
+		pages, err := w.renderDeveloperProfilePages(ctx, reportData)
+		if err != nil {
+			return err
+		}
+		files = append(files, pages...)
+		files = append(files,
+			reportFile{name: "styles.css", data: []byte(cssTemplate)},
+			reportFile{name: "charts.js", data: []byte(jsTemplate)},
+		)
 	}
 
-	// Generate JavaScript
-	if err := w.generateJavaScript(); err != nil {
+	if err := ctx.Err(); err != nil {
 		return err
 	}
+	return w.writeFiles(files)
+}
 
+// writeFiles creates the output directory and writes the rendered files
+func (w *WebReportGenerator) writeFiles(files []reportFile) error {
+	if err := os.MkdirAll(w.outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %v", err)
+	}
+	for _, f := range files {
+		if err := os.WriteFile(filepath.Join(w.outputDir, f.name), f.data, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %v", f.name, err)
+		}
+	}
 	return nil
 }
 
 // prepareReportData prepares data for web report
-func (w *WebReportGenerator) prepareReportData(stats *analyzer.Statistics, aiAnalysis string, aiStatus AIStatus, projectName string, developerProfiles []*developer.DeveloperProfile) *ReportData {
+func (w *WebReportGenerator) prepareReportData(stats *analyzer.Statistics, aiAnalysis *ai.AnalysisResult, aiStatus AIStatus, projectName string, developerProfiles []*developer.DeveloperProfile) *ReportData {
 	lang := i18n.GetLanguage()
 	msg := i18n.GetMessages(lang)
 	
@@ -142,42 +246,25 @@ func (w *WebReportGenerator) prepareReportData(stats *analyzer.Statistics, aiAna
 		GeneratedAt:       time.Now(),
 		ProjectName:       projectName,
 		Stats:             stats,
-		AIAnalysis:        aiAnalysis,
 		AIStatus:          aiStatus,
 		CodeHealthMetrics: stats.CodeHealthMetrics,
 		DeveloperProfiles: developerProfiles,
+		Live:              w.live,
 		Messages:          msg,
 		Language:          lang,
+		Charts:            make(map[string]template.HTML),
 	}
-
-	// Prepare top authors
-	type authorPair struct {
-		key   string
-		stats *analyzer.AuthorStat
+	for _, c := range chart.Render(stats, msg) {
+		data.Charts[c.Name] = template.HTML(c.SVG)
 	}
-	
-	var authors []authorPair
-	for key, stat := range stats.AuthorStats {
-		authors = append(authors, authorPair{key, stat})
+	data.Calendars = staticCharts(chart.Calendars(stats.Activity().Calendar, msg))
+	if aiAnalysis != nil {
+		data.AIAnalysis = aiAnalysis.Text
+		data.AIStructured = aiAnalysis.Structured
 	}
-	
-	sort.Slice(authors, func(i, j int) bool {
-		return authors[i].stats.CommitCount > authors[j].stats.CommitCount
-	})
 
-	for i, author := range authors {
-		if i >= 10 { // Top 10 authors
-			break
-		}
-		percentage := float64(author.stats.CommitCount) / float64(stats.TotalCommits) * 100
-		data.TopAuthors = append(data.TopAuthors, AuthorData{
-			Name:        author.stats.Name,
-			CommitCount: author.stats.CommitCount,
-			Additions:   author.stats.Additions,
-			Deletions:   author.stats.Deletions,
-			Percentage:  percentage,
-		})
-	}
+	// Prepare top authors
+	data.TopAuthors = topAuthors(stats, 10)
 
 	// Prepare hourly data
 	for hour, count := range stats.TimeStats.HourlyPattern {
@@ -190,38 +277,18 @@ func (w *WebReportGenerator) prepareReportData(stats *analyzer.Statistics, aiAna
 		return data.HourlyData[i].Hour < data.HourlyData[j].Hour
 	})
 
-	// Prepare daily data
-	for day, count := range stats.TimeStats.DailyPattern {
-		data.DailyData = append(data.DailyData, DayData{
-			Day:   msg.DayNames[day],
-			Count: count,
-		})
+	// Prepare daily data, Sunday first
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if count, ok := stats.TimeStats.DailyPattern[day]; ok {
+			data.DailyData = append(data.DailyData, DayData{
+				Day:   msg.DayNames[day],
+				Count: count,
+			})
+		}
 	}
 
 	// Prepare file data
-	type filePair struct {
-		file  string
-		count int
-	}
-	
-	var files []filePair
-	for file, count := range stats.FileStats {
-		files = append(files, filePair{file, count})
-	}
-	
-	sort.Slice(files, func(i, j int) bool {
-		return files[i].count > files[j].count
-	})
-
-	for i, f := range files {
-		if i >= 15 { // Top 15 files
-			break
-		}
-		data.FileData = append(data.FileData, FileData{
-			Name:  f.file,
-			Count: f.count,
-		})
-	}
+	data.FileData = topFiles(stats, 15)
 
 	// Prepare timeline data
 	type timelinePair struct {
@@ -248,8 +315,55 @@ func (w *WebReportGenerator) prepareReportData(stats *analyzer.Statistics, aiAna
 	return data
 }
 
-// generateHTMLReport generates the main HTML report
-func (w *WebReportGenerator) generateHTMLReport(data *ReportData) error {
+// topAuthors returns the limit authors with the most commits
+func topAuthors(stats *analyzer.Statistics, limit int) []AuthorData {
+	var authors []*analyzer.AuthorStat
+	for _, stat := range stats.AuthorStats {
+		authors = append(authors, stat)
+	}
+	sort.Slice(authors, func(i, j int) bool {
+		if authors[i].CommitCount != authors[j].CommitCount {
+			return authors[i].CommitCount > authors[j].CommitCount
+		}
+		return authors[i].Name < authors[j].Name
+	})
+
+	var top []AuthorData
+	for i, author := range authors {
+		if i >= limit {
+			break
+		}
+		top = append(top, AuthorData{
+			Name:        author.Name,
+			CommitCount: author.CommitCount,
+			Additions:   author.Additions,
+			Deletions:   author.Deletions,
+			Percentage:  float64(author.CommitCount) / float64(stats.TotalCommits) * 100,
+		})
+	}
+	return top
+}
+
+// topFiles returns the limit most modified files
+func topFiles(stats *analyzer.Statistics, limit int) []FileData {
+	var files []FileData
+	for file, count := range stats.FileStats {
+		files = append(files, FileData{Name: file, Count: count})
+	}
+	sort.Slice(files, func(i, j int) bool {
+		if files[i].Count != files[j].Count {
+			return files[i].Count > files[j].Count
+		}
+		return files[i].Name < files[j].Name
+	})
+	if len(files) > limit {
+		files = files[:limit]
+	}
+	return files
+}
+
+// renderHTMLReport renders the main HTML report
+func (w *WebReportGenerator) renderHTMLReport(data *ReportData) ([]byte, error) {
 	// Create template functions for JSON serialization
 	funcMap := template.FuncMap{
 		"toJSON": func(v interface{}) template.JS {
@@ -294,6 +408,9 @@ func (w *WebReportGenerator) generateHTMLReport(data *ReportData) error {
 		"getDeveloperProfileLink": func(authorName string, profiles []*developer.DeveloperProfile) string {
 			for _, profile := range profiles {
 				if profile.Name == authorName {
+					if w.singleFile {
+						return "#" + developerViewID(profile.Name)
+					}
 					return fmt.Sprintf("developer-%s.html", sanitizeFilename(profile.Name))
 				}
 			}
@@ -312,25 +429,87 @@ func (w *WebReportGenerator) generateHTMLReport(data *ReportData) error {
 	// Read HTML template from embedded content
 	t, err := template.New("report").Funcs(funcMap).Parse(htmlTemplate)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	file, err := os.Create(filepath.Join(w.outputDir, "index.html"))
+	var html bytes.Buffer
+	if err := t.Execute(&html, data); err != nil {
+		return nil, err
+	}
+	return html.Bytes(), nil
+}
+
+// renderDeveloperProfilePages renders individual developer profile pages
+func (w *WebReportGenerator) renderDeveloperProfilePages(ctx context.Context, data *ReportData) ([]reportFile, error) {
+	if len(data.DeveloperProfiles) == 0 {
+		return nil, nil
+	}
+
+	t, err := parseDeveloperProfileTemplate()
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	// Render a page for each developer profile
+	var pages []reportFile
+	for _, profile := range data.DeveloperProfiles {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		// Create profile data structure
+		profileData := newProfilePageData(data, profile, "index.html")
+
+		var page bytes.Buffer
+		if err := t.Execute(&page, profileData); err != nil {
+			return nil, fmt.Errorf("failed to generate developer profile for %s: %v", profile.Name, err)
+		}
+
+		// Create filename based on developer name (sanitized)
+		pages = append(pages, reportFile{
+			name: fmt.Sprintf("developer-%s.html", sanitizeFilename(profile.Name)),
+			data: page.Bytes(),
+		})
 	}
-	defer file.Close()
 
-	return t.Execute(file, data)
+	return pages, nil
 }
 
-// generateDeveloperProfilePages generates individual developer profile pages
-func (w *WebReportGenerator) generateDeveloperProfilePages(data *ReportData) error {
-	if len(data.DeveloperProfiles) == 0 {
-		return nil
+// newProfilePageData returns the data of the profile page of a developer,
+// with the punch card and calendar of their commits
+func newProfilePageData(data *ReportData, profile *developer.DeveloperProfile, homeLink string) profilePageData {
+	page := profilePageData{
+		DeveloperProfile: profile,
+		Language:         data.Language,
+		HomeLink:         homeLink,
+		Messages:         data.Messages,
+	}
+	if data.Stats == nil {
+		return page
 	}
+	author, ok := data.Stats.AuthorStats[fmt.Sprintf("%s <%s>", profile.Name, profile.Email)]
+	if !ok || author.CommitCount == 0 {
+		return page
+	}
+	activity := author.Activity()
+	page.PunchCard = template.HTML(chart.PunchCard(data.Messages.ChartPunchCard, data.Messages.DayNames, activity.PunchCard))
+	page.Calendars = staticCharts(chart.Calendars(activity.Calendar, data.Messages))
+	return page
+}
+
+// staticCharts returns the titles and markup of charts
+func staticCharts(charts []chart.Chart) []StaticChart {
+	var static []StaticChart
+	for _, c := range charts {
+		static = append(static, StaticChart{Title: c.Title, SVG: template.HTML(c.SVG)})
+	}
+	return static
+}
 
-	// Create template functions
+// parseDeveloperProfileTemplate parses the developer profile page. Its
+// "profile-styles" and "profile-body" blocks are also rendered on their own
+// for single-file reports.
+func parseDeveloperProfileTemplate() (*template.Template, error) {
 	funcMap := template.FuncMap{
 		"mul": func(a, b float64) float64 {
 			return a * b
@@ -340,41 +519,82 @@ func (w *WebReportGenerator) generateDeveloperProfilePages(data *ReportData) err
 		},
 	}
 
-	// Parse developer profile template
 	t, err := template.New("developer-profile").Funcs(funcMap).Parse(developerProfileTemplate)
 	if err != nil {
-		return fmt.Errorf("failed to parse developer profile template: %v", err)
+		return nil, fmt.Errorf("failed to parse developer profile template: %v", err)
 	}
+	return t, nil
+}
 
-	// Generate a page for each developer profile
-	for _, profile := range data.DeveloperProfiles {
-		// Create profile data structure
-		profileData := struct {
-			DeveloperProfile *developer.DeveloperProfile
-			Language         i18n.Language
-		}{
-			DeveloperProfile: profile,
-			Language:         data.Language,
-		}
+// inlineAssets fills in the styles, scripts and developer views of a
+// single-file report
+func (w *WebReportGenerator) inlineAssets(ctx context.Context, data *ReportData) error {
+	t, err := parseDeveloperProfileTemplate()
+	if err != nil {
+		return err
+	}
 
-		// Create filename based on developer name (sanitized)
-		filename := fmt.Sprintf("developer-%s.html", sanitizeFilename(profile.Name))
-		filePath := filepath.Join(w.outputDir, filename)
+	var styles bytes.Buffer
+	if err := t.ExecuteTemplate(&styles, "profile-styles", nil); err != nil {
+		return fmt.Errorf("failed to render developer profile styles: %v", err)
+	}
+
+	data.SingleFile = true
+	data.InlineStyles = template.CSS(cssTemplate + "\n" + scopeCSS(styles.String(), ".developer-view"))
+	data.ChartLibrary = inlineScript(offlineChartsTemplate)
+	data.MarkdownLibrary = inlineScript(offlineMarkdownTemplate)
+	data.ChartScript = inlineScript(jsTemplate)
 
-		file, err := os.Create(filePath)
+	for _, profile := range data.DeveloperProfiles {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		var body bytes.Buffer
+		err := t.ExecuteTemplate(&body, "profile-body", newProfilePageData(data, profile, "#"))
 		if err != nil {
-			return fmt.Errorf("failed to create developer profile file %s: %v", filename, err)
+			return fmt.Errorf("failed to generate developer profile for %s: %v", profile.Name, err)
 		}
+		data.DeveloperViews = append(data.DeveloperViews, DeveloperView{
+			ID:   developerViewID(profile.Name),
+			Name: profile.Name,
+			HTML: template.HTML(body.String()),
+		})
+	}
+	return nil
+}
 
-		err = t.Execute(file, profileData)
-		file.Close()
+// developerViewID returns the element id of a developer view
+func developerViewID(name string) string {
+	return "developer-" + sanitizeFilename(name)
+}
 
-		if err != nil {
-			return fmt.Errorf("failed to generate developer profile for %s: %v", profile.Name, err)
+// scopeCSS prefixes every selector of a flat style sheet with scope, so the
+// profile styles do not leak into the rest of a single-file report
+func scopeCSS(css, scope string) string {
+	var out strings.Builder
+	for _, rule := range strings.SplitAfter(css, "}") {
+		open := strings.Index(rule, "{")
+		if open < 0 {
+			out.WriteString(rule)
+			continue
 		}
+		selectors := strings.Split(rule[:open], ",")
+		for i, selector := range selectors {
+			if i > 0 {
+				out.WriteString(",")
+			}
+			trimmed := strings.TrimSpace(selector)
+			out.WriteString(selector[:strings.Index(selector, trimmed)])
+			out.WriteString(scope + " " + trimmed)
+		}
+		out.WriteString(" " + rule[open:])
 	}
+	return out.String()
+}
 
-	return nil
+// inlineScript keeps an inlined script from ending its script element early
+func inlineScript(js string) template.JS {
+	return template.JS(strings.ReplaceAll(js, "</script", "<\\/script"))
 }
 
 // sanitizeFilename sanitizes a string to be safe for use as a filename
@@ -395,18 +615,6 @@ func sanitizeFilename(name string) string {
 	return result
 }
 
-// generateCSS generates the CSS file for the report
-func (w *WebReportGenerator) generateCSS() error {
-	// Use embedded CSS template
-	return os.WriteFile(filepath.Join(w.outputDir, "styles.css"), []byte(cssTemplate), 0644)
-}
-
-// generateJavaScript generates the JavaScript file for charts
-func (w *WebReportGenerator) generateJavaScript() error {
-	// Use embedded JavaScript template
-	return os.WriteFile(filepath.Join(w.outputDir, "charts.js"), []byte(jsTemplate), 0644)
-}
-
 // GetReportPath returns the path to the generated report
 func (w *WebReportGenerator) GetReportPath() string {
 	return filepath.Join(w.outputDir, "index.html")
@@ -0,0 +1,290 @@
+package report
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"git-log-analyzer/internal/ai"
+	"git-log-analyzer/internal/analyzer"
+	"git-log-analyzer/internal/developer"
+	"git-log-analyzer/internal/fixture"
+	"git-log-analyzer/internal/git"
+	"git-log-analyzer/internal/quality"
+)
+
+// analyzeFixture returns statistics for a small two-author history
+func analyzeFixture(t *testing.T) *analyzer.Statistics {
+	t.Helper()
+	day := func(d int) time.Time {
+		return time.Date(2023, 3, d, 10, 0, 0, 0, time.UTC)
+	}
+	repo := fixture.Build(t, fixture.Script{
+		fixture.Commit{Author: fixture.Alice, Date: day(1), Message: "Initial commit",
+			Files: map[string]string{"main.go": fixture.Lines(10)}},
+		fixture.Commit{Author: fixture.Bob, Date: day(2), Message: "Extend main",
+			Files: map[string]string{"main.go": fixture.Lines(12)}},
+		fixture.Commit{Author: fixture.Alice, Date: day(3), Message: "Trim main",
+			Files: map[string]string{"main.go": fixture.Lines(5)}},
+	})
+
+	stats, err := analyzer.NewAnalyzer(repo.Dir).Analyze(context.Background())
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	return stats
+}
+
+func generateIndex(t *testing.T, stats *analyzer.Statistics, result *ai.AnalysisResult) string {
+	t.Helper()
+	dir := t.TempDir()
+	status := AIStatus{Enabled: true, Available: true}
+	if err := NewWebReportGenerator(dir).GenerateReport(context.Background(), stats, result, status, "demo", nil); err != nil {
+		t.Fatalf("GenerateReport failed: %v", err)
+	}
+	html, err := os.ReadFile(filepath.Join(dir, "index.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(html)
+}
+
+func TestGenerateReport_StructuredAI(t *testing.T) {
+	stats := analyzeFixture(t)
+	analysis := &ai.StructuredAnalysis{
+		Summary: "Healthy but concentrated",
+		Risks:   []ai.Risk{{Title: "Bus factor", Severity: "critical", Description: "Only Alice knows main.go"}},
+		Recommendations: []ai.Recommendation{
+			{Title: "Pair on main.go", Files: []string{"main.go"}, Authors: []string{"Bob"}},
+		},
+		Confidence: 0.75,
+	}
+
+	html := generateIndex(t, stats, &ai.AnalysisResult{Structured: analysis, Text: "markdown text"})
+	for _, want := range []string{
+		"Healthy but concentrated",
+		"置信度: 75%",
+		`<span class="severity severity-critical">critical</span>`,
+		"Only Alice knows main.go",
+		`<code class="ai-ref file">main.go</code>`,
+		`<span class="ai-ref author">👤 Bob</span>`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("Report is missing %q", want)
+		}
+	}
+	if strings.Contains(html, `onclick="toggleAIAnalysis()"`) {
+		t.Error("Structured results should not use the free-text card")
+	}
+}
+
+func TestGenerateReport_FreeTextAI(t *testing.T) {
+	stats := analyzeFixture(t)
+
+	html := generateIndex(t, stats, &ai.AnalysisResult{Text: "## Free text insight"})
+	if !strings.Contains(html, `onclick="toggleAIAnalysis()"`) {
+		t.Error("Free-text results should use the collapsible analysis card")
+	}
+	if !strings.Contains(html, "## Free text insight") {
+		t.Error("Report is missing the free-text analysis")
+	}
+	if strings.Contains(html, "severity-") {
+		t.Error("Free-text results should not render structured sections")
+	}
+}
+
+func TestGenerateReport_MessageQuality(t *testing.T) {
+	stats := analyzeFixture(t)
+
+	html := generateIndex(t, stats, nil)
+	if !strings.Contains(html, `<li class="menu-item disabled" data-section="quality">`) {
+		t.Error("The quality section should stay disabled without message scores")
+	}
+
+	scorer := quality.NewMessageScorer()
+	scorer.AddCommit(&git.GitCommit{Hash: "0123456789abcdef", Author: "Bob", Email: "bob@example.com", Subject: "wip"})
+	stats.MessageQuality = scorer.Result()
+
+	html = generateIndex(t, stats, nil)
+	for _, want := range []string{
+		`<li class="menu-item" data-section="quality">`,
+		"质量最差的提交信息",
+		"<code>01234567</code> wip",
+		"描述过于笼统",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("Report is missing %q", want)
+		}
+	}
+}
+
+func TestGenerateReport_AIUsageFooter(t *testing.T) {
+	stats := analyzeFixture(t)
+
+	if html := generateIndex(t, stats, nil); strings.Contains(html, "report-footer\">") {
+		t.Error("The usage footer should only be shown when AI was used")
+	}
+
+	dir := t.TempDir()
+	usage := &ai.UsageSummary{Calls: 2, CachedCalls: 1, PromptTokens: 1200, CompletionTokens: 300, Cost: 0.0012, CostKnown: true}
+	status := AIStatus{Enabled: true, Available: true, Usage: usage}
+	if err := NewWebReportGenerator(dir).GenerateReport(context.Background(), stats, nil, status, "demo", nil); err != nil {
+		t.Fatalf("GenerateReport failed: %v", err)
+	}
+	html, err := os.ReadFile(filepath.Join(dir, "index.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"2 次调用 (另有 1 次命中缓存)", "1500 tokens (输入 1200 / 输出 300)", "预估费用 $0.0012"} {
+		if !strings.Contains(string(html), want) {
+			t.Errorf("Footer is missing %q", want)
+		}
+	}
+}
+
+func TestGenerateReport_Calendar(t *testing.T) {
+	html := generateIndex(t, analyzeFixture(t), nil)
+
+	start := strings.Index(html, `<section id="timeline-section"`)
+	end := strings.Index(html[start:], "</section>")
+	if start < 0 || end < 0 {
+		t.Fatal("Report is missing the timeline section")
+	}
+	section := html[start : start+end]
+	if strings.Count(section, "<svg") != 1 || strings.Contains(section, "coming-soon") {
+		t.Error("Expected the timeline section to show the calendar of 2023")
+	}
+	if !strings.Contains(section, "<title>2023-03-02: 1</title>") {
+		t.Error("Expected a tooltip with the commits of each day")
+	}
+	if strings.Contains(html, `<li class="menu-item disabled" data-section="timeline">`) {
+		t.Error("Expected the timeline menu item to be enabled")
+	}
+}
+
+func TestGenerateReport_DeveloperNarrative(t *testing.T) {
+	stats := analyzeFixture(t)
+	profiles, err := developer.NewProfileAnalyzer(stats).AnalyzeAllDevelopers(context.Background())
+	if err != nil {
+		t.Fatalf("AnalyzeAllDevelopers failed: %v", err)
+	}
+	for _, p := range profiles {
+		if p.Name == fixture.Alice.Name {
+			p.AINarrative = &developer.AINarrative{Narrative: "Alice keeps main lean.", Suggestions: []string{"Add tests"}}
+		}
+	}
+
+	dir := t.TempDir()
+	status := AIStatus{Enabled: true, Available: true}
+	if err := NewWebReportGenerator(dir).GenerateReport(context.Background(), stats, nil, status, "demo", profiles); err != nil {
+		t.Fatalf("GenerateReport failed: %v", err)
+	}
+
+	for _, p := range profiles {
+		html, err := os.ReadFile(filepath.Join(dir, "developer-"+sanitizeFilename(p.Name)+".html"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		shown := strings.Contains(string(html), "AI 画像解读")
+		if want := p.AINarrative != nil; shown != want {
+			t.Errorf("%s: expected the narrative shown = %v", p.Name, want)
+		}
+		if p.AINarrative != nil && (!strings.Contains(string(html), "Alice keeps main lean.") || !strings.Contains(string(html), "<li>Add tests</li>")) {
+			t.Errorf("%s: the narrative or suggestions are missing", p.Name)
+		}
+		if n := strings.Count(string(html), "<svg"); !strings.Contains(string(html), "提交活动") || n != 2 {
+			t.Errorf("%s: expected the punch card and one calendar year, got %d charts", p.Name, n)
+		}
+	}
+}
+
+// cancelAfter is a context that is canceled once Err has been called n times
+type cancelAfter struct {
+	context.Context
+	n int
+}
+
+func (c *cancelAfter) Err() error {
+	if c.n--; c.n < 0 {
+		return context.Canceled
+	}
+	return nil
+}
+
+func TestGenerateReport_CanceledWritesNothing(t *testing.T) {
+	stats := analyzeFixture(t)
+	profiles, err := developer.NewProfileAnalyzer(stats).AnalyzeAllDevelopers(context.Background())
+	if err != nil {
+		t.Fatalf("AnalyzeAllDevelopers failed: %v", err)
+	}
+
+	// Canceled while the second developer page is rendered
+	dir := filepath.Join(t.TempDir(), "web")
+	ctx := &cancelAfter{Context: context.Background(), n: 2}
+	err = NewWebReportGenerator(dir).GenerateReport(ctx, stats, nil, AIStatus{ErrorType: "disabled"}, "demo", profiles)
+	if err != context.Canceled {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("Expected no output after cancellation, got %v", err)
+	}
+}
+
+func TestGenerateReport_SingleFile(t *testing.T) {
+	stats := analyzeFixture(t)
+	profiles, err := developer.NewProfileAnalyzer(stats).AnalyzeAllDevelopers(context.Background())
+	if err != nil {
+		t.Fatalf("AnalyzeAllDevelopers failed: %v", err)
+	}
+
+	dir := t.TempDir()
+	gen := NewWebReportGenerator(dir)
+	gen.SetSingleFile(true)
+	if err := gen.GenerateReport(context.Background(), stats, nil, AIStatus{ErrorType: "disabled"}, "demo", profiles); err != nil {
+		t.Fatalf("GenerateReport failed: %v", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "index.html" {
+		t.Fatalf("Expected only index.html, got %v", entries)
+	}
+	data, err := os.ReadFile(gen.GetReportPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	html := string(data)
+
+	for _, external := range []string{"<script src=", "<link ", `href="styles.css"`, "developer-Alice.html"} {
+		if strings.Contains(html, external) {
+			t.Errorf("Single-file report still references %q", external)
+		}
+	}
+	for _, want := range []string{"Chart.offline = true", "global.marked = {", "function initCharts(", ".developer-view .profile-header {"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("Report is missing the inlined %q", want)
+		}
+	}
+	for _, p := range profiles {
+		id := "developer-" + sanitizeFilename(p.Name)
+		if !strings.Contains(html, `href="#`+id+`"`) || !strings.Contains(html, `<section class="developer-view" id="`+id+`" hidden>`) {
+			t.Errorf("%s: the profile view or its link is missing", p.Name)
+		}
+	}
+	if !strings.Contains(html, `<a href="#" class="back-button">`) {
+		t.Error("Profile views should link back within the page")
+	}
+}
+
+func TestScopeCSS(t *testing.T) {
+	got := scopeCSS("\n  .a, .b h1 {\n    color: red;\n  }\n  .c:hover { x: y; }\n", ".v")
+	want := "\n  .v .a, .v .b h1 {\n    color: red;\n  }\n  .v .c:hover { x: y; }\n"
+	if got != want {
+		t.Errorf("scopeCSS = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,185 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"git-log-analyzer/internal/analyzer"
+)
+
+// dateLayout is the date format of filters
+const dateLayout = "2006-01-02"
+
+// FilterRequest is the JSON form of a filter. Dates are YYYY-MM-DD in local
+// time and Until is inclusive. Authors and paths are arrays or comma
+// separated strings, as posted by the report's filter panel.
+type FilterRequest struct {
+	Since   string `json:"since,omitempty"`
+	Until   string `json:"until,omitempty"`
+	Authors list   `json:"authors,omitempty"`
+	Paths   list   `json:"paths,omitempty"`
+}
+
+// list is a string array that also decodes from a comma separated string
+type list []string
+
+func (l *list) UnmarshalJSON(data []byte) error {
+	var values []string
+	if err := json.Unmarshal(data, &values); err != nil {
+		var joined string
+		if json.Unmarshal(data, &joined) != nil {
+			return fmt.Errorf("expected a string or an array of strings")
+		}
+		values = strings.Split(joined, ",")
+	}
+
+	*l = nil
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			*l = append(*l, v)
+		}
+	}
+	return nil
+}
+
+// Filter converts the request into an analyzer filter
+func (req FilterRequest) Filter() (analyzer.Filter, error) {
+	filter := analyzer.Filter{Authors: req.Authors, Paths: req.Paths}
+	if s := strings.TrimSpace(req.Since); s != "" {
+		since, err := time.ParseInLocation(dateLayout, s, time.Local)
+		if err != nil {
+			return filter, fmt.Errorf("invalid since date %q (expected YYYY-MM-DD)", s)
+		}
+		filter.Since = since
+	}
+	if s := strings.TrimSpace(req.Until); s != "" {
+		until, err := time.ParseInLocation(dateLayout, s, time.Local)
+		if err != nil {
+			return filter, fmt.Errorf("invalid until date %q (expected YYYY-MM-DD)", s)
+		}
+		filter.Until = until.AddDate(0, 0, 1)
+	}
+	if !filter.Since.IsZero() && !filter.Until.IsZero() && !filter.Since.Before(filter.Until) {
+		return filter, fmt.Errorf("since date must not be after until date")
+	}
+	return filter, nil
+}
+
+// requestFromFilter is the inverse of FilterRequest.Filter
+func requestFromFilter(filter analyzer.Filter) FilterRequest {
+	req := FilterRequest{Authors: filter.Authors, Paths: filter.Paths}
+	if !filter.Since.IsZero() {
+		req.Since = filter.Since.Format(dateLayout)
+	}
+	if !filter.Until.IsZero() {
+		req.Until = filter.Until.AddDate(0, 0, -1).Format(dateLayout)
+	}
+	return req
+}
+
+// authorView is an author in /api/authors
+type authorView struct {
+	Name        string    `json:"name"`
+	Email       string    `json:"email"`
+	Commits     int       `json:"commits"`
+	Additions   int       `json:"additions"`
+	Deletions   int       `json:"deletions"`
+	Files       int       `json:"files"`
+	FirstCommit time.Time `json:"firstCommit"`
+	LastCommit  time.Time `json:"lastCommit"`
+}
+
+// authors lists the authors by commits, most active first
+func authors(stats *analyzer.Statistics) []authorView {
+	views := make([]authorView, 0, len(stats.AuthorStats))
+	for _, a := range stats.AuthorStats {
+		views = append(views, authorView{
+			Name:        a.Name,
+			Email:       a.Email,
+			Commits:     a.CommitCount,
+			Additions:   a.Additions,
+			Deletions:   a.Deletions,
+			Files:       len(a.Files),
+			FirstCommit: a.FirstCommit,
+			LastCommit:  a.LastCommit,
+		})
+	}
+	sort.Slice(views, func(i, j int) bool {
+		if views[i].Commits != views[j].Commits {
+			return views[i].Commits > views[j].Commits
+		}
+		return views[i].Name < views[j].Name
+	})
+	return views
+}
+
+// fileView is a file in /api/files
+type fileView struct {
+	Path    string `json:"path"`
+	Changes int    `json:"changes"`
+}
+
+// files lists the most changed files; limit 0 lists all
+func files(stats *analyzer.Statistics, limit int) []fileView {
+	views := make([]fileView, 0, len(stats.FileStats))
+	for path, changes := range stats.FileStats {
+		views = append(views, fileView{Path: path, Changes: changes})
+	}
+	sort.Slice(views, func(i, j int) bool {
+		if views[i].Changes != views[j].Changes {
+			return views[i].Changes > views[j].Changes
+		}
+		return views[i].Path < views[j].Path
+	})
+	if limit > 0 && len(views) > limit {
+		views = views[:limit]
+	}
+	return views
+}
+
+// dayView is a day in /api/timeline
+type dayView struct {
+	Date    string `json:"date"`
+	Commits int    `json:"commits"`
+}
+
+// timeline lists the commits per day in date order
+func timeline(stats *analyzer.Statistics) []dayView {
+	views := make([]dayView, 0, len(stats.CommitFrequency))
+	for date, commits := range stats.CommitFrequency {
+		views = append(views, dayView{Date: date, Commits: commits})
+	}
+	sort.Slice(views, func(i, j int) bool {
+		return views[i].Date < views[j].Date
+	})
+	return views
+}
+
+// activity returns the punch card and calendar of the repository, or of the
+// authors whose name or email is author (ignoring case). Authors with
+// several emails are added up; no match gives an empty activity.
+func activity(stats *analyzer.Statistics, author string) analyzer.Activity {
+	author = strings.TrimSpace(author)
+	if author == "" {
+		return stats.Activity()
+	}
+	var punchCard [7][24]int
+	frequency := make(map[string]int)
+	for _, a := range stats.AuthorStats {
+		if !strings.EqualFold(a.Name, author) && !strings.EqualFold(a.Email, author) {
+			continue
+		}
+		for day := range a.PunchCard {
+			for hour, count := range a.PunchCard[day] {
+				punchCard[day][hour] += count
+			}
+		}
+		for date, count := range a.CommitFrequency {
+			frequency[date] += count
+		}
+	}
+	return analyzer.Activity{PunchCard: punchCard, Calendar: analyzer.Calendar(frequency)}
+}
@@ -0,0 +1,268 @@
+// Package server hosts the HTML report over HTTP along with the analysis as
+// JSON endpoints. Changing the filter in the page re-runs the analysis on
+// the server and regenerates the report.
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"git-log-analyzer/internal/analyzer"
+	"git-log-analyzer/internal/developer"
+	"git-log-analyzer/internal/report"
+)
+
+// AnalyzeFunc analyzes the repository restricted to a filter
+type AnalyzeFunc func(ctx context.Context, filter analyzer.Filter) (*analyzer.Statistics, error)
+
+// maxProfiles is the number of most active developers with a profile page
+const maxProfiles = 10
+
+// FilterEndpoint is the path the report's filter panel posts to
+const FilterEndpoint = "/api/filter"
+
+// Server serves one repository's report and analysis
+type Server struct {
+	analyze     AnalyzeFunc
+	generator   *report.WebReportGenerator
+	dir         string
+	projectName string
+
+	// analyzing serializes analyses; mu guards the current analysis and the
+	// report files while they are replaced
+	analyzing sync.Mutex
+	mu        sync.RWMutex
+	stats     *analyzer.Statistics
+	filter    analyzer.Filter
+
+	// Log, when set, receives a line for every analysis
+	Log io.Writer
+
+	// Addr is the address the server listens on. Requests must name it,
+	// localhost or an IP address in their Host header, so a page on another
+	// domain cannot read the analysis through DNS rebinding.
+	Addr string
+}
+
+// New creates a server writing the report to dir
+func New(analyze AnalyzeFunc, dir, projectName string) *Server {
+	return &Server{
+		analyze:     analyze,
+		generator:   report.NewWebReportGenerator(dir),
+		dir:         dir,
+		projectName: projectName,
+	}
+}
+
+// Refresh analyzes the repository with filter and regenerates the report.
+// On failure the previous analysis is kept and the report shows the error.
+func (s *Server) Refresh(ctx context.Context, filter analyzer.Filter) error {
+	s.analyzing.Lock()
+	defer s.analyzing.Unlock()
+
+	start := time.Now()
+	stats, err := s.analyze(ctx, filter)
+	if err != nil {
+		s.logf("❌ 分析失败 (%s): %v", filter, err)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.stats != nil {
+			// Show the error above the previous analysis
+			s.generateReport(ctx, s.stats, s.filter, err.Error())
+		}
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.generateReport(ctx, stats, filter, ""); err != nil {
+		return fmt.Errorf("failed to generate report: %v", err)
+	}
+	s.stats, s.filter = stats, filter
+	s.logf("✅ 已分析 %d 个提交 (%s), 用时 %v", stats.TotalCommits, filter, time.Since(start).Round(time.Millisecond))
+	return nil
+}
+
+// generateReport writes the report of stats; the caller holds mu
+func (s *Server) generateReport(ctx context.Context, stats *analyzer.Statistics, filter analyzer.Filter, failure string) error {
+	profiles, err := developer.NewProfileAnalyzer(stats).AnalyzeAllDevelopers(ctx)
+	if err != nil {
+		return err
+	}
+	if len(profiles) > maxProfiles {
+		profiles = profiles[:maxProfiles]
+	}
+
+	live := requestFromFilter(filter)
+	s.generator.SetLiveFilter(&report.LiveFilter{
+		Since:    live.Since,
+		Until:    live.Until,
+		Authors:  strings.Join(live.Authors, ", "),
+		Paths:    strings.Join(live.Paths, ", "),
+		Endpoint: FilterEndpoint,
+		Error:    failure,
+	})
+	return s.generator.GenerateReport(ctx, stats, nil, report.AIStatus{ErrorType: "disabled"}, s.projectName, profiles)
+}
+
+func (s *Server) logf(format string, args ...any) {
+	if s.Log != nil {
+		fmt.Fprintf(s.Log, format+"\n", args...)
+	}
+}
+
+// Handler returns the HTTP handler serving the report and the API:
+//
+//	GET  /api/stats      the whole analysis
+//	GET  /api/authors    authors by commits
+//	GET  /api/files      files by changes (?limit=N)
+//	GET  /api/timeline   commits per day
+//	GET  /api/health     code health metrics
+//	GET  /api/activity   punch card and calendar heatmap (?author=name or email)
+//	GET  /api/filter     the current filter
+//	POST /api/filter     re-analyze with a new filter
+//
+// Requests for another host are rejected, and POST requests must be
+// same-origin JSON so other sites cannot trigger an analysis.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/stats", s.get(func(r *http.Request, stats *analyzer.Statistics) any { return stats }))
+	mux.HandleFunc("/api/authors", s.get(func(r *http.Request, stats *analyzer.Statistics) any { return authors(stats) }))
+	mux.HandleFunc("/api/files", s.get(func(r *http.Request, stats *analyzer.Statistics) any {
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		return files(stats, limit)
+	}))
+	mux.HandleFunc("/api/timeline", s.get(func(r *http.Request, stats *analyzer.Statistics) any { return timeline(stats) }))
+	mux.HandleFunc("/api/health", s.get(func(r *http.Request, stats *analyzer.Statistics) any { return stats.CodeHealthMetrics }))
+	mux.HandleFunc("/api/activity", s.get(func(r *http.Request, stats *analyzer.Statistics) any {
+		return activity(stats, r.URL.Query().Get("author"))
+	}))
+	mux.HandleFunc(FilterEndpoint, s.handleFilter)
+
+	static := http.FileServer(http.Dir(s.dir))
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		// The report changes with every analysis
+		w.Header().Set("Cache-Control", "no-store")
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+		static.ServeHTTP(w, r)
+	})
+	return s.guard(mux)
+}
+
+// guard rejects requests for a foreign host and cross-site POST requests
+// before they reach next
+func (s *Server) guard(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !s.allowedHost(r.Host) {
+			writeError(w, http.StatusForbidden, fmt.Errorf("host %q not allowed", r.Host))
+			return
+		}
+		if r.Method == http.MethodPost {
+			if origin := r.Header.Get("Origin"); origin != "" {
+				if u, err := url.Parse(origin); err != nil || !strings.EqualFold(u.Host, r.Host) {
+					writeError(w, http.StatusForbidden, fmt.Errorf("origin %q not allowed", origin))
+					return
+				}
+			}
+			if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType != "application/json" {
+				writeError(w, http.StatusUnsupportedMediaType, fmt.Errorf("content type must be application/json"))
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// allowedHost reports whether a Host header names this server: localhost,
+// an IP address or the host of Addr. DNS rebinding always comes with the
+// attacker's domain name, never with an IP address.
+func (s *Server) allowedHost(host string) bool {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.ToLower(strings.Trim(host, "[]"))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") || net.ParseIP(host) != nil {
+		return true
+	}
+	if s.Addr == "" {
+		return false
+	}
+	listen, _, err := net.SplitHostPort(s.Addr)
+	if err != nil {
+		listen = s.Addr
+	}
+	return listen != "" && strings.EqualFold(host, listen)
+}
+
+// get serves a view of the current analysis as JSON
+func (s *Server) get(view func(r *http.Request, stats *analyzer.Statistics) any) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+			return
+		}
+		s.mu.RLock()
+		stats := s.stats
+		s.mu.RUnlock()
+		if stats == nil {
+			writeError(w, http.StatusServiceUnavailable, fmt.Errorf("analysis not ready"))
+			return
+		}
+		writeJSON(w, http.StatusOK, view(r, stats))
+	}
+}
+
+// handleFilter returns the current filter or re-analyzes with a new one
+func (s *Server) handleFilter(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		s.mu.RLock()
+		filter := s.filter
+		s.mu.RUnlock()
+		writeJSON(w, http.StatusOK, requestFromFilter(filter))
+
+	case http.MethodPost:
+		var req FilterRequest
+		if err := json.NewDecoder(io.LimitReader(r.Body, 1<<20)).Decode(&req); err != nil && err != io.EOF {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid filter: %v", err))
+			return
+		}
+		filter, err := req.Filter()
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		if err := s.Refresh(r.Context(), filter); err != nil {
+			writeError(w, http.StatusUnprocessableEntity, err)
+			return
+		}
+		s.mu.RLock()
+		commits := s.stats.TotalCommits
+		s.mu.RUnlock()
+		writeJSON(w, http.StatusOK, map[string]any{"filter": requestFromFilter(filter), "commits": commits})
+
+	default:
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,226 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"git-log-analyzer/internal/analyzer"
+	"git-log-analyzer/internal/fixture"
+)
+
+// newTestServer serves a history of Alice's and Bob's commits
+func newTestServer(t *testing.T) (*Server, *httptest.Server) {
+	t.Helper()
+	day := func(d int) time.Time {
+		return time.Date(2024, 3, d, 12, 0, 0, 0, time.Local)
+	}
+	repo := fixture.Build(t, fixture.Script{
+		fixture.Commit{Author: fixture.Alice, Date: day(1), Message: "Add main", Files: map[string]string{"main.go": fixture.Lines(5)}},
+		fixture.Commit{Author: fixture.Bob, Date: day(2), Message: "Add docs", Files: map[string]string{"docs/intro.md": fixture.Lines(3)}},
+		fixture.Commit{Author: fixture.Alice, Date: day(3), Message: "Grow main", Files: map[string]string{"main.go": fixture.Lines(8)}},
+	})
+
+	analyze := func(ctx context.Context, filter analyzer.Filter) (*analyzer.Statistics, error) {
+		return analyzer.NewAnalyzerWithOptions(repo.Dir, analyzer.Options{Filter: filter}).Analyze(ctx)
+	}
+	s := New(analyze, t.TempDir(), "shop")
+	if err := s.Refresh(context.Background(), analyzer.Filter{}); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+	ts := httptest.NewServer(s.Handler())
+	t.Cleanup(ts.Close)
+	return s, ts
+}
+
+// request sends a request and decodes the JSON response into v
+func request(t *testing.T, method, url, body string, v any) int {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if method == http.MethodPost {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s failed: %v", method, url, err)
+	}
+	defer resp.Body.Close()
+	if v != nil {
+		if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+			t.Fatalf("%s %s returned invalid JSON: %v", method, url, err)
+		}
+	}
+	return resp.StatusCode
+}
+
+func TestServer_Report(t *testing.T) {
+	_, ts := newTestServer(t)
+
+	resp, err := http.Get(ts.URL + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	page, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK || !strings.Contains(string(page), `data-endpoint="/api/filter"`) {
+		t.Errorf("Expected the report with the filter panel, got %d", resp.StatusCode)
+	}
+	if resp.Header.Get("Cache-Control") != "no-store" {
+		t.Error("Expected the report not to be cached")
+	}
+}
+
+func TestServer_API(t *testing.T) {
+	_, ts := newTestServer(t)
+
+	var authors []authorView
+	if status := request(t, "GET", ts.URL+"/api/authors", "", &authors); status != http.StatusOK || len(authors) != 2 {
+		t.Fatalf("Expected 2 authors, got %d: %+v", status, authors)
+	}
+	if authors[0].Name != "Alice" || authors[0].Commits != 2 || authors[0].Files != 1 {
+		t.Errorf("Expected Alice first, got %+v", authors[0])
+	}
+
+	var files []fileView
+	request(t, "GET", ts.URL+"/api/files?limit=1", "", &files)
+	if !reflect.DeepEqual(files, []fileView{{Path: "main.go", Changes: 2}}) {
+		t.Errorf("Unexpected files %+v", files)
+	}
+
+	var days []dayView
+	request(t, "GET", ts.URL+"/api/timeline", "", &days)
+	if len(days) != 3 || days[0].Date != "2024-03-01" {
+		t.Errorf("Unexpected timeline %+v", days)
+	}
+
+	// 2024-03-01 is a Friday, 03-03 a Sunday
+	var activity analyzer.Activity
+	request(t, "GET", ts.URL+"/api/activity?author=ALICE@example.com", "", &activity)
+	if activity.PunchCard[time.Friday][12] != 1 || activity.PunchCard[time.Sunday][12] != 1 || activity.PunchCard[time.Saturday][12] != 0 {
+		t.Errorf("Unexpected punch card of Alice %v", activity.PunchCard)
+	}
+	if len(activity.Calendar) != 1 || activity.Calendar[0].Year != 2024 || activity.Calendar[0].Total != 2 {
+		t.Errorf("Unexpected calendar of Alice %+v", activity.Calendar)
+	}
+	request(t, "GET", ts.URL+"/api/activity", "", &activity)
+	if len(activity.Calendar) != 1 || activity.Calendar[0].Total != 3 || activity.PunchCard[time.Saturday][12] != 1 {
+		t.Errorf("Unexpected repository activity %+v", activity)
+	}
+
+	var stats struct{ TotalCommits int }
+	if request(t, "GET", ts.URL+"/api/stats", "", &stats); stats.TotalCommits != 3 {
+		t.Errorf("Expected 3 commits, got %d", stats.TotalCommits)
+	}
+
+	if status := request(t, "DELETE", ts.URL+"/api/stats", "", nil); status != http.StatusMethodNotAllowed {
+		t.Errorf("Expected 405, got %d", status)
+	}
+}
+
+func TestServer_Filter(t *testing.T) {
+	s, ts := newTestServer(t)
+
+	var result struct {
+		Filter  FilterRequest `json:"filter"`
+		Commits int           `json:"commits"`
+	}
+	status := request(t, "POST", ts.URL+"/api/filter", `{"since": "2024-03-02", "until": "2024-03-03", "authors": "alice, "}`, &result)
+	if status != http.StatusOK || result.Commits != 1 {
+		t.Fatalf("Expected Alice's commit of March 3, got %d: %+v", status, result)
+	}
+
+	var current FilterRequest
+	request(t, "GET", ts.URL+"/api/filter", "", &current)
+	want := FilterRequest{Since: "2024-03-02", Until: "2024-03-03", Authors: list{"alice"}}
+	if !reflect.DeepEqual(current, want) {
+		t.Errorf("Expected the current filter %+v, got %+v", want, current)
+	}
+
+	// A filter without commits keeps the previous analysis
+	var failure map[string]string
+	if status := request(t, "POST", ts.URL+"/api/filter", `{"authors": ["dave"]}`, &failure); status != http.StatusUnprocessableEntity || !strings.Contains(failure["error"], "no commits match") {
+		t.Errorf("Expected 422 for an empty result, got %d: %v", status, failure)
+	}
+	if s.stats.TotalCommits != 1 {
+		t.Errorf("Expected the previous analysis to be kept, got %d commits", s.stats.TotalCommits)
+	}
+	resp, err := http.Get(ts.URL + "/index.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	page, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if !strings.Contains(string(page), "no commits match the filter") {
+		t.Error("Expected the report to show the failure")
+	}
+
+	if status := request(t, "POST", ts.URL+"/api/filter", `{"since": "March"}`, &failure); status != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an invalid date, got %d", status)
+	}
+
+	// An empty body resets the filter
+	if request(t, "POST", ts.URL+"/api/filter", "", &result); result.Commits != 3 {
+		t.Errorf("Expected the reset to analyze all commits, got %d", result.Commits)
+	}
+}
+
+func TestServer_RejectsForeignRequests(t *testing.T) {
+	s, ts := newTestServer(t)
+	s.Addr = "devbox:8080"
+
+	send := func(method, path, host string, header map[string]string) int {
+		t.Helper()
+		req, err := http.NewRequest(method, ts.URL+path, strings.NewReader(`{"authors": "bob"}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if host != "" {
+			req.Host = host
+		}
+		for key, value := range header {
+			req.Header.Set(key, value)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	jsonType := map[string]string{"Content-Type": "application/json"}
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		host   string
+		header map[string]string
+		want   int
+	}{
+		{"rebound domain", "GET", "/api/stats", "attacker.example:8080", nil, http.StatusForbidden},
+		{"rebound report", "GET", "/", "attacker.example", nil, http.StatusForbidden},
+		{"localhost", "GET", "/api/stats", "localhost:8080", nil, http.StatusOK},
+		{"listen address", "GET", "/api/stats", "DEVBOX:8080", nil, http.StatusOK},
+		{"form post", "POST", "/api/filter", "", map[string]string{"Content-Type": "text/plain"}, http.StatusUnsupportedMediaType},
+		{"cross-site post", "POST", "/api/filter", "", map[string]string{"Content-Type": "application/json", "Origin": "https://attacker.example"}, http.StatusForbidden},
+		{"same-origin post", "POST", "/api/filter", "", map[string]string{"Content-Type": "application/json", "Origin": ts.URL}, http.StatusOK},
+		{"json post", "POST", "/api/filter", "", jsonType, http.StatusOK},
+	}
+	for _, tt := range tests {
+		if got := send(tt.method, tt.path, tt.host, tt.header); got != tt.want {
+			t.Errorf("%s: expected %d, got %d", tt.name, tt.want, got)
+		}
+	}
+	if s.stats.TotalCommits != 1 {
+		t.Errorf("Expected only the allowed posts to filter the analysis, got %d commits", s.stats.TotalCommits)
+	}
+}
@@ -0,0 +1,690 @@
+// Package tui is an interactive terminal dashboard of an analysis, for
+// working over SSH where the HTML report cannot be opened. It has tabs for
+// the overview, contributors, files, code health, branches and developer
+// profiles; the tables can be sorted by any column, and authors and files
+// open into drill-downs of each other.
+package tui
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"git-log-analyzer/internal/analyzer"
+	"git-log-analyzer/internal/developer"
+	"git-log-analyzer/internal/health"
+	"git-log-analyzer/internal/i18n"
+)
+
+// Tabs of the dashboard
+const (
+	tabOverview = iota
+	tabContributors
+	tabFiles
+	tabHealth
+	tabBranches
+	tabDevelopers
+	tabCount
+)
+
+// view is the content of a tab or a drill-down: intro lines above a table,
+// or scrollable text
+type view struct {
+	title  string
+	intro  []line
+	table  *table
+	text   []line
+	offset int
+	open   func(key string) *view // drill-down of the selected row, if any
+}
+
+// Dashboard is the state of the terminal dashboard. It is driven by key
+// presses and drawn by View, independently of the terminal.
+type Dashboard struct {
+	stats     *analyzer.Statistics
+	profiles  []*developer.DeveloperProfile
+	msg       *i18n.Messages
+	project   string
+	fileStats map[string][]fileAuthor // file -> authors, most changes first
+
+	tab   int
+	views [tabCount]*view
+	stack []*view // open drill-downs, innermost last
+	page  int     // rows per page on the last drawn screen
+}
+
+// fileAuthor is the number of changes of an author to a file
+type fileAuthor struct {
+	key     string // key of the author in Statistics.AuthorStats
+	changes int
+}
+
+// NewDashboard creates a dashboard of stats and the developer profiles
+func NewDashboard(stats *analyzer.Statistics, profiles []*developer.DeveloperProfile, msg *i18n.Messages, project string) *Dashboard {
+	d := &Dashboard{
+		stats:     stats,
+		profiles:  profiles,
+		msg:       msg,
+		project:   project,
+		fileStats: make(map[string][]fileAuthor),
+		page:      10,
+	}
+	for _, key := range d.authorKeys() {
+		for file, changes := range stats.AuthorStats[key].Files {
+			d.fileStats[file] = append(d.fileStats[file], fileAuthor{key: key, changes: changes})
+		}
+	}
+	for _, authors := range d.fileStats {
+		sort.SliceStable(authors, func(i, j int) bool {
+			return authors[i].changes > authors[j].changes
+		})
+	}
+
+	d.views[tabOverview] = &view{}
+	d.views[tabContributors] = d.contributorsView()
+	d.views[tabFiles] = d.filesView()
+	d.views[tabHealth] = d.healthView()
+	d.views[tabBranches] = d.branchesView()
+	d.views[tabDevelopers] = d.developersView()
+	return d
+}
+
+// authorKeys returns the keys of the authors in order
+func (d *Dashboard) authorKeys() []string {
+	keys := make([]string, 0, len(d.stats.AuthorStats))
+	for key := range d.stats.AuthorStats {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// current returns the innermost drill-down or the current tab
+func (d *Dashboard) current() *view {
+	if n := len(d.stack); n > 0 {
+		return d.stack[n-1]
+	}
+	return d.views[d.tab]
+}
+
+// HandleKey applies a key press and reports whether the user quit
+func (d *Dashboard) HandleKey(key Key) bool {
+	v := d.current()
+	switch key {
+	case "q", "Q", KeyCtrlC:
+		return true
+	case KeyEscape, KeyBack:
+		if n := len(d.stack); n > 0 {
+			d.stack = d.stack[:n-1]
+		}
+	case KeyRight, KeyTab, "l":
+		d.switchTab(d.tab + 1)
+	case KeyLeft, KeyBackTab, "h":
+		d.switchTab(d.tab + tabCount - 1)
+	case KeyUp, "k":
+		v.move(-1)
+	case KeyDown, "j":
+		v.move(1)
+	case KeyPageUp:
+		v.move(-d.page)
+	case KeyPageDown, " ":
+		v.move(d.page)
+	case KeyHome, "g":
+		v.move(math.MinInt32)
+	case KeyEnd, "G":
+		v.move(math.MaxInt32)
+	case "s":
+		if v.table != nil {
+			v.table.cycleSort()
+		}
+	case "r":
+		if v.table != nil {
+			v.table.reverse()
+		}
+	case KeyEnter:
+		if v.table == nil || v.open == nil {
+			break
+		}
+		if key, ok := v.table.selectedKey(); ok {
+			if next := v.open(key); next != nil {
+				d.stack = append(d.stack, next)
+			}
+		}
+	default:
+		if n, err := strconv.Atoi(string(key)); err == nil && n >= 1 && n <= tabCount {
+			d.switchTab(n - 1)
+		}
+	}
+	return false
+}
+
+// switchTab shows tab, closing the drill-downs
+func (d *Dashboard) switchTab(tab int) {
+	d.tab = tab % tabCount
+	d.stack = nil
+}
+
+// move moves the selection of a table, or scrolls text
+func (v *view) move(delta int) {
+	if v.table != nil {
+		v.table.move(delta)
+		return
+	}
+	v.offset += delta
+	if v.offset < 0 {
+		v.offset = 0
+	}
+}
+
+// render draws the view into height lines
+func (v *view) render(width, height int, empty string) []line {
+	var lines []line
+	if v.title != "" {
+		lines = append(lines, plain(v.title, styleBold), nil)
+	}
+	lines = append(lines, v.intro...)
+	if v.table != nil {
+		lines = append(lines, v.table.render(width, height-len(lines), empty)...)
+	} else {
+		visible := height - len(lines)
+		if v.offset > len(v.text)-visible {
+			v.offset = len(v.text) - visible
+		}
+		if v.offset < 0 {
+			v.offset = 0
+		}
+		end := v.offset + visible
+		if end > len(v.text) {
+			end = len(v.text)
+		}
+		if v.offset < end {
+			lines = append(lines, v.text[v.offset:end]...)
+		}
+	}
+	if len(lines) > height {
+		lines = lines[:height]
+	}
+	return lines
+}
+
+// View draws the dashboard as height lines of exactly width columns, with
+// ANSI styles
+func (d *Dashboard) View(width, height int) []string {
+	if width <= 0 || height <= 0 {
+		return nil
+	}
+	tabs := []string{d.msg.TUITabOverview, d.msg.Contributors, d.msg.TUITabFiles, d.msg.PrintHealth, d.msg.TUITabBranches, d.msg.TUITabDevelopers}
+	var tabBar line
+	for i, name := range tabs {
+		style := styleNone
+		if i == d.tab {
+			style = styleSelected
+		}
+		tabBar = append(tabBar, span{text: fmt.Sprintf(" %d %s ", i+1, name), style: style}, span{text: " "})
+	}
+	help := d.msg.TUIHelp
+	if len(d.stack) > 0 {
+		help = d.msg.TUIHelpDetail
+	}
+
+	contentHeight := height - 4
+	if contentHeight < 0 {
+		contentHeight = 0
+	}
+	if len(d.stack) == 0 && d.tab == tabOverview {
+		d.views[tabOverview].text = d.overviewLines(width)
+	}
+	content := d.current().render(width, contentHeight, d.msg.TUIEmpty)
+	d.page = contentHeight - 2
+	if d.page < 1 {
+		d.page = 1
+	}
+
+	lines := []line{
+		plain(" git-log-analyzer · "+d.project, styleTitle),
+		tabBar,
+		plain(strings.Repeat("─", width), styleDim),
+	}
+	lines = append(lines, content...)
+	for len(lines) < height-1 {
+		lines = append(lines, nil)
+	}
+	lines = append(lines[:height-1], plain(help, styleDim))
+
+	screen := make([]string, len(lines))
+	for i, l := range lines {
+		screen[i] = l.render(width)
+	}
+	return screen
+}
+
+// overviewLines draws the summary, the timeline and hour sparklines, the
+// weekday bars and the top contributors and files for width
+func (d *Dashboard) overviewLines(width int) []line {
+	msg := d.msg
+	stats := d.stats
+	ts := stats.TimeStats
+
+	var lines []line
+	summary := [][2]string{
+		{msg.TotalCommits, fmt.Sprint(stats.TotalCommits)},
+		{msg.Contributors, fmt.Sprint(len(stats.AuthorStats))},
+		{msg.ActivePeriod, ts.FirstCommit.Format("2006-01-02") + " – " + ts.LastCommit.Format("2006-01-02")},
+		{msg.ActiveDays, fmt.Sprint(ts.ActiveDays)},
+	}
+	if metrics := stats.CodeHealthMetrics; metrics != nil {
+		summary = append(summary, [2]string{msg.PrintHealthScore, fmt.Sprintf("%.0f/100", metrics.HealthScore*100)})
+	}
+	labelWidth := 0
+	for _, s := range summary {
+		if w := displayWidth(s[0]); w > labelWidth {
+			labelWidth = w
+		}
+	}
+	for _, s := range summary {
+		lines = append(lines, line{{text: "  " + fit(s[0], labelWidth, false) + "  ", style: styleBold}, {text: s[1]}})
+	}
+
+	// Commits per day over the whole period, summed to fit the screen
+	days := dailySeries(stats.CommitFrequency)
+	lines = append(lines, nil, line{{text: msg.CommitTimeline, style: styleBold}})
+	if len(days) > 0 {
+		lines[len(lines)-1] = append(lines[len(lines)-1], span{text: "  " + ts.FirstCommit.Format("2006-01-02") + " – " + ts.LastCommit.Format("2006-01-02"), style: styleDim})
+		lines = append(lines, line{{text: "  "}, {text: Sparkline(days, width-4), style: styleAccent}})
+	}
+
+	// Two columns per hour, with the hour below every sixth
+	var hours []int
+	for hour := 0; hour < 24; hour++ {
+		hours = append(hours, ts.HourlyPattern[hour])
+	}
+	var spark strings.Builder
+	for _, r := range Sparkline(hours, 24) {
+		spark.WriteRune(r)
+		spark.WriteRune(r)
+	}
+	axis := []byte(strings.Repeat(" ", 48))
+	for hour := 0; hour < 24; hour += 6 {
+		copy(axis[hour*2:], strconv.Itoa(hour))
+	}
+	lines = append(lines, nil, plain(msg.HourlyActivity, styleBold),
+		line{{text: "  "}, {text: spark.String(), style: styleAccent}},
+		plain("  "+string(axis), styleDim))
+
+	// Weekdays from Sunday
+	lines = append(lines, nil, plain(msg.DailyActivity, styleBold))
+	maxDay := 0
+	for _, count := range ts.DailyPattern {
+		if count > maxDay {
+			maxDay = count
+		}
+	}
+	dayWidth := 0
+	for _, name := range msg.DayNames {
+		if w := displayWidth(name); w > dayWidth {
+			dayWidth = w
+		}
+	}
+	for day, name := range msg.DayNames {
+		count := ts.DailyPattern[time.Weekday(day)]
+		lines = append(lines, line{{text: "  " + fit(name, dayWidth, false) + "  "}, {text: bar(count, maxDay, 30), style: styleAccent}, {text: fmt.Sprintf(" %d", count)}})
+	}
+
+	// The first rows of the contributor and file tabs
+	for _, top := range []struct {
+		title string
+		table *table
+	}{
+		{msg.TopContributors, d.views[tabContributors].table},
+		{msg.MostModifiedFiles, d.views[tabFiles].table},
+	} {
+		lines = append(lines, nil, plain(top.title, styleBold))
+		rows := append([]row(nil), top.table.rows...)
+		sort.SliceStable(rows, func(i, j int) bool {
+			return rows[i].cells[1].value > rows[j].cells[1].value
+		})
+		if len(rows) > 5 {
+			rows = rows[:5]
+		}
+		max := 0
+		if len(rows) > 0 {
+			max = int(rows[0].cells[1].value)
+		}
+		nameWidth := width / 3
+		for _, r := range rows {
+			count := int(r.cells[1].value)
+			lines = append(lines, line{{text: "  " + fit(r.cells[0].text, nameWidth, false) + "  "}, {text: bar(count, max, 20), style: styleAccent}, {text: fmt.Sprintf(" %d", count)}})
+		}
+	}
+	return lines
+}
+
+// dailySeries returns the commits of every day from the first to the last
+// date of frequency, including days without commits
+func dailySeries(frequency map[string]int) []int {
+	var first, last time.Time
+	for date := range frequency {
+		day, err := time.Parse("2006-01-02", date)
+		if err != nil {
+			continue
+		}
+		if first.IsZero() || day.Before(first) {
+			first = day
+		}
+		if day.After(last) {
+			last = day
+		}
+	}
+	if first.IsZero() {
+		return nil
+	}
+	var series []int
+	for day := first; !day.After(last); day = day.AddDate(0, 0, 1) {
+		series = append(series, frequency[day.Format("2006-01-02")])
+	}
+	return series
+}
+
+// contributorsView is the table of authors
+func (d *Dashboard) contributorsView() *view {
+	msg := d.msg
+	var rows []row
+	for _, key := range d.authorKeys() {
+		a := d.stats.AuthorStats[key]
+		share := 0.0
+		if d.stats.TotalCommits > 0 {
+			share = float64(a.CommitCount) / float64(d.stats.TotalCommits) * 100
+		}
+		rows = append(rows, row{key: key, cells: []cell{
+			text(a.Name),
+			number(a.CommitCount),
+			{text: fmt.Sprintf("%.1f%%", share), value: share},
+			{text: fmt.Sprintf("+%d", a.Additions), value: float64(a.Additions)},
+			{text: fmt.Sprintf("-%d", a.Deletions), value: float64(a.Deletions)},
+			number(len(a.Files)),
+			text(a.LastCommit.Format("2006-01-02")),
+		}})
+	}
+	return &view{
+		table: newTable([]column{
+			{title: msg.PrintAuthor, flex: true},
+			{title: msg.PrintCommits, numeric: true, right: true},
+			{title: msg.PrintShare, numeric: true, right: true},
+			{title: msg.PrintAdditions, numeric: true, right: true},
+			{title: msg.PrintDeletions, numeric: true, right: true},
+			{title: msg.TUIFiles, numeric: true, right: true},
+			{title: msg.TUILastCommit},
+		}, rows, 1),
+		open: d.authorView,
+	}
+}
+
+// authorView is the drill-down of an author: their recent commits and the
+// files they changed
+func (d *Dashboard) authorView(key string) *view {
+	a := d.stats.AuthorStats[key]
+	if a == nil {
+		return nil
+	}
+	msg := d.msg
+	intro := []line{
+		line{{text: a.Name, style: styleBold}, {text: " <" + a.Email + ">", style: styleDim}},
+		plain(fmt.Sprintf("%s %d · %s +%d · %s -%d · %s %s – %s", msg.PrintCommits, a.CommitCount,
+			msg.PrintAdditions, a.Additions, msg.PrintDeletions, a.Deletions,
+			msg.ActivePeriod, a.FirstCommit.Format("2006-01-02"), a.LastCommit.Format("2006-01-02")), styleNone),
+	}
+	if len(a.RecentCommits) > 0 {
+		intro = append(intro, nil, plain(msg.TUIRecentCommits, styleBold))
+		for i, c := range a.RecentCommits {
+			if i == 5 {
+				break
+			}
+			hash := c.Hash
+			if len(hash) > 7 {
+				hash = hash[:7]
+			}
+			intro = append(intro, line{
+				{text: "  " + c.Date.Format("2006-01-02") + "  " + hash + "  ", style: styleDim},
+				{text: c.Subject},
+				{text: fmt.Sprintf("  +%d -%d", c.Additions, c.Deletions), style: styleDim},
+			})
+		}
+	}
+	intro = append(intro, nil)
+
+	total := 0
+	for _, n := range a.Files {
+		total += n
+	}
+	var files []string
+	for file := range a.Files {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	var rows []row
+	for _, file := range files {
+		n := a.Files[file]
+		rows = append(rows, row{key: file, cells: []cell{text(file), number(n), percent(n, total)}})
+	}
+	return &view{
+		title: fmt.Sprintf(msg.TUIAuthorFiles, a.Name),
+		intro: intro,
+		table: newTable([]column{
+			{title: msg.PrintFile, flex: true},
+			{title: msg.PrintChanges, numeric: true, right: true},
+			{title: msg.PrintShare, numeric: true, right: true},
+		}, rows, 1),
+		open: d.fileView,
+	}
+}
+
+// filesView is the table of files
+func (d *Dashboard) filesView() *view {
+	msg := d.msg
+	var files []string
+	for file := range d.stats.FileStats {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	var rows []row
+	for _, file := range files {
+		authors := d.fileStats[file]
+		mainAuthor := ""
+		if len(authors) > 0 {
+			mainAuthor = d.stats.AuthorStats[authors[0].key].Name
+		}
+		rows = append(rows, row{key: file, cells: []cell{
+			text(file),
+			number(d.stats.FileStats[file]),
+			number(len(authors)),
+			text(mainAuthor),
+		}})
+	}
+	return &view{
+		table: newTable([]column{
+			{title: msg.PrintFile, flex: true},
+			{title: msg.PrintChanges, numeric: true, right: true},
+			{title: msg.TUIAuthors, numeric: true, right: true},
+			{title: msg.TUIMainAuthor},
+		}, rows, 1),
+		open: d.fileView,
+	}
+}
+
+// fileView is the drill-down of a file: its authors and, for a hotspot,
+// the risk
+func (d *Dashboard) fileView(file string) *view {
+	msg := d.msg
+	intro := []line{plain(fmt.Sprintf("%s %d", msg.PrintChanges, d.stats.FileStats[file]), styleNone)}
+	if metrics := d.stats.CodeHealthMetrics; metrics != nil {
+		for _, h := range metrics.TechnicalDebtHotspots {
+			if h.FilePath == file {
+				intro = append(intro, plain(fmt.Sprintf("%s: %.2f · %s", msg.ChartHotspots, h.RiskScore, h.Reason), styleWarning))
+			}
+		}
+	}
+	intro = append(intro, nil)
+
+	authors := d.fileStats[file]
+	total := 0
+	for _, a := range authors {
+		total += a.changes
+	}
+	var rows []row
+	for _, a := range authors {
+		rows = append(rows, row{key: a.key, cells: []cell{
+			text(d.stats.AuthorStats[a.key].Name),
+			number(a.changes),
+			percent(a.changes, total),
+		}})
+	}
+	return &view{
+		title: fmt.Sprintf(msg.TUIFileAuthors, file),
+		intro: intro,
+		table: newTable([]column{
+			{title: msg.PrintAuthor, flex: true},
+			{title: msg.PrintChanges, numeric: true, right: true},
+			{title: msg.PrintShare, numeric: true, right: true},
+		}, rows, 1),
+		open: d.authorView,
+	}
+}
+
+// levelOrder ranks the finding levels for sorting, most severe highest
+var levelOrder = map[string]float64{health.LevelError: 3, health.LevelWarning: 2, health.LevelNote: 1}
+
+// levelStyle colors the findings by level
+var levelStyle = map[string]string{health.LevelError: styleError, health.LevelWarning: styleWarning}
+
+// healthView is the health score and the table of findings
+func (d *Dashboard) healthView() *view {
+	msg := d.msg
+	metrics := d.stats.CodeHealthMetrics
+	var intro []line
+	if metrics != nil {
+		intro = append(intro, line{{text: msg.PrintHealthScore + "  ", style: styleBold}, {text: fmt.Sprintf("%.0f/100", metrics.HealthScore*100)}})
+		if metrics.HealthSummary != "" {
+			intro = append(intro, plain(metrics.HealthSummary, styleNone))
+		}
+		intro = append(intro, nil)
+	}
+
+	var rows []row
+	for _, f := range health.Findings(metrics) {
+		rows = append(rows, row{key: f.File, style: levelStyle[f.Level], cells: []cell{
+			{text: f.Level, value: levelOrder[f.Level]},
+			text(f.RuleID),
+			text(f.File),
+			text(f.Message),
+		}})
+	}
+	return &view{
+		intro: intro,
+		table: newTable([]column{
+			{title: msg.PrintLevel, numeric: true},
+			{title: msg.PrintRule},
+			{title: msg.PrintFile, flex: true},
+			{title: msg.PrintMessage, flex: true},
+		}, rows, 0),
+		open: d.fileView,
+	}
+}
+
+// branchesView is the table of branches
+func (d *Dashboard) branchesView() *view {
+	msg := d.msg
+	data := d.stats.BranchData
+	if data == nil {
+		return &view{text: []line{plain(msg.TUINoBranches, styleDim)}}
+	}
+	var rows []row
+	for _, b := range data.Branches {
+		active := ""
+		if b.IsActive {
+			active = "✓"
+		}
+		rows = append(rows, row{key: b.Name, cells: []cell{
+			text(b.Name),
+			number(b.CommitCount),
+			text(b.FirstCommit.Format("2006-01-02")),
+			text(b.LastCommit.Format("2006-01-02")),
+			text(active),
+			text(strings.Join(b.MainAuthors, ", ")),
+		}})
+	}
+	return &view{
+		intro: []line{plain(fmt.Sprintf(msg.TUIBranchSummary, len(data.Branches), len(data.MergePatterns)), styleNone), nil},
+		table: newTable([]column{
+			{title: msg.TUIBranch, flex: true},
+			{title: msg.PrintCommits, numeric: true, right: true},
+			{title: msg.TUIFirstCommit},
+			{title: msg.TUILastCommit},
+			{title: msg.TUIActive},
+			{title: msg.TUIMainAuthor, flex: true},
+		}, rows, 1),
+	}
+}
+
+// developersView is the table of developer profiles
+func (d *Dashboard) developersView() *view {
+	msg := d.msg
+	var rows []row
+	for i, p := range d.profiles {
+		languages := p.TechnicalProfile.PrimaryLanguages
+		if len(languages) > 3 {
+			languages = languages[:3]
+		}
+		rows = append(rows, row{key: strconv.Itoa(i), cells: []cell{
+			text(p.Name),
+			text(p.PersonalityTraits.WorkStyleType),
+			{text: fmt.Sprintf("%.2f", p.WorkStyleMetrics.CommitFrequency), value: p.WorkStyleMetrics.CommitFrequency},
+			{text: fmt.Sprintf("%.0f", p.WorkStyleMetrics.AverageCommitSize), value: p.WorkStyleMetrics.AverageCommitSize},
+			{text: fmt.Sprintf("%.0f", p.WorkStyleMetrics.ConsistencyScore), value: p.WorkStyleMetrics.ConsistencyScore},
+			text(p.TechnicalProfile.ArchitecturalFocus),
+			text(strings.Join(languages, ", ")),
+		}})
+	}
+	return &view{
+		table: newTable([]column{
+			{title: msg.PrintAuthor, flex: true},
+			{title: msg.TUIWorkStyle},
+			{title: msg.TUICommitsPerDay, numeric: true, right: true},
+			{title: msg.TUICommitSize, numeric: true, right: true},
+			{title: msg.TUIConsistency, numeric: true, right: true},
+			{title: msg.TUIFocus},
+			{title: msg.TUILanguages, flex: true},
+		}, rows, 2),
+		open: d.profileView,
+	}
+}
+
+// profileView is the full profile report of a developer
+func (d *Dashboard) profileView(key string) *view {
+	i, err := strconv.Atoi(key)
+	if err != nil || i < 0 || i >= len(d.profiles) {
+		return nil
+	}
+	p := d.profiles[i]
+	var text []line
+	for _, s := range strings.Split(strings.TrimRight(p.GenerateReport(), "\n"), "\n") {
+		text = append(text, plain(s, styleNone))
+	}
+	return &view{title: p.Name, text: text}
+}
+
+// number returns a numeric cell
+func number(n int) cell {
+	return cell{text: strconv.Itoa(n), value: float64(n)}
+}
+
+// percent returns the share of n in total as a numeric cell
+func percent(n, total int) cell {
+	share := 0.0
+	if total > 0 {
+		share = float64(n) / float64(total) * 100
+	}
+	return cell{text: fmt.Sprintf("%.1f%%", share), value: share}
+}
@@ -0,0 +1,169 @@
+package tui
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"git-log-analyzer/internal/analyzer"
+	"git-log-analyzer/internal/developer"
+	"git-log-analyzer/internal/fixture"
+	"git-log-analyzer/internal/i18n"
+)
+
+var ansi = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// newTestDashboard analyzes a small history:
+//
+//	Alice  03-01  main.go, README.md
+//	Bob    03-02  util.go
+//	Carol  03-03  feature.go   (feature)
+//	Alice  03-06  main.go
+//	Alice  03-07  merge feature
+func newTestDashboard(t *testing.T) *Dashboard {
+	t.Helper()
+	day := func(d int) time.Time {
+		return time.Date(2023, 3, d, 10, 0, 0, 0, time.UTC)
+	}
+	repo := fixture.Build(t, fixture.Script{
+		fixture.Commit{Author: fixture.Alice, Date: day(1), Message: "Initial commit",
+			Files: map[string]string{"main.go": fixture.Lines(10), "README.md": fixture.Lines(2)}},
+		fixture.Commit{Author: fixture.Bob, Date: day(2), Message: "Add util",
+			Files: map[string]string{"util.go": fixture.Lines(5)}},
+		fixture.Branch{Name: "feature"},
+		fixture.Checkout{Ref: "feature"},
+		fixture.Commit{Author: fixture.Carol, Date: day(3), Message: "Add feature",
+			Files: map[string]string{"feature.go": fixture.Lines(8)}},
+		fixture.Checkout{Ref: fixture.DefaultBranch},
+		fixture.Commit{Author: fixture.Alice, Date: day(6), Message: "Grow main",
+			Files: map[string]string{"main.go": fixture.Lines(15)}},
+		fixture.Merge{Branch: "feature", Author: fixture.Alice, Date: day(7)},
+	})
+
+	stats, err := analyzer.NewAnalyzer(repo.Dir).Analyze(context.Background())
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	profiles, err := developer.NewProfileAnalyzer(stats).AnalyzeAllDevelopers(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewDashboard(stats, profiles, i18n.GetMessages(i18n.LangEN), "demo")
+}
+
+// screen draws d and returns the text without styles
+func screen(t *testing.T, d *Dashboard, width, height int) string {
+	t.Helper()
+	lines := d.View(width, height)
+	if len(lines) != height {
+		t.Fatalf("Expected %d lines, got %d", height, len(lines))
+	}
+	for i, l := range lines {
+		lines[i] = ansi.ReplaceAllString(l, "")
+		if w := displayWidth(lines[i]); w != width {
+			t.Errorf("Line %d is %d columns wide, expected %d: %q", i, w, width, lines[i])
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestDashboard_Overview(t *testing.T) {
+	d := newTestDashboard(t)
+	s := screen(t, d, 80, 50)
+	for _, want := range []string{
+		"git-log-analyzer · demo",
+		" 1 Overview ",
+		"Total Commits  5",
+		"2023-03-01 – 2023-03-07",
+		"█",
+		"Alice",
+		"main.go",
+	} {
+		if !strings.Contains(s, want) {
+			t.Errorf("Overview is missing %q:\n%s", want, s)
+		}
+	}
+
+	// A small screen keeps its size and scrolls
+	screen(t, d, 30, 8)
+	d.HandleKey(KeyEnd)
+	if s := screen(t, d, 30, 8); strings.Contains(s, "Total Commits") {
+		t.Errorf("Expected the overview to scroll:\n%s", s)
+	}
+}
+
+func TestDashboard_DrillDown(t *testing.T) {
+	d := newTestDashboard(t)
+	d.HandleKey("2")
+	s := screen(t, d, 80, 20)
+	if !strings.Contains(s, "Commits ↓") || !regexp.MustCompile(`(?m)^Alice +3 `).MatchString(s) {
+		t.Fatalf("Expected the contributors by commits, Alice first:\n%s", s)
+	}
+
+	// Alice -> her files -> the authors of main.go
+	d.HandleKey(KeyEnter)
+	s = screen(t, d, 80, 20)
+	if !strings.Contains(s, "Files changed by Alice") || !strings.Contains(s, "Grow main") || !regexp.MustCompile(`(?m)^main\.go +2 `).MatchString(s) {
+		t.Fatalf("Expected Alice's commits and files, main.go first:\n%s", s)
+	}
+	d.HandleKey(KeyEnter)
+	if s = screen(t, d, 80, 20); !strings.Contains(s, "Authors of main.go") {
+		t.Fatalf("Expected the authors of main.go:\n%s", s)
+	}
+
+	d.HandleKey(KeyEscape)
+	if s = screen(t, d, 80, 20); !strings.Contains(s, "Files changed by Alice") {
+		t.Errorf("Expected Esc to go back one level:\n%s", s)
+	}
+	d.HandleKey(KeyRight)
+	if s = screen(t, d, 80, 20); strings.Contains(s, "Files changed by") || !strings.Contains(s, "Main author") {
+		t.Errorf("Expected switching tabs to close the drill-down:\n%s", s)
+	}
+	if !d.HandleKey("q") {
+		t.Error("Expected q to quit")
+	}
+}
+
+func TestDashboard_Sort(t *testing.T) {
+	d := newTestDashboard(t)
+	d.HandleKey("3")
+	d.HandleKey("s") // by authors
+	d.HandleKey("s") // by main author, A to Z
+	s := screen(t, d, 80, 20)
+	if !strings.Contains(s, "Main author ↑") || !regexp.MustCompile(`(?m)^(main\.go|README\.md) .*Alice`).MatchString(s) {
+		t.Errorf("Expected the files by main author:\n%s", s)
+	}
+	d.HandleKey("r")
+	if s = screen(t, d, 80, 20); !strings.Contains(s, "Main author ↓") || !regexp.MustCompile(`(?m)^util\.go .*Bob`).MatchString(s) {
+		t.Errorf("Expected the reversed order:\n%s", s)
+	}
+}
+
+func TestDashboard_Tabs(t *testing.T) {
+	d := newTestDashboard(t)
+
+	d.HandleKey("4")
+	if s := screen(t, d, 100, 20); !strings.Contains(s, "Health score") || !strings.Contains(s, "Level") {
+		t.Errorf("Expected the health tab:\n%s", s)
+	}
+
+	d.HandleKey("5")
+	if s := screen(t, d, 100, 20); !strings.Contains(s, "feature") || !strings.Contains(s, "merges") {
+		t.Errorf("Expected the branches:\n%s", s)
+	}
+
+	d.HandleKey("6")
+	d.HandleKey(KeyEnter)
+	if s := screen(t, d, 100, 30); !strings.Contains(s, "开发者风格画像") {
+		t.Errorf("Expected the developer profile:\n%s", s)
+	}
+
+	// Left from the first tab wraps around to the last
+	d.HandleKey("1")
+	d.HandleKey(KeyLeft)
+	if d.tab != tabDevelopers {
+		t.Errorf("Expected the developers tab, got %d", d.tab)
+	}
+}
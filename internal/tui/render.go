@@ -0,0 +1,162 @@
+package tui
+
+import (
+	"math"
+	"strings"
+
+	"github.com/rivo/uniseg"
+)
+
+// ANSI styles
+const (
+	styleNone     = ""
+	styleBold     = "\x1b[1m"
+	styleDim      = "\x1b[2m"
+	styleHeader   = "\x1b[1;4m"
+	styleSelected = "\x1b[7m"
+	styleTitle    = "\x1b[1;37;44m"
+	styleAccent   = "\x1b[34m"
+	styleError    = "\x1b[31m"
+	styleWarning  = "\x1b[33m"
+	styleReset    = "\x1b[0m"
+)
+
+// span is a piece of a line in one style
+type span struct {
+	text  string
+	style string
+}
+
+// line is a screen line made of styled spans
+type line []span
+
+// plain returns a line of text in one style
+func plain(text, style string) line {
+	return line{{text: text, style: style}}
+}
+
+// text returns the line without styles
+func (l line) text() string {
+	var b strings.Builder
+	for _, s := range l {
+		b.WriteString(s.text)
+	}
+	return b.String()
+}
+
+// render returns the line cut or padded to exactly width columns. The
+// padding takes the style of the last span, so that a selected row is
+// highlighted across the screen.
+func (l line) render(width int) string {
+	var b strings.Builder
+	used := 0
+	style := styleNone
+	for _, s := range l {
+		if used >= width {
+			break
+		}
+		text := s.text
+		if used+displayWidth(text) > width {
+			text = fit(text, width-used, false)
+		}
+		style = s.style
+		b.WriteString(s.style)
+		b.WriteString(text)
+		if s.style != styleNone {
+			b.WriteString(styleReset)
+		}
+		used += displayWidth(text)
+	}
+	if used < width {
+		b.WriteString(style)
+		b.WriteString(strings.Repeat(" ", width-used))
+		if style != styleNone {
+			b.WriteString(styleReset)
+		}
+	}
+	return b.String()
+}
+
+// displayWidth returns the number of terminal columns of s; wide (e.g.
+// Chinese) characters take two
+func displayWidth(s string) int {
+	return uniseg.StringWidth(s)
+}
+
+// fit cuts s to width columns, ending in "…" when cut, and pads it with
+// spaces on the right, or on the left when right is set
+func fit(s string, width int, right bool) string {
+	if width <= 0 {
+		return ""
+	}
+	w := displayWidth(s)
+	if w > width {
+		var b strings.Builder
+		used := 0
+		g := uniseg.NewGraphemes(s)
+		for g.Next() {
+			gw := g.Width()
+			if used+gw > width-1 {
+				break
+			}
+			b.WriteString(g.Str())
+			used += gw
+		}
+		b.WriteString("…")
+		s, w = b.String(), used+1
+	}
+	pad := strings.Repeat(" ", width-w)
+	if right {
+		return pad + s
+	}
+	return s + pad
+}
+
+// sparkBlocks are the sparkline levels from low to high
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline draws values as block characters, at most width of them. Longer
+// series are summed into width buckets; empty buckets are blank.
+func Sparkline(values []int, width int) string {
+	if width <= 0 || len(values) == 0 {
+		return ""
+	}
+	buckets := values
+	if len(values) > width {
+		buckets = make([]int, width)
+		for i := range buckets {
+			for _, v := range values[i*len(values)/width : (i+1)*len(values)/width] {
+				buckets[i] += v
+			}
+		}
+	}
+
+	max := 0
+	for _, v := range buckets {
+		if v > max {
+			max = v
+		}
+	}
+	var b strings.Builder
+	for _, v := range buckets {
+		if v <= 0 {
+			b.WriteRune(' ')
+			continue
+		}
+		level := int(math.Ceil(float64(v)/float64(max)*float64(len(sparkBlocks)))) - 1
+		b.WriteRune(sparkBlocks[level])
+	}
+	return b.String()
+}
+
+// bar draws value as a horizontal bar of at most width blocks relative to max
+func bar(value, max, width int) string {
+	if max <= 0 || value <= 0 {
+		return ""
+	}
+	n := int(math.Round(float64(value) / float64(max) * float64(width)))
+	if n < 1 {
+		n = 1
+	}
+	return strings.Repeat("█", n)
+}
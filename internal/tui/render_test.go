@@ -0,0 +1,49 @@
+package tui
+
+import "testing"
+
+func TestSparkline(t *testing.T) {
+	for _, tc := range []struct {
+		values []int
+		width  int
+		want   string
+	}{
+		{[]int{0, 1, 2, 4, 8}, 10, " ▁▂▄█"},
+		{[]int{1, 1, 0, 0, 2, 2}, 3, "▄ █"}, // summed in pairs
+		{nil, 10, ""},
+		{[]int{0, 0}, 10, "  "},
+	} {
+		if got := Sparkline(tc.values, tc.width); got != tc.want {
+			t.Errorf("Sparkline(%v, %d) = %q, want %q", tc.values, tc.width, got, tc.want)
+		}
+	}
+}
+
+func TestFit(t *testing.T) {
+	for _, tc := range []struct {
+		s     string
+		width int
+		right bool
+		want  string
+	}{
+		{"main.go", 10, false, "main.go   "},
+		{"42", 5, true, "   42"},
+		{"internal/analyzer.go", 10, false, "internal/…"},
+		{"提交时间线", 6, false, "提交… "}, // wide characters take two columns
+		{"abc", 0, false, ""},
+	} {
+		if got := fit(tc.s, tc.width, tc.right); got != tc.want {
+			t.Errorf("fit(%q, %d) = %q, want %q", tc.s, tc.width, got, tc.want)
+		}
+	}
+}
+
+func TestLineRender(t *testing.T) {
+	l := line{{text: "ab"}, {text: "cdef", style: styleBold}}
+	if got := l.render(4); got != "ab"+styleBold+"c…"+styleReset {
+		t.Errorf("Unexpected cut line %q", got)
+	}
+	if got := l.render(8); got != "ab"+styleBold+"cdef"+styleReset+styleBold+"  "+styleReset {
+		t.Errorf("Expected the padding in the last style, got %q", got)
+	}
+}
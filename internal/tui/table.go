@@ -0,0 +1,212 @@
+package tui
+
+import (
+	"sort"
+	"strings"
+)
+
+// column is a table column
+type column struct {
+	title   string
+	numeric bool // sorted by the cell values, largest first
+	right   bool // right aligned
+	flex    bool // shares the width left over by the other columns
+}
+
+// cell is a table cell; value is the sort key of numeric columns
+type cell struct {
+	text  string
+	value float64
+}
+
+// row is a table row; key identifies it for drill-downs
+type row struct {
+	key   string
+	cells []cell
+	style string
+	order int // position before sorting, the final tie-break
+}
+
+// table is a sortable, scrollable table with a selected row
+type table struct {
+	columns  []column
+	rows     []row
+	sortCol  int
+	desc     bool
+	selected int
+	offset   int
+}
+
+// newTable creates a table sorted by sortCol, descending for numeric
+// columns. The rows keep their given order among equal values.
+func newTable(columns []column, rows []row, sortCol int) *table {
+	for i := range rows {
+		rows[i].order = i
+	}
+	t := &table{columns: columns, rows: rows, sortCol: sortCol, desc: columns[sortCol].numeric}
+	t.sort()
+	t.selected = 0
+	return t
+}
+
+// text returns a text cell
+func text(s string) cell {
+	return cell{text: s}
+}
+
+// sort orders the rows by the sort column, keeping the selected row selected
+func (t *table) sort() {
+	key, ok := t.selectedKey()
+	c := t.sortCol
+	sort.SliceStable(t.rows, func(i, j int) bool {
+		a, b := t.rows[i].cells[c], t.rows[j].cells[c]
+		var less, greater bool
+		if t.columns[c].numeric {
+			less, greater = a.value < b.value, a.value > b.value
+		} else {
+			x, y := strings.ToLower(a.text), strings.ToLower(b.text)
+			less, greater = x < y, x > y
+		}
+		if less == greater {
+			return t.rows[i].order < t.rows[j].order
+		}
+		if t.desc {
+			return greater
+		}
+		return less
+	})
+	if !ok {
+		return
+	}
+	for i, r := range t.rows {
+		if r.key == key {
+			t.selected = i
+		}
+	}
+}
+
+// cycleSort sorts by the next column: numbers largest first, text A to Z
+func (t *table) cycleSort() {
+	t.sortCol = (t.sortCol + 1) % len(t.columns)
+	t.desc = t.columns[t.sortCol].numeric
+	t.sort()
+}
+
+// reverse flips the sort order
+func (t *table) reverse() {
+	t.desc = !t.desc
+	t.sort()
+}
+
+// move moves the selection by delta rows, stopping at the first and last
+func (t *table) move(delta int) {
+	t.selected += delta
+	if t.selected >= len(t.rows) {
+		t.selected = len(t.rows) - 1
+	}
+	if t.selected < 0 {
+		t.selected = 0
+	}
+}
+
+// selectedKey returns the key of the selected row
+func (t *table) selectedKey() (string, bool) {
+	if t.selected < 0 || t.selected >= len(t.rows) {
+		return "", false
+	}
+	return t.rows[t.selected].key, true
+}
+
+// widths lays the columns out in width: fixed columns as wide as their
+// widest cell (at most a third of the screen), flexible columns share the
+// rest
+func (t *table) widths(width int) []int {
+	const gap = 2
+	widths := make([]int, len(t.columns))
+	flex := 0
+	rest := width - gap*(len(t.columns)-1)
+	for i, c := range t.columns {
+		if c.flex {
+			flex++
+			continue
+		}
+		w := displayWidth(c.title) + 2 // room for the sort arrow
+		for _, r := range t.rows {
+			if cw := displayWidth(r.cells[i].text); cw > w {
+				w = cw
+			}
+		}
+		if w > width/3 {
+			w = width / 3
+		}
+		widths[i] = w
+		rest -= w
+	}
+	for i, c := range t.columns {
+		if c.flex {
+			widths[i] = rest / flex
+			if widths[i] < 6 {
+				widths[i] = 6
+			}
+		}
+	}
+	return widths
+}
+
+// render draws the header and the rows that fit into height lines,
+// scrolling to keep the selected row visible
+func (t *table) render(width, height int, empty string) []line {
+	if height < 2 {
+		return nil
+	}
+	widths := t.widths(width)
+	cellLine := func(cells []string) string {
+		parts := make([]string, len(cells))
+		for i, s := range cells {
+			parts[i] = fit(s, widths[i], t.columns[i].right)
+		}
+		return strings.Join(parts, "  ")
+	}
+
+	titles := make([]string, len(t.columns))
+	for i, c := range t.columns {
+		titles[i] = c.title
+		if i == t.sortCol {
+			if t.desc {
+				titles[i] += " ↓"
+			} else {
+				titles[i] += " ↑"
+			}
+		}
+	}
+	lines := []line{plain(cellLine(titles), styleHeader)}
+	if len(t.rows) == 0 {
+		return append(lines, plain(empty, styleDim))
+	}
+
+	visible := height - 1
+	if t.selected < t.offset {
+		t.offset = t.selected
+	}
+	if t.selected >= t.offset+visible {
+		t.offset = t.selected - visible + 1
+	}
+	if t.offset > len(t.rows)-visible {
+		t.offset = len(t.rows) - visible
+	}
+	if t.offset < 0 {
+		t.offset = 0
+	}
+	for i := t.offset; i < len(t.rows) && i < t.offset+visible; i++ {
+		cells := make([]string, len(t.columns))
+		for j, c := range t.rows[i].cells {
+			cells[j] = c.text
+		}
+		style := t.rows[i].style
+		if i == t.selected {
+			style = styleSelected
+		}
+		lines = append(lines, plain(cellLine(cells), style))
+	}
+	return lines
+}
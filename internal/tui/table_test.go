@@ -0,0 +1,78 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+)
+
+func testTable() *table {
+	return newTable([]column{
+		{title: "Name", flex: true},
+		{title: "Count", numeric: true, right: true},
+	}, []row{
+		{key: "b", cells: []cell{text("beta"), number(2)}},
+		{key: "a", cells: []cell{text("Alpha"), number(5)}},
+		{key: "c", cells: []cell{text("gamma"), number(2)}},
+	}, 1)
+}
+
+func keys(t *table) string {
+	var keys []string
+	for _, r := range t.rows {
+		keys = append(keys, r.key)
+	}
+	return strings.Join(keys, "")
+}
+
+func TestTableSort(t *testing.T) {
+	tbl := testTable()
+	if got := keys(tbl); got != "abc" {
+		t.Errorf("Expected the largest count first and equal counts in order, got %s", got)
+	}
+	if key, _ := tbl.selectedKey(); key != "a" {
+		t.Errorf("Expected the first row selected, got %s", key)
+	}
+
+	tbl.move(2)
+	tbl.cycleSort()
+	if got := keys(tbl); got != "abc" || tbl.desc {
+		t.Errorf("Expected names A to Z ignoring case, got %s", got)
+	}
+	if key, _ := tbl.selectedKey(); key != "c" {
+		t.Errorf("Expected the selection to follow the row, got %s", key)
+	}
+	tbl.reverse()
+	if got := keys(tbl); got != "cba" {
+		t.Errorf("Expected names Z to A, got %s", got)
+	}
+	tbl.cycleSort()
+	if tbl.sortCol != 1 || !tbl.desc {
+		t.Errorf("Expected to cycle back to the count, largest first")
+	}
+}
+
+func TestTableRender(t *testing.T) {
+	tbl := testTable()
+	lines := tbl.render(30, 3, "none")
+	if len(lines) != 3 {
+		t.Fatalf("Expected the header and two rows, got %d lines", len(lines))
+	}
+	if got := lines[0].text(); got != "Name"+strings.Repeat(" ", 19)+"Count ↓" {
+		t.Errorf("Unexpected header %q", got)
+	}
+	if got := lines[1].text(); got != "Alpha"+strings.Repeat(" ", 24)+"5" || lines[1][0].style != styleSelected {
+		t.Errorf("Expected the selected first row, got %q", got)
+	}
+
+	// The selection stays visible
+	tbl.move(10)
+	lines = tbl.render(30, 3, "none")
+	if got := lines[2].text(); !strings.HasPrefix(got, "gamma") || lines[2][0].style != styleSelected {
+		t.Errorf("Expected the last row selected and visible, got %q", got)
+	}
+
+	empty := newTable(tbl.columns, nil, 0)
+	if lines := empty.render(20, 5, "none"); len(lines) != 2 || lines[1].text() != "none" {
+		t.Errorf("Expected the empty message, got %v", lines)
+	}
+}
@@ -0,0 +1,175 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// Key is a key press: a named key or the typed character
+type Key string
+
+// Named keys
+const (
+	KeyUp       Key = "up"
+	KeyDown     Key = "down"
+	KeyLeft     Key = "left"
+	KeyRight    Key = "right"
+	KeyPageUp   Key = "pgup"
+	KeyPageDown Key = "pgdown"
+	KeyHome     Key = "home"
+	KeyEnd      Key = "end"
+	KeyTab      Key = "tab"
+	KeyBackTab  Key = "backtab"
+	KeyEnter    Key = "enter"
+	KeyEscape   Key = "esc"
+	KeyBack     Key = "backspace"
+	KeyCtrlC    Key = "ctrl-c"
+)
+
+// escapeKeys are the escape sequences of the named keys, in the variants
+// sent by common terminals
+var escapeKeys = map[string]Key{
+	"\x1b[A":  KeyUp,
+	"\x1bOA":  KeyUp,
+	"\x1b[B":  KeyDown,
+	"\x1bOB":  KeyDown,
+	"\x1b[C":  KeyRight,
+	"\x1bOC":  KeyRight,
+	"\x1b[D":  KeyLeft,
+	"\x1bOD":  KeyLeft,
+	"\x1b[5~": KeyPageUp,
+	"\x1b[6~": KeyPageDown,
+	"\x1b[H":  KeyHome,
+	"\x1bOH":  KeyHome,
+	"\x1b[1~": KeyHome,
+	"\x1b[7~": KeyHome,
+	"\x1b[F":  KeyEnd,
+	"\x1bOF":  KeyEnd,
+	"\x1b[4~": KeyEnd,
+	"\x1b[8~": KeyEnd,
+	"\x1b[Z":  KeyBackTab,
+}
+
+// DecodeKeys splits terminal input into key presses. Unknown escape
+// sequences are dropped.
+func DecodeKeys(input []byte) []Key {
+	var keys []Key
+	s := string(input)
+	for len(s) > 0 {
+		if s[0] == 0x1b {
+			if len(s) == 1 || s[1] == 0x1b {
+				keys = append(keys, KeyEscape)
+				s = s[1:]
+				continue
+			}
+			// A CSI or SS3 sequence ends with a letter or "~"
+			end := strings.IndexFunc(s[2:], func(r rune) bool {
+				return r == '~' || (r >= 'A' && r <= 'Z') || (r >= 'a' && r <= 'z')
+			})
+			if (s[1] != '[' && s[1] != 'O') || end < 0 {
+				keys = append(keys, KeyEscape)
+				s = s[1:]
+				continue
+			}
+			seq := s[:end+3]
+			if key, ok := escapeKeys[seq]; ok {
+				keys = append(keys, key)
+			}
+			s = s[len(seq):]
+			continue
+		}
+
+		switch s[0] {
+		case '\r', '\n':
+			keys = append(keys, KeyEnter)
+		case '\t':
+			keys = append(keys, KeyTab)
+		case 0x7f, 0x08:
+			keys = append(keys, KeyBack)
+		case 0x03:
+			keys = append(keys, KeyCtrlC)
+		default:
+			r := []rune(s)[0]
+			keys = append(keys, Key(string(r)))
+			s = s[len(string(r)):]
+			continue
+		}
+		s = s[1:]
+	}
+	return keys
+}
+
+// Run shows the dashboard on the terminal of in and out until the user
+// quits or ctx is done. The screen is redrawn after every key press and when
+// the terminal is resized.
+func Run(ctx context.Context, d *Dashboard, in, out *os.File) error {
+	fd := int(in.Fd())
+	if !term.IsTerminal(fd) || !term.IsTerminal(int(out.Fd())) {
+		return fmt.Errorf("the dashboard needs an interactive terminal")
+	}
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return fmt.Errorf("failed to switch the terminal to raw mode: %v", err)
+	}
+	defer term.Restore(fd, state)
+
+	// Alternate screen without cursor, restored on exit
+	fmt.Fprint(out, "\x1b[?1049h\x1b[?25l")
+	defer fmt.Fprint(out, "\x1b[?25h\x1b[?1049l")
+
+	done := make(chan struct{})
+	defer close(done)
+	input := make(chan []byte)
+	go func() {
+		buf := make([]byte, 256)
+		for {
+			n, err := in.Read(buf)
+			if err != nil {
+				return
+			}
+			data := append([]byte(nil), buf[:n]...)
+			select {
+			case input <- data:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	width, height := 0, 0
+	draw := func() {
+		w, h, err := term.GetSize(int(out.Fd()))
+		if err != nil {
+			w, h = 80, 24
+		}
+		width, height = w, h
+		out.WriteString("\x1b[H" + strings.Join(d.View(w, h), "\r\n"))
+	}
+	draw()
+
+	// Polling the size also works where there is no SIGWINCH
+	ticker := time.NewTicker(250 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if w, h, err := term.GetSize(int(out.Fd())); err == nil && (w != width || h != height) {
+				draw()
+			}
+		case data := <-input:
+			for _, key := range DecodeKeys(data) {
+				if d.HandleKey(key) {
+					return nil
+				}
+			}
+			draw()
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package tui
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDecodeKeys(t *testing.T) {
+	for input, want := range map[string][]Key{
+		"q":                    {"q"},
+		"\x1b[A\x1b[B":         {KeyUp, KeyDown},
+		"\x1bOC\x1b[D":         {KeyRight, KeyLeft},
+		"\x1b[5~\x1b[6~\x1b[H": {KeyPageUp, KeyPageDown, KeyHome},
+		"\x1b":                 {KeyEscape},
+		"\x1b\x1b[Z":           {KeyEscape, KeyBackTab},
+		"\r\t\x7f\x03":         {KeyEnter, KeyTab, KeyBack, KeyCtrlC},
+		"\x1b[15~s":            {"s"}, // F5 is ignored
+		"é2":                   {"é", "2"},
+	} {
+		if got := DecodeKeys([]byte(input)); !reflect.DeepEqual(got, want) {
+			t.Errorf("DecodeKeys(%q) = %v, want %v", input, got, want)
+		}
+	}
+}
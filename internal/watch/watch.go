@@ -0,0 +1,150 @@
+// Package watch notices new commits by polling a repository's HEAD and refs.
+// Polling a handful of small files needs no platform specific notification
+// API and also works on network file systems.
+package watch
+
+import (
+	"context"
+	"crypto/sha1"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// DefaultInterval is the polling interval used when none is set
+const DefaultInterval = 2 * time.Second
+
+// Watcher reports changes of HEAD, the loose refs and packed-refs
+type Watcher struct {
+	dirs     []string
+	interval time.Duration
+	last     string
+}
+
+// New creates a watcher for the repository at repoPath, polling every
+// interval. The current state counts as seen.
+func New(repoPath string, interval time.Duration) (*Watcher, error) {
+	dirs, err := GitDirs(repoPath)
+	if err != nil {
+		return nil, err
+	}
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	w := &Watcher{dirs: dirs, interval: interval}
+	if w.last, err = Fingerprint(dirs); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Wait blocks until HEAD or a ref has changed since the last call and
+// returns ctx.Err() once ctx is done. Errors reading the refs, e.g. while
+// git rewrites packed-refs, are retried at the next poll.
+func (w *Watcher) Wait(ctx context.Context) error {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+		current, err := Fingerprint(w.dirs)
+		if err != nil || current == w.last {
+			continue
+		}
+		w.last = current
+		return nil
+	}
+}
+
+// GitDirs returns the directories holding the HEAD and refs of the
+// repository at repoPath: its git directory and, for a linked worktree, the
+// main repository's git directory with the shared refs
+func GitDirs(repoPath string) ([]string, error) {
+	gitDir := filepath.Join(repoPath, ".git")
+	info, err := os.Stat(gitDir)
+	switch {
+	case err == nil && !info.IsDir():
+		// A worktree or submodule: .git is a file "gitdir: <path>"
+		data, err := os.ReadFile(gitDir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %v", gitDir, err)
+		}
+		target, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir:")
+		if !ok {
+			return nil, fmt.Errorf("unexpected content in %s", gitDir)
+		}
+		gitDir = strings.TrimSpace(target)
+		if !filepath.IsAbs(gitDir) {
+			gitDir = filepath.Join(repoPath, gitDir)
+		}
+	case err != nil:
+		// A bare repository
+		gitDir = repoPath
+	}
+	if _, err := os.Stat(filepath.Join(gitDir, "HEAD")); err != nil {
+		return nil, fmt.Errorf("not a git repository: %s", repoPath)
+	}
+
+	dirs := []string{gitDir}
+	if data, err := os.ReadFile(filepath.Join(gitDir, "commondir")); err == nil {
+		common := strings.TrimSpace(string(data))
+		if !filepath.IsAbs(common) {
+			common = filepath.Join(gitDir, common)
+		}
+		dirs = append(dirs, filepath.Clean(common))
+	}
+	return dirs, nil
+}
+
+// Fingerprint summarizes HEAD, the loose refs and packed-refs of dirs; it
+// changes whenever a ref moves. Loose refs are read since they are tiny and
+// their content is exact where modification times are coarse; packed-refs
+// is compared by size and modification time.
+func Fingerprint(dirs []string) (string, error) {
+	h := sha1.New()
+	for _, dir := range dirs {
+		if data, err := os.ReadFile(filepath.Join(dir, "HEAD")); err == nil {
+			fmt.Fprintf(h, "HEAD %s\n", data)
+		} else if !os.IsNotExist(err) {
+			return "", err
+		}
+
+		if info, err := os.Stat(filepath.Join(dir, "packed-refs")); err == nil {
+			fmt.Fprintf(h, "packed-refs %d %d\n", info.Size(), info.ModTime().UnixNano())
+		}
+
+		refs := filepath.Join(dir, "refs")
+		err := filepath.WalkDir(refs, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				if os.IsNotExist(err) {
+					// A ref removed while walking
+					return nil
+				}
+				return err
+			}
+			if d.IsDir() || strings.HasSuffix(path, ".lock") {
+				return nil
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				if os.IsNotExist(err) {
+					return nil
+				}
+				return err
+			}
+			rel, _ := filepath.Rel(refs, path)
+			fmt.Fprintf(h, "%s %s\n", filepath.ToSlash(rel), data)
+			return nil
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to read refs: %v", err)
+		}
+	}
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
@@ -0,0 +1,70 @@
+package watch
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"git-log-analyzer/internal/fixture"
+)
+
+func day(d int) time.Time {
+	return time.Date(2024, 5, d, 12, 0, 0, 0, time.UTC)
+}
+
+func TestWatcher_Wait(t *testing.T) {
+	repo := fixture.Build(t, fixture.Script{
+		fixture.Commit{Author: fixture.Alice, Date: day(1), Message: "Initial commit", Files: map[string]string{"main.go": fixture.Lines(3)}},
+	})
+	w, err := New(repo.Dir, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	// No change until the context ends
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := w.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected no change, got %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		done <- w.Wait(ctx)
+	}()
+	repo.Apply(t, fixture.Script{
+		fixture.Commit{Author: fixture.Bob, Date: day(2), Message: "Grow main", Files: map[string]string{"main.go": fixture.Lines(5)}},
+	})
+	if err := <-done; err != nil {
+		t.Errorf("Expected a commit to be noticed, got %v", err)
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	repo := fixture.Build(t, fixture.Script{
+		fixture.Commit{Author: fixture.Alice, Date: day(1), Message: "Initial commit", Files: map[string]string{"main.go": fixture.Lines(3)}},
+	})
+	dirs, err := GitDirs(repo.Dir)
+	if err != nil {
+		t.Fatalf("GitDirs failed: %v", err)
+	}
+	before, err := Fingerprint(dirs)
+	if err != nil {
+		t.Fatalf("Fingerprint failed: %v", err)
+	}
+	if again, _ := Fingerprint(dirs); again != before {
+		t.Error("Expected the fingerprint to be stable")
+	}
+
+	repo.Apply(t, fixture.Script{fixture.Branch{Name: "feature"}})
+	if after, _ := Fingerprint(dirs); after == before {
+		t.Error("Expected a new branch to change the fingerprint")
+	}
+
+	if _, err := GitDirs(t.TempDir()); err == nil {
+		t.Error("Expected an error outside a repository")
+	}
+}